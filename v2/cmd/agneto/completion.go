@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/durch/agneto/v2/pkg/natsconn"
+)
+
+// completeCommand is the hidden command the completion scripts call:
+// agneto __complete <word being typed> [command]
+const completeCommand = "__complete"
+
+// completions returns the words offered for the word being typed
+// Words starting with - complete flags (connection flags before the command,
+// the command's own after it); others complete commands or their positional words
+func completions(args []string) ([]string, error) {
+	cur := ""
+	if len(args) > 0 {
+		cur = args[0]
+	}
+	flagWord := strings.HasPrefix(cur, "-")
+
+	if len(args) < 2 {
+		var words []string
+		if flagWord {
+			for _, f := range natsconn.Flags {
+				words = append(words, "--"+f.Name)
+			}
+			return words, nil
+		}
+		for _, c := range commands {
+			words = append(words, c.name)
+		}
+		return append(words, "completion", "help"), nil
+	}
+
+	switch name := args[1]; name {
+	case "completion":
+		return []string{"bash", "zsh", "fish"}, nil
+	case "help":
+		return completions(args[:1])
+	default:
+		if flagWord {
+			return commandFlags(name)
+		}
+		c, _ := lookup(name)
+		return c.subcommands, nil
+	}
+}
+
+// completionScript returns the completion script for a shell
+func completionScript(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return bashCompletion, nil
+	case "zsh":
+		return "autoload -U +X bashcompinit && bashcompinit\n" + bashCompletion, nil
+	case "fish":
+		return fishCompletion, nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q (bash, zsh or fish)", shell)
+	}
+}
+
+// bashCompletion completes commands and flags by asking agneto itself, so
+// flags added to a command are offered without regenerating the script
+// Connection flags before the command take a value, which is skipped
+const bashCompletion = `# agneto completion for bash - source it, e.g. from ~/.bashrc:
+#   source <(agneto completion bash)
+_agneto() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" cmd="" i
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		case "${COMP_WORDS[i]}" in
+		-*=*) ;;
+		-*) ((i++)) ;;
+		*)
+			cmd="${COMP_WORDS[i]}"
+			break
+			;;
+		esac
+	done
+	COMPREPLY=($(compgen -W "$(agneto __complete "$cur" $cmd 2>/dev/null)" -- "$cur"))
+}
+complete -o default -F _agneto agneto
+`
+
+// fishCompletion is the fish equivalent of bashCompletion
+const fishCompletion = `# agneto completion for fish - save it, e.g.:
+#   agneto completion fish > ~/.config/fish/completions/agneto.fish
+function __agneto_complete
+	set -l cmd
+	set -l skip 0
+	for word in (commandline -opc)[2..-1]
+		if test $skip -eq 1
+			set skip 0
+			continue
+		end
+		switch $word
+			case '-*=*'
+			case '-*'
+				set skip 1
+			case '*'
+				set cmd $word
+				break
+		end
+	end
+	agneto __complete (commandline -ct) $cmd 2>/dev/null
+end
+complete -c agneto -a '(__agneto_complete)'
+`
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/internal/cmd/agent"
+	"github.com/durch/agneto/v2/internal/cmd/autorespond"
+	"github.com/durch/agneto/v2/internal/cmd/doctor"
+	"github.com/durch/agneto/v2/internal/cmd/export"
+	"github.com/durch/agneto/v2/internal/cmd/forward"
+	"github.com/durch/agneto/v2/internal/cmd/httpbridge"
+	"github.com/durch/agneto/v2/internal/cmd/outbox"
+	"github.com/durch/agneto/v2/internal/cmd/publisher"
+	"github.com/durch/agneto/v2/internal/cmd/recorder"
+	"github.com/durch/agneto/v2/internal/cmd/tail"
+	"github.com/durch/agneto/v2/internal/cmd/tui"
+	"github.com/durch/agneto/v2/internal/cmd/web"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+)
+
+// command is a subcommand, run in this process by its package's Run
+type command struct {
+	name        string
+	run         func(args []string) error
+	summary     string
+	subcommands []string          // Positional words offered by shell completion
+	aliases     map[string]string // Flags renamed before running the command (e.g. from: from-file)
+}
+
+// commands are the subcommands agneto knows about, in help order
+// Any other name runs an agneto-<name> executable from PATH, git-style
+var commands = []command{
+	{name: "tui", run: tui.Run, summary: "Monitor events and answer decisions"},
+	{name: "replay", run: tui.Run, summary: "Replay a recording, archive directory or segment (--from path or s3://bucket/key)", aliases: map[string]string{"from": "from-file", "speed": "replay-speed"}},
+	{name: "publish", run: publisher.Run, summary: "Publish an event, optionally with actions"},
+	{name: "agent", run: agent.Run, summary: "Keep a NATS connection open for publishers on a unix socket"},
+	{name: "tail", run: tail.Run, summary: "Print live events as JSON Lines, without a terminal UI"},
+	{name: "record", run: recorder.Run, summary: "Record live events to a SQLite database, without a terminal UI"},
+	{name: "export", run: export.Run, summary: "Convert recorded events to CSV or JSON Lines"},
+	{name: "forward", run: forward.Run, summary: "Forward actionable events to Slack and relay the answers"},
+	{name: "httpbridge", run: httpbridge.Run, summary: "Publish events over HTTP and stream them as server-sent events"},
+	{name: "web", run: web.Run, summary: "Serve the panes and action buttons as a browser dashboard"},
+	{name: "autorespond", run: autorespond.Run, summary: "Answer decisions unattended by the rules of a policy file"},
+	{name: "outbox", run: outbox.Run, summary: "Inspect, flush and drop queued responses", subcommands: []string{"ls", "flush", "drop"}},
+	{name: "doctor", run: doctor.Run, summary: "Diagnose the NATS connection, credentials and permissions"},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: agneto [connection flags] <command> [flags]
+
+Commands:
+`)
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(os.Stderr, `  %-12s %s
+  %-12s %s
+
+Run "agneto help <command>" for a command's flags.
+
+Connection flags (also accepted by each command):
+`, "completion", "Print a bash, zsh or fish completion script", "help", "Show this help or a command's flags")
+	flag.PrintDefaults()
+}
+
+// AIDEV-NOTE: Every command is a package under internal/cmd with a
+// Run(args) entry point, linked in here, so one agneto binary holds them all;
+// cmd/<command> builds the same package as a standalone binary for the docs
+// and scripts that use them. Connection flags given before the command reach
+// it through the environment natsconn.Load reads. Subcommand parsing is stdlib
+// flag like every other command - cobra isn't vendored
+func main() {
+	natsconn.AddFlags(flag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name, args := flag.Arg(0), flag.Args()[1:]
+	switch name {
+	case "help":
+		if len(args) == 0 {
+			usage()
+			return
+		}
+		os.Exit(run(args[0], []string{"-h"}))
+
+	case "completion":
+		if len(args) != 1 {
+			log.Fatal("completion requires a shell: bash, zsh or fish")
+		}
+		script, err := completionScript(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(script)
+
+	case completeCommand:
+		// Called by the completion scripts
+		words, err := completions(args)
+		if err != nil {
+			os.Exit(1)
+		}
+		for _, word := range words {
+			fmt.Println(word)
+		}
+
+	default:
+		os.Exit(run(name, args))
+	}
+}
+
+// lookup returns the known command with a name
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// run runs a command and returns its exit status
+// Unknown names run an agneto-<name> executable from PATH
+func run(name string, args []string) int {
+	c, known := lookup(name)
+	if !known {
+		return runExternal(name, args)
+	}
+	return cli.Status(c.run(c.rename(args)))
+}
+
+// runExternal runs an agneto-<name> executable from PATH with the terminal
+// attached and returns its exit status
+func runExternal(name string, args []string) int {
+	path, err := exec.LookPath("agneto-" + name)
+	if err != nil {
+		log.Printf("unknown command %q (see agneto help)", name)
+		return 2
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	// Ctrl+C reaches the command from the terminal; only pass on termination
+	signal.Ignore(os.Interrupt)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+
+	if err := cmd.Start(); err != nil {
+		log.Print(err)
+		return 1
+	}
+	go func() {
+		for sig := range signals {
+			cmd.Process.Signal(sig)
+		}
+	}()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode()
+	case err != nil:
+		log.Print(err)
+		return 1
+	}
+	return 0
+}
+
+// rename applies the command's flag aliases to its arguments
+func (c command) rename(args []string) []string {
+	if len(c.aliases) == 0 {
+		return args
+	}
+	renamed := make([]string, len(args))
+	for i, arg := range args {
+		renamed[i] = arg
+		if arg == "--" {
+			copy(renamed[i:], args[i:])
+			break
+		}
+		dashes := arg[:len(arg)-len(strings.TrimLeft(arg, "-"))]
+		if dashes == "" {
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg[len(dashes):], "=")
+		if alias, ok := c.aliases[name]; ok {
+			renamed[i] = dashes + alias
+			if hasValue {
+				renamed[i] += "=" + value
+			}
+		}
+	}
+	return renamed
+}
+
+// flagPattern matches a flag in flag.PrintDefaults output
+var flagPattern = regexp.MustCompile(`(?m)^\s+-([\w-]+)`)
+
+// commandFlags returns a command's flags by asking it for its usage
+func commandFlags(name string) ([]string, error) {
+	var usage bytes.Buffer
+	c, known := lookup(name)
+	if known {
+		cli.Output = &usage
+		c.run([]string{"-h"}) // Prints the usage before doing anything
+		cli.Output = os.Stderr
+	} else {
+		path, err := exec.LookPath("agneto-" + name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown command %q", name)
+		}
+		out, _ := exec.Command(path, "-h").CombinedOutput() // -h exits non-zero in some commands
+		usage.Write(out)
+	}
+	var names []string
+	for _, m := range flagPattern.FindAllStringSubmatch(usage.String(), -1) {
+		names = append(names, "--"+m[1])
+	}
+	for alias := range c.aliases {
+		names = append(names, "--"+alias)
+	}
+	return names, nil
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/internal/cmd/recorder"
+)
+
+func main() {
+	cli.Main(recorder.Run)
+}
@@ -0,0 +1,69 @@
+// Package cli is what the agneto commands share: each command is a package
+// under internal/cmd with a Run(args []string) error entry point, linked into
+// its own cmd/ binary (through Main) and into agneto as a subcommand.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// Output is where flag sets created by NewFlagSet print usage and errors
+// agneto points it at a buffer to read a command's flags for completion
+var Output io.Writer = os.Stderr
+
+// ExitCode ends a command with an exit status, once it has printed why
+type ExitCode int
+
+func (e ExitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// NewFlagSet creates a command's flags; Parse parses them
+func NewFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(Output)
+	return fs
+}
+
+// Parse parses a command's arguments
+// -h and --help return flag.ErrHelp and invalid flags ExitCode(2), the flag
+// package's own exit statuses; both have printed the usage already
+func Parse(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return ExitCode(2)
+	}
+	return err
+}
+
+// Given reports whether a flag was set on the command line
+func Given(fs *flag.FlagSet, name string) bool {
+	given := false
+	fs.Visit(func(f *flag.Flag) {
+		given = given || f.Name == name
+	})
+	return given
+}
+
+// Status returns the exit status of a command's result, logging its error
+func Status(err error) int {
+	var exit ExitCode
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &exit):
+		return int(exit)
+	}
+	log.Print(err)
+	return 1
+}
+
+// Main runs a command as the program, with its arguments and exit status
+func Main(run func(args []string) error) {
+	os.Exit(Status(run(os.Args[1:])))
+}
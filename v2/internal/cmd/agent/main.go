@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/agent"
+	"github.com/durch/agneto/v2/pkg/metrics"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/nats-io/nats.go"
+)
+
+// Run runs the agent command with its arguments
+func Run(args []string) error {
+	fs := cli.NewFlagSet("agent")
+	socket := fs.String("socket", agent.DefaultSocket(), "Unix socket publishers reach the agent on (also $"+agent.EnvSocket+")")
+	metricsAddr := fs.String("metrics-addr", "", "Serve Prometheus metrics (requests, messages published) on this address at /metrics, e.g. :9465")
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	// Publishers rely on the connection, so it is re-established however long NATS is away
+	settings := natsconn.Load()
+	nc, err := settings.Connect("agneto-agent", nats.MaxReconnects(-1))
+	if err != nil {
+		return fmt.Errorf("%v (run the doctor command for diagnostics)", err)
+	}
+	defer nc.Close()
+
+	ln, err := agent.Listen(*socket)
+	if err != nil {
+		return fmt.Errorf("Failed to listen on %s: %v", *socket, err)
+	}
+
+	// The socket goes away with the agent, so publishers stop trying it
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		ln.Close()
+	}()
+
+	server := agent.NewServer(nc, settings.URL)
+	if *metricsAddr != "" {
+		registry := metrics.NewRegistry()
+		server.Instrument(registry)
+		if _, err := registry.Serve(*metricsAddr); err != nil {
+			return fmt.Errorf("Failed to serve --metrics-addr: %v", err)
+		}
+	}
+
+	log.Printf("Publishing for agneto commands on %s through %s", *socket, settings.URL)
+	return server.Serve(ln)
+}
@@ -0,0 +1,163 @@
+package autorespond
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/durch/agneto/v2/pkg/policy"
+	"github.com/nats-io/nats.go"
+)
+
+// responder answers the decisions its policy covers, for unattended runs
+type responder struct {
+	policy  *policy.Policy
+	log     *policy.Log
+	subject string           // Subject responses are published on
+	pub     outbox.Publisher // Delivers outbox entries (nc, or the edge stream)
+	nc      *nats.Conn       // Sends replies to producers' reply subjects
+	outbox  *outbox.Outbox
+	dedup   *monitor.Dedup
+	dryRun  bool
+}
+
+// Run runs the autorespond command with its arguments
+func Run(args []string) error {
+	// Define flags
+	fs := cli.NewFlagSet("autorespond")
+	policyFile := fs.String("policy", "", "Path to JSON file of auto-respond rules (required)")
+	subject := fs.String("subject", "test.events", "Subject to answer decisions on (responses are published here too)")
+	logPath := fs.String("log", "", "Append every decision to this file as JSON Lines (they are always logged to stderr)")
+	outboxDir := fs.String("outbox", outbox.DefaultDir("autorespond"), "Directory of the durable outbox for responses")
+	dryRun := fs.Bool("dry-run", false, "Log the decisions rules would take without publishing responses")
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	if *policyFile == "" {
+		return errors.New("--policy is required")
+	}
+	rules, err := policy.Load(*policyFile)
+	if err != nil {
+		return fmt.Errorf("Failed to load --policy: %v", err)
+	}
+	var decisions *policy.Log
+	if *logPath != "" {
+		if decisions, err = policy.OpenLog(*logPath); err != nil {
+			return fmt.Errorf("Failed to open --log: %v", err)
+		}
+		defer decisions.Close()
+	}
+
+	ob, err := outbox.Open(*outboxDir)
+	if err != nil {
+		return fmt.Errorf("Failed to open outbox: %v", err)
+	}
+
+	settings := natsconn.Load()
+	nc, err := settings.Connect("agneto-autorespond")
+	if err != nil {
+		return fmt.Errorf("%v (run the doctor command for diagnostics)", err)
+	}
+	defer nc.Close()
+	pub, err := settings.Publisher(nc)
+	if err != nil {
+		return fmt.Errorf("Failed to use the edge stream: %v", err)
+	}
+
+	r := &responder{
+		policy:  rules,
+		log:     decisions,
+		subject: *subject,
+		pub:     pub,
+		nc:      nc,
+		outbox:  ob,
+		dedup:   monitor.NewDedup(monitor.DefaultDedupWindow),
+		dryRun:  *dryRun,
+	}
+
+	// Handled one at a time, so decision log lines and responses keep the events' order
+	sub, err := nc.Subscribe(*subject, func(msg *nats.Msg) {
+		evts, err := events.DecodeAll(msg.Header.Get(events.ContentTypeHeader), msg.Data)
+		if err != nil {
+			return
+		}
+		for _, event := range evts {
+			event.AddressReplies()
+			r.handleEvent(event)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe to %s: %v", *subject, err)
+	}
+	defer sub.Unsubscribe()
+
+	// Retry responses stuck in the outbox
+	go func() {
+		for range time.Tick(5 * time.Second) {
+			if ob.Len() > 0 {
+				if _, err := ob.Flush(pub); err != nil {
+					log.Printf("outbox: %v", err)
+				}
+			}
+		}
+	}()
+
+	mode := ""
+	if *dryRun {
+		mode = " (dry run)"
+	}
+	log.Printf("Answering decisions on %s with %s%s", *subject, *policyFile, mode)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+	return nil
+}
+
+// handleEvent answers an event if a rule covers it
+func (r *responder) handleEvent(event events.Event) {
+	// A producer retry of an event already answered
+	if r.dedup.Seen(event.IdempotencyKey) {
+		return
+	}
+	decision, ok := r.policy.Decide(event)
+	if !ok {
+		return
+	}
+	if r.dryRun {
+		log.Printf("dry run: %s", decision)
+		return
+	}
+
+	log.Print(decision)
+	if err := r.log.Record(decision); err != nil {
+		log.Printf("failed to log decision on %s: %v", event.ID, err)
+	}
+
+	response := decision.Action.Response()
+	payload, err := response.ToJSON()
+	if err == nil {
+		_, err = r.outbox.Enqueue(r.subject, payload)
+	}
+	if err != nil {
+		log.Printf("failed to queue response to %s: %v", event.ID, err)
+		return
+	}
+	if _, err := r.outbox.Flush(r.pub); err != nil {
+		log.Printf("outbox: response queued, will retry: %v", err)
+	}
+	if decision.Action.ReplyTo != "" {
+		r.nc.Publish(decision.Action.ReplyTo, payload) // Inboxes don't outlive the producer, so not through the outbox
+	}
+}
@@ -0,0 +1,329 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nuid"
+)
+
+// check is the outcome of a single diagnostic
+type check struct {
+	name   string
+	ok     bool
+	skip   bool
+	detail string
+	fix    string // Actionable hint shown for failed checks
+}
+
+// Run runs the doctor command with its arguments
+func Run(args []string) error {
+	// Define flags
+	fs := cli.NewFlagSet("doctor")
+	subject := fs.String("subject", "test.events", "Subject the TUI and publisher use (checked for permissions)")
+	maxSkew := fs.Duration("max-skew", 2*time.Second, "Maximum tolerated clock skew against the server")
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	settings := natsconn.Load()
+	fmt.Printf("Agneto doctor - checking %s\n\n", settings.URL)
+
+	var checks []check
+	checks = append(checks, checkSettings(settings)...)
+
+	// Connect with a permission error handler so async violations are visible
+	permErrs := make(chan error, 8)
+	nc, err := settings.Connect("agneto-doctor",
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			select {
+			case permErrs <- err:
+			default:
+			}
+		}),
+	)
+	checks = append(checks, checkConnect(settings, nc, err))
+
+	if err == nil {
+		defer nc.Close()
+		checks = append(checks, checkPermissions(nc, *subject, permErrs)...)
+		js, jsCheck := checkJetStream(nc)
+		checks = append(checks, jsCheck)
+		checks = append(checks, checkClockSkew(nc, js, *maxSkew))
+	}
+
+	failed := report(checks)
+	if failed > 0 {
+		fmt.Printf("\n%d check(s) failed\n", failed)
+		return cli.ExitCode(1)
+	}
+	fmt.Println("\nAll checks passed")
+	return nil
+}
+
+// checkSettings validates that configured credential and TLS files are readable
+func checkSettings(s natsconn.Settings) []check {
+	var checks []check
+	files := []struct{ env, path string }{
+		{natsconn.EnvCreds, s.Creds},
+		{natsconn.EnvCA, s.CA},
+		{natsconn.EnvCert, s.Cert},
+		{natsconn.EnvKey, s.Key},
+	}
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		c := check{name: "file " + f.env, ok: true, detail: f.path}
+		if _, err := os.Stat(f.path); err != nil {
+			c.ok = false
+			c.detail = err.Error()
+			c.fix = fmt.Sprintf("point %s at an existing, readable file", f.env)
+		}
+		checks = append(checks, c)
+	}
+
+	if (s.Cert == "") != (s.Key == "") {
+		checks = append(checks, check{
+			name:   "mutual TLS",
+			detail: "only one of the client certificate and key is set",
+			fix:    fmt.Sprintf("set both %s and %s", natsconn.EnvCert, natsconn.EnvKey),
+		})
+	}
+	return checks
+}
+
+// checkConnect reports connection success, or translates the error into a diagnosis
+func checkConnect(s natsconn.Settings, nc *nats.Conn, err error) check {
+	c := check{name: "connect"}
+	if err == nil {
+		c.ok = true
+		rtt, _ := nc.RTT()
+		tls := "plain"
+		if nc.TLSRequired() || s.UsesTLS() {
+			tls = "TLS"
+		}
+		c.detail = fmt.Sprintf("%s (server %s, %s, rtt %s)", nc.ConnectedUrl(), nc.ConnectedServerVersion(), tls, rtt.Round(time.Microsecond))
+		return c
+	}
+
+	c.detail = err.Error()
+	msg := strings.ToLower(err.Error())
+	switch {
+	case errors.Is(err, nats.ErrNoServers):
+		c.fix = fmt.Sprintf("no server answered at %s - start one with `nats-server -js` or set %s", s.URL, natsconn.EnvURL)
+	case errors.Is(err, nats.ErrAuthorization), errors.Is(err, nats.ErrAuthExpired), strings.Contains(msg, "authorization"):
+		c.fix = fmt.Sprintf("the server rejected the credentials - check %s, %s or %s/%s", natsconn.EnvCreds, natsconn.EnvToken, natsconn.EnvUser, natsconn.EnvPassword)
+	case strings.Contains(msg, "tls") || strings.Contains(msg, "x509") || strings.Contains(msg, "certificate"):
+		c.fix = fmt.Sprintf("TLS handshake failed - use a tls:// URL and check %s (server CA) and %s/%s", natsconn.EnvCA, natsconn.EnvCert, natsconn.EnvKey)
+	case strings.Contains(msg, "timeout"):
+		c.fix = "the server did not answer in time - check firewalls and that the port is correct"
+	default:
+		c.fix = fmt.Sprintf("check that %s is a valid nats:// or tls:// URL", natsconn.EnvURL)
+	}
+	return c
+}
+
+// checkPermissions verifies publish/subscribe on a scratch subject, then
+// subscribe and publish on the events subject each on its own
+func checkPermissions(nc *nats.Conn, subject string, permErrs chan error) []check {
+	scratch := "agneto.doctor." + nuid.Next()
+	roundTrip := check{name: "publish/subscribe", detail: scratch}
+
+	sub, err := nc.SubscribeSync(scratch)
+	if err == nil {
+		err = nc.Publish(scratch, []byte("ping"))
+	}
+	if err == nil {
+		_, err = sub.NextMsg(2 * time.Second)
+	}
+	if sub != nil {
+		sub.Unsubscribe()
+	}
+	if err == nil {
+		err = drainPermissionError(nc, permErrs)
+	}
+	if err != nil {
+		roundTrip.detail = fmt.Sprintf("%s: %v", scratch, err)
+		roundTrip.fix = "grant this user publish and subscribe on agneto.> (and _INBOX.> for replies)"
+	} else {
+		roundTrip.ok = true
+	}
+
+	return append([]check{roundTrip}, checkSubject(nc, subject, permErrs)...)
+}
+
+// checkSubject verifies subscribe and publish on the events subject itself
+// Permission errors only surface asynchronously, so each step drains them
+// before the next; the probe carries events.ProbeHeader so monitors skip it
+func checkSubject(nc *nats.Conn, subject string, permErrs chan error) []check {
+	subscribe := check{name: "subscribe " + subject, detail: "allowed"}
+	publish := check{name: "publish " + subject, detail: "allowed"}
+
+	sub, err := nc.SubscribeSync(subject)
+	if err == nil {
+		defer sub.Unsubscribe()
+		err = drainPermissionError(nc, permErrs)
+	}
+	if err != nil {
+		subscribe.detail = err.Error()
+		subscribe.fix = fmt.Sprintf("grant this user subscribe on %s - monitors read events there", subject)
+		sub = nil
+	} else {
+		subscribe.ok = true
+	}
+
+	probe := nats.NewMsg(concreteSubject(subject))
+	if probe.Subject != subject {
+		publish.detail = "allowed on " + probe.Subject
+	}
+	probe.Header.Set(events.ProbeHeader, nuid.Next())
+	err = nc.PublishMsg(probe)
+	if err == nil {
+		err = drainPermissionError(nc, permErrs)
+	}
+	if err != nil {
+		publish.detail = err.Error()
+		publish.fix = fmt.Sprintf("grant this user publish on %s - agents send events there", subject)
+		return []check{subscribe, publish}
+	}
+	publish.ok = true
+
+	// With both allowed the probe must come back; other traffic may arrive first
+	for deadline := time.Now().Add(2 * time.Second); sub != nil; {
+		msg, err := sub.NextMsg(time.Until(deadline))
+		if err != nil {
+			subscribe.ok = false
+			subscribe.detail = fmt.Sprintf("probe not delivered: %v", err)
+			subscribe.fix = fmt.Sprintf("check that no deny rule or import hides %s from this user", subject)
+			break
+		}
+		if msg.Header.Get(events.ProbeHeader) == probe.Header.Get(events.ProbeHeader) {
+			break
+		}
+	}
+	return []check{subscribe, publish}
+}
+
+// concreteSubject fills a subject's wildcard tokens so it can be published to
+func concreteSubject(subject string) string {
+	tokens := strings.Split(subject, ".")
+	for i, token := range tokens {
+		if token == "*" || token == ">" {
+			tokens[i] = "doctor"
+		}
+	}
+	return strings.Join(tokens, ".")
+}
+
+// drainPermissionError flushes and returns any permission violation reported meanwhile
+func drainPermissionError(nc *nats.Conn, permErrs chan error) error {
+	if err := nc.FlushTimeout(2 * time.Second); err != nil {
+		return err
+	}
+	select {
+	case err := <-permErrs:
+		return err
+	default:
+		return nil
+	}
+}
+
+// checkJetStream reports whether JetStream is enabled for this account
+func checkJetStream(nc *nats.Conn) (nats.JetStreamContext, check) {
+	c := check{name: "jetstream"}
+	js, err := nc.JetStream(nats.MaxWait(2 * time.Second))
+	if err == nil {
+		var info *nats.AccountInfo
+		info, err = js.AccountInfo()
+		if err == nil {
+			c.ok = true
+			c.detail = fmt.Sprintf("available (%d streams, %d consumers)", info.Streams, info.Consumers)
+			return js, c
+		}
+	}
+
+	// JetStream is optional - history replay needs it, live monitoring doesn't
+	c.skip = true
+	c.detail = fmt.Sprintf("unavailable: %v", err)
+	c.fix = "start the server with `nats-server -js` (or enable JetStream for this account) to use history replay"
+	return nil, c
+}
+
+// checkClockSkew compares local time with the server's timestamp on a stored message
+// The skew estimate is corrected by half the round-trip time
+func checkClockSkew(nc *nats.Conn, js nats.JetStreamContext, maxSkew time.Duration) check {
+	c := check{name: "clock skew"}
+	if js == nil {
+		c.skip = true
+		c.detail = "needs JetStream for a server timestamp"
+		return c
+	}
+
+	stream := "AGNETO_DOCTOR_" + nuid.Next()
+	subject := "agneto.doctor.clock." + nuid.Next()
+	_, err := js.AddStream(&nats.StreamConfig{
+		Name:     stream,
+		Subjects: []string{subject},
+		Storage:  nats.MemoryStorage,
+	})
+	if err != nil {
+		c.skip = true
+		c.detail = fmt.Sprintf("could not create scratch stream: %v", err)
+		return c
+	}
+	defer js.DeleteStream(stream)
+
+	sent := time.Now()
+	ack, err := js.Publish(subject, []byte("clock"))
+	received := time.Now()
+	if err != nil {
+		c.skip = true
+		c.detail = fmt.Sprintf("could not publish to scratch stream: %v", err)
+		return c
+	}
+	msg, err := js.GetMsg(stream, ack.Sequence)
+	if err != nil {
+		c.skip = true
+		c.detail = fmt.Sprintf("could not read back scratch message: %v", err)
+		return c
+	}
+
+	local := sent.Add(received.Sub(sent) / 2)
+	skew := msg.Time.Sub(local)
+	c.detail = fmt.Sprintf("%s (server ahead if positive)", skew.Round(time.Millisecond))
+	if skew > maxSkew || skew < -maxSkew {
+		c.fix = "synchronise clocks (NTP) - event timestamps and timeouts will be off"
+		return c
+	}
+	c.ok = true
+	return c
+}
+
+// report prints every check and returns the number of failures
+func report(checks []check) int {
+	failed := 0
+	for _, c := range checks {
+		switch {
+		case c.skip:
+			fmt.Printf("  - %-20s %s\n", c.name, c.detail)
+		case c.ok:
+			fmt.Printf("  ✓ %-20s %s\n", c.name, c.detail)
+		default:
+			failed++
+			fmt.Printf("  ✗ %-20s %s\n", c.name, c.detail)
+		}
+		if !c.ok && c.fix != "" {
+			fmt.Printf("      → %s\n", c.fix)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/export"
+)
+
+// Run runs the export command with its arguments
+func Run(args []string) error {
+	// Define flags
+	fs := cli.NewFlagSet("export")
+	from := fs.String("from", "-", "JSON Lines file of events to read (- for stdin), e.g. a TUI export")
+	out := fs.String("out", "-", "Output file (- for stdout)")
+	format := fs.String("format", export.FormatCSV, "Output format: csv or jsonl")
+	fields := fs.String("fields", "", "Comma-separated fields, e.g. id,type,timestamp,data.cost (csv default: "+strings.Join(export.DefaultFields, ",")+")")
+	types := fs.String("type", "", "Comma-separated event type globs to include (e.g. test.*,agent.done)")
+	since := fs.String("since", "", "Only events at or after this time (RFC 3339, YYYY-MM-DD, or a duration ago like 2h)")
+	until := fs.String("until", "", "Only events before this time (same formats as --since)")
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	opts := export.Options{
+		Format: *format,
+		Fields: splitList(*fields),
+		Types:  splitList(*types),
+	}
+	var err error
+	if opts.Since, err = export.ParseTime(*since, now); err != nil {
+		return fmt.Errorf("--since: %v", err)
+	}
+	if opts.Until, err = export.ParseTime(*until, now); err != nil {
+		return fmt.Errorf("--until: %v", err)
+	}
+
+	in := io.Reader(os.Stdin)
+	if *from != "-" {
+		f, err := os.Open(*from)
+		if err != nil {
+			return fmt.Errorf("Failed to open --from: %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	output := io.Writer(os.Stdout)
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("Failed to create --out: %v", err)
+		}
+		defer f.Close()
+		output = f
+	}
+
+	w, err := export.NewWriter(output, opts)
+	if err != nil {
+		return err
+	}
+
+	// Read events line by line; payloads can be large
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		event, err := events.FromJSON([]byte(text))
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", *from, line, err)
+		}
+		if err := w.Write(*event); err != nil {
+			return fmt.Errorf("Failed to write: %v", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Failed to read --from: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("Failed to write: %v", err)
+	}
+
+	if *out != "-" {
+		fmt.Fprintf(os.Stderr, "Exported %d events to %s\n", w.Written(), *out)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,353 @@
+package forward
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/health"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/durch/agneto/v2/pkg/slack"
+	"github.com/nats-io/nats.go"
+)
+
+// maxCallbackBody bounds interaction callback bodies
+const maxCallbackBody = 1 << 20
+
+// posted is an actionable event waiting for an answer in Slack
+type posted struct {
+	event     events.Event
+	channel   string // Slack channel ID and message timestamp (for chat.update)
+	ts        string
+	approvers map[string][]string // Slack users who approved each four-eyes action, by action ID
+}
+
+// forwarder posts actionable events to Slack and publishes button clicks as responses
+type forwarder struct {
+	slack   *slack.Client
+	channel string
+	secret  string
+	subject string           // Subject responses are published on
+	types   []string         // Type globs to forward (empty: all actionable events)
+	pub     outbox.Publisher // Delivers outbox entries (nc, or the edge stream)
+	nc      *nats.Conn       // Sends replies to producers' reply subjects
+	outbox  *outbox.Outbox
+	signKey []byte // Signs approvals (nil: unsigned, see events.ApprovalSecretEnv)
+
+	mu      sync.Mutex
+	pending map[string]posted // By event ID; removed once answered (one-shot)
+}
+
+// Run runs the forward command with its arguments
+func Run(args []string) error {
+	// Define flags
+	fs := cli.NewFlagSet("forward")
+	to := fs.String("to", "", "Forwarding target (supported: slack)")
+	subject := fs.String("subject", "test.events", "Subject to forward events from (responses are published here too)")
+	channel := fs.String("slack-channel", "", "Slack channel ID or name to post to")
+	listen := fs.String("listen", ":3000", "Address of the Slack interactivity callback server")
+	callbackPath := fs.String("callback-path", "/slack/actions", "Path of the interactivity request URL")
+	types := fs.String("type", "", "Comma-separated event type globs to forward (default: every actionable event)")
+	outboxDir := fs.String("outbox", outbox.DefaultDir("forward"), "Directory of the durable outbox for responses")
+	maxLag := fs.Int("max-lag", 1000, "Events waiting to be forwarded before /readyz fails")
+	maxBacklog := fs.Int("max-backlog", 100, "Queued outbox responses before /readyz fails")
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	if *to != "slack" {
+		return fmt.Errorf("--to must be slack (got %q)", *to)
+	}
+	token, secret := os.Getenv(slack.EnvBotToken), os.Getenv(slack.EnvSigningSecret)
+	if token == "" || secret == "" {
+		return fmt.Errorf("%s and %s must be set (from the Slack app's settings)", slack.EnvBotToken, slack.EnvSigningSecret)
+	}
+	if *channel == "" {
+		return errors.New("--slack-channel is required")
+	}
+
+	ob, err := outbox.Open(*outboxDir)
+	if err != nil {
+		return fmt.Errorf("Failed to open outbox: %v", err)
+	}
+
+	settings := natsconn.Load()
+	nc, err := settings.Connect("agneto-forward")
+	if err != nil {
+		return fmt.Errorf("%v (run the doctor command for diagnostics)", err)
+	}
+	defer nc.Close()
+	pub, err := settings.Publisher(nc)
+	if err != nil {
+		return fmt.Errorf("Failed to use the edge stream: %v", err)
+	}
+
+	f := &forwarder{
+		slack:   slack.NewClient(token),
+		channel: *channel,
+		secret:  secret,
+		subject: *subject,
+		types:   splitList(*types),
+		pub:     pub,
+		nc:      nc,
+		outbox:  ob,
+		signKey: events.ApprovalSecret(),
+		pending: make(map[string]posted),
+	}
+
+	sub, err := nc.Subscribe(*subject, func(msg *nats.Msg) {
+		evts, err := events.DecodeAll(msg.Header.Get(events.ContentTypeHeader), msg.Data)
+		if err != nil {
+			return
+		}
+		for _, event := range evts {
+			event.AddressReplies()
+			f.handleEvent(event)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe to %s: %v", *subject, err)
+	}
+
+	// Retry responses stuck in the outbox
+	go func() {
+		for range time.Tick(5 * time.Second) {
+			if ob.Len() > 0 {
+				if _, err := ob.Flush(pub); err != nil {
+					log.Printf("outbox: %v", err)
+				}
+			}
+		}
+	}()
+
+	// Probes for supervisors (Kubernetes liveness/readiness)
+	probes := &health.Probes{
+		Live: []health.Check{health.NATSOpen(nc)},
+		Ready: []health.Check{
+			health.NATSConnected(nc),
+			health.ConsumerLag(sub, *maxLag),
+			health.OutboxBacklog(ob, *maxBacklog),
+		},
+	}
+	probes.Register(http.DefaultServeMux)
+
+	http.HandleFunc(*callbackPath, f.handleCallback)
+	log.Printf("Forwarding actionable events on %s to Slack %s; callbacks on %s%s (probes: %s, %s)", *subject, *channel, *listen, *callbackPath, health.LivePath, health.ReadyPath)
+	return http.ListenAndServe(*listen, nil)
+}
+
+// handleEvent posts actionable events and retires ones answered elsewhere
+func (f *forwarder) handleEvent(event events.Event) {
+	// Answered in the TUI or by an alternate approver - take the buttons away
+	if answered, ok := event.AnsweredEventID(); ok {
+		f.mu.Lock()
+		p, found := f.pending[answered]
+		delete(f.pending, answered)
+		f.mu.Unlock()
+		if found && event.Data["responded_via"] != "slack" {
+			text := fmt.Sprintf("%s - answered elsewhere: %s", p.event.Message, event.Message)
+			if err := f.slack.UpdateMessage(p.channel, p.ts, text); err != nil {
+				log.Printf("slack: %v", err)
+			}
+		}
+		return
+	}
+
+	if !slack.Actionable(event) || !f.forwards(event.Type) {
+		return
+	}
+
+	channel, ts, err := f.slack.PostMessage(f.channel, event.Message, slack.MessageBlocks(event))
+	if err != nil {
+		log.Printf("slack: failed to post event %s: %v", event.ID, err)
+		return
+	}
+
+	f.mu.Lock()
+	f.pending[event.ID] = posted{event: event, channel: channel, ts: ts, approvers: make(map[string][]string)}
+	f.mu.Unlock()
+}
+
+// forwards reports whether events of a type are forwarded
+func (f *forwarder) forwards(eventType string) bool {
+	if len(f.types) == 0 {
+		return true
+	}
+	for _, glob := range f.types {
+		if ok, _ := path.Match(glob, eventType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// handleCallback turns a verified button click into the action's response event
+func (f *forwarder) handleCallback(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackBody))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if err := slack.VerifySignature(f.secret, r.Header, body, time.Now()); err != nil {
+		log.Printf("slack: rejected callback: %v", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	interaction, err := slack.ParseInteraction(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	eventID, actionID, err := slack.ParseValue(interaction.Value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Slack expects an answer within 3 seconds; message updates happen afterwards
+	w.WriteHeader(http.StatusOK)
+
+	// One-shot: the first click wins (for four-eyes actions, the click completing their approvals)
+	f.mu.Lock()
+	p, found := f.pending[eventID]
+	if !found {
+		f.mu.Unlock()
+		go f.reply(interaction.ResponseURL, "This request was already answered.")
+		return
+	}
+	var action *events.Action
+	for i := range p.event.Actions {
+		if p.event.Actions[i].ID == actionID {
+			action = &p.event.Actions[i]
+			break
+		}
+	}
+	if action == nil {
+		f.mu.Unlock()
+		go f.reply(interaction.ResponseURL, fmt.Sprintf("Unknown action %q.", actionID))
+		return
+	}
+	approvers, added := p.approve(*action, interaction.UserName)
+	complete := len(approvers) >= action.RequiredApprovals()
+	if complete {
+		delete(f.pending, eventID)
+	}
+	f.mu.Unlock()
+
+	if !complete {
+		f.announceApproval(p, *action, approvers, interaction.UserName, added)
+		return
+	}
+	if action.RequiredApprovals() > 1 {
+		approved := action.WithApprovers(approvers)
+		action = &approved
+		defer f.publishApproval(events.Approval{EventID: eventID, Operator: interaction.UserName, Final: true, At: time.Now()})
+	}
+
+	response := action.Response()
+	data := make(map[string]interface{}, len(response.Data)+3)
+	for k, v := range response.Data {
+		data[k] = v
+	}
+	data[events.AnswersEventIDKey] = eventID
+	data["responded_via"] = "slack"
+	data["responded_by"] = interaction.UserName
+	response.Data = data
+
+	payload, err := response.ToJSON()
+	if err == nil {
+		_, err = f.outbox.Enqueue(f.subject, payload)
+	}
+	if err != nil {
+		log.Printf("failed to queue response to %s: %v", eventID, err)
+		go f.reply(interaction.ResponseURL, fmt.Sprintf("Failed to record %s: %v", action.Label, err))
+		return
+	}
+	if _, err := f.outbox.Flush(f.pub); err != nil {
+		log.Printf("outbox: response queued, will retry: %v", err)
+	}
+	if action.ReplyTo != "" {
+		f.nc.Publish(action.ReplyTo, payload) // Inboxes don't outlive the producer, so not through the outbox
+	}
+
+	go f.reply(interaction.ResponseURL, fmt.Sprintf("%s\n✓ *%s* by @%s", p.event.Message, action.Label, interaction.UserName))
+}
+
+// approve records a Slack user's click on an action; approvers is everyone
+// who approved it so far, and added is false if the user had already
+// Actions needing one approval are approved by the click alone
+func (p posted) approve(action events.Action, user string) (approvers []string, added bool) {
+	if action.RequiredApprovals() == 1 {
+		return []string{user}, true
+	}
+	approvers = p.approvers[action.ID]
+	if slices.Contains(approvers, user) {
+		return approvers, false
+	}
+	approvers = append(approvers, user)
+	p.approvers[action.ID] = approvers
+	return approvers, true
+}
+
+// announceApproval tells the channel, and monitors, that a four-eyes action
+// is waiting for another approver
+func (f *forwarder) announceApproval(p posted, action events.Action, approvers []string, user string, added bool) {
+	needed := action.RequiredApprovals()
+	text := fmt.Sprintf("@%s approved *%s* (%d/%d) - %s needs another approver.", user, action.Label, len(approvers), needed, p.event.Message)
+	if !added {
+		text = fmt.Sprintf("@%s already approved *%s* (%d/%d) - it needs a different approver.", user, action.Label, len(approvers), needed)
+	} else {
+		f.publishApproval(events.Approval{EventID: p.event.ID, ActionID: action.ApprovalID(), Operator: user, Needed: needed, At: time.Now()})
+	}
+	go func() {
+		if _, _, err := f.slack.PostMessage(p.channel, text, nil); err != nil {
+			log.Printf("slack: %v", err)
+		}
+	}()
+}
+
+// publishApproval announces an approval on events.ApprovalsSubject, through the outbox
+func (f *forwarder) publishApproval(approval events.Approval) {
+	payload, err := approval.Sign(f.signKey).ToJSON()
+	if err == nil {
+		_, err = f.outbox.Enqueue(events.ApprovalsSubject, payload)
+	}
+	if err != nil {
+		log.Printf("failed to queue approval of %s: %v", approval.EventID, err)
+		return
+	}
+	if _, err := f.outbox.Flush(f.pub); err != nil {
+		log.Printf("outbox: approval queued, will retry: %v", err)
+	}
+}
+
+// reply updates the clicked message via its response URL
+func (f *forwarder) reply(responseURL, text string) {
+	if err := f.slack.ReplaceMessage(responseURL, text); err != nil {
+		log.Printf("slack: %v", err)
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
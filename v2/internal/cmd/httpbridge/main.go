@@ -0,0 +1,239 @@
+package httpbridge
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/client"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/health"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// EnvToken is the bearer token requests must carry (unset: no authentication)
+const EnvToken = "AGNETO_HTTP_TOKEN"
+
+// maxBody bounds POST /events bodies, as NATS bounds payloads
+const maxBody = 1 << 20
+
+// publishTimeout bounds publishing one request's events, retries included
+const publishTimeout = 10 * time.Second
+
+// bridge publishes events posted over HTTP and streams the subject's events
+// to HTTP clients
+type bridge struct {
+	pub         *client.Publisher
+	hub         *hub
+	token       string
+	allowOrigin string
+}
+
+// Run runs the httpbridge command with its arguments
+func Run(args []string) error {
+	fs := cli.NewFlagSet("httpbridge")
+	listen := fs.String("listen", ":8080", "Address of the HTTP server")
+	subject := fs.String("subject", "test.events", "Subject events are published to and streamed from")
+	allowOrigin := fs.String("allow-origin", "", "Origin browsers may call the bridge from (CORS), e.g. https://ops.example.com or *")
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	settings := natsconn.Load()
+	nc, err := settings.Connect("agneto-httpbridge")
+	if err != nil {
+		return fmt.Errorf("%v (run the doctor command for diagnostics)", err)
+	}
+	defer nc.Close()
+	t := transport.NATS{Conn: nc}
+
+	b := &bridge{
+		pub:         client.NewWithTransport(t, *subject),
+		hub:         newHub(),
+		token:       os.Getenv(EnvToken),
+		allowOrigin: *allowOrigin,
+	}
+	sub, err := t.Subscribe(*subject, func(msg transport.Msg) {
+		evts, err := events.DecodeAll(msg.Header.Get(events.ContentTypeHeader), msg.Data)
+		if err != nil {
+			return
+		}
+		for _, event := range evts {
+			b.hub.broadcast(event)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe to %s: %v", *subject, err)
+	}
+	defer sub.Unsubscribe()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", b.handle(b.handlePublish))
+	mux.HandleFunc("/events/stream", b.handle(b.handleStream))
+	probes := &health.Probes{
+		Live:  []health.Check{health.NATSOpen(nc)},
+		Ready: []health.Check{health.NATSConnected(nc)},
+	}
+	probes.Register(mux)
+
+	auth := "no authentication: set " + EnvToken + " to require a bearer token"
+	if b.token != "" {
+		auth = "bearer token required"
+	}
+	log.Printf("Bridging %s over HTTP on %s: POST /events, GET /events/stream (%s)", *subject, *listen, auth)
+	server := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return server.ListenAndServe()
+}
+
+// handle wraps an endpoint with CORS headers, preflight answers and the token check
+func (b *bridge) handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if b.allowOrigin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", b.allowOrigin)
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !b.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "missing or wrong bearer token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// authorized checks the request's bearer token
+// EventSource can't set headers, so browsers may pass it as ?token= instead
+func (b *bridge) authorized(r *http.Request) bool {
+	if b.token == "" {
+		return true
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		given = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(b.token)) == 1
+}
+
+// handlePublish publishes the event in the body, or a JSON array of events
+// as one batch envelope (see events.Batch)
+// Answers 202 with the IDs given to the events
+func (b *bridge) handlePublish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeError(w, http.StatusMethodNotAllowed, "POST an event")
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body over %d bytes", maxBody))
+		return
+	}
+	evts, err := decodeBody(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), publishTimeout)
+	defer cancel()
+	result := map[string]interface{}{}
+	if len(evts) == 1 {
+		event, err := b.pub.Publish(ctx, evts[0])
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		result["id"] = event.ID
+	} else {
+		batch, err := b.pub.PublishBatch(ctx, evts)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		ids := make([]string, len(batch.Events))
+		for i, event := range batch.Events {
+			ids[i] = event.ID
+		}
+		result["batch_id"], result["ids"] = batch.ID, ids
+	}
+	writeJSON(w, http.StatusAccepted, result)
+}
+
+// decodeBody decodes and checks an event, or a non-empty array of events
+func decodeBody(body []byte) ([]events.Event, error) {
+	var evts []events.Event
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(body, &evts); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		if len(evts) == 0 {
+			return nil, errors.New("no events in the array")
+		}
+	} else {
+		event, err := events.FromJSON(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		evts = []events.Event{*event}
+	}
+	for i, event := range evts {
+		if err := checkEvent(event); err != nil {
+			if len(evts) > 1 {
+				return nil, fmt.Errorf("event %d: %w", i, err)
+			}
+			return nil, err
+		}
+	}
+	return evts, nil
+}
+
+// checkEvent rejects events monitors would misread, as the publisher's flags do
+func checkEvent(event events.Event) error {
+	if event.Type == "" {
+		return errors.New("missing type")
+	}
+	if event.Severity != "" {
+		if _, err := events.ParseSeverity(event.Severity); err != nil {
+			return err
+		}
+	}
+	if err := event.ValidateContentType(); err != nil {
+		return err
+	}
+	if err := event.ValidateStream(); err != nil {
+		return err
+	}
+	if event.TimeoutSeconds != 0 || event.DefaultActionID != "" {
+		if err := event.ValidateTimeout(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSON answers with a JSON body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with {"error": message}
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,135 @@
+package httpbridge
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// streamBuffer is how many events a stream client may fall behind by before
+// further events are dropped for it
+const streamBuffer = 256
+
+// keepAlive is how often an idle stream gets a comment line, so proxies
+// don't close it
+const keepAlive = 15 * time.Second
+
+// hub fans the subject's events out to the connected stream clients
+// AIDEV-NOTE: A slow client only loses events of its own: broadcast never
+// blocks the subscription, so publishing and the other clients carry on
+type hub struct {
+	mu      sync.Mutex
+	clients map[chan events.Event]bool
+}
+
+// newHub creates a hub without clients
+func newHub() *hub {
+	return &hub{clients: make(map[chan events.Event]bool)}
+}
+
+// add registers a client and returns the channel its events arrive on
+func (h *hub) add() chan events.Event {
+	ch := make(chan events.Event, streamBuffer)
+	h.mu.Lock()
+	h.clients[ch] = true
+	h.mu.Unlock()
+	return ch
+}
+
+// remove unregisters a client
+func (h *hub) remove(ch chan events.Event) {
+	h.mu.Lock()
+	delete(h.clients, ch)
+	h.mu.Unlock()
+}
+
+// broadcast hands an event to every client with room for it
+func (h *hub) broadcast(event events.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.clients {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// handleStream streams the subject's events as server-sent events, one JSON
+// event per message with its ID as the SSE id, until the client goes away
+// ?type=glob[,glob...] only streams matching event types
+func (b *bridge) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, "GET the stream")
+		return
+	}
+	var types []string
+	for _, glob := range strings.Split(r.URL.Query().Get("type"), ",") {
+		if glob = strings.TrimSpace(glob); glob == "" {
+			continue
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid type glob %q", glob))
+			return
+		}
+		types = append(types, glob)
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Unbuffered behind nginx
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return // Streaming unsupported (never with net/http's own writer)
+	}
+
+	ch := b.hub.add()
+	defer b.hub.remove(ch)
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case event := <-ch:
+			if !matchesType(types, event.Type) {
+				continue
+			}
+			data, jsonErr := event.ToJSON()
+			if jsonErr != nil {
+				continue
+			}
+			id := strings.NewReplacer("\r", "", "\n", "").Replace(event.ID) // A line break would end the field
+			_, err = fmt.Fprintf(w, "id: %s\ndata: %s\n\n", id, data)
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// matchesType reports whether an event type matches one of the globs (no globs: any)
+func matchesType(globs []string, eventType string) bool {
+	if len(globs) == 0 {
+		return true
+	}
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, eventType); ok {
+			return true
+		}
+	}
+	return false
+}
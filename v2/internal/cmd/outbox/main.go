@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/durch/agneto/v2/pkg/outbox"
+)
+
+// usage prints the command's usage and flags
+func usage(fs *flag.FlagSet) {
+	fmt.Fprintf(fs.Output(), `Usage: outbox [--instance name | --dir path] <command>
+
+Inspect the TUI's outbox of queued responses.
+
+Commands:
+  ls          List queued entries in publish order
+  flush       Publish queued entries now
+  drop <seq>  Discard an entry without publishing it
+
+Flags:
+`)
+	fs.PrintDefaults()
+}
+
+// Run runs the outbox command with its arguments
+func Run(args []string) error {
+	// Define flags
+	fs := cli.NewFlagSet("outbox")
+	instance := fs.String("instance", "default", "TUI instance whose outbox to use")
+	dir := fs.String("dir", "", "Outbox directory (overrides --instance)")
+	fs.Usage = func() { usage(fs) }
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return cli.ExitCode(2)
+	}
+
+	path := *dir
+	if path == "" {
+		path = outbox.DefaultDir(*instance)
+	}
+	ob, err := outbox.Open(path)
+	if err != nil {
+		return fmt.Errorf("Failed to open outbox: %v", err)
+	}
+
+	switch fs.Arg(0) {
+	case "ls":
+		entries, err := ob.List()
+		if err != nil {
+			return fmt.Errorf("Failed to list outbox: %v", err)
+		}
+		if len(entries) == 0 {
+			fmt.Printf("Outbox %s is empty\n", ob.Dir())
+			return nil
+		}
+		fmt.Printf("%-6s %-20s %-8s %-8s %s\n", "SEQ", "SUBJECT", "AGE", "ATTEMPTS", "LAST ERROR")
+		for _, e := range entries {
+			age := time.Since(e.EnqueuedAt).Round(time.Second)
+			fmt.Printf("%-6d %-20s %-8s %-8d %s\n", e.Seq, e.Subject, age, e.Attempts, e.LastError)
+		}
+
+	case "flush":
+		settings := natsconn.Load()
+		nc, err := settings.Connect("agneto-outbox")
+		if err != nil {
+			return fmt.Errorf("Failed to connect to NATS: %v", err)
+		}
+		defer nc.Close()
+		pub, err := settings.Publisher(nc)
+		if err != nil {
+			return fmt.Errorf("Failed to use the edge stream: %v", err)
+		}
+
+		sent, err := ob.Flush(pub)
+		fmt.Printf("Published %d entries, %d left\n", sent, ob.Len())
+		if err != nil {
+			return fmt.Errorf("Flush stopped: %v", err)
+		}
+
+	case "drop":
+		if fs.NArg() != 2 {
+			return errors.New("drop requires a sequence number (see ls)")
+		}
+		seq, err := outbox.ParseSeq(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		if err := ob.Drop(seq); err != nil {
+			return fmt.Errorf("Failed to drop entry %d: %v", seq, err)
+		}
+		fmt.Printf("Dropped entry %d\n", seq)
+
+	default:
+		fs.Usage()
+		return cli.ExitCode(2)
+	}
+	return nil
+}
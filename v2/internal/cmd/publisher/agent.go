@@ -0,0 +1,60 @@
+package publisher
+
+import (
+	"fmt"
+
+	"github.com/durch/agneto/v2/pkg/agent"
+	"github.com/durch/agneto/v2/pkg/client"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// sender publishes messages and looks up panes, over a connection of our own
+// or through a running agent (agneto agent)
+type sender interface {
+	publish(msgs []transport.Msg) error // Returns once the server took every message
+	discoverPanes() ([]string, error)
+}
+
+// transportSender uses the publisher's own connection
+type transportSender struct{ t transport.Transport }
+
+func (s transportSender) publish(msgs []transport.Msg) error {
+	for _, msg := range msgs {
+		if err := s.t.Publish(msg); err != nil {
+			return fmt.Errorf("%s: %w", msg.Subject, err)
+		}
+	}
+	return transport.Flush(s.t, broadcastFlushTimeout)
+}
+
+func (s transportSender) discoverPanes() ([]string, error) {
+	return client.DiscoverPanes(s.t, client.DefaultDiscoveryTimeout)
+}
+
+// agentSender goes through the agent's connection, saving the connect and TLS handshake
+type agentSender struct{ agent *agent.Client }
+
+func (s agentSender) publish(msgs []transport.Msg) error {
+	return s.agent.Publish(msgs...)
+}
+
+func (s agentSender) discoverPanes() ([]string, error) {
+	return s.agent.DiscoverPanes(client.DefaultDiscoveryTimeout)
+}
+
+// dialAgent returns the agent running for the NATS server at url, or nil when none is
+// No agent listening is the usual case and goes unmentioned
+func dialAgent(url string) *agent.Client {
+	path := agent.DefaultSocket()
+	c, err := agent.Dial(path, url)
+	if err != nil {
+		return nil
+	}
+	if err := c.Ping(); err != nil {
+		fmt.Printf("Not using the agent at %s: %v\n", path, err)
+		c.Close()
+		return nil
+	}
+	fmt.Printf("Publishing through the agent at %s\n", path)
+	return c
+}
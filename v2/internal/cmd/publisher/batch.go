@@ -0,0 +1,85 @@
+package publisher
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/durch/agneto/v2/pkg/client"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// readBatchFile reads the events of a --batch-file (JSON Lines, "-" for stdin)
+// Events without a pane, source, session or severity get the ones given on
+// the command line, and missing IDs and timestamps are filled in
+func readBatchFile(path string, defaults events.Event) ([]events.Event, error) {
+	var r io.Reader = os.Stdin
+	if path != stdinName {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	evts, err := events.ReadJSONL(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(evts) == 0 {
+		return nil, fmt.Errorf("no events in %s", path)
+	}
+	for i := range evts {
+		event := &evts[i]
+		if event.Type == "" {
+			return nil, fmt.Errorf("event %d: missing type", i+1)
+		}
+		if event.Pane == "" {
+			event.Pane = defaults.Pane
+		}
+		if event.Source == "" {
+			event.Source = defaults.Source
+		}
+		if event.SessionID == "" {
+			event.SessionID = defaults.SessionID
+		}
+		if event.Severity == "" {
+			event.Severity = defaults.Severity
+		}
+		client.Prepare(event)
+	}
+	return evts, nil
+}
+
+// publishBatches publishes events in envelopes of up to size events to
+// every subject, an envelope at a time
+// Each envelope is retried as a whole (see publishAll); its events keep
+// their idempotency keys, so monitors drop the ones they already have
+func publishBatches(s sender, subjects []string, evts []events.Event, size int) error {
+	if len(subjects) == 0 {
+		return fmt.Errorf("--broadcast needs at least one subject")
+	}
+	if size < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+	for start := 0; start < len(evts); start += size {
+		batch := events.NewBatch(evts[start:min(start+size, len(evts))])
+		data, err := batch.ToJSON()
+		if err != nil {
+			return err
+		}
+		payloads := make(map[string]transport.Msg, len(subjects))
+		for _, subject := range subjects {
+			msg := transport.NewMsg(subject, data)
+			msg.Header.Set(client.MsgIDHeader, batch.ID)
+			payloads[subject] = msg
+		}
+		if err := publishAll(s, subjects, payloads); err != nil {
+			return fmt.Errorf("batch %s (events %d-%d): %w", batch.ID, start+1, start+len(batch.Events), err)
+		}
+		fmt.Printf("Published batch %s (%d events) to %s\n", batch.ID, len(batch.Events), strings.Join(subjects, ", "))
+	}
+	return nil
+}
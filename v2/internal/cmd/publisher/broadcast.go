@@ -0,0 +1,136 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/client"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// broadcastFlushTimeout bounds how long a broadcast waits for the server to take every copy
+const broadcastFlushTimeout = 5 * time.Second
+
+// subjectOverride changes fields of the copy published to one subject
+type subjectOverride struct {
+	Pane string `json:"pane,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+// broadcastConfig holds per-subject overrides for --broadcast
+type broadcastConfig struct {
+	Subjects map[string]subjectOverride `json:"subjects"`
+}
+
+// loadBroadcastConfig reads per-subject overrides from a JSON file
+func loadBroadcastConfig(path string) (*broadcastConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg broadcastConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: invalid JSON: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// broadcastPayloads serializes the event once per subject, applying overrides
+// Every copy keeps the event ID and idempotency key, so monitors see the same logical event
+// contentType picks the encoding (see events.Encode)
+func broadcastPayloads(event events.Event, subjects []string, cfg *broadcastConfig, contentType string) (map[string]transport.Msg, error) {
+	payloads := make(map[string]transport.Msg, len(subjects))
+	for _, subject := range subjects {
+		copy := event
+		if cfg != nil {
+			if o, ok := cfg.Subjects[subject]; ok {
+				if o.Pane != "" {
+					copy.Pane = o.Pane
+				}
+				if o.Type != "" {
+					copy.Type = o.Type
+				}
+			}
+		}
+		msg, err := client.EncodeMsg(subject, copy, contentType)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", subject, err)
+		}
+		payloads[subject] = msg
+	}
+	return payloads, nil
+}
+
+// publishAll publishes every payload and flushes once, retrying the whole set on failure
+// AIDEV-NOTE: All payloads are prepared before the first publish and go out
+// in a single flush, so a bad override or serialization error sends nothing.
+// Core NATS can't make a multi-subject publish transactional; retrying the
+// whole set is safe because copies the server already took carry the same
+// idempotency key and are dropped as duplicates
+func publishAll(s sender, subjects []string, payloads map[string]transport.Msg) error {
+	backoff := client.DefaultBackoff
+	var err error
+	for attempt := 0; attempt <= client.DefaultRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		msgs := make([]transport.Msg, 0, len(subjects))
+		for _, subject := range subjects {
+			msgs = append(msgs, payloads[subject])
+		}
+		if err = s.publish(msgs); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// splitSubjects splits a comma-separated subject list, dropping empty and duplicate entries
+func splitSubjects(s string) []string {
+	var subjects []string
+	seen := make(map[string]bool)
+	for _, subject := range strings.Split(s, ",") {
+		subject = strings.TrimSpace(subject)
+		if subject == "" || seen[subject] {
+			continue
+		}
+		seen[subject] = true
+		subjects = append(subjects, subject)
+	}
+	return subjects
+}
+
+// checkPanes validates the event's pane and any per-subject pane overrides
+// Names other than the standard and --panes ones are looked up on running
+// monitors, so typos fail here instead of landing in the default pane
+func checkPanes(s sender, pane string, cfg *broadcastConfig, extra []string) error {
+	panes := []string{pane}
+	if cfg != nil {
+		for _, o := range cfg.Subjects {
+			panes = append(panes, o.Pane)
+		}
+	}
+
+	known := append(append([]string(nil), events.StandardPanes...), extra...)
+	discovered := false
+	for _, p := range panes {
+		if events.ValidatePane(p, known) == nil {
+			continue
+		}
+		if !discovered {
+			discovered = true
+			if names, err := s.discoverPanes(); err == nil {
+				known = append(known, names...)
+			}
+		}
+		if err := events.ValidatePane(p, known); err != nil {
+			return err
+		}
+	}
+	return nil
+}
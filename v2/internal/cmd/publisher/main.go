@@ -0,0 +1,436 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/client"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/durch/agneto/v2/pkg/transport"
+	"github.com/google/uuid"
+)
+
+// Run runs the publisher command with its arguments
+func Run(args []string) error {
+	// Define flags
+	fs := cli.NewFlagSet("publisher")
+	paneFlag := fs.String("pane", events.PaneLeft, "Target pane: left or right (or a pane running monitors report)")
+	panesFlag := fs.String("panes", "", "Comma-separated extra pane names to accept without asking running monitors")
+	noPaneCheck := fs.Bool("no-pane-check", false, "Publish to any pane name without validating it")
+	typeFlag := fs.String("type", "test.message", "Event type")
+	session := fs.String("session", "", "Session the event belongs to: tags it and publishes to agneto.<session>.events unless --broadcast is given")
+	source := fs.String("source", "", "Producer name monitors tell interleaved producers apart by (e.g. the agent's name)")
+	severity := fs.String("severity", "", "Event severity: debug, info, warn, error or critical (default info)")
+	contentFlag := fs.String("content", "", "Event Content (markdown shown in the payload pane); - reads it from stdin")
+	contentFormat := fs.String("content-type", "", "How the content is rendered: markdown (default) or diff (colorized unified diff)")
+	dataJSON := fs.String("data-json", "", "Inline JSON object for event data/payload")
+	actionsJSON := fs.String("actions-json", "", "Inline JSON array of actions")
+	actionsFile := fs.String("actions-file", "", "Path to JSON file containing actions")
+	questionJSON := fs.String("question-json", "", "Inline JSON question with typed answer schema")
+	broadcast := fs.String("broadcast", "test.events", "Comma-separated subjects to publish the event to")
+	broadcastFile := fs.String("broadcast-config", "", "JSON file with per-subject pane/type overrides for --broadcast")
+	idempotencyKey := fs.String("idempotency-key", "", "Key identifying the logical event across retries (default: the event ID)")
+	correlationID := fs.String("correlation-id", "", "Correlation ID of the exchange the event belongs to (responses carry it; default: the event ID)")
+	causationID := fs.String("causation-id", "", "ID of the event that caused this one")
+	timeout := fs.Int("timeout", 0, "Seconds to wait for an answer before monitors take --default-action")
+	defaultAction := fs.String("default-action", "", "ID of the button action taken when --timeout runs out")
+	streamID := fs.String("stream-id", "", "Stream the event belongs to: monitors grow one entry from the events of a stream")
+	delta := fs.Bool("delta", false, "Append the content to the stream's (with --stream-id) instead of replacing it")
+	final := fs.Bool("final", false, "Finish the stream (with --stream-id)")
+	usageJSON := fs.String("usage-json", "", "Inline JSON of what producing the event cost: input_tokens, output_tokens, cost_usd, duration_ms, model")
+	encoding := fs.String("encoding", "json", "Payload encoding: json or proto (protobuf, faster for large content; see pkg/events/event.proto)")
+	noAgent := fs.Bool("no-agent", false, "Connect to NATS even when an agent (agneto agent) is running")
+	batchFile := fs.String("batch-file", "", "JSON Lines file of events to publish in batch envelopes instead of one event; - reads stdin")
+	batchSize := fs.Int("batch-size", events.MaxBatch, "Events per envelope with --batch-file")
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	// Get message from remaining args
+	if fs.NArg() < 1 && *contentFlag == "" && *batchFile == "" && !stdinPiped() {
+		fmt.Println("Usage: publisher [options] <message>")
+		fmt.Println("       ... | publisher [options] [-]          (message from stdin)")
+		fmt.Println("       ... | publisher [options] --content - [message]")
+		fmt.Println("\nOptions:")
+		fmt.Println("  --pane <left|right>        Target pane (default: left)")
+		fmt.Println("  --panes <names>            Extra pane names to accept (comma-separated)")
+		fmt.Println("  --no-pane-check            Don't validate pane names")
+		fmt.Println("  --type <event-type>        Event type (default: test.message)")
+		fmt.Println("  --session <id>             Session: publishes to agneto.<id>.events (see the TUI's --sessions)")
+		fmt.Println("  --source <name>            Producer name, color-coded in the TUI's lane view")
+		fmt.Println("  --severity <level>         debug, info, warn, error or critical (default: info)")
+		fmt.Println("  --content <text|->         Event content (markdown); - reads it from stdin")
+		fmt.Println("                             (the message defaults to its first line)")
+		fmt.Println("  --content-type <type>      markdown (default) or diff: a colorized unified diff")
+		fmt.Println("  --data-json <json>         Event data payload as JSON object")
+		fmt.Println("  --actions-json <json>      Actions as inline JSON array")
+		fmt.Println("  --actions-file <path>      Actions from JSON file")
+		fmt.Println("  --question-json <json>     Typed question (prompt, kind, options, default)")
+		fmt.Println("  --broadcast <subjects>     Comma-separated subjects (default: test.events)")
+		fmt.Println("  --broadcast-config <path>  Per-subject pane/type overrides for --broadcast")
+		fmt.Println("  --idempotency-key <key>    Key shared by retries of the same logical event")
+		fmt.Println("  --correlation-id <id>      Exchange the event belongs to (default: its own ID)")
+		fmt.Println("  --causation-id <id>        Event that caused this one")
+		fmt.Println("  --timeout <seconds>        Take --default-action if nobody answers in time")
+		fmt.Println("  --default-action <id>      Button action taken when --timeout runs out")
+		fmt.Println("  --usage-json <json>        Tokens, cost and duration of producing the event")
+		fmt.Println("  --stream-id <id>           Stream the event belongs to: one growing entry in monitors")
+		fmt.Println("  --delta                    Append the content to the stream's instead of replacing it")
+		fmt.Println("  --final                    Finish the stream")
+		fmt.Println("  --encoding <json|proto>    Payload encoding (default: json)")
+		fmt.Println("  --no-agent                 Connect to NATS even when an agent is running")
+		fmt.Println("  --batch-file <path|->      Publish a JSON Lines file of events in batch envelopes")
+		fmt.Println("  --batch-size <n>           Events per envelope (default: 100)")
+		fmt.Println("\nExamples:")
+		fmt.Println("  publisher \"hello\"")
+		fmt.Println("  publisher --pane right --severity error \"error message\"")
+		fmt.Println("  publisher --type \"custom.event\" \"Custom event\"")
+		fmt.Println("  cat plan.md | publisher --type plan.ready --content -")
+		fmt.Println("  git diff | publisher --type review.patch --content-type diff --content - \"Patch for review\"")
+		fmt.Println("  publisher --data-json '{\"count\":42,\"status\":\"ok\"}' \"With payload\"")
+		fmt.Println("  publisher --actions-file examples/approve-reject.json \"Plan ready\"")
+		fmt.Println("  publisher --question-json '{\"prompt\":\"Risk?\",\"kind\":\"enum\",\"options\":[\"low\",\"high\"]}' \"Assess\"")
+		fmt.Println("  publisher --stream-id run-7 --delta --content ' more output' \"Drafting\"")
+		fmt.Println("  publisher --source planner --usage-json '{\"input_tokens\":1200,\"output_tokens\":350,\"cost_usd\":0.0041}' \"Plan drafted\"")
+		fmt.Println("  publisher --broadcast staging.events,prod.events --broadcast-config mirror.json \"Deploying\"")
+		fmt.Println("  publisher --batch-file events.jsonl --source indexer")
+		return cli.ExitCode(1)
+	}
+	var message, content string
+	var err error
+	if *batchFile == "" {
+		if message, content, err = readText(fs.Arg(0), fs.NArg() > 0, *contentFlag); err != nil {
+			return err
+		}
+	}
+	contentType := events.ContentTypeJSON
+	switch *encoding {
+	case "json":
+	case "proto":
+		contentType = events.ContentTypeProto
+	default:
+		return fmt.Errorf("--encoding: want json or proto, got %q", *encoding)
+	}
+	if *session != "" {
+		if err := events.ValidateSession(*session); err != nil {
+			return fmt.Errorf("--session: %v", err)
+		}
+		if !cli.Given(fs, "broadcast") {
+			*broadcast = events.SessionSubject(*session)
+		}
+	}
+	if *severity != "" {
+		var err error
+		if *severity, err = events.ParseSeverity(*severity); err != nil {
+			return fmt.Errorf("--severity: %v", err)
+		}
+	}
+
+	// A batch file's events go out in envelopes, without waiting for responses
+	if *batchFile != "" {
+		if contentType == events.ContentTypeProto {
+			return errors.New("--batch-file: batches are published as JSON, drop --encoding proto")
+		}
+		defaults := events.Event{Pane: *paneFlag, Source: *source, SessionID: *session, Severity: *severity}
+		evts, err := readBatchFile(*batchFile, defaults)
+		if err != nil {
+			return fmt.Errorf("--batch-file: %v", err)
+		}
+		s, _, closeSender, err := openSender(natsconn.Load(), !*noAgent)
+		if err != nil {
+			return err
+		}
+		defer closeSender()
+		subjects := splitSubjects(*broadcast)
+		if err := publishBatches(s, subjects, evts, *batchSize); err != nil {
+			return fmt.Errorf("Batch incomplete: %v", err)
+		}
+		if *session != "" {
+			announceSession(s, evts[len(evts)-1])
+		}
+		return nil
+	}
+
+	// Create event
+	event := events.Event{
+		ID:             uuid.New().String(),
+		Type:           *typeFlag,
+		Timestamp:      time.Now(),
+		Message:        message,
+		Content:        content,
+		ContentType:    *contentFormat,
+		Pane:           *paneFlag,
+		Source:         *source,
+		SessionID:      *session,
+		Severity:       *severity,
+		IdempotencyKey: *idempotencyKey,
+		CorrelationID:  *correlationID,
+		CausationID:    *causationID,
+	}
+	client.Prepare(&event) // Key defaults to the ID
+	if err := event.ValidateContentType(); err != nil {
+		return fmt.Errorf("--content-type: %v", err)
+	}
+
+	// Parse data JSON if provided
+	if *dataJSON != "" {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(*dataJSON), &data); err != nil {
+			return fmt.Errorf("Failed to parse --data-json: %v", err)
+		}
+		event.Data = data
+		fmt.Printf("Loaded data payload with %d fields\n", len(data))
+	}
+
+	// Parse actions from JSON if provided
+	var actions []events.Action
+
+	if *actionsJSON != "" && *actionsFile != "" {
+		return errors.New("Cannot specify both --actions-json and --actions-file")
+	}
+
+	if *actionsJSON != "" {
+		var err error
+		actions, err = parseActionsFromJSON([]byte(*actionsJSON))
+		if err != nil {
+			return fmt.Errorf("Failed to parse --actions-json: %v", err)
+		}
+		fmt.Printf("Loaded %d actions from inline JSON\n", len(actions))
+	} else if *actionsFile != "" {
+		data, err := os.ReadFile(*actionsFile)
+		if err != nil {
+			return fmt.Errorf("Failed to read --actions-file: %v", err)
+		}
+		var parseErr error
+		actions, parseErr = parseActionsFromJSON(data)
+		if parseErr != nil {
+			return fmt.Errorf("Failed to parse actions from file: %v", parseErr)
+		}
+		fmt.Printf("Loaded %d actions from %s\n", len(actions), *actionsFile)
+	}
+
+	// Parse question if provided - its answer actions are synthesized from the schema
+	if *questionJSON != "" {
+		if len(actions) > 0 {
+			return errors.New("Cannot combine --question-json with explicit actions")
+		}
+		var question events.Question
+		if err := json.Unmarshal([]byte(*questionJSON), &question); err != nil {
+			return fmt.Errorf("Failed to parse --question-json: %v", err)
+		}
+		if err := question.Validate(); err != nil {
+			return fmt.Errorf("Invalid --question-json: %v", err)
+		}
+		event.Type = events.TypeQuestion
+		event.Question = &question
+		actions = question.Actions(event.ID)
+		fmt.Printf("Loaded %s question: %s\n", question.Kind, question.Prompt)
+	}
+
+	// Monitors take the default action of events nobody answers in time
+	if *timeout != 0 || *defaultAction != "" {
+		if len(actions) == 0 {
+			return errors.New("--timeout and --default-action need actions")
+		}
+		event.Actions = actions
+		event.TimeoutSeconds = *timeout
+		event.DefaultActionID = *defaultAction
+		if err := event.ValidateTimeout(); err != nil {
+			return fmt.Errorf("--timeout/--default-action: %v", err)
+		}
+	}
+
+	// Monitors add usage up per session and producer
+	if *usageJSON != "" {
+		var usage events.Usage
+		if err := json.Unmarshal([]byte(*usageJSON), &usage); err != nil {
+			return fmt.Errorf("Failed to parse --usage-json: %v", err)
+		}
+		if err := usage.Validate(); err != nil {
+			return fmt.Errorf("Invalid --usage-json: %v", err)
+		}
+		event.Usage = &usage
+	}
+
+	// Monitors grow one entry from a stream's events
+	event.StreamID, event.Delta, event.Final = *streamID, *delta, *final
+	if err := event.ValidateStream(); err != nil {
+		return fmt.Errorf("--stream-id: %v", err)
+	}
+	if event.IsStreamed() && len(actions) > 0 {
+		return errors.New("--stream-id can't be combined with actions")
+	}
+	if event.Delta && fs.NArg() == 0 {
+		event.Message = "" // Keep the stream's message rather than one made up from this chunk
+	}
+
+	// A running agent publishes for us, unless we wait for a response: that
+	// needs a subscription of our own, so the connection is ours too
+	s, t, closeSender, err := openSender(natsconn.Load(), len(actions) == 0 && !*noAgent)
+	if err != nil {
+		return err
+	}
+	defer closeSender()
+
+	if len(actions) > 0 {
+		event.Actions = actions
+		event.ReplyTo = transport.NewInbox() // Monitors answer here as well as on the subjects
+		// Display what actions were added
+		for _, action := range actions {
+			if action.IsInput() {
+				fmt.Printf("  [INPUT] %s (%s) → event type: %s\n", action.Label, action.InputHint(), action.Event.Type)
+			} else {
+				fmt.Printf("  [%s] %s → event type: %s\n", action.Key, action.Label, action.Event.Type)
+			}
+		}
+	}
+
+	// Serialize a copy per subject (with overrides), then publish them together
+	subjects := splitSubjects(*broadcast)
+	if len(subjects) == 0 {
+		return errors.New("--broadcast needs at least one subject")
+	}
+	var overrides *broadcastConfig
+	if *broadcastFile != "" {
+		if overrides, err = loadBroadcastConfig(*broadcastFile); err != nil {
+			return fmt.Errorf("Failed to load --broadcast-config: %v", err)
+		}
+	}
+	if !*noPaneCheck {
+		if err := checkPanes(s, event.Pane, overrides, splitSubjects(*panesFlag)); err != nil {
+			return fmt.Errorf("%v (--no-pane-check skips this)", err)
+		}
+	}
+	payloads, err := broadcastPayloads(event, subjects, overrides, contentType)
+	if err != nil {
+		return err
+	}
+
+	// Listen for the response before publishing, so a quick answer isn't missed
+	var awaiter *client.Awaiter
+	if len(actions) > 0 {
+		if awaiter, err = client.Await(t, event, subjects...); err != nil {
+			return fmt.Errorf("Failed to subscribe for the response: %v", err)
+		}
+		defer awaiter.Close()
+	}
+
+	if err := publishAll(s, subjects, payloads); err != nil {
+		return fmt.Errorf("Broadcast incomplete, retry it: %v", err)
+	}
+
+	fmt.Printf("Published event to %s (pane: %s): %s\n", strings.Join(subjects, ", "), *paneFlag, message)
+	if *session != "" {
+		announceSession(s, event)
+	}
+
+	// If actions were included, wait for response
+	if awaiter != nil {
+		fmt.Println("\nWaiting for user response (timeout: 30s)...")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		response, err := awaiter.Wait(ctx)
+		if err != nil {
+			fmt.Println("\n⏱ Timeout - no response received")
+			return nil
+		}
+		printResponse(response)
+	}
+	return nil
+}
+
+// parseActionsFromJSON parses a JSON array of actions
+func parseActionsFromJSON(data []byte) ([]events.Action, error) {
+	var actions []events.Action
+	if err := json.Unmarshal(data, &actions); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	// Validate each action
+	for i, action := range actions {
+		if action.ID == "" {
+			return nil, fmt.Errorf("action[%d]: missing 'id' field", i)
+		}
+		if action.Label == "" {
+			return nil, fmt.Errorf("action[%d]: missing 'label' field", i)
+		}
+		// Key is only required for non-input actions
+		if action.Key == "" && action.InputType == "" {
+			return nil, fmt.Errorf("action[%d]: missing 'key' field (required unless input_type is set)", i)
+		}
+		if action.Event.Type == "" {
+			return nil, fmt.Errorf("action[%d]: missing 'event.type' field", i)
+		}
+		if err := action.ValidateLength(); err != nil {
+			return nil, fmt.Errorf("action[%d]: %w", i, err)
+		}
+		if err := action.ValidateInput(); err != nil {
+			return nil, fmt.Errorf("action[%d]: %w", i, err)
+		}
+		if err := action.ValidateApprovals(); err != nil {
+			return nil, fmt.Errorf("action[%d]: %w", i, err)
+		}
+		if !events.ValidActionStyle(action.Style) {
+			return nil, fmt.Errorf("action[%d]: unknown 'style' %q (want primary, danger or neutral)", i, action.Style)
+		}
+	}
+
+	return actions, nil
+}
+
+// printResponse prints the response to the published event
+func printResponse(response client.Response) {
+	fmt.Printf("\n✓ Received response!\n")
+	fmt.Printf("  Type: %s\n", response.Type)
+	if response.Action != nil {
+		fmt.Printf("  Action: %s\n", response.Action.Label)
+	}
+	fmt.Printf("  Time: %s\n", response.Timestamp.Format("15:04:05"))
+	fmt.Printf("  Message: %s\n", response.Message)
+	fmt.Printf("  Pane: %s\n", response.Pane)
+	if len(response.Data) > 0 {
+		fmt.Printf("  Data:\n")
+		for key, value := range response.Data {
+			fmt.Printf("    %s: %v\n", key, value)
+		}
+	}
+}
+
+// openSender publishes through a running agent when useAgent allows and one
+// is running, else connects to NATS (URL, credentials and TLS from environment)
+// The transport is nil when the agent publishes; close releases either
+func openSender(settings natsconn.Settings, useAgent bool) (sender, transport.Transport, func(), error) {
+	if useAgent {
+		if c := dialAgent(settings.URL); c != nil {
+			return agentSender{agent: c}, nil, func() { c.Close() }, nil
+		}
+	}
+	nc, err := settings.Connect("agneto-publisher")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%v (run the doctor command for diagnostics)", err)
+	}
+	fmt.Printf("Connected to NATS at %s\n", settings.URL)
+	t := transport.NATS{Conn: nc}
+	return transportSender{t: t}, t, nc.Close, nil
+}
+
+// announceSession lets monitors picking a session know this one is active
+// Best effort: the event is published either way
+func announceSession(s sender, event events.Event) {
+	data, err := events.NewSessionAnnouncement(event).ToJSON()
+	if err == nil {
+		err = s.publish([]transport.Msg{{Subject: events.SessionsSubject, Data: data}})
+	}
+	if err != nil {
+		log.Printf("Failed to announce session %s: %v", event.SessionID, err)
+	}
+}
@@ -0,0 +1,82 @@
+package publisher
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// stdinName is the argument that stands for standard input
+const stdinName = "-"
+
+// maxSummary caps the message taken from the first line of Content
+const maxSummary = 120
+
+// stdinPiped reports whether standard input is a pipe or file rather than a terminal
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// readStdin reads standard input to the end
+func readStdin() (string, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("reading stdin: %w", err)
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("stdin isn't UTF-8 text")
+	}
+	return string(data), nil
+}
+
+// readText resolves the message and Content, either of which may be "-" to
+// read stdin; with no message argument, piped input is the message, and
+// Content's first line stands in for a message that is still missing
+func readText(message string, hasMessage bool, content string) (string, string, error) {
+	if message == stdinName && content == stdinName {
+		return "", "", fmt.Errorf("only one of the message and --content can be read from stdin")
+	}
+	if content == stdinName {
+		text, err := readStdin()
+		if err != nil {
+			return "", "", fmt.Errorf("--content: %w", err)
+		}
+		if strings.TrimSpace(text) == "" {
+			return "", "", fmt.Errorf("--content -: stdin is empty")
+		}
+		content = text
+	}
+	if message == stdinName || !hasMessage && content == "" && stdinPiped() {
+		text, err := readStdin()
+		if err != nil {
+			return "", "", fmt.Errorf("message: %w", err)
+		}
+		message = strings.TrimSpace(text)
+	}
+	if message == "" && content != "" {
+		message = summary(content)
+	}
+	if message == "" {
+		return "", "", fmt.Errorf("the message is empty")
+	}
+	return message, content, nil
+}
+
+// summary returns the first non-blank line of markdown without its heading
+// or list marker, shortened to maxSummary characters
+func summary(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#>*- "))
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > maxSummary {
+			line = string(runes[:maxSummary-1]) + "…"
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package recorder
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/durch/agneto/v2/pkg/recording"
+)
+
+// errCheckInterval is how often write failures are logged
+const errCheckInterval = 10 * time.Second
+
+// drainTimeout bounds how long the events on the bus get to be written on exit
+const drainTimeout = 5 * time.Second
+
+// Run runs the recorder command with its arguments
+// AIDEV-NOTE: Recordings are SQLite databases (see pkg/recording) through
+// modernc.org/sqlite, a pure-Go driver, so the binaries still build without
+// cgo. The TUI's --archive keeps writing JSON Lines segments; --from-file
+// replays either
+func Run(args []string) error {
+	// Define flags
+	fs := cli.NewFlagSet("recorder")
+	subject := fs.String("subject", "test.events", "Subject to record events from (wildcards work, e.g. agneto.session.>)")
+	dbPath := fs.String("db", recording.DefaultPath(), "SQLite database to record into (created if missing, appended to otherwise)")
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	sink, err := recording.Create(*dbPath)
+	if err != nil {
+		return fmt.Errorf("Failed to open --db: %v", err)
+	}
+	defer sink.Close() // Closing again after the explicit Close below does nothing
+
+	settings := natsconn.Load()
+	nc, err := settings.Connect("agneto-recorder")
+	if err != nil {
+		return fmt.Errorf("%v (run the doctor command for diagnostics)", err)
+	}
+	defer nc.Close()
+
+	// The bus decodes protobuf and unpacks batch envelopes, so every event is
+	// one row with its JSON
+	bus := monitor.New(monitor.DefaultBuffer)
+	bus.AddSink(sink)
+	bus.Start()
+	stopSource, err := monitor.NATSSource{Conn: nc, Subject: *subject}.Start(bus)
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe to %s: %v", *subject, err)
+	}
+
+	log.Printf("Recording events on %s to %s (connected to %s)", *subject, *dbPath, settings.URL)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(errCheckInterval)
+	defer ticker.Stop()
+	for running := true; running; {
+		select {
+		case <-stop:
+			running = false
+		case <-ticker.C:
+			if err := sink.Err(); err != nil {
+				log.Printf("Recording: %v", err)
+			}
+		}
+	}
+
+	// Events already on the bus are written before the database is closed
+	stopSource()
+	for deadline := time.Now().Add(drainTimeout); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if drained(bus.Stats()) {
+			break
+		}
+	}
+	bus.Close()
+	if err := sink.Err(); err != nil {
+		log.Printf("Recording: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		return fmt.Errorf("Failed to close the recording: %v", err)
+	}
+	return nil
+}
+
+// drained reports whether the bus delivered every message it received
+func drained(stats monitor.Stats) bool {
+	var received uint64
+	for _, n := range stats.Received {
+		received += n
+	}
+	return stats.Delivered >= received
+}
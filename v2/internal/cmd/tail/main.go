@@ -0,0 +1,174 @@
+package tail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"syscall"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/nats-io/nats.go"
+)
+
+// filter keeps events whose field matches one of its globs
+type filter struct {
+	field string   // Event field path, as in Event.Field
+	globs []string // Matched against the field's value as text
+}
+
+// filters is the repeatable --filter flag
+type filters []filter
+
+func (f *filters) String() string {
+	parts := make([]string, 0, len(*f))
+	for _, fl := range *f {
+		parts = append(parts, fl.field+"="+strings.Join(fl.globs, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Set parses "field=glob[,glob...]"
+func (f *filters) Set(value string) error {
+	field, list, ok := strings.Cut(value, "=")
+	field = strings.TrimSpace(field)
+	if !ok || field == "" {
+		return fmt.Errorf("want field=glob, e.g. type=review.*")
+	}
+	fl := filter{field: field}
+	for _, glob := range strings.Split(list, ",") {
+		if glob = strings.TrimSpace(glob); glob == "" {
+			continue
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+		fl.globs = append(fl.globs, glob)
+	}
+	if len(fl.globs) == 0 {
+		return fmt.Errorf("filter on %s has no globs", field)
+	}
+	*f = append(*f, fl)
+	return nil
+}
+
+// matches reports whether an event passes every filter
+func (f filters) matches(event events.Event) bool {
+	for _, fl := range f {
+		value, ok := event.Field(fl.field)
+		if !ok {
+			return false
+		}
+		text := fmt.Sprint(value)
+		matched := false
+		for _, glob := range fl.globs {
+			if ok, _ := path.Match(glob, text); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Run runs the tail command with its arguments
+func Run(args []string) error {
+	// Define flags
+	fs := cli.NewFlagSet("tail")
+	var only filters
+	subject := fs.String("subject", "test.events", "Subject to read events from (wildcards work, e.g. agneto.session.>)")
+	fs.Var(&only, "filter", "Only print events whose field matches a glob: field=glob[,glob...], e.g. type=review.* or data.status=failed (repeatable; all must match)")
+	count := fs.Int("count", 0, "Exit after printing this many events (0: run until interrupted)")
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	settings := natsconn.Load()
+	nc, err := settings.Connect("agneto-tail")
+	if err != nil {
+		return fmt.Errorf("%v (run the doctor command for diagnostics)", err)
+	}
+	defer nc.Close()
+
+	// Messages are handled one at a time, so lines keep the order events arrived in
+	out := bufio.NewWriter(os.Stdout)
+	done := make(chan struct{})
+	printed := 0
+	var failed error // Ends the command, like reaching --count
+	printEvent := func(subject, contentType string, data []byte) {
+		if failed != nil || *count > 0 && printed >= *count {
+			return
+		}
+		event, err := events.Decode(contentType, data)
+		if err != nil {
+			log.Printf("skipping a message on %s that isn't an event: %v", subject, err)
+			return
+		}
+		if !only.matches(*event) {
+			return
+		}
+		// The producer's JSON as sent, fields this version doesn't know included, on one line
+		// Protobuf events are printed as the JSON they decode to
+		if events.IsProto(contentType) {
+			if data, err = event.ToJSON(); err != nil {
+				return
+			}
+		}
+		var line bytes.Buffer
+		if err := json.Compact(&line, data); err != nil {
+			return
+		}
+		line.WriteByte('\n')
+		if _, err := out.Write(line.Bytes()); err == nil {
+			err = out.Flush()
+		}
+		if err != nil {
+			failed = fmt.Errorf("Failed to write: %v", err) // Usually the reader went away (a closed pipe)
+			close(done)
+			return
+		}
+		printed++
+		if *count > 0 && printed == *count {
+			close(done)
+		}
+	}
+	sub, err := nc.Subscribe(*subject, func(msg *nats.Msg) {
+		if msg.Header.Get(events.ProbeHeader) != "" {
+			return
+		}
+		contentType := msg.Header.Get(events.ContentTypeHeader)
+		payloads := [][]byte{msg.Data}
+		if batch, ok := events.SplitBatch(msg.Data); ok && !events.IsProto(contentType) {
+			payloads = batch // An envelope's events print one per line
+		}
+		for _, data := range payloads {
+			printEvent(msg.Subject, contentType, data)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe to %s: %v", *subject, err)
+	}
+	defer sub.Unsubscribe()
+
+	log.Printf("Printing events on %s as JSON Lines (connected to %s)", *subject, settings.URL)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	select {
+	case <-stop:
+		return nil
+	case <-done:
+		return failed
+	}
+}
@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// approvalState is how far a four-eyes decision got: who approved which of its actions
+type approvalState struct {
+	needed    map[string]int      // Approvals each action requires, by events.Action.ApprovalID
+	approvers map[string][]string // Distinct operators who approved each action, in order
+}
+
+// approvalsReadyMsg is sent when the approvals subscription is ready
+type approvalsReadyMsg struct{ msgChan chan transport.Msg }
+
+// approvalMsg is sent when a monitor announces an approval
+type approvalMsg struct {
+	approval events.Approval
+	err      error // Why the approval is ignored (it isn't signed with the approval secret)
+}
+
+// approvalSentMsg is sent when this operator's approval was queued (and, unless deferred, published)
+type approvalSentMsg struct{ deferred error }
+
+// subscribeToApprovals subscribes to the approvals of four-eyes actions
+func subscribeToApprovals(t transport.Transport) tea.Cmd {
+	return func() tea.Msg {
+		msgChan := make(chan transport.Msg, 64)
+		if _, err := transport.Chan(t, events.ApprovalsSubject, msgChan); err != nil {
+			return errMsg{err}
+		}
+		return approvalsReadyMsg{msgChan: msgChan}
+	}
+}
+
+// waitForApproval waits for the next valid approval, checking its
+// signature when approvals are signed
+// Like presence, approvals keep flowing while the event stream is blocked
+func waitForApproval(msgChan chan transport.Msg, secret []byte) tea.Cmd {
+	return func() tea.Msg {
+		for msg := range msgChan {
+			if a, err := events.ApprovalFromJSON(msg.Data); err == nil {
+				return approvalMsg{approval: *a, err: a.Verify(secret)}
+			}
+		}
+		return nil
+	}
+}
+
+// publishApprovalCmd queues an approval durably, then publishes it
+func publishApprovalCmd(m *model, approval events.Approval) tea.Cmd {
+	ob, pub := m.outbox, m.durable
+	approval = approval.Sign(m.approvalSecret)
+	return func() tea.Msg {
+		data, err := approval.ToJSON()
+		if err != nil {
+			return errMsg{err}
+		}
+		deferred, err := publishDurably(ob, pub, events.ApprovalsSubject, data)
+		if err != nil {
+			return errMsg{err}
+		}
+		return approvalSentMsg{deferred: deferred}
+	}
+}
+
+// add records an approval; false if the operator had already approved the action
+func (s *approvalState) add(a events.Approval) bool {
+	if slices.Contains(s.approvers[a.ActionID], a.Operator) {
+		return false
+	}
+	s.approvers[a.ActionID] = append(s.approvers[a.ActionID], a.Operator)
+	s.needed[a.ActionID] = a.Needed
+	return true
+}
+
+// approvalsOf returns an event's approval state, creating it
+func (m *model) approvalsOf(eventID string) *approvalState {
+	state := m.approvals[eventID]
+	if state == nil {
+		state = &approvalState{needed: make(map[string]int), approvers: make(map[string][]string)}
+		m.approvals[eventID] = state
+	}
+	return state
+}
+
+// approve records this operator's approval of a four-eyes action of the
+// active decision: the approval completing the count answers the event, and
+// earlier ones are announced to the other monitors, leaving the decision open
+func (m *model) approve(action events.Action) tea.Cmd {
+	event := m.blockingEvent()
+	if event == nil {
+		return nil
+	}
+	// The key press cleared the buttons; a decision left open gets them back
+	keepOpen := func() {
+		m.actionManager.RegisterActions(event.Actions, *m.blockingEventIndex)
+	}
+	if m.operator == "" {
+		keepOpen()
+		m.status = i18n.T("status.approval_no_operator", action.Label)
+		return nil
+	}
+
+	state := m.approvalsOf(event.ID)
+	approval := events.Approval{
+		EventID:  event.ID,
+		ActionID: action.ApprovalID(),
+		Operator: m.operator,
+		Needed:   action.RequiredApprovals(),
+		At:       time.Now(),
+	}
+	if !state.add(approval) {
+		keepOpen()
+		m.status = i18n.T("status.approval_repeated", action.Label, len(state.approvers[approval.ActionID]), approval.Needed)
+		return nil
+	}
+	approvers := state.approvers[approval.ActionID]
+	if len(approvers) < approval.Needed {
+		keepOpen()
+		m.status = i18n.T("status.approved_partly", action.Label, len(approvers), approval.Needed)
+		m.recordApproval(approval, action, len(approvers))
+		return publishApprovalCmd(m, approval)
+	}
+
+	// The last approval needed: answer, naming everyone who approved
+	action = action.WithApprovers(approvers)
+	if len(action.Reasons) > 0 {
+		m.openReasonPicker(action)
+		return nil
+	}
+	return publishActionResponseCmd(m.transport, m.outbox, m.durable, m.subject, event.ID, action)
+}
+
+// noteApproval records another monitor's approval, or settles an event
+// another monitor answered
+func (m *model) noteApproval(a events.Approval) {
+	if a.Final {
+		delete(m.approvals, a.EventID)
+		if !m.consumedActions[a.EventID] {
+			m.settleAnsweredElsewhere(a.EventID)
+		}
+		return
+	}
+	if m.consumedActions[a.EventID] {
+		return // Answered here already
+	}
+	state := m.approvalsOf(a.EventID)
+	if state.add(a) && a.Operator != m.operator {
+		m.status = i18n.T("status.approval_from", a.Operator, shortID(a.EventID), len(state.approvers[a.ActionID]), a.Needed)
+	}
+}
+
+// finishApprovals voids an answered event's approvals, telling the other
+// monitors so they settle it too
+func (m *model) finishApprovals(eventID string) tea.Cmd {
+	if _, ok := m.approvals[eventID]; !ok {
+		return nil
+	}
+	delete(m.approvals, eventID)
+	return publishApprovalCmd(m, events.Approval{EventID: eventID, Operator: m.operator, Final: true, At: time.Now()})
+}
+
+// approvalBadge labels an event with the approvals its actions have so far
+func (m model) approvalBadge(eventID string) string {
+	state := m.approvals[eventID]
+	if state == nil {
+		return ""
+	}
+	ids := make([]string, 0, len(state.approvers))
+	for id := range state.approvers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var parts []string
+	for _, id := range ids {
+		approvers := state.approvers[id]
+		parts = append(parts, fmt.Sprintf("[%s %d/%d: %s]", id, len(approvers), state.needed[id], strings.Join(approvers, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// withApprovalLabels returns the active actions with four-eyes ones labeled
+// by how many approvals they have and need
+func (m model) withApprovalLabels(actions []events.Action) []events.Action {
+	event := m.blockingEvent()
+	labeled := make([]events.Action, len(actions))
+	for i, action := range actions {
+		if needed := action.RequiredApprovals(); needed > 1 {
+			have := 0
+			if event != nil && m.approvals[event.ID] != nil {
+				have = len(m.approvals[event.ID].approvers[action.ApprovalID()])
+			}
+			action.Label += " " + i18n.T("actions.approvals", have, needed)
+		}
+		labeled[i] = action
+	}
+	return labeled
+}
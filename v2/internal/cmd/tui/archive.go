@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/archive"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/recording"
+)
+
+// openArchive opens the event archive for --archive, uploading to an
+// s3://bucket/prefix when upload is set
+// Closed segments left by a previous run are uploaded in the background
+func openArchive(dir, instance, upload string, policy archive.Policy) (*archive.Sink, error) {
+	if dir == "" {
+		dir = archive.DefaultDir(instance)
+	}
+	w, err := archive.OpenWriter(dir, policy)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open --archive-dir: %v", err)
+	}
+
+	var store archive.Uploader
+	if upload != "" {
+		s3, err := archive.S3FromEnv(upload)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid --archive-upload: %v", err)
+		}
+		store = s3
+	}
+
+	sink := archive.NewSink(w, store)
+	if store != nil {
+		go sink.Upload()
+	}
+	return sink, nil
+}
+
+// readRecording reads the events of --from-file: a recorder database, or
+// anything archive.Open opens
+func readRecording(location string) ([]events.Event, error) {
+	if recording.IsDatabase(location) {
+		recorded, err := recording.Read(location)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read --from-file: %v", err)
+		}
+		return recorded, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	f, err := archive.Open(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open --from-file: %v", err)
+	}
+	defer f.Close()
+	recorded, err := events.ReadJSONL(f)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read --from-file: %v", err)
+	}
+	return recorded, nil
+}
@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// respondedViaTUI is the audit log's "via" for answers the operator gave here
+const respondedViaTUI = "tui"
+
+// auditView is the audit panel state: the cursor counts from the newest entry
+type auditView struct {
+	cursor int
+}
+
+// recordAction records a response this monitor published in the audit log
+// input is the value of an input action (nil for buttons)
+func (m *model) recordAction(eventID string, action events.Action, input interface{}, deferred error) {
+	data := action.Event.Data
+	entry := m.auditEntry(eventID)
+	entry.ActionID = action.ApprovalID()
+	entry.Label = action.Label
+	entry.Response = action.Event.Type
+	entry.Via = respondedViaTUI
+	entry.Responder = m.operator
+	if via, ok := data["responded_via"].(string); ok && via != "" {
+		entry.Via, entry.Responder = via, ""
+	}
+	if by, ok := data["responded_by"].(string); ok && by != "" {
+		entry.Responder = by
+	}
+	entry.Input = input
+	entry.Reasons = stringList(data[events.ReasonCodesKey])
+	entry.Approvers = stringList(data[events.ApprovedByKey])
+	entry.Deferred = deferred != nil
+	m.addAudit(entry)
+}
+
+// recordAnswerElsewhere records a response given outside this monitor (the
+// web dashboard, Slack) to an event it shows
+func (m *model) recordAnswerElsewhere(eventID string, response events.Event) {
+	if _, _, known := m.locateEvent(eventID); !known {
+		return
+	}
+	entry := m.auditEntry(eventID)
+	entry.Response = response.Type
+	entry.Via, _ = response.Data["responded_via"].(string)
+	if entry.Via == "" {
+		entry.Via = "elsewhere"
+	}
+	entry.Responder, _ = response.Data["responded_by"].(string)
+	if event := m.findEvent(eventID); event != nil {
+		for _, action := range event.Actions {
+			if action.Event.Type == response.Type {
+				entry.ActionID, entry.Label = action.ApprovalID(), action.Label
+				break
+			}
+		}
+	}
+	if input, ok := response.Data[events.InputKey]; ok {
+		entry.Input = input
+	} else if answer, ok := response.Data["answer"]; ok {
+		entry.Input = answer
+	}
+	entry.Reasons = stringList(response.Data[events.ReasonCodesKey])
+	entry.Approvers = stringList(response.Data[events.ApprovedByKey])
+	m.addAudit(entry)
+}
+
+// recordApproval records this operator's approval of a four-eyes action
+// short of the count it needs (nothing is answered yet)
+func (m *model) recordApproval(approval events.Approval, action events.Action, have int) {
+	entry := m.auditEntry(approval.EventID)
+	entry.Time = approval.At
+	entry.ActionID = approval.ActionID
+	entry.Label = action.Label
+	entry.Via = respondedViaTUI
+	entry.Responder = approval.Operator
+	entry.Approval = fmt.Sprintf("%d/%d", have, approval.Needed)
+	m.addAudit(entry)
+}
+
+// auditEntry starts an audit entry describing the event, as far as it's still in memory
+func (m model) auditEntry(eventID string) tui.AuditEntry {
+	entry := tui.AuditEntry{Time: time.Now(), EventID: eventID, EventIndex: -1}
+	if pane, index, ok := m.locateEvent(eventID); ok {
+		entry.Pane, entry.EventIndex = pane, index
+		if event := m.paneManager.GetEventByIndex(pane, index); event != nil {
+			entry.EventType, entry.Message = event.Type, event.Message
+		}
+	}
+	return entry
+}
+
+// findEvent returns an event in memory by ID, or nil
+func (m model) findEvent(id string) *events.Event {
+	pane, index, ok := m.locateEvent(id)
+	if !ok {
+		return nil
+	}
+	return m.paneManager.GetEventByIndex(pane, index)
+}
+
+// addAudit records an entry, reporting a failure to write the audit log file
+func (m *model) addAudit(entry tui.AuditEntry) {
+	if err := m.audit.Add(entry); err != nil {
+		m.status = i18n.T("audit.write_failed", err)
+	}
+}
+
+// stringList returns a JSON list of strings (e.g. reason codes) as strings
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			list = append(list, fmt.Sprint(item))
+		}
+		return list
+	}
+	return nil
+}
+
+// openAudit shows the audit panel
+func (m *model) openAudit() {
+	if m.audit.Len() == 0 {
+		m.status = i18n.T("audit.empty")
+		return
+	}
+	m.auditView = &auditView{}
+}
+
+// updateAudit handles keys in the audit panel
+func (m model) updateAudit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := m.audit.Entries()
+	a := m.auditView
+	a.cursor = max(0, min(a.cursor, len(entries)-1))
+	switch msg.String() {
+	case "ctrl+c":
+		return m.requestQuit()
+	case "esc", "q", "A":
+		m.auditView = nil
+	case "up", "k":
+		if a.cursor > 0 {
+			a.cursor--
+		}
+	case "down", "j":
+		if a.cursor < len(entries)-1 {
+			a.cursor++
+		}
+	case "home", "g":
+		a.cursor = 0
+	case "end", "G":
+		a.cursor = len(entries) - 1
+	case "enter":
+		// Show the event the action answered
+		entry := entries[len(entries)-1-a.cursor]
+		if _, _, ok := m.locateEvent(entry.EventID); !ok {
+			m.status = i18n.T("audit.evicted", shortID(entry.EventID))
+			return m, nil
+		}
+		m.auditView = nil
+		m.jumpTo(entry.EventID)
+	case "w":
+		m.exportAudit()
+	}
+	return m, nil
+}
+
+// exportAudit writes the audit log to a JSON file in the working directory
+func (m *model) exportAudit() {
+	path := fmt.Sprintf("agneto-audit-%s.json", time.Now().Format("20060102-150405"))
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		m.status = i18n.T("audit.export_failed", err)
+		return
+	}
+	err = m.audit.WriteJSON(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		m.status = i18n.T("audit.export_failed", err)
+		return
+	}
+	m.status = i18n.T("audit.exported", m.audit.Len(), path)
+}
+
+// renderAudit renders the actions taken, newest first, with the one under the
+// cursor in full underneath
+func (m model) renderAudit(width, height int) string {
+	entries := m.audit.Entries()
+	cursor := max(0, min(m.auditView.cursor, len(entries)-1))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(tui.Colors().Muted))
+	text := lipgloss.NewStyle().Foreground(lipgloss.Color(tui.Colors().Text))
+
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(tui.Colors().Title)).Render(i18n.T("audit.title")))
+	content.WriteString("  ")
+	content.WriteString(dim.Render(i18n.T("audit.count", len(entries))))
+	content.WriteString("\n\n")
+
+	// Keep the cursor in view, leaving room for the details
+	rows := max(1, height-14)
+	start := 0
+	if cursor >= rows {
+		start = cursor - rows + 1
+	}
+	end := min(start+rows, len(entries))
+	for i := start; i < end; i++ {
+		entry := entries[len(entries)-1-i]
+		pointer := "  "
+		if i == cursor {
+			pointer = "> "
+		}
+		who := entry.Responder
+		if who == "" {
+			who = entry.Via
+		} else if entry.Via != respondedViaTUI {
+			who += " (" + entry.Via + ")"
+		}
+		what := entry.Label
+		if what == "" {
+			what = entry.Response
+		}
+		if entry.Approval != "" {
+			what += " " + i18n.T("audit.approval", entry.Approval)
+		}
+		line := pointer + i18n.T("audit.line", entry.Time.Local().Format("2006-01-02 15:04:05"), who, what, entry.EventType, entry.Message)
+		content.WriteString(text.Render(tui.Truncate(line, width-8)))
+		content.WriteString("\n")
+	}
+
+	// Details of the entry under the cursor
+	entry := entries[len(entries)-1-cursor]
+	details := []string{
+		i18n.T("audit.event", entry.EventID, entry.EventIndex, entry.Pane),
+		i18n.T("audit.action", entry.ActionID, entry.Response),
+	}
+	if entry.Input != nil {
+		input := strings.Join(strings.Fields(fmt.Sprint(entry.Input)), " ")
+		details = append(details, i18n.T("audit.input", input))
+	}
+	if len(entry.Reasons) > 0 {
+		details = append(details, i18n.T("audit.reasons", strings.Join(entry.Reasons, ", ")))
+	}
+	if len(entry.Approvers) > 0 {
+		details = append(details, i18n.T("audit.approvers", strings.Join(entry.Approvers, ", ")))
+	}
+	if entry.Deferred {
+		details = append(details, i18n.T("audit.deferred"))
+	}
+	content.WriteString("\n")
+	for _, line := range details {
+		content.WriteString(dim.Render(tui.Truncate(line, width-8)))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(text.Render(i18n.T("audit.help")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(tui.Colors().Border)).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// rowBadges labels events in the listed pane (lifecycle, bookmarks, escalation state, late arrival, streaming, other operators)
+func (m model) rowBadges() map[int]string {
+	pane := m.paneManager.GetPane(m.activePane)
+	if pane == nil {
+		return nil
+	}
+
+	badges := make(map[int]string)
+	for i, event := range pane.Events {
+		labels := []string{m.lifecycles.Get(event.ID).Badge()}
+		if m.isBookmarked(event.ID) {
+			labels = append(labels, "★")
+		}
+		if state, ok := m.escalations[event.ID]; ok {
+			if state.answered {
+				labels = append(labels, i18n.T("badge.answered_elsewhere"))
+			} else {
+				labels = append(labels, i18n.T("badge.escalated"))
+			}
+		}
+		if m.drafts[event.ID] != nil {
+			labels = append(labels, i18n.T("badge.draft"))
+		}
+		if m.isQueued(event.ID) {
+			labels = append(labels, i18n.T("badge.queued"))
+		}
+		if behind, ok := pane.Late[event.ID]; ok {
+			labels = append(labels, lateBadge(behind))
+		}
+		if pane.Streaming(i) {
+			labels = append(labels, i18n.T("badge.streaming"))
+		}
+		if badge := m.approvalBadge(event.ID); badge != "" {
+			labels = append(labels, badge)
+		}
+		labels = append(labels, m.peersOn(event.ID)...)
+		badges[i] = strings.Join(labels, " ")
+	}
+	return badges
+}
+
+// lateBadge labels an event that arrived after newer ones, with how far
+// behind the newest it was timestamped
+func lateBadge(behind time.Duration) string {
+	if behind < time.Second {
+		return fmt.Sprintf("↶%dms", behind.Milliseconds())
+	}
+	return "↶" + formatAge(behind)
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"sort"
+
+	"github.com/durch/agneto/v2/pkg/config"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// selectedEvent returns the selected event, or nil
+func (m model) selectedEvent() *events.Event {
+	return m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex)
+}
+
+// selectedEventID returns the ID of the selected event, if any
+func (m model) selectedEventID() string {
+	event := m.selectedEvent()
+	if event == nil {
+		return ""
+	}
+	return event.ID
+}
+
+// locateEvent finds an event by ID across all panes
+func (m model) locateEvent(id string) (string, int, bool) {
+	for _, name := range m.paneManager.PaneNames() {
+		pane := m.paneManager.GetPane(name)
+		for i, event := range pane.Events {
+			if event.ID == id {
+				return name, i, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// isBookmarked reports whether an event is bookmarked
+func (m model) isBookmarked(id string) bool {
+	for _, b := range m.bookmarks {
+		if b.EventID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleBookmark adds or removes a bookmark on the selected event and persists the list
+func (m *model) toggleBookmark() {
+	event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex)
+	if event == nil {
+		return
+	}
+
+	if m.isBookmarked(event.ID) {
+		kept := m.bookmarks[:0]
+		for _, b := range m.bookmarks {
+			if b.EventID != event.ID {
+				kept = append(kept, b)
+			}
+		}
+		m.bookmarks = kept
+		m.status = i18n.T("bookmark.removed", event.Message)
+	} else {
+		m.bookmarks = append(m.bookmarks, config.Bookmark{
+			EventID:   event.ID,
+			Pane:      m.activePane,
+			Type:      event.Type,
+			Message:   event.Message,
+			Timestamp: event.Timestamp,
+		})
+		sort.SliceStable(m.bookmarks, func(i, j int) bool {
+			return m.bookmarks[i].Timestamp.Before(m.bookmarks[j].Timestamp)
+		})
+		m.status = i18n.T("bookmark.added", event.Message, len(m.bookmarks))
+	}
+
+	if err := config.SaveBookmarks(m.bookmarksPath, m.bookmarks); err != nil {
+		m.status = i18n.T("bookmark.save_failed", err)
+	}
+}
+
+// nextBookmark jumps to the next bookmarked event still in memory, wrapping around
+func (m *model) nextBookmark() {
+	if len(m.bookmarks) == 0 {
+		m.status = i18n.T("bookmark.none")
+		return
+	}
+
+	// Start after the selected event if it's bookmarked, otherwise at the first
+	start := 0
+	current := m.selectedEventID()
+	for i, b := range m.bookmarks {
+		if b.EventID == current {
+			start = i + 1
+			break
+		}
+	}
+
+	for n := 0; n < len(m.bookmarks); n++ {
+		b := m.bookmarks[(start+n)%len(m.bookmarks)]
+		if b.EventID == current {
+			continue
+		}
+		if _, _, ok := m.locateEvent(b.EventID); ok {
+			m.jumpTo(b.EventID)
+			m.status = i18n.T("bookmark.jumped", b.Message)
+			return
+		}
+	}
+	m.status = i18n.T("bookmark.no_other")
+}
+
+// jumpTo selects an event by ID, recording the current position in the jump list
+func (m *model) jumpTo(id string) {
+	pane, index, ok := m.locateEvent(id)
+	if !ok {
+		return
+	}
+	if current := m.selectedEventID(); current != "" && current != id {
+		m.jumps = append(m.jumps[:m.jumpPos], current)
+		m.jumpPos = len(m.jumps)
+	}
+	m.focusPane(pane)
+	m.selectedEventIndex = index
+}
+
+// jumpBack returns to the previous position in the jump list (ctrl+o)
+func (m *model) jumpBack() {
+	if m.jumpPos == 0 {
+		return
+	}
+	// Remember where we came from so jumpForward can return here
+	if m.jumpPos == len(m.jumps) {
+		if current := m.selectedEventID(); current != "" {
+			m.jumps = append(m.jumps, current)
+		}
+	}
+	m.jumpPos--
+	m.selectJump()
+}
+
+// jumpForward undoes a jumpBack (tab, the terminal's ctrl+i)
+func (m *model) jumpForward() {
+	if m.jumpPos+1 >= len(m.jumps) {
+		return
+	}
+	m.jumpPos++
+	m.selectJump()
+}
+
+// selectJump selects the jump list entry at jumpPos, if its event is still in memory
+func (m *model) selectJump() {
+	pane, index, ok := m.locateEvent(m.jumps[m.jumpPos])
+	if !ok {
+		m.status = i18n.T("bookmark.jump_evicted")
+		return
+	}
+	m.focusPane(pane)
+	m.selectedEventIndex = index
+	m.status = i18n.T("bookmark.jump", m.jumpPos+1, len(m.jumps))
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// cancelArmWindow is how long the first X waits for the confirming second press
+const cancelArmWindow = 3 * time.Second
+
+// taskCancelMsg is sent when a task.cancel was queued (and, unless deferred, published)
+type taskCancelMsg struct {
+	task     string
+	deferred error
+}
+
+// requestCancel publishes a task.cancel for the selected event's task
+// The first press arms the stop and names the task; a second press within
+// cancelArmWindow sends it, so a stray X can't stop a task
+func (m *model) requestCancel() tea.Cmd {
+	event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex)
+	switch {
+	case event == nil:
+		m.status = i18n.T("cancel.no_event")
+		return nil
+	case m.transport == nil:
+		m.status = i18n.T("cancel.not_connected")
+		return nil
+	}
+
+	task := event.Task()
+	if task == "" {
+		task = "of " + event.Type // Producer set no task or session ID
+	}
+	if m.cancelArmedFor != event.ID || time.Since(m.cancelArmedAt) > cancelArmWindow {
+		m.cancelArmedFor, m.cancelArmedAt = event.ID, time.Now()
+		m.status = i18n.T("cancel.arm", cancelArmWindow, task)
+		return nil
+	}
+
+	m.cancelArmedFor = ""
+	return publishCancelCmd(m.outbox, m.durable, m.subject, events.NewTaskCancel(*event, m.operator, m.instance), task)
+}
+
+// publishCancelCmd publishes a task.cancel through the outbox
+func publishCancelCmd(ob *outbox.Outbox, pub outbox.Publisher, subject string, cancel events.Event, task string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := cancel.ToJSON()
+		if err != nil {
+			return errMsg{err}
+		}
+		deferred, err := publishDurably(ob, pub, subject, data)
+		if err != nil {
+			return errMsg{err}
+		}
+		return taskCancelMsg{task: task, deferred: deferred}
+	}
+}
+
+// renderStopBanner renders a task.cancel or task.abort across both panes
+func renderStopBanner(event *events.Event, width int) string {
+	id := "cancel.banner_cancelled"
+	if event.Type == events.TypeTaskAbort {
+		id = "cancel.banner_aborted"
+	}
+	task := event.Task()
+	if task != "" {
+		task = " " + task
+	}
+	text := i18n.T(id, task, event.Timestamp.Format("15:04:05"), event.Message)
+
+	// Fill the width (minus padding) so the banner spans both panes
+	hint := "  " + i18n.T("cancel.dismiss")
+	room := width - 2 - len(hint)
+	if room > 3 {
+		text = tui.Truncate(text, room)
+	}
+	text += strings.Repeat(" ", max(0, room-tui.Width(text))) + hint
+	return lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color("160")).
+		Foreground(lipgloss.Color("231")).
+		Padding(0, 1).
+		Render(text) + "\n"
+}
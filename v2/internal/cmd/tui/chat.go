@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// chatState is the operator chat (--chat): its subscription, the line being
+// composed and messages not yet seen
+type chatState struct {
+	sub       transport.Subscription
+	msgChan   chan transport.Msg
+	input     textinput.Model
+	composing bool   // The chat line is open
+	returnTo  string // Pane to go back to when leaving the chat pane
+	unread    int    // Messages from others since the chat pane was last shown
+}
+
+// chatReadyMsg is sent when the chat subscription is ready
+type chatReadyMsg struct {
+	sub     transport.Subscription
+	msgChan chan transport.Msg
+}
+
+// chatMsg is sent when an operator (this one included) posts a chat message
+type chatMsg struct{ event events.Event }
+
+// subscribeToChat subscribes to the chat subject
+func subscribeToChat(t transport.Transport) tea.Cmd {
+	return func() tea.Msg {
+		msgChan := make(chan transport.Msg, 64)
+		sub, err := transport.Chan(t, events.ChatSubject, msgChan)
+		if err != nil {
+			return errMsg{err}
+		}
+		return chatReadyMsg{sub: sub, msgChan: msgChan}
+	}
+}
+
+// waitForChat waits for the next valid chat message
+// Like presence, chat keeps flowing while the event stream is blocked
+func waitForChat(msgChan chan transport.Msg) tea.Cmd {
+	return func() tea.Msg {
+		for msg := range msgChan {
+			if event, err := events.ChatFromJSON(msg.Data); err == nil {
+				return chatMsg{event: *event}
+			}
+		}
+		return nil
+	}
+}
+
+// noteChat lists a chat message in the chat pane
+// Chat bypasses transforms, hooks and routing: it's between operators, not from agents
+func (m *model) noteChat(event events.Event) {
+	pane := m.paneManager.GetPane(events.ChatPane)
+	if pane == nil {
+		return
+	}
+	event.Pane = events.ChatPane
+	pane.AddEvent(event)
+
+	own := event.ChatOperator() == m.operator && event.Data["instance"] == m.instance
+	if own || m.activePane == events.ChatPane {
+		return
+	}
+	m.chat.unread++
+	m.status = "💬 " + event.Message
+}
+
+// toggleChat shows the chat pane, or goes back to the pane shown before it
+func (m *model) toggleChat() {
+	if m.chat == nil {
+		m.status = i18n.T("chat.disabled")
+		return
+	}
+	if m.activePane == events.ChatPane {
+		m.focusPane(m.chat.returnTo)
+		m.selectedEventIndex = len(m.paneManager.GetPane(m.activePane).Events) - 1
+		return
+	}
+	m.chat.returnTo = m.activePane
+	m.chat.unread = 0
+	m.focusPane(events.ChatPane)
+	m.selectedEventIndex = len(m.paneManager.GetPane(events.ChatPane).Events) - 1
+}
+
+// openChatCompose opens the chat line
+func (m *model) openChatCompose() tea.Cmd {
+	switch {
+	case m.chat == nil:
+		m.status = i18n.T("chat.disabled")
+		return nil
+	case m.transport == nil:
+		m.status = i18n.T("chat.not_connected")
+		return nil
+	}
+	input := textinput.New()
+	input.Prompt = i18n.T("chat.prompt", m.operator)
+	input.Width = 80
+	input.Focus()
+	m.chat.input = input
+	m.chat.composing = true
+	return textinput.Blink
+}
+
+// updateChatCompose handles keys while the chat line is open
+// Chat is ephemeral, so it is published directly rather than through the outbox
+func (m model) updateChatCompose(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.chat.composing = false
+		return m, nil
+	case "enter":
+		m.chat.composing = false
+		text := strings.TrimSpace(m.chat.input.Value())
+		if text == "" {
+			return m, nil
+		}
+		data, err := events.NewChatMessage(m.operator, m.instance, text).ToJSON()
+		if err == nil {
+			err = m.transport.Publish(transport.Msg{Subject: events.ChatSubject, Data: data})
+		}
+		if err != nil {
+			m.status = i18n.T("chat.send_failed", err)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.chat.input, cmd = m.chat.input.Update(msg)
+	return m, cmd
+}
+
+// renderChatHeader renders the chat line or the unread count for the header
+func (m model) renderChatHeader() string {
+	switch {
+	case m.chat == nil:
+		return ""
+	case m.chat.composing:
+		return m.chat.input.View() + "  (enter: send, esc: cancel)\n"
+	case m.chat.unread > 0:
+		return i18n.T("chat.unread", m.chat.unread)
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// osc52Limit is the most text sent to the terminal's clipboard: terminals
+// and tmux drop longer OSC 52 sequences
+const osc52Limit = 64 * 1024
+
+// osc52 returns the escape sequence asking the terminal to put text on the
+// system clipboard, wrapped for tmux to pass it through
+func osc52(text string) string {
+	return tmuxPassthrough("\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07")
+}
+
+// tmuxPassthrough wraps an escape sequence for tmux to pass it on to the
+// terminal (outside tmux it is returned as is)
+func tmuxPassthrough(seq string) string {
+	if os.Getenv("TMUX") != "" {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return seq
+}
+
+// writeClipboard copies text to the system clipboard, and reports how
+// AIDEV-NOTE: OSC 52 goes through the terminal, so it reaches the operator's
+// clipboard over SSH, where the native one (the host's) is missing or the
+// wrong machine's. Both are tried: terminals without OSC 52 ignore it, and
+// nothing tells us whether it worked
+func writeClipboard(text string) (string, error) {
+	sent := false
+	if len(text) <= osc52Limit {
+		_, err := os.Stdout.WriteString(osc52(text))
+		sent = err == nil
+	}
+	err := clipboard.WriteAll(text)
+	switch {
+	case err == nil:
+		return "clipboard", nil
+	case sent:
+		return "terminal clipboard (OSC 52)", nil
+	}
+	return "", err
+}
+
+// payloadText returns what copying an event's payload copies: its Content
+// as written, else its Data as indented JSON, else its message
+// Data is masked as on screen
+func payloadText(event events.Event) (string, string, error) {
+	event = tui.Redacted(event)
+	switch {
+	case event.Content != "":
+		return event.Content, "content", nil
+	case len(event.Data) > 0:
+		data, err := json.MarshalIndent(event.Data, "", "  ")
+		return string(data), "data", err
+	}
+	return event.Message, "message", nil
+}
+
+// copyPayloadCmd copies the selected event's payload to the clipboard
+func (m model) copyPayloadCmd() tea.Cmd {
+	event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex)
+	if event == nil {
+		return func() tea.Msg { return yankDoneMsg{status: i18n.T("status.no_event_selected")} }
+	}
+	selected := *event
+	return func() tea.Msg {
+		text, what, err := payloadText(selected)
+		if err != nil {
+			return yankDoneMsg{status: i18n.T("copy.failed", err)}
+		}
+		where, err := writeClipboard(text)
+		if err != nil {
+			return yankDoneMsg{status: i18n.T("copy.failed_long", err)}
+		}
+		return yankDoneMsg{status: i18n.T("copy.done", what, selected.ShortHash(), where)}
+	}
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/nats-io/nats.go"
+)
+
+// NATS connection states shown in the header
+const (
+	connConnected    = "connected"
+	connReconnecting = "reconnecting"
+	connClosed       = "closed"
+)
+
+// connEventBuffer is the capacity of connectionState.events
+const connEventBuffer = 16
+
+// connectionState tracks the NATS connection through disconnects
+type connectionState struct {
+	events         chan connEventMsg // Fed by the NATS connection handlers
+	status         string            // connConnected, connReconnecting or connClosed ("" before connecting)
+	err            error             // Why the connection dropped or closed
+	disconnectedAt time.Time
+	reconnects     int
+}
+
+// connEventMsg reports a change of the NATS connection state
+type connEventMsg struct {
+	status string
+	err    error
+	at     time.Time
+}
+
+// connOptions reports connection state changes on events
+// The TUI keeps reconnecting for as long as it runs rather than giving up
+// after the client's default number of attempts
+func connOptions(events chan connEventMsg) []nats.Option {
+	report := func(status string, err error) {
+		// Handlers run on the client's goroutine, which must not block on a TUI that quit
+		select {
+		case events <- connEventMsg{status: status, err: err, at: time.Now()}:
+		default:
+		}
+	}
+	return []nats.Option{
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			report(connReconnecting, err)
+		}),
+		nats.ReconnectHandler(func(*nats.Conn) {
+			report(connConnected, nil)
+		}),
+		nats.ClosedHandler(func(nc *nats.Conn) {
+			report(connClosed, nc.LastError())
+		}),
+	}
+}
+
+// waitForConnEvent waits for the next connection state change
+func waitForConnEvent(events chan connEventMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// noteConnEvent records a connection state change, resubscribing after a reconnect
+func (m model) noteConnEvent(msg connEventMsg) (tea.Model, tea.Cmd) {
+	wait := waitForConnEvent(m.conn.events)
+	previous := m.conn.status
+	m.conn.status = msg.status
+
+	switch msg.status {
+	case connReconnecting:
+		m.conn.err = msg.err
+		if previous != connReconnecting {
+			m.conn.disconnectedAt = msg.at
+		}
+
+	case connConnected:
+		m.conn.err = nil
+		m.conn.reconnects++
+		m.status = i18n.T("connection.reconnected", msg.at.Sub(m.conn.disconnectedAt).Round(time.Second))
+		return m, tea.Batch(wait, resubscribeEvents(m.nc, m.bus, m.subject, m.instance, m.lag.reporter, m.conn.disconnectedAt))
+
+	case connClosed:
+		m.conn.err = msg.err
+		m.keepOpenInputs() // Answers can't go out until a restart, which keeps the text
+		return m, nil      // Nothing follows a close
+	}
+	return m, wait
+}
+
+// resubscribeEvents restarts the event source after a reconnect if its stream consumer is gone
+// AIDEV-NOTE: The client restores plain subscriptions (events, control,
+// presence, chat) by itself on reconnect. A JetStream consumer is server
+// state: when the server restarted, the ephemeral history consumer (and a
+// durable one on a stream without file storage) no longer exists and its
+// subscription stays silent. The replacement replays what the stream stored
+// since the disconnect, so events published meanwhile arrive as history
+func resubscribeEvents(nc *nats.Conn, bus *monitor.Bus, subject, instance string, lag monitor.LagReporter, since time.Time) tea.Cmd {
+	if lag == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, err := lag.Lag(); !errors.Is(err, nats.ErrConsumerNotFound) {
+			return nil
+		}
+		msg := subscribeToEvents(nc, bus, subject, instance, historyOptions{since: since})()
+		if ready, ok := msg.(subscriptionReadyMsg); ok && ready.note == "" {
+			ready.note = i18n.T("connection.resubscribed")
+			return ready
+		}
+		return msg
+	}
+}
+
+// renderConnection renders the connection line for the header ("" while connected)
+func (m model) renderConnection() string {
+	switch m.conn.status {
+	case connReconnecting:
+		reason := ""
+		if m.conn.err != nil {
+			reason = fmt.Sprintf(" (%v)", m.conn.err)
+		}
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Bold(true).
+			Render(i18n.T("connection.disconnected",
+				reason, time.Since(m.conn.disconnectedAt).Round(time.Second))) + "\n"
+	case connClosed:
+		reason := ""
+		if m.conn.err != nil {
+			reason = fmt.Sprintf(": %v", m.conn.err)
+		}
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true).
+			Render(i18n.T("connection.closed", reason)) + "\n"
+	}
+	return ""
+}
@@ -0,0 +1,389 @@
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/transport"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// controlReadyMsg is sent when the control subscription is ready
+type controlReadyMsg struct {
+	sub     transport.Subscription
+	msgChan chan transport.Msg
+}
+
+// controlCommandMsg is sent when a control command arrives
+type controlCommandMsg struct{ cmd events.ControlCommand }
+
+// controlErrMsg is sent when a control message can't be decoded
+// Unlike errMsg it doesn't quit the TUI
+type controlErrMsg struct{ err error }
+
+// paneDirectory holds the answer to pane discovery requests
+// Requests are answered on NATS' goroutine while panes are created in Update,
+// so Update publishes each change here
+type paneDirectory struct {
+	mu     sync.Mutex
+	answer []byte
+}
+
+// update records the monitor's panes, and whether it still creates new ones
+func (d *paneDirectory) update(pm *tui.PaneManager) {
+	names := pm.PaneNames()
+	if pm.CanCreate() {
+		names = append(names, events.AnyPane)
+	}
+	data, _ := json.Marshal(names)
+	d.mu.Lock()
+	d.answer = data
+	d.mu.Unlock()
+}
+
+// get returns the current answer
+func (d *paneDirectory) get() []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.answer
+}
+
+// answerPaneDiscovery answers publishers asking which panes exist (see client.DiscoverPanes)
+func answerPaneDiscovery(t transport.Transport, panes *paneDirectory) tea.Cmd {
+	return func() tea.Msg {
+		_, err := t.Subscribe(events.PanesSubject, func(msg transport.Msg) {
+			transport.Respond(t, msg, panes.get())
+		})
+		if err != nil {
+			return errMsg{err}
+		}
+		return nil
+	}
+}
+
+// subscribeToControl subscribes to this instance's control subject
+func subscribeToControl(t transport.Transport, instance string) tea.Cmd {
+	return func() tea.Msg {
+		msgChan := make(chan transport.Msg, 16)
+
+		sub, err := transport.Chan(t, events.ControlSubject(instance), msgChan)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return controlReadyMsg{
+			sub:     sub,
+			msgChan: msgChan,
+		}
+	}
+}
+
+// waitForControl waits for the next control command
+// AIDEV-NOTE: Runs independently of waitForEvent so automation keeps
+// working while the event stream is blocked on a pending action
+func waitForControl(msgChan chan transport.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg := <-msgChan
+		cmd, err := events.ControlCommandFromJSON(msg.Data)
+		if err != nil {
+			return controlErrMsg{err}
+		}
+		return controlCommandMsg{cmd: *cmd}
+	}
+}
+
+// applyControl executes a control command against the model
+func (m *model) applyControl(cmd events.ControlCommand) {
+	switch cmd.Command {
+	case events.ControlSelectEvent:
+		pane := m.paneManager.GetPane(m.activePane)
+		if pane == nil {
+			return
+		}
+		if cmd.Index != nil {
+			if *cmd.Index < 0 || *cmd.Index >= len(pane.Events) {
+				m.status = i18n.T("control.no_index", *cmd.Index)
+				return
+			}
+			m.selectedEventIndex = *cmd.Index
+			m.status = i18n.T("control.selected_index", *cmd.Index)
+			return
+		}
+		for i, event := range pane.Events {
+			if event.ID == cmd.EventID {
+				m.selectedEventIndex = i
+				m.status = i18n.T("control.selected", cmd.EventID)
+				return
+			}
+		}
+		m.status = i18n.T("control.not_found", cmd.EventID, m.activePane)
+
+	case events.ControlSwitchTab:
+		pane := m.paneManager.GetPane(cmd.Pane)
+		if pane == nil {
+			m.status = i18n.T("control.unknown_pane", cmd.Pane)
+			return
+		}
+		m.focusPane(pane.Name)
+		m.selectedEventIndex = len(pane.Events) - 1
+		m.status = i18n.T("control.pane", pane.Name)
+
+	case events.ControlSetFilter:
+		m.filter = cmd.Filter
+		m.moveSelection(0)
+		if cmd.Filter == "" {
+			m.status = i18n.T("control.filter_cleared")
+		} else {
+			m.status = i18n.T("control.filter", cmd.Filter)
+		}
+
+	case events.ControlExport:
+		pane := m.paneManager.GetPane(m.activePane)
+		if pane == nil {
+			return
+		}
+		path, err := exportPath(m.exportDir, cmd.Path)
+		if err != nil {
+			m.status = i18n.T("control.export_failed", err)
+			return
+		}
+		count, err := exportEvents(path, pane, m.listFilter())
+		if err != nil {
+			m.status = i18n.T("control.export_failed", err)
+			return
+		}
+		m.status = i18n.T("control.exported", count, path)
+	}
+}
+
+// moveSelection moves the selection by delta among the events visible under the filter
+// A delta of 0 snaps the selection onto a visible event
+func (m *model) moveSelection(delta int) {
+	pane := m.paneManager.GetPane(m.activePane)
+	if pane == nil {
+		return
+	}
+	visible := pane.VisibleIndices(m.listFilter())
+	if len(visible) == 0 {
+		return
+	}
+
+	// Find the selection's position in the visible list (default: newest)
+	pos := len(visible) - 1
+	for i, idx := range visible {
+		if idx == m.selectedEventIndex {
+			pos = i
+			break
+		}
+	}
+
+	pos += delta
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= len(visible) {
+		pos = len(visible) - 1
+	}
+	m.selectedEventIndex = visible[pos]
+}
+
+// pageSize is how many events pgup/pgdown move the selection by
+// Rows with chips take two lines, so a page may scroll a little less than a screen
+func (m model) pageSize() int {
+	height := m.height
+	if height == 0 {
+		height = 30
+	}
+	return max(1, height-12)
+}
+
+// scrollToEdge selects the oldest listed event, or the newest (following new arrivals again)
+func (m *model) scrollToEdge(newest bool) {
+	pane := m.paneManager.GetPane(m.activePane)
+	if pane == nil {
+		return
+	}
+	if newest {
+		pane.Scroll = 0
+		m.moveSelection(len(pane.Events))
+		return
+	}
+	m.moveSelection(-len(pane.Events))
+}
+
+// focusPane shows a pane in the event list
+// Events that arrived while the pane was out of focus are marked with a
+// "new" separator until the operator switches away again
+func (m *model) focusPane(name string) {
+	if name == m.activePane {
+		return
+	}
+	if m.paneLeftAt == nil {
+		m.paneLeftAt = make(map[string]time.Time)
+	}
+	m.paneLeftAt[m.activePane] = time.Now()
+	m.newSince = m.paneLeftAt[name] // Zero (no separator) for a pane never shown
+	m.activePane = name
+}
+
+// cyclePane shows the pane delta places after the listed one, newest event selected
+func (m *model) cyclePane(delta int) {
+	name := m.paneManager.NextPane(m.activePane, delta)
+	m.focusPane(name)
+	m.selectedEventIndex = len(m.paneManager.GetPane(name).Events) - 1
+	m.status = i18n.T("status.pane", name)
+}
+
+// renderPaneTabs lists the panes with their event counts once there are more
+// than the standard two, the listed one highlighted and the others with how
+// many events arrived since they were last shown
+func (m model) renderPaneTabs() string {
+	names := m.paneManager.PaneNames()
+	if len(names) <= len(events.StandardPanes) {
+		return ""
+	}
+	tabs := make([]string, 0, len(names))
+	for _, name := range names {
+		pane := m.paneManager.GetPane(name)
+		tab := fmt.Sprintf("%s %d", name, len(pane.Events))
+		if name == m.activePane {
+			tabs = append(tabs, lipgloss.NewStyle().Reverse(true).Render(" "+tab+" "))
+			continue
+		}
+		unseen := 0
+		for i := range pane.Events {
+			if pane.ArrivedAt(i).After(m.paneLeftAt[name]) {
+				unseen++
+			}
+		}
+		if unseen > 0 {
+			tab += fmt.Sprintf(" (+%d)", unseen)
+		}
+		tabs = append(tabs, " "+tab+" ")
+	}
+	return i18n.T("header.panes") + strings.Join(tabs, "│") + "  " + i18n.T("header.panes_hint") + "\n"
+}
+
+// exportPath resolves an export control command's path inside dir
+// AIDEV-NOTE: Control commands are unauthenticated, so export must not let
+// any publisher create or overwrite files of the operator's choosing: it is
+// off unless --export-dir is set, and only relative paths that stay inside
+// that directory are accepted
+func exportPath(dir, path string) (string, error) {
+	if dir == "" {
+		return "", errors.New(i18n.T("control.export_off"))
+	}
+	if !filepath.IsLocal(path) {
+		return "", errors.New(i18n.T("control.export_path", path))
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, path), nil
+}
+
+// exportEvents writes the pane's events matching filter to path as JSON Lines
+// Data values matching the redact patterns are masked, as on screen
+func exportEvents(path string, pane *tui.Pane, filter tui.ListFilter) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	count := 0
+	for _, idx := range pane.VisibleIndices(filter) {
+		data, err := tui.Redacted(pane.Events[idx]).ToJSON()
+		if err != nil {
+			return count, err
+		}
+		w.Write(data)
+		w.WriteString("\n")
+		count++
+	}
+
+	return count, w.Flush()
+}
+
+// closeConnections stops the event sources and closes the NATS connection
+func (m *model) closeConnections() {
+	m.leavePresence()
+	for _, stop := range m.stopSources {
+		stop()
+	}
+	if m.bus != nil {
+		m.bus.Close()
+	}
+	if m.controlSub != nil {
+		m.controlSub.Unsubscribe()
+	}
+	if m.nc != nil {
+		m.nc.Close()
+	}
+}
+
+// resumeListening returns a command waiting for the next event, unless one is already outstanding
+// AIDEV-NOTE: Several paths can unblock the stream (action taken, escalation,
+// input cancelled); this guard keeps exactly one waitForEvent in flight
+func (m *model) resumeListening() tea.Cmd {
+	if m.eventChan == nil || m.listening || m.hookStalled != nil {
+		return nil // The hooks' queue is full: they resume the stream once it drains
+	}
+	m.listening = true
+	if m.batching {
+		return nil // handleEventBatch continues with the batch instead
+	}
+	if len(m.backlog) > 0 {
+		backlog := m.backlog
+		m.backlog = nil
+		return func() tea.Msg { return backlog }
+	}
+	return waitForEvent(m.eventChan, m.strict)
+}
+
+// handleEventBatch handles a batch of events in one update
+// AIDEV-NOTE: Each event's handler "resumes listening" as usual; while
+// batching that only marks the stream as flowing. An event that blocks
+// (input mode) leaves listening off, and the rest of the batch is
+// kept as backlog until the stream resumes, so blocking semantics are the
+// same as handling events one by one
+func (m model) handleEventBatch(batch eventBatchMsg) (tea.Model, tea.Cmd) {
+	m.listening = false
+	m.batching = true
+
+	var cmds []tea.Cmd
+	flowing := true
+	for i, item := range batch {
+		next, cmd := m.update(item)
+		nm, ok := next.(model)
+		if !ok {
+			return next, cmd
+		}
+		m = nm
+		cmds = append(cmds, cmd)
+		if !m.listening {
+			m.backlog = append(eventBatchMsg(nil), batch[i+1:]...)
+			flowing = false
+			break
+		}
+		m.listening = false
+	}
+
+	m.batching = false
+	if flowing {
+		cmds = append(cmds, m.resumeListening())
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+func TestExportStaysInsideExportDir(t *testing.T) {
+	m := newTestModel(t)
+	m = deliver(t, m, events.Event{Type: "build", Message: "done"})
+	outside := filepath.Join(t.TempDir(), "outside.jsonl")
+
+	// Without --export-dir, nothing is written
+	m.applyControl(events.ControlCommand{Command: events.ControlExport, Path: outside})
+	if _, err := os.Stat(outside); err == nil {
+		t.Fatal("export wrote a file without --export-dir")
+	}
+
+	m.exportDir = t.TempDir()
+	for _, path := range []string{outside, "../outside.jsonl", "a/../../outside.jsonl"} {
+		m.applyControl(events.ControlCommand{Command: events.ControlExport, Path: path})
+		if !strings.Contains(m.status, "relative path") {
+			t.Errorf("export to %s: status %q, want it rejected", path, m.status)
+		}
+	}
+
+	m.applyControl(events.ControlCommand{Command: events.ControlExport, Path: "events.jsonl"})
+	data, err := os.ReadFile(filepath.Join(m.exportDir, "events.jsonl"))
+	if err != nil {
+		t.Fatalf("export inside the directory failed (status %q): %v", m.status, err)
+	}
+	if !strings.Contains(string(data), `"done"`) {
+		t.Fatalf("exported %q, want the build event", data)
+	}
+}
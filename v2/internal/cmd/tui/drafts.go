@@ -0,0 +1,278 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/config"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// inputDraft is an input request set aside with tab, keeping its textarea
+// state (text, cursor, undo history, vim mode) until the operator returns to it
+type inputDraft struct {
+	eventID  string
+	action   events.Action
+	since    time.Time // When the request started waiting
+	textarea textarea.Model
+	editor   editor
+	choice   int // Highlighted option of a select input
+}
+
+// text returns the draft's value as text (see savedInputText)
+func (d *inputDraft) text() string {
+	return savedInputText(d.action, d.textarea, d.choice)
+}
+
+// parkInput sets the open input aside as a draft and resumes listening,
+// so other events (and further input requests) can arrive meanwhile
+func (m *model) parkInput() tea.Cmd {
+	if !m.stashInput() {
+		return nil
+	}
+	return m.resumeListening()
+}
+
+// stashInput moves the open input into a draft and leaves input mode
+// Returns false if no input was open
+func (m *model) stashInput() bool {
+	event := m.blockingEvent()
+	if event == nil || m.inputAction == nil {
+		return false
+	}
+	m.textarea.Blur()
+	m.drafts[event.ID] = &inputDraft{
+		eventID:  event.ID,
+		action:   *m.inputAction,
+		since:    m.blockingSince,
+		textarea: m.textarea,
+		editor:   m.editor,
+		choice:   m.inputChoice,
+	}
+	m.inputMode = false
+	m.inputAction = nil
+	m.blockingEventIndex = nil
+	return true
+}
+
+// parkNewInput stores an input request that arrived while another input is open
+func (m *model) parkNewInput(event events.Event, action events.Action) {
+	m.drafts[event.ID] = &inputDraft{
+		eventID:  event.ID,
+		action:   action,
+		since:    time.Now(),
+		textarea: m.newInputTextarea(action),
+		editor:   editor{mode: m.editor.mode},
+	}
+	if text, ok := m.takeKeptInput(event, action); ok {
+		draft := m.drafts[event.ID]
+		draft.choice = restoreInputText(action, &draft.textarea, text)
+	}
+	m.status = i18n.T("draft.stashed", event.Message)
+}
+
+// resumeDraft reopens a parked input, restoring its text and editing state
+func (m *model) resumeDraft(eventID string) tea.Cmd {
+	draft, ok := m.drafts[eventID]
+	if !ok {
+		return nil
+	}
+	if m.blockingEventIndex != nil {
+		m.status = i18n.T("draft.answer_first")
+		return nil
+	}
+	pane, index, found := m.locateEvent(eventID)
+	delete(m.drafts, eventID)
+	if !found {
+		m.status = i18n.T("draft.evicted", shortID(eventID))
+		return nil
+	}
+
+	m.inputMode = true
+	m.inputAction = &draft.action
+	m.blockingEventIndex = &index
+	m.blockingPane = pane
+	m.blockingSince = draft.since
+	m.focusPane(pane)
+	m.selectedEventIndex = index
+	m.textarea = draft.textarea
+	m.editor = draft.editor
+	m.inputChoice = draft.choice
+	m.textarea.Focus()
+	return textarea.Blink
+}
+
+// nextDraft returns the oldest parked draft other than the given event, if any
+func (m model) nextDraft(except string) (string, bool) {
+	ids := m.draftIDs()
+	for _, id := range ids {
+		if id != except {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// draftIDs returns the parked drafts' event IDs, oldest request first
+func (m model) draftIDs() []string {
+	ids := make([]string, 0, len(m.drafts))
+	for id := range m.drafts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return m.drafts[ids[i]].since.Before(m.drafts[ids[j]].since)
+	})
+	return ids
+}
+
+// switchDraft parks the open input and opens the next draft (tab in input mode)
+func (m *model) switchDraft() tea.Cmd {
+	current := ""
+	if event := m.blockingEvent(); event != nil {
+		current = event.ID
+	}
+	next, ok := m.nextDraft(current)
+	park := m.parkInput()
+	if !ok {
+		m.status = i18n.T("draft.set_aside")
+		return park
+	}
+	return tea.Batch(park, m.resumeDraft(next))
+}
+
+// maxKeptInputs bounds the kept input texts; the oldest are dropped first
+const maxKeptInputs = 100
+
+// keptInputKey identifies an input across cancels, reconnects and restarts:
+// the request's correlation ID (the event ID unless the producer sets one,
+// so a producer asking again after a reconnect gets the same key) and the
+// action's ID
+func keptInputKey(event events.Event, action events.Action) string {
+	return event.Correlation() + "/" + action.ApprovalID()
+}
+
+// keepInput keeps the text typed into an input that goes unsubmitted, for
+// when input mode opens for the same action again
+// Blank text and confirm inputs (a single key) aren't kept
+func (m *model) keepInput(event events.Event, action events.Action, text string) {
+	if strings.TrimSpace(text) == "" || action.InputType == events.InputConfirm {
+		return
+	}
+	if m.keptInputs == nil {
+		m.keptInputs = make(map[string]config.KeptInput)
+	}
+	m.keptInputs[keptInputKey(event, action)] = config.KeptInput{Text: text, SavedAt: time.Now()}
+	for len(m.keptInputs) > maxKeptInputs {
+		oldest := ""
+		for key, kept := range m.keptInputs {
+			if oldest == "" || kept.SavedAt.Before(m.keptInputs[oldest].SavedAt) {
+				oldest = key
+			}
+		}
+		delete(m.keptInputs, oldest)
+	}
+	m.saveKeptInputs()
+}
+
+// keepOpenInputs keeps the text of the open input and every draft, before
+// they'd be lost with the connection or the process
+func (m *model) keepOpenInputs() {
+	if event := m.blockingEvent(); event != nil && m.inputMode && m.inputAction != nil {
+		m.keepInput(*event, *m.inputAction, savedInputText(*m.inputAction, m.textarea, m.inputChoice))
+	}
+	for id, draft := range m.drafts {
+		if pane, index, found := m.locateEvent(id); found {
+			if event := m.paneManager.GetEventByIndex(pane, index); event != nil {
+				m.keepInput(*event, draft.action, draft.text())
+			}
+		}
+	}
+}
+
+// takeKeptInput removes and returns the text kept for an event's input action
+func (m *model) takeKeptInput(event events.Event, action events.Action) (string, bool) {
+	key := keptInputKey(event, action)
+	kept, ok := m.keptInputs[key]
+	if !ok {
+		return "", false
+	}
+	delete(m.keptInputs, key)
+	m.saveKeptInputs()
+	return kept.Text, true
+}
+
+// hasKeptInput reports whether text is kept for an event's input action
+func (m model) hasKeptInput(event events.Event, action events.Action) bool {
+	_, ok := m.keptInputs[keptInputKey(event, action)]
+	return ok
+}
+
+// saveKeptInputs writes the kept inputs to disk, if they're persisted
+func (m *model) saveKeptInputs() {
+	if m.keptInputsPath == "" {
+		return
+	}
+	if err := config.SaveKeptInputs(m.keptInputsPath, m.keptInputs); err != nil {
+		m.status = i18n.T("draft.save_failed", err)
+	}
+}
+
+// restoreInputText puts saved text back into an input's textarea, or
+// highlights it for a select input; returns the highlighted option
+func restoreInputText(action events.Action, ta *textarea.Model, text string) int {
+	if action.InputType == events.InputSelect {
+		return inputChoiceOf(action, text)
+	}
+	ta.SetValue(text)
+	return 0
+}
+
+// openInput enters input mode for an input action of the event at index in
+// pane, restoring text kept from an earlier attempt at it
+func (m *model) openInput(event events.Event, action events.Action, pane string, index int) tea.Cmd {
+	m.inputMode = true
+	m.inputAction = &action
+	m.blockingEventIndex = &index
+	m.blockingPane = pane
+	m.blockingSince = time.Now()
+	m.focusPane(pane)
+	m.selectedEventIndex = index
+
+	m.textarea = m.newInputTextarea(action)
+	m.inputChoice = 0
+	m.editor.Reset()
+	if text, ok := m.takeKeptInput(event, action); ok {
+		m.inputChoice = restoreInputText(action, &m.textarea, text)
+		m.status = i18n.T("draft.restored")
+	}
+	return textarea.Blink
+}
+
+// reopenInput opens an input request cancelled with esc again, with the
+// text typed before (enter on the request)
+// Returns false if the selected event has no cancelled input to reopen
+func (m *model) reopenInput() (tea.Cmd, bool) {
+	event := m.selectedEvent()
+	if event == nil || m.consumedActions[event.ID] || m.lifecycles.Get(event.ID) == tui.LifecycleResponded {
+		return nil, false
+	}
+	action, ok := event.InputAction()
+	if !ok || !m.hasKeptInput(*event, action) {
+		return nil, false
+	}
+	if m.blockingEventIndex != nil {
+		m.status = i18n.T("draft.answer_first_input")
+		return nil, true
+	}
+	pane, index, found := m.locateEvent(event.ID)
+	if !found {
+		return nil, false
+	}
+	m.lifecycles.Set(event.ID, tui.LifecycleSeen)
+	return m.openInput(*event, action, pane, index), true
+}
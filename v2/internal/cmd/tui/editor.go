@@ -0,0 +1,286 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbletea"
+)
+
+// Edit modes for the input textarea
+const (
+	editModeEmacs = "emacs" // Readline bindings (textarea defaults) - the default
+	editModeVim   = "vim"   // Modal editing: starts in insert mode, Esc for normal mode
+)
+
+// maxUndo bounds the undo history per input
+const maxUndo = 200
+
+// editorState is a snapshot of the textarea for undo/redo
+type editorState struct {
+	value string
+	row   int
+	col   int
+}
+
+// editor adds undo/redo, a kill ring and optional vim modal editing on top
+// of the textarea, whose defaults already cover readline movement and
+// deletion (ctrl+a/e/w/u/k, alt+b/f/d)
+type editor struct {
+	mode    string        // editModeEmacs or editModeVim
+	normal  bool          // Vim normal mode (false: insert mode)
+	pending string        // Vim operator waiting for a motion ("d", "g")
+	undo    []editorState // Older states, newest last
+	redo    []editorState // Undone states, newest last
+	typing  bool          // Last edit was typing a word (coalesced into one undo step)
+	killed  string        // Last text deleted by a kill command (yanked with ctrl+y / p)
+}
+
+// Reset clears the editor for a new input, starting in insert mode
+func (e *editor) Reset() {
+	*e = editor{mode: e.mode}
+}
+
+// Escape handles Esc; returns true if it was consumed (vim insert → normal)
+// Otherwise Esc cancels input mode as usual
+func (e *editor) Escape() bool {
+	if e.mode != editModeVim || e.normal {
+		return false
+	}
+	e.normal = true
+	e.pending = ""
+	return true
+}
+
+// ModeLabel returns the vim mode shown in the input instructions ("" in emacs mode)
+func (e *editor) ModeLabel() string {
+	switch {
+	case e.mode != editModeVim:
+		return ""
+	case e.normal:
+		return "-- NORMAL --"
+	default:
+		return "-- INSERT --"
+	}
+}
+
+// Update applies a key to the textarea
+func (e *editor) Update(ta *textarea.Model, msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+z", "ctrl+_":
+		e.undoEdit(ta)
+		return nil
+	case "ctrl+r":
+		e.redoEdit(ta)
+		return nil
+	case "ctrl+y":
+		e.yank(ta)
+		return nil
+	}
+
+	if e.mode == editModeVim && e.normal {
+		return e.updateNormal(ta, msg)
+	}
+	return e.apply(ta, msg)
+}
+
+// apply passes a key to the textarea, recording undo history and killed text
+func (e *editor) apply(ta *textarea.Model, msg tea.KeyMsg) tea.Cmd {
+	before := e.snapshot(ta)
+	var cmd tea.Cmd
+	*ta, cmd = ta.Update(msg)
+	after := ta.Value()
+	if after == before.value {
+		return cmd
+	}
+
+	// Consecutive typing within a word is a single undo step
+	typing := msg.Type == tea.KeyRunes && !msg.Alt && !msg.Paste && string(msg.Runes) != " "
+	if !typing || !e.typing {
+		e.push(before)
+	}
+	e.typing = typing
+	e.redo = nil
+
+	// Remember what kill commands removed
+	switch msg.String() {
+	case "ctrl+w", "ctrl+u", "ctrl+k", "alt+d", "alt+backspace", "alt+delete":
+		e.killed = removedText(before.value, after)
+	}
+	return cmd
+}
+
+// updateNormal handles vim normal mode keys by translating them into textarea keys
+func (e *editor) updateNormal(ta *textarea.Model, msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+
+	// Two-key commands
+	if e.pending != "" {
+		op := e.pending + key
+		e.pending = ""
+		switch op {
+		case "dd":
+			e.deleteLine(ta)
+		case "dw":
+			e.apply(ta, altKey('d'))
+		case "d$":
+			e.apply(ta, tea.KeyMsg{Type: tea.KeyCtrlK})
+		case "d0":
+			e.apply(ta, tea.KeyMsg{Type: tea.KeyCtrlU})
+		case "gg":
+			*ta, _ = ta.Update(tea.KeyMsg{Type: tea.KeyCtrlHome})
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	move := func(m tea.KeyMsg) { *ta, cmd = ta.Update(m) }
+	switch key {
+	case "d", "g":
+		e.pending = key
+	case "h", "left":
+		move(tea.KeyMsg{Type: tea.KeyLeft})
+	case "l", "right":
+		move(tea.KeyMsg{Type: tea.KeyRight})
+	case "j", "down":
+		move(tea.KeyMsg{Type: tea.KeyDown})
+	case "k", "up":
+		move(tea.KeyMsg{Type: tea.KeyUp})
+	case "w":
+		move(altKey('f'))
+	case "b":
+		move(altKey('b'))
+	case "0":
+		move(tea.KeyMsg{Type: tea.KeyHome})
+	case "$":
+		move(tea.KeyMsg{Type: tea.KeyEnd})
+	case "G":
+		move(tea.KeyMsg{Type: tea.KeyCtrlEnd})
+	case "x":
+		cmd = e.apply(ta, tea.KeyMsg{Type: tea.KeyDelete})
+	case "D":
+		cmd = e.apply(ta, tea.KeyMsg{Type: tea.KeyCtrlK})
+	case "u":
+		e.undoEdit(ta)
+	case "p":
+		e.yank(ta)
+	case "i":
+		e.normal = false
+	case "a":
+		move(tea.KeyMsg{Type: tea.KeyRight})
+		e.normal = false
+	case "A":
+		move(tea.KeyMsg{Type: tea.KeyEnd})
+		e.normal = false
+	case "I":
+		move(tea.KeyMsg{Type: tea.KeyHome})
+		e.normal = false
+	case "o":
+		move(tea.KeyMsg{Type: tea.KeyEnd})
+		cmd = e.apply(ta, tea.KeyMsg{Type: tea.KeyEnter})
+		e.normal = false
+	case "O":
+		move(tea.KeyMsg{Type: tea.KeyHome})
+		cmd = e.apply(ta, tea.KeyMsg{Type: tea.KeyEnter})
+		move(tea.KeyMsg{Type: tea.KeyUp})
+		e.normal = false
+	}
+	e.typing = false
+	return cmd
+}
+
+// deleteLine deletes the cursor's line (vim dd), keeping it in the kill ring
+func (e *editor) deleteLine(ta *textarea.Model) {
+	before := e.snapshot(ta)
+	ta.CursorStart()
+	*ta, _ = ta.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if ta.Line() < ta.LineCount()-1 {
+		*ta, _ = ta.Update(tea.KeyMsg{Type: tea.KeyDelete})
+	} else if ta.Line() > 0 {
+		*ta, _ = ta.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	if ta.Value() != before.value {
+		e.push(before)
+		e.redo = nil
+		e.killed = removedText(before.value, ta.Value())
+	}
+}
+
+// yank inserts the last killed text at the cursor
+func (e *editor) yank(ta *textarea.Model) {
+	if e.killed == "" {
+		return
+	}
+	e.push(e.snapshot(ta))
+	e.redo = nil
+	e.typing = false
+	ta.InsertString(e.killed)
+}
+
+// undoEdit restores the previous state
+func (e *editor) undoEdit(ta *textarea.Model) {
+	if len(e.undo) == 0 {
+		return
+	}
+	e.redo = append(e.redo, e.snapshot(ta))
+	state := e.undo[len(e.undo)-1]
+	e.undo = e.undo[:len(e.undo)-1]
+	e.typing = false
+	restore(ta, state)
+}
+
+// redoEdit reapplies the last undone state
+func (e *editor) redoEdit(ta *textarea.Model) {
+	if len(e.redo) == 0 {
+		return
+	}
+	e.undo = append(e.undo, e.snapshot(ta))
+	state := e.redo[len(e.redo)-1]
+	e.redo = e.redo[:len(e.redo)-1]
+	e.typing = false
+	restore(ta, state)
+}
+
+// push records an undo state, dropping the oldest past maxUndo
+func (e *editor) push(state editorState) {
+	e.undo = append(e.undo, state)
+	if len(e.undo) > maxUndo {
+		e.undo = e.undo[1:]
+	}
+}
+
+// snapshot captures the textarea's value and cursor
+func (e *editor) snapshot(ta *textarea.Model) editorState {
+	info := ta.LineInfo()
+	return editorState{
+		value: ta.Value(),
+		row:   ta.Line(),
+		col:   info.StartColumn + info.ColumnOffset,
+	}
+}
+
+// restore sets the textarea's value and moves the cursor back to where it was
+func restore(ta *textarea.Model, state editorState) {
+	ta.SetValue(state.value) // Leaves the cursor at the end
+	for ta.Line() > state.row {
+		ta.CursorUp()
+	}
+	ta.SetCursor(state.col)
+}
+
+// removedText returns the text deleted between two values
+func removedText(before, after string) string {
+	b, a := []rune(before), []rune(after)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && b[prefix] == a[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && b[len(b)-1-suffix] == a[len(a)-1-suffix] {
+		suffix++
+	}
+	return string(b[prefix : len(b)-suffix])
+}
+
+// altKey builds an alt+<r> key message
+func altKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: true}
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/export"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// defaultClosedPanesDir returns where closed ephemeral panes are archived
+// (~/.config/agneto/closed-panes/<instance>)
+func defaultClosedPanesDir(instance string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join("agneto-closed-panes", instance)
+	}
+	return filepath.Join(dir, "agneto", "closed-panes", instance)
+}
+
+// validatePanes validates the settings file's pane options
+func validatePanes(options []tui.PaneOptions) error {
+	for _, o := range options {
+		if err := o.Validate(); err != nil {
+			return fmt.Errorf("Invalid panes setting: %v", err)
+		}
+	}
+	return nil
+}
+
+// closeIdlePanes closes ephemeral panes nobody sent events to for a while,
+// archiving their events first
+// Panes holding a decision still waiting (active, queued or a draft) stay open
+func (m *model) closeIdlePanes() {
+	if m.replay != nil {
+		return // Scrubbing re-adds events; panes come and go with the recording
+	}
+	idle := m.paneManager.IdlePanes(time.Now())
+	if len(idle) == 0 {
+		return
+	}
+	waiting := make(map[string]bool)
+	for _, pending := range m.pendingDecisions() {
+		waiting[pending.pane] = true
+	}
+
+	for _, name := range idle {
+		if waiting[name] {
+			continue
+		}
+		pane := m.paneManager.GetPane(name)
+		file, err := archivePane(m.closedPanesDir, pane)
+		if err != nil {
+			m.status = i18n.T("status.pane_archive_failed", name, err)
+			continue // Kept open rather than losing its events; retried next tick
+		}
+		m.paneManager.ClosePane(name)
+		delete(m.paneLeftAt, name)
+		if m.activePane == name {
+			m.focusPane(m.paneManager.DefaultPane)
+			m.selectedEventIndex = len(m.paneManager.GetPane(m.activePane).Events) - 1
+		}
+		m.paneDirectory.update(m.paneManager)
+		if file == "" {
+			m.status = i18n.T("status.pane_closed", name)
+		} else {
+			m.status = i18n.T("status.pane_closed_archived", name, file)
+		}
+	}
+}
+
+// archivePane writes a pane's events to <dir>/<pane>-<time>.jsonl
+// Returns "" without writing anything for an empty pane
+func archivePane(dir string, pane *tui.Pane) (string, error) {
+	if len(pane.Events) == 0 {
+		return "", nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, fmt.Sprintf("%s-%s.jsonl", pane.Name, time.Now().Format("20060102-150405")))
+	f, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	w, err := export.NewWriter(f, export.Options{Format: export.FormatJSONL})
+	if err != nil {
+		return "", err
+	}
+	for _, event := range pane.Events {
+		if err := w.Write(event); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return file, f.Close()
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// escalationState tracks an event handed to alternate approvers
+type escalationState struct {
+	target   string // Subject or webhook the copy went to
+	answered bool   // Someone else responded
+}
+
+// escalationDueMsg is sent when an actionable event's escalation delay expires
+type escalationDueMsg struct{ eventID string }
+
+// escalatedMsg is sent when the escalated copy was published (or failed)
+type escalatedMsg struct {
+	eventID string
+	target  string
+	err     error
+}
+
+// scheduleEscalation arms the escalation timer for an actionable event, if it has one
+func (m *model) scheduleEscalation(event events.Event) tea.Cmd {
+	if event.Escalation == nil {
+		return nil
+	}
+	if err := event.Escalation.Validate(); err != nil {
+		m.status = i18n.T("escalation.invalid", shortID(event.ID), err)
+		return nil
+	}
+
+	eventID := event.ID
+	return tea.Tick(event.Escalation.After(), func(time.Time) tea.Msg {
+		return escalationDueMsg{eventID: eventID}
+	})
+}
+
+// escalate publishes the escalated copy if the event is still waiting on this
+// monitor, as the active decision or in the queue
+func (m model) escalate(eventID string) tea.Cmd {
+	if m.activeEventID() != eventID && !m.isQueued(eventID) {
+		return nil // Answered meanwhile
+	}
+	pane, index, _ := m.locateEvent(eventID)
+	event := m.paneManager.GetEventByIndex(pane, index)
+	if event == nil || event.Escalation == nil || m.transport == nil {
+		return nil
+	}
+	if _, done := m.escalations[eventID]; done {
+		return nil
+	}
+	return publishEscalationCmd(m.outbox, m.durable, *event)
+}
+
+// publishEscalationCmd sends the escalated copy to a NATS subject or webhook
+// Subject targets go through the outbox like any other response
+func publishEscalationCmd(ob *outbox.Outbox, pub outbox.Publisher, event events.Event) tea.Cmd {
+	return func() tea.Msg {
+		target := event.Escalation.Target
+		data, err := event.EscalatedCopy().ToJSON()
+		if err != nil {
+			return escalatedMsg{eventID: event.ID, target: target, err: err}
+		}
+
+		if event.Escalation.IsWebhook() {
+			client := &http.Client{Timeout: 10 * time.Second}
+			resp, err := client.Post(target, "application/json", bytes.NewReader(data))
+			if err != nil {
+				return escalatedMsg{eventID: event.ID, target: target, err: err}
+			}
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				err = fmt.Errorf("webhook returned %s", resp.Status)
+			}
+			return escalatedMsg{eventID: event.ID, target: target, err: err}
+		}
+
+		// A deferred publish still counts as escalated - the outbox retries it
+		_, err = publishDurably(ob, pub, target, data)
+		return escalatedMsg{eventID: event.ID, target: target, err: err}
+	}
+}
+
+// settleAnsweredElsewhere records a response given outside this monitor
+// (an alternate approver, Slack); if the event is still blocking here, its
+// actions are withdrawn
+func (m *model) settleAnsweredElsewhere(eventID string) {
+	if state, ok := m.escalations[eventID]; ok {
+		state.answered = true
+		m.escalations[eventID] = state
+	}
+	if _, _, known := m.locateEvent(eventID); !known {
+		return
+	}
+	m.lifecycles.Set(eventID, tui.LifecycleResponded)
+	m.status = i18n.T("escalation.answered", shortID(eventID))
+	delete(m.drafts, eventID)
+
+	m.actionManager.Remove(eventID)
+	if event := m.blockingEvent(); event != nil && event.ID == eventID {
+		m.actionManager.ClearAll()
+		m.consumedActions[eventID] = true
+		m.blockingEventIndex = nil
+		m.inputMode = false
+		m.inputAction = nil
+		m.promoteQueued()
+	}
+}
+
+// shortID abbreviates an event ID for status messages
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/archive"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// archivedEvent is an event no longer in any pane, shown from an archive
+type archivedEvent struct {
+	event  events.Event
+	file   string             // Archive file it was found in
+	scroll *tui.PayloadScroll // Payload scroll position
+}
+
+// gotoFoundMsg is sent when an archive search for a reference finishes
+type gotoFoundMsg struct {
+	ref   string
+	found []archive.Found
+	err   error
+}
+
+// openGoto opens the goto prompt (: or g)
+func (m *model) openGoto() tea.Cmd {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = i18n.T("goto.placeholder")
+	input.Width = 40
+	input.Focus()
+	m.gotoInput = &input
+	return textinput.Blink
+}
+
+// updateGoto handles keys while the goto prompt is open
+func (m model) updateGoto(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.gotoInput = nil
+		return m, nil
+	case "enter":
+		ref := strings.TrimSpace(m.gotoInput.Value())
+		m.gotoInput = nil
+		if ref == "" {
+			return m, nil
+		}
+		return m, m.gotoRef(ref)
+	}
+	var cmd tea.Cmd
+	*m.gotoInput, cmd = m.gotoInput.Update(msg)
+	return m, cmd
+}
+
+// gotoRef selects the event a reference names (an ID, or a prefix of its ID
+// or short hash), searching the archives when no pane holds it any more
+func (m *model) gotoRef(ref string) tea.Cmd {
+	var matches []string
+	for _, name := range m.paneManager.PaneNames() {
+		for _, event := range m.paneManager.GetPane(name).Events {
+			if event.MatchesRef(ref) {
+				matches = append(matches, event.ID)
+			}
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		m.jumpTo(matches[0])
+		m.status = i18n.T("goto.found", shortID(matches[0]))
+		if event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex); event != nil && !m.listFilter().Matches(*event) {
+			m.status = i18n.T("goto.found_hidden", shortID(matches[0]))
+		}
+		return nil
+	case len(matches) > 1:
+		m.status = i18n.T("goto.ambiguous", ref, len(matches))
+		return nil
+	}
+
+	dirs := m.archiveDirs()
+	m.status = i18n.T("goto.searching", ref)
+	return func() tea.Msg {
+		found, err := archive.Find(dirs, ref)
+		return gotoFoundMsg{ref: ref, found: found, err: err}
+	}
+}
+
+// archiveDirs returns where evicted events may be found: the archive
+// (--archive) and the closed ephemeral panes
+func (m model) archiveDirs() []string {
+	dirs := []string{m.closedPanesDir}
+	if m.archive != nil {
+		dirs = append(dirs, m.archive.Dir())
+	}
+	return dirs
+}
+
+// noteGotoFound shows the event an archive search found
+func (m *model) noteGotoFound(msg gotoFoundMsg) {
+	switch {
+	case len(msg.found) == 1:
+		found := msg.found[0]
+		if _, _, ok := m.locateEvent(found.Event.ID); ok {
+			m.jumpTo(found.Event.ID) // Arrived again while searching
+			return
+		}
+		m.archived = &archivedEvent{event: found.Event, file: found.File, scroll: &tui.PayloadScroll{}}
+		m.status = i18n.T("goto.archived", shortID(found.Event.ID), found.File)
+	case len(msg.found) > 1:
+		m.status = i18n.T("goto.ambiguous", msg.ref, len(msg.found))
+	case msg.err != nil:
+		m.status = i18n.T("goto.failed", msg.err)
+	default:
+		m.status = i18n.T("goto.not_found", msg.ref)
+	}
+}
+
+// updateArchived handles keys while an archived event is shown
+func (m model) updateArchived(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.archived.scroll.Offset = max(0, m.archived.scroll.Offset-1)
+	case "down", "j":
+		m.archived.scroll.Offset++
+	case "ctrl+u", "pgup":
+		m.archived.scroll.Offset = max(0, m.archived.scroll.Offset-10)
+	case "ctrl+d", "pgdown":
+		m.archived.scroll.Offset += 10
+	default:
+		m.archived = nil
+	}
+	return m, nil
+}
+
+// renderArchived renders an archived event full screen
+func (m model) renderArchived(width, height int) string {
+	a := m.archived
+	label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99")).
+		Render(i18n.T("goto.archived_title", a.event.ShortHash(), a.file))
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render(i18n.T("goto.archived_hint"))
+	return label + "\n" + tui.RenderEventDetails(&a.event, width-4, height-6, a.scroll) + "\n" + hint
+}
+
+// renderGotoBar renders the goto prompt for the header ("" while closed)
+func (m model) renderGotoBar() string {
+	if m.gotoInput == nil {
+		return ""
+	}
+	return m.gotoInput.View() + "  " + i18n.T("goto.help") + "\n"
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/nats-io/nats.go"
+)
+
+// historyOptions selects what a live monitor replays from JetStream on startup
+type historyOptions struct {
+	ephemeral bool      // Plain subscription: only events published after connecting
+	last      int       // Replay the last N events
+	since     time.Time // Replay events since this time instead, when set
+}
+
+// historySource returns the source replaying history before live events
+// Returns nil in ephemeral mode
+func (h historyOptions) historySource(nc *nats.Conn, subject string) *monitor.HistorySource {
+	if h.ephemeral {
+		return nil
+	}
+	js, err := nc.JetStream(nats.MaxWait(2 * time.Second))
+	if err != nil {
+		return nil
+	}
+	return &monitor.HistorySource{JS: js, Subject: subject, Last: h.last, Since: h.since}
+}
+
+// historyEventMsg is an event replayed from the stream on startup
+type historyEventMsg events.Event
+
+// receivedMsg wraps a decoded event, telling replayed history from live events
+func receivedMsg(msg monitor.Message, event events.Event) tea.Msg {
+	if msg.Source == (&monitor.HistorySource{}).Name() {
+		return historyEventMsg(event)
+	}
+	return eventReceivedMsg(event)
+}
+
+// handleHistoryEvent shows a replayed event for context
+// AIDEV-NOTE: History doesn't block, run hooks or raise stop banners - its
+// decisions were usually answered long ago, and the answers only come later in
+// the replay. Responses still settle the events they answer, so decisions
+// answered while the monitor was away show as responded
+func (m model) handleHistoryEvent(msg historyEventMsg) (tea.Model, tea.Cmd) {
+	m.listening = false
+	if m.dedup != nil && m.dedup.Seen(msg.IdempotencyKey) {
+		return m, m.resumeListening()
+	}
+
+	event, keep := m.prepareEvent(events.Event(msg))
+	if !keep {
+		return m, m.resumeListening()
+	}
+	if m.routeEvent(event) == nil {
+		return m, m.resumeListening()
+	}
+	if answered, ok := event.AnsweredEventID(); ok {
+		m.settleAnsweredElsewhere(answered)
+	}
+	m.historyReplayed++
+	return m, m.resumeListening()
+}
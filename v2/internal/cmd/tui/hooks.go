@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/hooks"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// respondedViaHook is the response Data value of "responded_via" for actions a hook chose
+const respondedViaHook = "hook"
+
+// hookQueue bounds the events waiting for the hooks; when it's full the
+// event stream pauses until they catch up
+const hookQueue = 256
+
+// hookedEventMsg is a live event back from the --hooks scripts
+type hookedEventMsg struct {
+	event  events.Event // With the fields hooks derived
+	result hooks.Result
+	err    error
+}
+
+// hookWorker runs the hooks on live events one at a time, in arrival order
+// AIDEV-NOTE: Hooks are scripts with a timeout each, far too slow for the
+// update loop: a burst of events would freeze the screen. Events are handed
+// over and come back as hookedEventMsg, so the stream keeps flowing while a
+// hook runs (and events already handed over still arrive while an input is
+// open). Hooks only see live events: replaying a session must not repeat
+// their automatic responses and alerts
+type hookWorker struct {
+	in  chan events.Event
+	out chan hookedEventMsg
+}
+
+// startHookWorker starts running the hooks in runner on the events handed over
+func startHookWorker(runner *hooks.Runner) *hookWorker {
+	w := &hookWorker{in: make(chan events.Event, hookQueue), out: make(chan hookedEventMsg)}
+	go func() {
+		for event := range w.in {
+			hooked, result, err := runner.Run(event)
+			w.out <- hookedEventMsg{event: hooked, result: result, err: err}
+		}
+	}()
+	return w
+}
+
+// waitForHooked waits for the next event back from the hooks
+func waitForHooked(w *hookWorker) tea.Cmd {
+	return func() tea.Msg {
+		return <-w.out
+	}
+}
+
+// sendToHooks hands a live event to the hooks and resumes listening
+// With the queue full the event is held back and the stream paused until
+// the hooks catch up (see hookedEvent)
+func (m *model) sendToHooks(event events.Event) tea.Cmd {
+	select {
+	case m.hookWorker.in <- event:
+		return m.resumeListening()
+	default:
+		m.hookStalled = &event
+		m.status = i18n.T("hook.behind", hookQueue)
+		return nil
+	}
+}
+
+// hookedEvent handles an event back from the hooks: the rest of the
+// pipeline runs on it as it would have without hooks
+func (m model) hookedEvent(msg hookedEventMsg) (tea.Model, tea.Cmd) {
+	cmds := []tea.Cmd{waitForHooked(m.hookWorker)}
+	if m.hookStalled != nil {
+		select {
+		case m.hookWorker.in <- *m.hookStalled:
+			m.hookStalled = nil
+			cmds = append(cmds, m.resumeListening())
+		default:
+		}
+	}
+
+	switch {
+	case msg.err != nil:
+		m.status = i18n.T("hook.failed", msg.event.Type, msg.err)
+	case msg.result.Alert != "":
+		m.status = "⚑ " + msg.result.Alert
+	}
+	if msg.result.Drop {
+		m.metrics.countDrop(dropFiltered)
+		return m, tea.Batch(cmds...)
+	}
+	next, cmd := m.handleEvent(msg.event, msg.result.Respond)
+	return next, tea.Batch(append(cmds, cmd)...)
+}
+
+// autoRespond triggers the action a hook chose for the event just routed
+// Returns nil if the event has no such button action, leaving it to the operator
+// An event that isn't the active decision is answered without touching it
+func (m *model) autoRespond(event events.Event, actionID string) tea.Cmd {
+	if actionID == "" || m.transport == nil {
+		return nil
+	}
+	for _, action := range event.Actions {
+		if action.ID != actionID || action.InputType != "" {
+			continue
+		}
+		if action.RequiredApprovals() > 1 {
+			m.status = i18n.T("hook.needs_approval", action.Label, event.Type, action.RequiredApprovals())
+			return nil
+		}
+		m.status = i18n.T("hook.answered", event.Type, action.Label)
+		if event.ID == m.activeEventID() {
+			m.actionManager.ClearAll()
+			m.actionManager.MarkInFlight()
+		}
+		return publishActionResponseCmd(m.transport, m.outbox, m.durable, m.subject, event.ID, action.RespondedVia(respondedViaHook))
+	}
+	m.status = i18n.T("hook.unknown_action", actionID, event.Type)
+	return nil
+}
@@ -0,0 +1,238 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// AIDEV-NOTE: Every input type goes through input mode (inputMode/inputAction),
+// so drafts, snapshots, escalation and the decision queue treat them alike.
+// Line inputs are a one-row textarea rather than a textinput, keeping the
+// editor's undo and vim support; select and confirm inputs keep only the
+// highlighted option (inputChoice) and ignore the textarea
+
+// newInputTextarea creates the textarea for an input action
+// Line inputs get a single row, and Enter submits instead of breaking the line
+func (m model) newInputTextarea(action events.Action) textarea.Model {
+	ta := m.newTextarea()
+	if action.InputType == events.InputLine {
+		ta.SetHeight(1)
+		ta.KeyMap.InsertNewline.SetEnabled(false)
+	}
+	return ta
+}
+
+// savedInputText returns the value of an input as text, for drafts and snapshots:
+// the highlighted option of a select input, otherwise what was typed
+func savedInputText(action events.Action, ta textarea.Model, choice int) string {
+	if action.InputType == events.InputSelect {
+		if choice >= 0 && choice < len(action.Options) {
+			return action.Options[choice]
+		}
+		return ""
+	}
+	return ta.Value()
+}
+
+// inputChoiceOf returns the option a select input's saved text highlights
+func inputChoiceOf(action events.Action, text string) int {
+	if i := action.OptionIndex(text); i >= 0 {
+		return i
+	}
+	return 0
+}
+
+// submitInput publishes the open input's value in the response's Data[events.InputKey]
+func (m model) submitInput(value interface{}) (tea.Model, tea.Cmd) {
+	if m.inputAction == nil || m.transport == nil {
+		return m, nil
+	}
+	return m, publishInputResponseCmd(m.transport, m.outbox, m.durable, m.subject, *m.inputAction, events.InputKey, value)
+}
+
+// updateChoiceInput handles keys of select and confirm inputs
+// Select: up/down move, Enter submits the highlighted option, 1-9 submit an option directly
+// Confirm: y submits true, n submits false
+func (m model) updateChoiceInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := m.inputAction
+	key := msg.String()
+
+	if action.InputType == events.InputConfirm {
+		switch key {
+		case "y", "Y":
+			return m.submitInput(true)
+		case "n", "N":
+			return m.submitInput(false)
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "up", "k":
+		if m.inputChoice > 0 {
+			m.inputChoice--
+		}
+	case "down", "j":
+		if m.inputChoice < len(action.Options)-1 {
+			m.inputChoice++
+		}
+	case "home", "g":
+		m.inputChoice = 0
+	case "end", "G":
+		m.inputChoice = max(len(action.Options)-1, 0)
+	case "enter":
+		if m.inputChoice < len(action.Options) {
+			return m.submitInput(action.Options[m.inputChoice])
+		}
+	default:
+		if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= len(action.Options) && n <= 9 {
+			m.inputChoice = n - 1
+			return m.submitInput(action.Options[n-1])
+		}
+	}
+	return m, nil
+}
+
+// inputWindow is the part of a growing textarea shown in the payload pane
+type inputWindow struct {
+	offset int // First row shown
+	shown  int // Rows shown
+	total  int // Rows of the text, soft-wrapped
+}
+
+// growsInput reports whether the open input is a textarea growing with its text
+// Line inputs keep their single row, and choice inputs don't show the textarea
+func (m model) growsInput() bool {
+	return m.inputMode && (m.inputAction == nil || m.inputAction.InputType == events.InputMultiline)
+}
+
+// AIDEV-NOTE: A growing textarea is kept taller than its text, so it never
+// scrolls itself and View renders every row; fitTextarea picks the rows shown
+// (inputWindow), following the cursor, and inputView cuts them out. Anything
+// adding more than a row at once (pastes) must grow it first
+
+// fitTextarea sizes the textarea to its text, up to the rows the payload pane
+// leaves under the prompt, and scrolls the rows shown to the cursor
+func (m *model) fitTextarea() {
+	if !m.growsInput() {
+		return
+	}
+	width, height := m.width, m.height
+	if width == 0 {
+		width = 120
+	}
+	if height == 0 {
+		height = 30
+	}
+	if w := (width-8)/2 - 2; m.textarea.Width() != w {
+		m.textarea.SetWidth(w)
+	}
+
+	// One row goes to the scroll indicator
+	selected := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex)
+	rows := max(1, tui.InputRows(selected, width, height-9)-1)
+	total, cursor := textareaRows(m.textarea)
+	m.textarea.SetHeight(total + rows)
+
+	win := &m.inputWindow
+	win.total, win.shown = total, min(total, rows)
+	if cursor < win.offset {
+		win.offset = cursor
+	}
+	if cursor >= win.offset+win.shown {
+		win.offset = cursor - win.shown + 1
+	}
+	win.offset = max(0, min(win.offset, total-win.shown))
+}
+
+// textareaRows counts the soft-wrapped rows of a textarea's text, and the row holding the cursor
+func textareaRows(ta textarea.Model) (total, cursor int) {
+	// A scratch textarea of the same width wraps each line exactly as ta does
+	scratch := textarea.New()
+	scratch.ShowLineNumbers = false
+	scratch.Prompt = ""
+	scratch.CharLimit = 0
+	scratch.MaxHeight = 0
+	scratch.SetWidth(ta.Width())
+	for i, line := range strings.Split(ta.Value(), "\n") {
+		scratch.SetValue(line)
+		rows := scratch.LineInfo().Height
+		if i < ta.Line() {
+			cursor += rows
+		}
+		total += rows
+	}
+	return total, cursor + ta.LineInfo().RowOffset
+}
+
+// textareaView renders the rows of the textarea fitTextarea chose, with a
+// scroll indicator when the text overflows them
+func (m model) textareaView() string {
+	win := m.inputWindow
+	if !m.growsInput() || win.shown == 0 {
+		return m.textarea.View()
+	}
+	lines := strings.Split(m.textarea.View(), "\n")
+	end := min(win.offset+win.shown, len(lines))
+	view := strings.Join(lines[min(win.offset, end):end], "\n")
+	if win.total <= win.shown {
+		return view
+	}
+	above, below := win.offset, win.total-win.offset-win.shown
+	return view + "\n" + lipgloss.NewStyle().
+		Foreground(lipgloss.Color("243")).
+		Render(i18n.T("input.more_lines", above, below))
+}
+
+// inputView renders the open input's widget for the payload pane
+func (m model) inputView() string {
+	if m.inputAction == nil {
+		return m.textareaView()
+	}
+
+	switch m.inputAction.InputType {
+	case events.InputSelect:
+		var b strings.Builder
+		for i, option := range m.inputAction.Options {
+			cursor := "  "
+			style := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+			if i == m.inputChoice {
+				cursor = "> "
+				style = style.Bold(true).Foreground(lipgloss.Color("62"))
+			}
+			number := " "
+			if i < 9 {
+				number = strconv.Itoa(i + 1)
+			}
+			b.WriteString(style.Render(fmt.Sprintf("%s%s %s", cursor, number, option)))
+			b.WriteString("\n")
+		}
+		return b.String()
+
+	case events.InputConfirm:
+		return lipgloss.NewStyle().Bold(true).Render(m.inputAction.Label) + "\n\n" +
+			lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render(i18n.T("input.yes_no"))
+	}
+	return m.textareaView()
+}
+
+// inputInstructions returns the key help of an input type
+func inputInstructions(action *events.Action) string {
+	switch action.InputType {
+	case events.InputSelect:
+		return i18n.T("input.select")
+	case events.InputConfirm:
+		return i18n.T("input.confirm")
+	case events.InputLine:
+		return i18n.T("input.line")
+	}
+	return i18n.T("input.multiline")
+}
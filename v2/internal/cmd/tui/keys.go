@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// keyBindings are the normal-mode commands the settings file can remap
+// ("keys"), by name, with their default keys
+// Arrows, enter, tab, esc and ctrl+c keep working whatever is remapped
+var keyBindings = map[string]string{
+	"quit":          "q",
+	"up":            "k",
+	"down":          "j",
+	"pending":       "P",
+	"load":          "L",
+	"state_filter":  "s",
+	"severity":      "S",
+	"bookmark":      "b",
+	"next_bookmark": "'",
+	"jump_back":     "ctrl+o",
+	"settings":      ",",
+	"chat":          "C",
+	"chat_compose":  "@",
+	"next_pane":     "]",
+	"prev_pane":     "[",
+	"tree":          "J",
+	"raw":           "M",
+	"zoom_in":       "+",
+	"zoom_out":      "-",
+	"search":        "/",
+	"goto":          ":",
+	"next_match":    "n",
+	"prev_match":    "N",
+	"lanes":         "m",
+	"stop":          "X",
+	"type_help":     "?",
+	"usage":         "$",
+	"types":         "T",
+	"audit":         "A",
+	"visual":        "v",
+	"copy":          "y",
+	"open_link":     "o",
+}
+
+// keymap translates pressed keys to the default keys of the commands they're
+// bound to; "" marks a default key freed by a remap
+// The zero keymap leaves every key as it is
+type keymap map[string]string
+
+// newKeymap builds the keymap for remapped keys (command name → key)
+// A command may be unbound with "", and keys may be swapped, but a key can't
+// be bound to two commands or take over another built-in key
+func newKeymap(custom map[string]string) (keymap, error) {
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		if _, ok := keyBindings[name]; !ok {
+			return nil, fmt.Errorf("unknown command %q (known: %s)", name, strings.Join(keyBindingNames(), ", "))
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Free the default keys of remapped commands first, so keys can be swapped
+	km := make(keymap)
+	for _, name := range names {
+		if def := keyBindings[name]; custom[name] != def {
+			km[def] = ""
+		}
+	}
+	bound := make(map[string]string)
+	for _, name := range names {
+		key, def := custom[name], keyBindings[name]
+		if key == "" || key == def {
+			continue
+		}
+		if other, ok := bound[key]; ok {
+			return nil, fmt.Errorf("key %q is bound to both %s and %s", key, other, name)
+		}
+		if freed, ok := km[key]; reservedKeys[key] && !(ok && freed == "") {
+			return nil, fmt.Errorf("%s: key %q is a built-in shortcut", name, key)
+		}
+		bound[key] = name
+		km[key] = def
+	}
+	return km, nil
+}
+
+// keyBindingNames returns the remappable command names in sorted order
+func keyBindingNames() []string {
+	names := make([]string, 0, len(keyBindings))
+	for name := range keyBindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolve returns the default key of the command a pressed key is bound to,
+// the key itself if it isn't remapped, or "" if a remap freed it
+func (k keymap) resolve(key string) string {
+	if def, ok := k[key]; ok {
+		return def
+	}
+	return key
+}
+
+// reserved reports whether a key runs a built-in command, so quick publish
+// keys can't take it over
+func (k keymap) reserved(key string) bool {
+	if def, ok := k[key]; ok {
+		return def != ""
+	}
+	return reservedKeys[key]
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/monitor"
+)
+
+// lagCheckInterval is how often the stream consumer's lag is queried
+const lagCheckInterval = 2 * time.Second
+
+// lagState tracks how far the monitor is behind its JetStream stream
+type lagState struct {
+	reporter  monitor.LagReporter // nil without a stream (plain subscription, replay)
+	max       uint64              // Warn above this many events behind (--max-lag)
+	last      monitor.Lag
+	err       error
+	checking  bool
+	checkedAt time.Time
+}
+
+// lagMsg carries the result of a lag query
+type lagMsg struct {
+	lag monitor.Lag
+	err error
+}
+
+// checkLag returns a command querying the consumer's lag, at most every lagCheckInterval
+func (m *model) checkLag() tea.Cmd {
+	r := m.lag.reporter
+	if r == nil || m.lag.checking || time.Since(m.lag.checkedAt) < lagCheckInterval {
+		return nil
+	}
+	m.lag.checking = true
+	return func() tea.Msg {
+		lag, err := r.Lag()
+		return lagMsg{lag: lag, err: err}
+	}
+}
+
+// noteLag records a lag query result
+func (m *model) noteLag(msg lagMsg) {
+	m.lag.checking = false
+	m.lag.checkedAt = time.Now()
+	m.lag.last, m.lag.err = msg.lag, msg.err
+}
+
+// renderLag renders the lag line for the header ("" while caught up)
+// Events waiting on the bus are counted separately in the header
+func (m model) renderLag() string {
+	switch {
+	case m.lag.reporter == nil:
+		return ""
+	case m.lag.err != nil:
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color("243")).
+			Render(i18n.T("lag.unknown", m.lag.err)) + "\n"
+	}
+
+	behind := m.lag.last.Behind()
+	switch {
+	case behind == 0:
+		return ""
+	case m.lag.max > 0 && behind > m.lag.max:
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Bold(true).
+			Render(i18n.T("lag.behind", behind, m.lag.last.Pending, m.lag.last.AckPending)) + "\n"
+	}
+	return i18n.T("lag.line", m.lag.last.Pending, m.lag.last.AckPending)
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// typeLegend is the type legend state: the event types seen so far, with
+// their counts, which can be hidden from the list one by one
+// AIDEV-NOTE: Types and counts are read from the panes at every render, so
+// the legend stays current while events arrive; hidden types live in
+// m.hiddenTypes and last for the session (mutes are the persistent kind)
+type typeLegend struct {
+	cursor int
+}
+
+// openTypeLegend shows the type legend
+func (m *model) openTypeLegend() {
+	if len(m.paneManager.TypeCounts()) == 0 {
+		m.status = i18n.T("legend.no_events")
+		return
+	}
+	m.legend = &typeLegend{}
+}
+
+// updateTypeLegend handles keys in the type legend
+func (m model) updateTypeLegend(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	types := m.paneManager.TypeCounts()
+	l := m.legend
+	l.cursor = max(0, min(l.cursor, len(types)-1))
+	switch msg.String() {
+	case "ctrl+c":
+		return m.requestQuit()
+	case "esc", "q", "T":
+		m.legend = nil
+	case "up", "k":
+		if l.cursor > 0 {
+			l.cursor--
+		}
+	case "down", "j":
+		if l.cursor < len(types)-1 {
+			l.cursor++
+		}
+	case "home", "g":
+		l.cursor = 0
+	case "end", "G":
+		l.cursor = len(types) - 1
+	case " ", "enter", "x":
+		// Hide or show the type under the cursor
+		typ := types[l.cursor].Type
+		if m.hiddenTypes[typ] {
+			delete(m.hiddenTypes, typ)
+		} else {
+			m.hiddenTypes[typ] = true
+		}
+	case "o":
+		// Only show the type under the cursor
+		clear(m.hiddenTypes)
+		for i, tc := range types {
+			if i != l.cursor {
+				m.hiddenTypes[tc.Type] = true
+			}
+		}
+	case "a":
+		// Show every type again
+		clear(m.hiddenTypes)
+	}
+	m.moveSelection(0)
+	return m, nil
+}
+
+// renderTypeLegend renders the event types with their counts around the cursor
+func (m model) renderTypeLegend(width, height int) string {
+	types := m.paneManager.TypeCounts()
+	filter := m.listFilter()
+	cursor := max(0, min(m.legend.cursor, len(types)-1))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(tui.Colors().Muted))
+
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(tui.Colors().Title)).Render(i18n.T("legend.title")))
+	content.WriteString("  ")
+	content.WriteString(dim.Render(i18n.T("legend.count", len(types), len(m.hiddenTypes))))
+	content.WriteString("\n\n")
+
+	// Keep the cursor in view
+	rows := max(1, height-6)
+	start := 0
+	if cursor >= rows {
+		start = cursor - rows + 1
+	}
+	end := min(start+rows, len(types))
+
+	countWidth := 0
+	for _, tc := range types {
+		countWidth = max(countWidth, len(fmt.Sprint(tc.Count)))
+	}
+	for i := start; i < end; i++ {
+		tc := types[i]
+		pointer := "  "
+		if i == cursor {
+			pointer = "> "
+		}
+		box, note := "[x]", ""
+		switch {
+		case filter.Muted(tc.Type):
+			box, note = "[-]", " "+i18n.T("legend.muted")
+		case m.hiddenTypes[tc.Type]:
+			box = "[ ]"
+		}
+		line := fmt.Sprintf("%s%s %*d  %s", pointer, box, countWidth, tc.Count, tc.Type)
+		line = tui.Truncate(line+note, width-8)
+		if box != "[x]" {
+			line = dim.Render(line)
+		}
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color(tui.Colors().Text)).
+		Render(i18n.T("legend.help")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(tui.Colors().Border)).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
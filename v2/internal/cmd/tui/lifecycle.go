@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// markSelectedSeen records that the operator has looked at the selected event
+func (m *model) markSelectedSeen() {
+	if event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex); event != nil {
+		m.lifecycles.Set(event.ID, tui.LifecycleSeen)
+	}
+}
+
+// setBlockingLifecycle records the outcome of the blocking event's actions
+func (m *model) setBlockingLifecycle(state tui.Lifecycle) {
+	if event := m.blockingEvent(); event != nil {
+		m.lifecycles.Set(event.ID, state)
+	}
+}
+
+// cycleStateFilter steps the list through all → new → seen → responded → expired
+func (m *model) cycleStateFilter() {
+	next := tui.LifecycleStates[0]
+	for i, state := range tui.LifecycleStates {
+		if state == m.stateFilter {
+			if i+1 < len(tui.LifecycleStates) {
+				next = tui.LifecycleStates[i+1]
+			} else {
+				next = ""
+			}
+			break
+		}
+	}
+	m.stateFilter = next
+	m.moveSelection(0)
+
+	if next == "" {
+		m.status = i18n.T("lifecycle.all")
+	} else {
+		m.status = i18n.T("lifecycle.state", next)
+	}
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"os/exec"
+	"runtime"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// linkOpenedMsg is sent when the browser was launched (or failed to)
+type linkOpenedMsg struct{ status string }
+
+// openNextLink opens the selected event's next link in the browser
+// Repeated presses on the same event cycle through its links
+func (m *model) openNextLink() tea.Cmd {
+	event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex)
+	if event == nil {
+		return nil
+	}
+	links := tui.EventLinks(*event)
+	if len(links) == 0 {
+		m.status = i18n.T("link.none")
+		return nil
+	}
+
+	if m.linkEventID != event.ID || m.linkNext >= len(links) {
+		m.linkEventID = event.ID
+		m.linkNext = 0
+	}
+	n := m.linkNext
+	m.linkNext++
+
+	return openURLCmd(links[n], n+1, len(links))
+}
+
+// openURLCmd launches the platform's URL opener
+func openURLCmd(url string, n, total int) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			cmd = exec.Command("xdg-open", url)
+		}
+		if err := cmd.Start(); err != nil {
+			return linkOpenedMsg{status: i18n.T("link.failed", url, err)}
+		}
+		go cmd.Wait() // Reap the opener; the browser outlives it
+
+		status := i18n.T("link.opened", url)
+		if total > 1 {
+			status = i18n.T("link.opened_nth", n, total, url)
+		}
+		return linkOpenedMsg{status: status}
+	}
+}
@@ -0,0 +1,2031 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/archive"
+	"github.com/durch/agneto/v2/pkg/config"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/export"
+	"github.com/durch/agneto/v2/pkg/hooks"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/metrics"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/durch/agneto/v2/pkg/policy"
+	"github.com/durch/agneto/v2/pkg/transform"
+	"github.com/durch/agneto/v2/pkg/transport"
+	"github.com/durch/agneto/v2/pkg/tui"
+	"github.com/nats-io/nats.go"
+)
+
+// eventReceivedMsg is sent when we receive an event from NATS
+type eventReceivedMsg events.Event
+
+// actionExecutedMsg is sent when an action response is queued (and, unless deferred, published)
+type actionExecutedMsg struct {
+	eventID  string // Event the action answered
+	action   events.Action
+	deferred error // Publish failed; the response waits in the outbox
+}
+
+// inputSubmittedMsg is sent when input is queued (and, unless deferred, published)
+type inputSubmittedMsg struct {
+	action   events.Action
+	input    interface{} // The value submitted
+	deferred error       // Publish failed; the response waits in the outbox
+}
+
+// defaultSubject carries events and responses unless a workspace or --subject picks another
+const defaultSubject = "test.events"
+
+// errMsg is sent when an error occurs
+type errMsg struct{ err error }
+
+func (e errMsg) Error() string { return e.err.Error() }
+
+// model holds the TUI state
+type model struct {
+	nc        *nats.Conn          // Only for NATS-only features: JetStream history and edge mode
+	transport transport.Transport // Messaging over nc; everything else gates on and goes through it
+
+	conn               connectionState    // NATS connection state through disconnects
+	durable            outbox.Publisher   // Where outbox entries are delivered: the transport, or the edge stream
+	bus                *monitor.Bus       // Orders events from every source
+	eventChan          monitor.ChanSink   // Bus sink the TUI reads events from
+	archive            *archive.Sink      // Records received events to rolling segments (--archive), nil when off
+	stopSources        []func()           // Stops each started source
+	tailFile           string             // JSON Lines file followed alongside NATS (--tail)
+	history            historyOptions     // What to replay from JetStream on startup
+	lag                lagState           // How far behind the stream the monitor is (JetStream only)
+	payloadScroll      *tui.PayloadScroll // Payload pane scroll position, shared with rendering
+	payloadFold        *tui.PayloadFold   // Payload tree folds and cursor, shared with rendering
+	rawContent         bool               // Show Content as written instead of as rendered markdown
+	zoom               tui.Zoom           // How much of each event list rows show (+/-)
+	lanes              tui.Lanes          // How rows are attributed to their producer (m)
+	historyReplayed    int                // Events replayed from history so far
+	dedup              *monitor.Dedup     // Recent idempotency keys, to drop retried publishes
+	quota              *monitor.Limiter   // Per-producer rate limits (--quota), nil when off
+	presence           *presenceState     // Cursors of other operators (--presence), nil when off
+	chat               *chatState         // Operator chat (--chat), nil when off
+	paneManager        *tui.PaneManager
+	transform          *transform.Pipeline // Optional mutators applied before routing
+	hookWorker         *hookWorker         // Runs the --hooks scripts on live events (nil without hooks)
+	hookStalled        *events.Event       // Event held back while the hooks' queue is full
+	policy             *policy.Policy      // Optional auto-respond rules for live decisions (--policy)
+	policyLog          *policy.Log         // Where policy decisions are recorded (--policy-log)
+	subject            string              // Subject events are read from and responses published to
+	workspace          string              // Named workspace whose settings are in use ("" for the settings file)
+	operator           string              // Operator name put on published cancels and presence
+	stopBanner         *events.Event       // Latest task.cancel / task.abort, shown until dismissed
+	cancelArmedFor     string              // Event whose task X was pressed for once
+	cancelArmedAt      time.Time           // When X was pressed the first time
+	actionManager      *tui.ActionManager
+	err                error
+	initialized        bool
+	width              int
+	height             int
+	selectedEventIndex int                         // Index of selected event in the active pane (for payload viewer)
+	blockingEventIndex *int                        // If non-nil, event index waiting for action (the active decision)
+	blockingPane       string                      // Pane holding the blocking event
+	blockingSince      time.Time                   // When the blocking event started waiting
+	consumedActions    map[string]bool             // Track which events have had actions consumed (one-shot), by ID
+	drafts             map[string]*inputDraft      // Input requests set aside with tab, by event ID
+	keptInputs         map[string]config.KeptInput // Text of inputs left unsubmitted, by request correlation and action ID
+	keptInputsPath     string                      // Where kept inputs are saved ("" in replays)
+	inputMode          bool                        // If true, right pane shows textarea for input
+	inputAction        *events.Action              // The action that triggered input mode
+	inputChoice        int                         // Highlighted option of a select input
+	textarea           textarea.Model              // Textarea component for multiline input
+	inputWindow        inputWindow                 // Textarea rows shown in the payload pane
+	editor             editor                      // Undo/redo, kill ring and vim mode for the textarea
+	instance           string                      // Instance name used for the control subject
+	exportDir          string                      // Directory the export control command writes into ("": export disabled)
+	controlSub         transport.Subscription
+	controlChan        chan transport.Msg         // Channel for receiving control commands
+	activePane         string                     // Pane shown in the event list (change with focusPane)
+	closedPanesDir     string                     // Where closed ephemeral panes' events are archived
+	paneDirectory      *paneDirectory             // Pane names answered to publishers' discovery requests
+	paneLeftAt         map[string]time.Time       // When each pane last lost focus
+	newSince           time.Time                  // Events after this are marked new in the active pane
+	filter             string                     // Event list filter (empty shows all)
+	search             *tui.Search                // The / search over the list (nil: none)
+	watches            *tui.Watches               // Watch expressions pinned above the list (nil: none)
+	searchBar          *searchBar                 // The / bar while it is open
+	gotoInput          *textinput.Model           // The goto prompt (: or g) while it is open
+	archived           *archivedEvent             // Event found in an archive by goto, shown full screen
+	sessionPicker      *sessionPicker             // Startup screen picking a session (--sessions), nil once attached
+	status             string                     // Last status message (e.g. control command result)
+	listening          bool                       // True while a waitForEvent command is outstanding
+	batching           bool                       // True while an event batch is being handled
+	backlog            eventBatchMsg              // Rest of a batch cut short by a blocking event
+	escalations        map[string]escalationState // Escalated events by ID
+	deadlines          map[string]time.Time       // When events with a timeout take their default action, by ID
+	approvals          map[string]*approvalState  // Approvals of four-eyes actions so far, by event ID
+	approvalMsgs       chan transport.Msg         // Approvals announced on events.ApprovalsSubject
+	approvalSecret     []byte                     // Signs and checks approvals (nil: unsigned, see events.ApprovalSecretEnv)
+	strict             bool                       // Reject payloads with unknown or missing fields
+	schemaViolations   int                        // Payloads rejected by strict mode
+	outbox             *outbox.Outbox             // Durable queue for everything the operator publishes
+	outboxQueued       int                        // Entries waiting in the outbox
+	flushingOutbox     bool                       // True while an outbox retry is running
+	bookmarks          []config.Bookmark          // Bookmarked events, oldest first
+	bookmarksPath      string                     // Where bookmarks are saved
+	jumps              []string                   // Jump list of event IDs (ctrl+o / tab)
+	jumpPos            int                        // Position in the jump list
+	loadedContent      map[string]bool            // Events whose full Content was loaded (L)
+	linkEventID        string                     // Event whose links o is cycling through
+	linkNext           int                        // Index of the link o opens next
+	replay             *replayState               // Recorded session being scrubbed (--from-file), nil when live
+	lifecycles         tui.Lifecycles             // Lifecycle state of events by ID
+	stateFilter        tui.Lifecycle              // Only list events in this state (empty lists all)
+	minSeverity        string                     // Only list events at least this severe (empty lists all)
+	visualMode         bool                       // If true, j/k extend a range selection for yanking
+	visualAnchor       int                        // Event index where visual selection started
+	mutes              []string                   // Event type globs hidden from the list
+	hiddenTypes        tui.TypeSet                // Event types hidden with the type legend (T), for the session
+	legend             *typeLegend                // Type legend state (nil when closed)
+	audit              *tui.AuditLog              // Actions taken: who answered what, when
+	auditView          *auditView                 // Audit panel state (nil when closed)
+	config             *config.Config             // Persistent settings (edited from the settings screen)
+	configPath         string                     // Where config is saved
+	keys               keymap                     // Remapped built-in keys from the settings file
+	settingsOpen       bool                       // If true, the settings screen replaces the split layout
+	typeHelpOpen       bool                       // If true, the selected event's type help replaces the split layout
+	usage              *tui.UsageTotals           // Tokens, cost and duration events reported, per session and producer
+	metrics            *monitorMetrics            // Prometheus metrics (nil without --metrics-addr)
+	notifier           *notifier                  // Notifications of decisions (nil without --notify)
+	usageOpen          bool                       // If true, the usage totals replace the split layout
+	tree               *payloadTree               // JSON tree viewer of the selected event's payload, nil when closed
+	quitConfirmOpen    bool                       // If true, quitting waits for confirmation of outstanding work
+	reasons            *reasonPicker              // Reason codes being picked for an action, nil when closed
+	quitUnsent         []outbox.Entry             // Outbox entries when the quit confirmation opened
+	snapshot           *snapshotState             // Crash-recovery snapshot, nil when disabled
+	restoreOffer       *config.Snapshot           // Snapshot of a crashed session awaiting restore or discard
+	types              tui.TypeRegistry           // Event type documentation (settings file + built-ins)
+	settingsCursor     int                        // Selected settings field
+	settingsEditing    bool                       // If true, the selected field is being edited
+	settingsInput      textinput.Model
+	settingsSaveAs     bool // The input names a workspace to save the settings as
+}
+
+// Init is called when the program starts
+func (m model) Init() tea.Cmd {
+	// Replays are offline
+	if m.replay != nil {
+		return tea.Batch(tickCmd(), m.replayNext())
+	}
+	cmds := []tea.Cmd{connectToNATS(m.conn.events), tickCmd()}
+	if m.hookWorker != nil {
+		cmds = append(cmds, waitForHooked(m.hookWorker))
+	}
+	return tea.Batch(cmds...)
+}
+
+// connectToNATS connects to NATS, reporting later state changes on connEvents
+func connectToNATS(connEvents chan connEventMsg) tea.Cmd {
+	return func() tea.Msg {
+		// Connect to NATS (URL, credentials and TLS from environment)
+		settings := natsconn.Load()
+		nc, err := settings.Connect("agneto-tui", connOptions(connEvents)...)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		// Edge mode: responses are delivered once the local stream stored them
+		var edge outbox.Publisher
+		if settings.EdgeStream != "" {
+			if edge, err = settings.Publisher(nc); err != nil {
+				nc.Close()
+				return errMsg{err}
+			}
+		}
+
+		return natsConnectedMsg{nc: nc, edge: edge}
+	}
+}
+
+// natsConnectedMsg is sent when NATS connection is established
+type natsConnectedMsg struct {
+	nc   *nats.Conn
+	edge outbox.Publisher // The local stream in edge mode, else nil
+}
+
+// subscribeToEvents starts feeding the events subject into the bus
+// In edge mode events come through a durable consumer on the local stream;
+// otherwise recent history is replayed from JetStream unless ephemeral, falling
+// back to a plain subscription when the server has no JetStream
+func subscribeToEvents(nc *nats.Conn, bus *monitor.Bus, subject, instance string, history historyOptions) tea.Cmd {
+	return func() tea.Msg {
+		if stream := natsconn.Load().EdgeStream; stream != "" {
+			js, err := nc.JetStream()
+			if err != nil {
+				return errMsg{err}
+			}
+			source := &monitor.JetStreamSource{JS: js, Stream: stream, Subject: subject, Durable: "agneto-tui-" + instance}
+			stop, err := source.Start(bus)
+			if err != nil {
+				return errMsg{err}
+			}
+			return subscriptionReadyMsg{stop: stop, lag: source}
+		}
+
+		var note string
+		if source := history.historySource(nc, subject); source != nil {
+			stop, err := source.Start(bus)
+			if err == nil {
+				return subscriptionReadyMsg{stop: stop, lag: source}
+			}
+			note = i18n.T("status.history_unavailable", err)
+		}
+		stop, err := monitor.NATSSource{Conn: nc, Subject: subject}.Start(bus)
+		if err != nil {
+			return errMsg{err}
+		}
+		return subscriptionReadyMsg{stop: stop, note: note}
+	}
+}
+
+// subscriptionReadyMsg is sent when subscription is ready
+type subscriptionReadyMsg struct {
+	stop func()              // Stops the NATS source
+	note string              // Shown in the status bar (e.g. why history isn't replayed)
+	lag  monitor.LagReporter // Stream consumer lag, nil for a plain subscription
+}
+
+// schemaViolationMsg is sent in strict mode for a payload that breaks the schema
+type schemaViolationMsg struct {
+	violation events.Event  // Event for the errors pane
+	event     *events.Event // Leniently decoded event, if the payload was usable
+}
+
+// maxEventBatch bounds how many queued events are handled in one update (one redraw)
+const maxEventBatch = 64
+
+// eventBatchMsg carries decoded events (eventReceivedMsg, schemaViolationMsg, errMsg) handled in one update
+type eventBatchMsg []tea.Msg
+
+// waitForEvent waits for the next message on the bus, then drains whatever
+// else is already queued (up to maxEventBatch) so a burst costs one redraw
+func waitForEvent(eventChan monitor.ChanSink, strict bool) tea.Cmd {
+	return func() tea.Msg {
+		batch := eventBatchMsg{decodeEvent(<-eventChan, strict)}
+		for len(batch) < maxEventBatch {
+			select {
+			case msg := <-eventChan:
+				batch = append(batch, decodeEvent(msg, strict))
+			default:
+				return batch
+			}
+		}
+		return batch
+	}
+}
+
+// decodeEvent turns a bus message into the message handling it
+// In strict mode, schema drift is reported instead of silently accepted
+func decodeEvent(msg monitor.Message, strict bool) tea.Msg {
+	if strict {
+		event, err := events.DecodeStrict(msg.Data)
+		if err != nil {
+			return schemaViolationMsg{
+				violation: events.NewSchemaViolation(msg.Subject, msg.Data, event, err),
+				event:     event,
+			}
+		}
+		return receivedMsg(msg, *event)
+	}
+
+	event, err := events.FromJSON(msg.Data)
+	if err != nil {
+		return errMsg{err}
+	}
+	return receivedMsg(msg, *event)
+}
+
+// Update handles messages and updates the model
+// Whatever ends up selected counts as seen
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if nm, ok := next.(model); ok {
+		nm.markSelectedSeen()
+		nm.fitTextarea()
+		return nm, cmd
+	}
+	return next, cmd
+}
+
+// newTextarea creates the input textarea sized to the payload pane
+func (m model) newTextarea() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "" // No placeholder (text is in header above)
+	ta.Focus()
+	ta.CharLimit = 0              // No limit
+	ta.ShowLineNumbers = false    // No line numbers
+	ta.Prompt = ""                // Remove prompt prefix
+
+	// Calculate textarea width to match pane content area
+	// Pane width = (termWidth - 8) / 2
+	// Usable width = pane width - 2 (to match separator line in layout.go:166)
+	paneWidth := (m.width - 8) / 2
+	textareaWidth := paneWidth - 2
+	ta.SetWidth(textareaWidth)
+	ta.MaxHeight = 0 // Grows with its text (see fitTextarea)
+	return ta
+}
+
+// update handles a single message
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// QUIT CONFIRMATION: Outstanding work was found on quit
+		if m.quitConfirmOpen {
+			return m.updateQuitConfirm(msg)
+		}
+
+		// SESSION PICKER: Nothing is listened to until a session is picked
+		if m.sessionPicker != nil {
+			return m.updateSessionPicker(msg)
+		}
+
+		// CRASH RECOVERY: Restore or discard the last session's pending decisions
+		if m.restoreOffer != nil {
+			return m.updateRestoreOffer(msg)
+		}
+
+		// REASON PICKER: Codes for the action just triggered
+		if m.reasons != nil {
+			return m.updateReasonPicker(msg)
+		}
+
+		// CHAT LINE: Typing a message to the other operators
+		if m.chat != nil && m.chat.composing {
+			return m.updateChatCompose(msg)
+		}
+
+		// SEARCH BAR: Typing a / search
+		if m.searchBar != nil {
+			return m.updateSearch(msg)
+		}
+
+		// GOTO PROMPT: Typing an event ID or short hash
+		if m.gotoInput != nil {
+			return m.updateGoto(msg)
+		}
+
+		// INPUT MODE: Handle textarea input
+		if m.inputMode {
+			keyStr := msg.String()
+
+			// Select and confirm inputs pick a value instead of typing one
+			if m.inputAction != nil && m.inputAction.IsChoiceInput() {
+				switch keyStr {
+				case "ctrl+c", "tab", "esc":
+					// Handled below, as for text inputs
+				default:
+					return m.updateChoiceInput(msg)
+				}
+			}
+
+			// Line inputs submit on Enter
+			lineSubmit := keyStr == "enter" && m.inputAction != nil && m.inputAction.InputType == events.InputLine
+
+			// Check for Alt+Enter (works cross-platform) or specific Ctrl combinations
+			// In Bubbletea, Ctrl+Enter is often sent as "ctrl+m" (Enter = Ctrl+M in ASCII)
+			if keyStr == "alt+enter" || keyStr == "ctrl+m" || lineSubmit ||
+			   (msg.Type == tea.KeyEnter && msg.Alt) {
+				// Submit input
+				if m.inputAction != nil && m.transport != nil {
+					inputText := m.textarea.Value()
+					if m.inputAction.InputType == events.InputLine {
+						inputText = strings.Join(strings.Fields(inputText), " ") // Pasted line breaks
+					}
+
+					// Answers outside the producer's length bounds aren't sent
+					if err := m.inputAction.CheckLength(inputText); err != nil {
+						m.status = i18n.T("status.submit_failed", err)
+						return m, nil
+					}
+
+					// Questions publish a typed answer instead of raw text
+					if question := m.blockingQuestion(); question != nil {
+						value, err := question.ParseAnswer(inputText)
+						if err != nil {
+							m.status = i18n.T("status.invalid_answer", err)
+							return m, nil
+						}
+						return m, publishInputResponseCmd(m.transport, m.outbox, m.durable, m.subject, *m.inputAction, "answer", value)
+					}
+
+					return m.submitInput(inputText)
+				}
+				return m, nil
+			}
+
+			switch keyStr {
+			case "ctrl+c":
+				// The input is unanswered, so this asks first (ctrl+c again quits)
+				return m.requestQuit()
+
+			case "tab":
+				// Set the input aside as a draft, opening the next draft if any
+				return m, m.switchDraft()
+
+			case "esc":
+				// Vim mode: Esc leaves insert mode first (there's none when picking)
+				if (m.inputAction == nil || !m.inputAction.IsChoiceInput()) && m.editor.Escape() {
+					return m, nil
+				}
+
+				// Cancel input mode - the request goes unanswered, but what was
+				// typed is kept for reopening it (enter) or the action asking again
+				if event := m.blockingEvent(); event != nil && m.inputAction != nil {
+					m.keepInput(*event, *m.inputAction, savedInputText(*m.inputAction, m.textarea, m.inputChoice))
+					if m.hasKeptInput(*event, *m.inputAction) {
+						m.status = i18n.T("status.input_cancelled")
+					}
+				}
+				m.setBlockingLifecycle(tui.LifecycleExpired)
+				m.inputMode = false
+				m.inputAction = nil
+				m.blockingEventIndex = nil
+				m.promoteQueued()
+				// Resume listening for events
+				return m, m.resumeListening()
+
+			default:
+				// Pass all other keys to the textarea (with undo and vim support)
+				if msg.Paste && m.growsInput() {
+					// Room for the pasted text, so the textarea doesn't scroll itself
+					m.textarea.SetHeight(m.textarea.Height() + len(msg.Runes))
+				}
+				return m, m.editor.Update(&m.textarea, msg)
+			}
+		}
+
+		// SETTINGS SCREEN: Edit routing, filters, mutes and theme
+		if m.settingsOpen {
+			return m.updateSettings(msg)
+		}
+
+		// TYPE HELP: Any key closes it
+		if m.typeHelpOpen {
+			return m.updateTypeHelp(msg)
+		}
+
+		// USAGE TOTALS: r resets them; any other key closes them
+		if m.usageOpen {
+			return m.updateUsage(msg)
+		}
+
+		// ARCHIVED EVENT: Scroll it; any other key closes it
+		if m.archived != nil {
+			return m.updateArchived(msg)
+		}
+
+		// PAYLOAD TREE: Browse the selected event's data and copy paths
+		if m.tree != nil {
+			return m.updatePayloadTree(msg)
+		}
+
+		// TYPE LEGEND: Hide and show event types
+		if m.legend != nil {
+			return m.updateTypeLegend(msg)
+		}
+
+		// AUDIT LOG: Browse and export the actions taken
+		if m.auditView != nil {
+			return m.updateAudit(msg)
+		}
+
+		// VISUAL MODE: Extend the range selection and yank it
+		if m.visualMode {
+			switch msg.String() {
+			case "ctrl+c":
+				return m.requestQuit()
+			case "up", "k":
+				m.moveSelection(-1)
+			case "down", "j":
+				m.moveSelection(1)
+			case "y":
+				m.visualMode = false
+				return m, yankEventsCmd(m.visualEvents(), yankJSON)
+			case "Y":
+				m.visualMode = false
+				return m, yankEventsCmd(m.visualEvents(), yankMarkdown)
+			case "esc", "v":
+				m.visualMode = false
+			}
+			return m, nil
+		}
+
+		// REPLAY: Playback keys
+		if m.replay != nil {
+			if cmd, ok := m.updateReplayPlayback(msg.String()); ok {
+				return m, cmd
+			}
+		}
+
+		// NORMAL MODE: Handle navigation and actions
+		// Built-in commands match on their default keys (see keys.go); action
+		// and quick publish keys on the key pressed
+		// Keys of the active decision's actions come first: producers pick
+		// them, so built-in commands added over time mustn't take them over
+		if !actionProofKeys[msg.String()] && m.actionManager.Bound(msg.String()) {
+			if cmd, ok := m.triggerAction(msg.String()); ok {
+				return m, cmd
+			}
+		}
+		key := m.keys.resolve(msg.String())
+
+		// Jump to the next or previous search match, only while searching
+		// (n answers "No" to yes/no questions)
+		if (key == "n" || key == "N") && m.search != nil {
+			if key == "n" {
+				m.jumpToMatch(1)
+			} else {
+				m.jumpToMatch(-1)
+			}
+			return m, nil
+		}
+
+		switch key {
+		case "q", "ctrl+c":
+			// Clean up, unless decisions or responses are outstanding
+			return m.requestQuit()
+
+		case "up", "k":
+			// Navigate up in event list
+			m.moveSelection(-1)
+
+		case "down", "j":
+			// Navigate down in event list
+			m.moveSelection(1)
+
+		case "enter":
+			// Continue a draft set aside with tab
+			if id := m.selectedEventID(); m.drafts[id] != nil {
+				return m, m.resumeDraft(id)
+			}
+
+			// Reopen an input cancelled with esc, with the text typed before
+			if cmd, ok := m.reopenInput(); ok {
+				return m, cmd
+			}
+
+			// Answer the pending question with its default, if it has one
+			if question := m.blockingQuestion(); question != nil && question.Default != nil && m.transport != nil {
+				for _, action := range m.actionManager.GetActiveActions() {
+					if fmt.Sprint(action.Event.Data["answer"]) == fmt.Sprint(question.Default) {
+						m.actionManager.ClearAll()
+						m.actionManager.MarkInFlight()
+						return m, publishActionResponseCmd(m.transport, m.outbox, m.durable, m.subject, m.activeEventID(), action)
+					}
+				}
+			}
+
+		case "P":
+			// Jump to the oldest pending decision
+			m.jumpToOldestPending()
+
+		case "left", "right", "shift+left", "shift+right", "home", "end":
+			// Scrub through a replayed session
+			if m.replay != nil {
+				steps := map[string]int{
+					"left": -1, "right": 1, "shift+left": -10, "shift+right": 10,
+					"home": -len(m.replay.events), "end": len(m.replay.events),
+				}
+				m.scrub(steps[key])
+			} else if key == "home" || key == "end" {
+				// Oldest or newest event in the list
+				m.scrollToEdge(key == "end")
+			} else if key == "left" {
+				// Fold the payload tree node under the cursor, or go to its parent
+				m.payloadFold.Collapse(m.treeEvent())
+			} else if key == "right" {
+				// Unfold the payload tree node under the cursor, or go into it
+				m.payloadFold.Expand(m.treeEvent())
+			}
+
+		case "shift+up", "shift+down":
+			// Move the payload tree cursor
+			step := 1
+			if key == "shift+up" {
+				step = -1
+			}
+			m.payloadFold.Move(m.treeEvent(), step)
+
+		case "ctrl+d", "ctrl+u":
+			// Scroll a long payload by half a pane
+			step := max(1, (m.pageSize()-3)/2)
+			if key == "ctrl+u" {
+				step = -step
+			}
+			m.payloadScroll.Offset = max(0, m.payloadScroll.Offset+step)
+
+		case "pgup":
+			// Page back through the list
+			m.moveSelection(-m.pageSize())
+
+		case "pgdown":
+			// Page forward through the list
+			m.moveSelection(m.pageSize())
+
+		case "L":
+			// Load the full Content of the selected event
+			m.loadFullContent()
+
+		case "s":
+			// Cycle the lifecycle state filter
+			m.cycleStateFilter()
+
+		case "S":
+			// Cycle the severity threshold
+			m.cycleMinSeverity()
+
+		case "b":
+			// Toggle a bookmark on the selected event
+			m.toggleBookmark()
+
+		case "'":
+			// Jump to the next bookmark
+			m.nextBookmark()
+
+		case "ctrl+o":
+			// Back in the jump list
+			m.jumpBack()
+
+		case "tab":
+			// Next pending decision if any are queued, else forward in the jump list
+			if !m.switchDecision() {
+				m.jumpForward()
+			}
+
+		case ",":
+			// Open the settings screen
+			m.openSettings()
+
+		case "C":
+			// Show the operator chat, or go back
+			m.toggleChat()
+
+		case "]", "[":
+			// List the next (or previous) pane
+			if key == "]" {
+				m.cyclePane(1)
+			} else {
+				m.cyclePane(-1)
+			}
+
+		case "@":
+			// Write to the other operators
+			return m, m.openChatCompose()
+
+		case "J":
+			// Browse the selected event's payload as a tree
+			m.openPayloadTree()
+
+		case "M":
+			// Switch Content between rendered markdown and the raw text, and
+			// payloads with a custom renderer to the default rendering
+			m.rawContent = !m.rawContent
+
+		case "+", "-":
+			// Trade list density for detail: compact, normal, detailed
+			if key == "+" {
+				m.zoom = m.zoom.In()
+			} else {
+				m.zoom = m.zoom.Out()
+			}
+			m.status = i18n.T("status.zoom", m.zoom)
+
+		case "/":
+			// Search the list by substring or regex
+			return m, m.openSearch()
+
+		case ":", "g":
+			// Jump to an event by ID or short hash, in the panes or the archives
+			return m, m.openGoto()
+
+		case "m":
+			// Attribute interleaved rows to their producers: off, colored tags, swim lanes
+			m.lanes = m.lanes.Next()
+			m.status = i18n.T("status.lanes", m.lanes)
+
+		case "X":
+			// Emergency stop for the selected event's task (press twice)
+			return m, m.requestCancel()
+
+		case "esc":
+			// Dismiss the task stop banner
+			m.stopBanner = nil
+
+		case "?":
+			// Explain the selected event's type
+			m.openTypeHelp()
+
+		case "$":
+			// Show what the producers' LLM calls cost so far
+			m.toggleUsage()
+
+		case "T":
+			// Hide or show event types across panes
+			m.openTypeLegend()
+
+		case "A":
+			// Show the actions taken: who answered what, when
+			m.openAudit()
+
+		case "v":
+			// Enter visual mode anchored at the selected event
+			m.visualMode = true
+			m.visualAnchor = m.selectedEventIndex
+
+		default:
+			// Check if key matches an active action
+			if cmd, ok := m.triggerAction(msg.String()); ok {
+				return m, cmd
+			}
+
+			// o opens links unless an action claimed it
+			if key == "o" {
+				return m, m.openNextLink()
+			}
+
+			// y copies the path and value under the payload tree's cursor, as in
+			// the tree viewer; c (and y without a tree) copies the whole payload
+			// Either only unless an action claimed it (yes/no questions answer with y)
+			if event := m.treeEvent(); key == "y" && event != nil {
+				return m, m.copyTreeNodeCmd(event)
+			}
+			if key == "y" || key == "c" {
+				return m, m.copyPayloadCmd()
+			}
+
+			// Otherwise it may be a quick publish key from the settings file
+			if q, found := m.quickPublishFor(msg.String()); found && m.transport != nil {
+				return m, publishQuickCmd(m.outbox, m.durable, m.subject, q)
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.FocusMsg:
+		m.notifier.setFocus(true)
+
+	case tea.BlurMsg:
+		m.notifier.setFocus(false)
+
+	case notifyFailedMsg:
+		m.status = i18n.T("status.notify_failed", msg.err)
+
+	case natsConnectedMsg:
+		m.nc = msg.nc
+		m.transport = transport.NATS{Conn: msg.nc}
+		m.durable = transport.Publisher{Transport: m.transport}
+		if msg.edge != nil {
+			m.durable = msg.edge
+		}
+		m.conn.status = connConnected
+		if m.sessionPicker != nil {
+			// Events wait until a session is picked
+			return m, tea.Batch(waitForConnEvent(m.conn.events), subscribeToSessions(m.transport))
+		}
+		return m, tea.Batch(waitForConnEvent(m.conn.events), m.startSubscriptions())
+
+	case sessionsReadyMsg:
+		if m.sessionPicker == nil {
+			// Attached before the subscription was ready
+			for _, sub := range msg.subs {
+				sub.Unsubscribe()
+			}
+			return m, nil
+		}
+		m.sessionPicker.subs = msg.subs
+		m.sessionPicker.msgChan = msg.msgChan
+		return m, waitForSession(msg.msgChan)
+
+	case sessionSeenMsg:
+		if m.sessionPicker == nil {
+			return m, nil
+		}
+		m.sessionPicker.noteSession(msg.session)
+		return m, waitForSession(m.sessionPicker.msgChan)
+
+	case connEventMsg:
+		return m.noteConnEvent(msg)
+
+	case controlReadyMsg:
+		m.controlSub = msg.sub
+		m.controlChan = msg.msgChan
+		return m, waitForControl(msg.msgChan)
+
+	case presenceReadyMsg:
+		m.presence.sub = msg.sub
+		m.presence.msgChan = msg.msgChan
+		return m, waitForPresence(msg.msgChan)
+
+	case presenceMsg:
+		m.notePresence(msg.presence)
+		return m, waitForPresence(m.presence.msgChan)
+
+	case approvalsReadyMsg:
+		m.approvalMsgs = msg.msgChan
+		return m, waitForApproval(msg.msgChan, m.approvalSecret)
+
+	case approvalMsg:
+		if msg.err != nil {
+			m.status = i18n.T("status.approval_unsigned", msg.approval.Operator, shortID(msg.approval.EventID))
+		} else {
+			m.noteApproval(msg.approval)
+		}
+		return m, waitForApproval(m.approvalMsgs, m.approvalSecret)
+
+	case approvalSentMsg:
+		m.noteDeferred(msg.deferred)
+		return m, nil
+
+	case chatReadyMsg:
+		m.chat.sub = msg.sub
+		m.chat.msgChan = msg.msgChan
+		return m, waitForChat(msg.msgChan)
+
+	case chatMsg:
+		m.noteChat(msg.event)
+		return m, waitForChat(m.chat.msgChan)
+
+	case controlCommandMsg:
+		// Control commands are handled even while blocked on an action
+		m.applyControl(msg.cmd)
+		return m, waitForControl(m.controlChan)
+
+	case controlErrMsg:
+		// A malformed command must not take the monitor down
+		m.status = i18n.T("status.control_error", msg.err)
+		return m, waitForControl(m.controlChan)
+
+	case subscriptionReadyMsg:
+		m.stopSources = append(m.stopSources, msg.stop)
+		m.lag.reporter = msg.lag
+		m.initialized = true
+		if msg.note != "" {
+			m.status = msg.note
+		}
+		// Start listening for events
+		return m, m.resumeListening()
+
+	case eventBatchMsg:
+		return m.handleEventBatch(msg)
+
+	case schemaViolationMsg:
+		m.listening = false
+		m.schemaViolations++
+		if pane := m.paneManager.GetPane(events.ErrorsPane); pane != nil {
+			pane.AddEvent(msg.violation)
+		}
+
+		// Usable payloads are still shown - strict mode reports drift, it doesn't hide events
+		if msg.event != nil {
+			return m.update(eventReceivedMsg(*msg.event))
+		}
+		return m, m.resumeListening()
+
+	case historyEventMsg:
+		return m.handleHistoryEvent(msg)
+
+	case eventReceivedMsg:
+		m.listening = false
+
+		// A producer retry of an event we already have
+		if m.dedup != nil && m.dedup.Seen(msg.IdempotencyKey) {
+			m.metrics.countDrop(dropDuplicate)
+			m.status = i18n.T("status.duplicate", msg.Type, msg.IdempotencyKey)
+			return m, m.resumeListening()
+		}
+
+		// A producer flooding the monitor is sampled or muted; decisions always get through
+		if !m.admitEvent(events.Event(msg)) {
+			m.metrics.countDrop(dropQuota)
+			return m, m.resumeListening()
+		}
+
+		// Transform, and synthesize answer actions for questions
+		event, keep := m.prepareEvent(events.Event(msg))
+		if !keep {
+			m.metrics.countDrop(dropFiltered)
+			return m, m.resumeListening()
+		}
+
+		// User scripts derive fields, drop noise or pick a response, off the update loop
+		if m.hookWorker != nil {
+			return m, m.sendToHooks(event)
+		}
+		return m.handleEvent(event, "")
+
+	case hookedEventMsg:
+		return m.hookedEvent(msg)
+
+	case taskCancelMsg:
+		m.outboxQueued = m.outbox.Len()
+		if msg.deferred != nil {
+			m.status = i18n.T("status.cancel_queued", msg.deferred, msg.task)
+		} else {
+			m.status = i18n.T("status.cancel_published", msg.task)
+		}
+
+	case quickPublishedMsg:
+		m.outboxQueued = m.outbox.Len()
+		if msg.deferred != nil {
+			m.status = i18n.T("status.publish_queued", msg.deferred, msg.label)
+		} else {
+			m.status = i18n.T("status.published", msg.label)
+		}
+
+	case actionExecutedMsg:
+		// Action response is safe in the outbox (and usually already published)
+		m.noteDeferred(msg.deferred)
+
+		// Mark the event as consumed (one-shot), and who answered it how in the audit log
+		m.recordAction(msg.eventID, msg.action, nil, msg.deferred)
+		m.observeResponse(msg.eventID)
+		m.lifecycles.Set(msg.eventID, tui.LifecycleResponded)
+		m.consumedActions[msg.eventID] = true
+
+		// Approvals other operators gave are void; their monitors settle the event
+		finished := m.finishApprovals(msg.eventID)
+
+		// A hook answered a decision that never became active
+		if msg.eventID != m.activeEventID() {
+			return m, finished
+		}
+		m.actionManager.Settle()
+		m.blockingEventIndex = nil // Clear blocking state
+		m.promoteQueued()
+
+		// Resume listening for new events
+		return m, tea.Batch(finished, m.resumeListening())
+
+	case inputSubmittedMsg:
+		// Input is safe in the outbox (and usually already published)
+		m.noteDeferred(msg.deferred)
+
+		// Clear input mode and resume; text kept while it was open is answered
+		if event := m.blockingEvent(); event != nil && m.inputAction != nil {
+			m.takeKeptInput(*event, *m.inputAction)
+		}
+		m.inputMode = false
+		m.inputAction = nil
+		m.setBlockingLifecycle(tui.LifecycleResponded)
+		if id := m.activeEventID(); id != "" {
+			m.recordAction(id, msg.action, msg.input, msg.deferred)
+			m.observeResponse(id)
+			m.consumedActions[id] = true
+			m.blockingEventIndex = nil
+		}
+		m.promoteQueued()
+
+		// Resume listening for new events
+		return m, m.resumeListening()
+
+	case replayStepMsg:
+		if m.replay == nil || msg.gen != m.replay.gen || !m.replay.playing {
+			return m, nil // Paused or superseded
+		}
+		m.scrub(1)
+		return m, m.replayNext()
+
+	case tickMsg:
+		// Periodic refresh keeps the pending-decision age current,
+		// retries responses stuck in the outbox, announces our cursor and
+		// snapshots the decision context
+		m.announcePresence()
+		m.saveSnapshot()
+		m.sweepQuota()
+		m.closeIdlePanes()
+		m.updateGauges()
+		if m.archive != nil {
+			if err := m.archive.Err(); err != nil {
+				m.status = i18n.T("status.archive_error", err)
+			}
+		}
+		return m, tea.Batch(tickCmd(), m.retryOutbox(), m.checkLag())
+
+	case lagMsg:
+		m.noteLag(msg)
+
+	case outboxFlushedMsg:
+		m.flushingOutbox = false
+		m.outboxQueued = msg.queued
+		if msg.err == nil && msg.sent > 0 {
+			m.status = i18n.T("status.outbox_delivered", msg.sent)
+		}
+
+	case gotoFoundMsg:
+		m.noteGotoFound(msg)
+
+	case escalationDueMsg:
+		return m, m.escalate(msg.eventID)
+
+	case timeoutDueMsg:
+		return m, m.takeDefaultAction(msg.eventID)
+
+	case escalatedMsg:
+		if msg.err != nil {
+			m.status = i18n.T("status.escalation_failed", msg.err)
+			return m, nil
+		}
+		m.escalations[msg.eventID] = escalationState{target: msg.target}
+		m.status = i18n.T("status.escalated", shortID(msg.eventID), msg.target)
+
+		// Decision is now shared with alternate approvers; an open input keeps holding the stream
+		if m.inputMode {
+			return m, nil
+		}
+		return m, m.resumeListening()
+
+	case yankDoneMsg:
+		m.status = msg.status
+
+	case linkOpenedMsg:
+		m.status = msg.status
+
+	case abandonedMsg:
+		// Abandoned responses are in the outbox (and usually delivered); quit as asked
+		m.closeConnections()
+		return m, tea.Quit
+
+	case errMsg:
+		m.err = msg.err
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+// handleEvent routes a live event that made it through dedup, quotas,
+// transforms and hooks, and makes a decision of its actions
+// hookResponse is the action a hook chose to answer with ("" for none)
+func (m model) handleEvent(event events.Event, hookResponse string) (tea.Model, tea.Cmd) {
+	// Route event to appropriate pane
+	pane := m.routeEvent(event)
+	if pane == nil || pane.LastAdded < 0 {
+		return m, m.resumeListening()
+	}
+
+	// Tokens and cost add up even after the event is evicted
+	m.countUsage(event)
+	m.metrics.countEvent(event.Type, pane.Name)
+
+	// Get the index of this event in the pane it was routed to
+	// (not necessarily the end: late events may be put in timestamp order)
+	eventIndex := pane.LastAdded
+
+	// Emergency stops are shown across both panes until dismissed
+	if event.IsTaskStop() {
+		m.stopBanner = &event
+	}
+
+	// A response given elsewhere (alternate approver, Slack) settles the decision
+	if answered, ok := event.AnsweredEventID(); ok {
+		m.recordAnswerElsewhere(answered, event)
+		m.settleAnsweredElsewhere(answered)
+	}
+
+	// Handle actions if present
+	if len(event.Actions) > 0 && m.actionManager != nil {
+		// Check if any action opens an input (text, select or confirm)
+		if inputAction, ok := event.InputAction(); ok {
+			// Another input or a decision is open: keep this one as a draft to switch to
+			if m.blockingEventIndex != nil {
+				m.parkNewInput(event, inputAction)
+				return m, tea.Batch(m.resumeListening(), m.scheduleDecisionTimers(event, time.Now()), m.notifier.decision(event, time.Now()))
+			}
+
+			// ENTER INPUT MODE, with any text kept from an earlier attempt at this action
+			blink := m.openInput(event, inputAction, pane.Name, eventIndex)
+			return m, tea.Batch(blink, m.scheduleDecisionTimers(event, time.Now()), m.notifier.decision(event, time.Now()))
+		}
+
+		// A decision takes over from an open draft, which is kept for later
+		if m.inputMode && m.stashInput() {
+			m.status = i18n.T("status.draft_for_decision")
+		}
+
+		// Another decision is active: wait in the queue, unless a hook or policy answers it
+		if m.blockingEventIndex != nil {
+			if cmd := m.answerUnattended(event, hookResponse); cmd != nil {
+				return m, tea.Batch(cmd, m.resumeListening())
+			}
+			m.queueDecision(event)
+			return m, tea.Batch(m.resumeListening(), m.scheduleDecisionTimers(event, time.Now()), m.notifier.decision(event, time.Now()))
+		}
+
+		// Regular actions (not input) - register them
+		m.actionManager.RegisterActions(event.Actions, eventIndex)
+
+		// Make it the active decision; the stream keeps flowing
+		m.blockingEventIndex = &eventIndex
+		m.blockingPane = pane.Name
+		m.blockingSince = time.Now()
+		m.focusPane(pane.Name)            // Bring the decision into view
+		m.selectedEventIndex = eventIndex // Auto-select the decision
+
+		// A hook or policy rule may answer trivial prompts itself
+		if cmd := m.answerUnattended(event, hookResponse); cmd != nil {
+			return m, tea.Batch(cmd, m.resumeListening())
+		}
+		return m, tea.Batch(m.resumeListening(), m.scheduleDecisionTimers(event, time.Now()), m.notifier.decision(event, time.Now()))
+	}
+
+	// No actions - continue listening for more events
+	return m, m.resumeListening()
+}
+
+// actionProofKeys are the keys whose commands producers' action keys can't
+// take over: quitting and moving through the list
+var actionProofKeys = map[string]bool{"q": true, "ctrl+c": true, "up": true, "down": true, "k": true, "j": true}
+
+// triggerAction triggers the active decision's action bound to key
+// Returns false if no action took the key
+func (m *model) triggerAction(key string) (tea.Cmd, bool) {
+	if m.actionManager == nil || m.transport == nil {
+		return nil, false
+	}
+	// Check if this event's actions have already been consumed (one-shot)
+	// before HandleKeyPress marks an action in flight that nothing would settle
+	if m.actionManager.Bound(key) && m.consumedActions[m.activeEventID()] {
+		// Action already taken for this event - ignore
+		return nil, true
+	}
+	action, found := m.actionManager.HandleKeyPress(key)
+	if !found {
+		return nil, m.actionManager.Bound(key) // A repeated press of an answered decision's key is ignored
+	}
+
+	// Four-eyes actions wait for a second operator's approval
+	if action.RequiredApprovals() > 1 {
+		return m.approve(action), true
+	}
+
+	// Actions offering reason codes ask for them first
+	if len(action.Reasons) > 0 {
+		m.openReasonPicker(action)
+		return nil, true
+	}
+
+	// Execute the action
+	return publishActionResponseCmd(m.transport, m.outbox, m.durable, m.subject, m.activeEventID(), action), true
+}
+
+// publishActionResponseCmd creates a command that publishes an action response to NATS
+func publishActionResponseCmd(t transport.Transport, ob *outbox.Outbox, pub outbox.Publisher, subject, eventID string, action events.Action) tea.Cmd {
+	return func() tea.Msg {
+		// Use the complete event from the action, just add ID and timestamp
+		responseEvent := action.Response()
+
+		// Serialize to JSON
+		data, err := responseEvent.ToJSON()
+		if err != nil {
+			return errMsg{err}
+		}
+
+		// Queue durably, then publish
+		deferred, err := publishDurably(ob, pub, subject, data)
+		if err != nil {
+			return errMsg{err}
+		}
+		publishReply(t, action, data)
+
+		return actionExecutedMsg{eventID: eventID, action: action, deferred: deferred}
+	}
+}
+
+// publishInputResponseCmd creates a command that publishes an input response to NATS
+// The input value is added to the response's Data under key
+func publishInputResponseCmd(t transport.Transport, ob *outbox.Outbox, pub outbox.Publisher, subject string, action events.Action, key string, value interface{}) tea.Cmd {
+	return func() tea.Msg {
+		// Use the complete event from the action
+		responseEvent := action.Response()
+
+		// Add the user's input to a copy of the event data (the action's map is shared)
+		data := make(map[string]interface{}, len(responseEvent.Data)+1)
+		for k, v := range responseEvent.Data {
+			data[k] = v
+		}
+		data[key] = value
+		responseEvent.Data = data
+
+		// Serialize to JSON
+		payload, err := responseEvent.ToJSON()
+		if err != nil {
+			return errMsg{err}
+		}
+
+		// Queue durably, then publish
+		deferred, err := publishDurably(ob, pub, subject, payload)
+		if err != nil {
+			return errMsg{err}
+		}
+		publishReply(t, action, payload)
+
+		return inputSubmittedMsg{action: action, input: value, deferred: deferred}
+	}
+}
+
+// renderActionBar renders the dynamic action buttons at the bottom of the UI
+// queued is how many decisions wait behind the one shown
+func renderActionBar(actions []events.Action, eventIndex int, queued int) string {
+	if len(actions) == 0 {
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color(tui.Colors().Border)).
+			Render(i18n.T("actions.none"))
+	}
+
+	var result strings.Builder
+
+	// Show which event the buttons answer, and what waits behind it
+	label := i18n.T("actions.required", eventIndex)
+	if queued > 0 {
+		label = i18n.T("actions.required_queued", eventIndex, queued)
+	}
+	warning := lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color("214")).
+		Foreground(lipgloss.Color("0")).
+		Padding(0, 1).
+		Render(label)
+	result.WriteString(warning)
+	result.WriteString("  ")
+
+	// Render action buttons
+	var buttons []string
+	for _, action := range actions {
+		btn := actionButtonStyle(action.Style).
+			Render(fmt.Sprintf("[%s] %s", action.Key, action.Label))
+		buttons = append(buttons, btn)
+	}
+	result.WriteString(strings.Join(buttons, "  "))
+
+	return lipgloss.NewStyle().
+		MarginTop(1).
+		Render(result.String())
+}
+
+// actionButtonStyle returns the button style for an action's style hint
+func actionButtonStyle(style string) lipgloss.Style {
+	btn := lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 2)
+
+	switch style {
+	case events.ActionStyleDanger:
+		return btn.
+			Background(lipgloss.Color("160")). // Red background
+			Foreground(lipgloss.Color("230"))
+	case events.ActionStyleNeutral:
+		return btn.
+			Bold(false).
+			Background(lipgloss.Color("238")). // Grey background
+			Foreground(lipgloss.Color("252"))
+	default:
+		return btn.
+			Background(lipgloss.Color("62")). // Purple background
+			Foreground(lipgloss.Color("230")) // White text
+	}
+}
+
+// renderInputInstructions renders instructions for input mode
+// modeLabel is the vim mode ("" outside vim mode)
+func renderInputInstructions(action *events.Action, modeLabel, text string) string {
+	if action == nil {
+		return ""
+	}
+
+	var result strings.Builder
+
+	// Show input mode indicator
+	indicator := lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color("62")).
+		Foreground(lipgloss.Color("0")).
+		Padding(0, 1).
+		Render(i18n.T("input.mode", action.Label))
+	result.WriteString(indicator)
+	result.WriteString("  ")
+
+	// Show instructions
+	instructions := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(tui.Colors().Text)).
+		Render(inputInstructions(action))
+	result.WriteString(instructions)
+
+	if modeLabel != "" {
+		result.WriteString("  ")
+		result.WriteString(lipgloss.NewStyle().Bold(true).Render(modeLabel))
+	}
+
+	if !action.IsChoiceInput() {
+		result.WriteString("  ")
+		result.WriteString(renderInputCounter(*action, text))
+	}
+
+	return lipgloss.NewStyle().
+		MarginTop(1).
+		Render(result.String())
+}
+
+// renderInputCounter renders the live character and word count of an input,
+// with the action's length bounds; red while the answer can't be submitted
+func renderInputCounter(action events.Action, text string) string {
+	chars, words := events.InputLength(text)
+	counter := i18n.T("input.counter", chars, words)
+	switch {
+	case action.MinLength > 0 && action.MaxLength > 0:
+		counter += i18n.T("input.counter_range", action.MinLength, action.MaxLength)
+	case action.MinLength > 0:
+		counter += i18n.T("input.counter_min", action.MinLength)
+	case action.MaxLength > 0:
+		counter += i18n.T("input.counter_max", action.MaxLength)
+	}
+
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(tui.Colors().Muted))
+	if action.CheckLength(text) != nil {
+		style = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	}
+	return style.Render(counter)
+}
+
+// View renders the UI
+func (m model) View() string {
+	if m.err != nil {
+		return i18n.T("error", m.err) + "\n"
+	}
+
+	if m.sessionPicker != nil && !m.quitConfirmOpen {
+		width := m.width
+		if width == 0 {
+			width = 120
+		}
+		return m.renderSessionPicker(width)
+	}
+	if !m.initialized {
+		return i18n.T("connecting") + "\n"
+	}
+
+	// Header
+	header := i18n.T("header.title") + "\n"
+	if m.workspace != "" {
+		header = i18n.T("header.title_workspace", m.workspace) + "\n"
+	}
+	if m.replay != nil {
+		header += i18n.T("header.replaying", m.replay.file) + "\n"
+	} else {
+		sources := m.subject
+		if m.tailFile != "" {
+			sources += " + " + m.tailFile
+		}
+		if m.historyReplayed > 0 {
+			sources += i18n.T("header.from_history", m.historyReplayed)
+		}
+		header += i18n.T("header.listening", sources, m.conn.status, events.ControlSubject(m.instance)) + "\n"
+	}
+	header += m.renderConnection()
+	if m.bus != nil {
+		if queued := m.bus.Stats().Queued; queued > 0 {
+			header += i18n.T("header.bus_queued", queued) + "\n"
+		}
+	}
+	if dropped := transport.Dropped(); dropped > 0 {
+		header += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Render(i18n.T("header.dropped", dropped)) + "\n"
+	}
+	header += m.renderLag()
+	header += m.renderQuota()
+	header += m.renderUsageSummary()
+	if m.outboxQueued > 0 {
+		header += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Render(i18n.T("header.outbox", m.outboxQueued)) + "\n"
+	}
+	if online := m.onlineOperators(); online != "" {
+		header += i18n.T("header.also_here", online) + "\n"
+	}
+	if m.strict {
+		header += i18n.T("header.strict", m.schemaViolations, events.ErrorsPane) + "\n"
+	}
+	if m.status != "" {
+		header += lipgloss.NewStyle().
+			Foreground(lipgloss.Color(tui.Colors().Muted)).
+			Render(m.status) + "\n"
+	}
+	header += m.renderPaneTabs()
+	header += m.renderChatHeader()
+	header += m.renderSearchBar()
+	header += m.renderGotoBar()
+	header += "\n"
+
+	// Use default dimensions if window size not yet received
+	width := m.width
+	height := m.height
+	if width == 0 {
+		width = 120
+	}
+	if height == 0 {
+		height = 30
+	}
+
+	if m.quitConfirmOpen {
+		return header + m.renderQuitConfirm(width)
+	}
+	if m.restoreOffer != nil {
+		return header + m.renderRestoreOffer(width)
+	}
+	if m.reasons != nil {
+		return header + m.renderReasonPicker(width)
+	}
+	if m.settingsOpen {
+		return header + m.renderSettings(width)
+	}
+	if m.typeHelpOpen {
+		return header + m.renderTypeHelp(width)
+	}
+	if m.usageOpen {
+		return header + tui.RenderUsage(m.usage, width)
+	}
+	if m.archived != nil {
+		return header + m.renderArchived(width, height)
+	}
+	if m.tree != nil {
+		return header + m.renderPayloadTree(width, height)
+	}
+	if m.legend != nil {
+		return header + m.renderTypeLegend(width, height)
+	}
+	if m.auditView != nil {
+		return header + m.renderAudit(width, height)
+	}
+
+	// Emergency stops span both panes
+	if m.stopBanner != nil {
+		header += renderStopBanner(m.stopBanner, width)
+	}
+	if m.watches.Len() > 0 {
+		header += m.watches.Render(width) + "\n"
+	}
+
+	// Render split layout (reserve space for header and action bar)
+	// Only highlight the blocking event when its pane is the one being shown
+	blockingIndex := m.blockingEventIndex
+	if m.blockingPane != m.activePane {
+		blockingIndex = nil
+	}
+	view := tui.ListView{
+		Pane:          m.activePane,
+		Filter:        m.listFilter(),
+		SelectedIndex: m.selectedEventIndex,
+		BlockingIndex: blockingIndex,
+		VisualStart:   -1,
+		Badges:        m.rowBadges(),
+		NewSince:      m.newSince,
+		Fresh:         tui.FreshHighlight,
+		FullContent:   m.loadedContent[m.selectedEventID()],
+		Chips:         tui.ChipConfig{Rules: m.config.Chips, Max: m.config.MaxChips},
+		Payload:       m.payloadScroll,
+		Fold:          m.payloadFold,
+		RawContent:    m.rawContent,
+		Zoom:          m.zoom,
+		Lanes:         m.lanes,
+	}
+	if m.visualMode {
+		view.VisualStart, view.VisualEnd = m.visualRange()
+	}
+	if m.replay != nil {
+		view.Fresh = 0 // Scrubbing re-adds every event; nothing is news
+	}
+	layout := tui.RenderSplitLayout(m.paneManager, view, width, height-9, m.inputMode, m.inputView()) // -9 for header + action bar + status bar
+
+	// Render action bar (or input instructions if in input mode)
+	var actionBar string
+	if m.inputMode {
+		actionBar = renderInputInstructions(m.inputAction, m.editor.ModeLabel(), m.textarea.Value()) + m.renderCountdown()
+	} else if m.visualMode {
+		actionBar = renderVisualInstructions(len(m.visualEvents()))
+	} else if m.actionManager.InFlight() {
+		actionBar = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(tui.Colors().Muted)).
+			Render(i18n.T("actions.in_flight"))
+	} else {
+		eventIndex := m.actionManager.GetEventIndex()
+		if m.blockingEventIndex != nil {
+			eventIndex = *m.blockingEventIndex
+		}
+		actionBar = renderActionBar(m.withApprovalLabels(m.actionManager.GetActiveActions()), eventIndex, m.actionManager.QueueLen()) + m.renderCountdown() + renderQuickKeys(m.config.QuickPublish)
+	}
+
+	// The scrubber takes the reminder's place when replaying
+	footer := renderPendingReminder(m.pendingDecisions(), time.Now())
+	if m.replay != nil {
+		footer = renderScrubber(m.replay)
+	}
+
+	return header + layout + "\n\n" + actionBar + "\n" + footer
+}
+
+// Run runs the tui command with its arguments
+func Run(args []string) error {
+	flags := cli.NewFlagSet("tui")
+	// Define flags
+	maxEvents := flags.Int("max-events", 200, "Events kept per pane; the oldest are dropped first (see --keep-per-type)")
+	keepPerType := flags.Int("keep-per-type", 0, "Always keep the last K events of each type, even past the pane limit (0 disables)")
+	quota := flags.Int("quota", 0, "Events per second each producer (the events' source) may send before its events are sampled or muted (0: the settings file's \"quota\", if any)")
+	quotaMode := flags.String("quota-mode", "", "What happens past --quota: sample (keep 1 in 10) or mute (drop everything for 30s); default sample")
+	maxPanes := flags.Int("max-panes", 8, "Create a pane for events naming one that doesn't exist, until there are this many (0 sends them to the default pane)")
+	instance := flags.String("instance", "default", "Instance name for the remote control subject (agneto.control.<instance>)")
+	exportDir := flags.String("export-dir", "", "Allow the export control command, writing files only inside this directory (export is refused without it)")
+	outboxDir := flags.String("outbox", "", "Directory of the durable outbox for responses (default ~/.config/agneto/outbox/<instance>)")
+	fromFile := flags.String("from-file", "", "Replay a recorded session (a recorder database, JSON Lines of events, or an archive directory of segments) offline, with a time-travel scrubber")
+	replaySpeed := flags.Float64("replay-speed", 0, "Play the --from-file recording from the start at this multiple of its recorded pace (e.g. 1 or 10; 0 opens it paused at the end)")
+	transformFile := flags.String("transform", "", "Path to JSON file of transformation rules applied to incoming events")
+	policyFile := flags.String("policy", "", "Path to JSON file of auto-respond rules answering matching decisions unattended")
+	policyLogPath := flags.String("policy-log", "", "Append the decisions of --policy rules to this file as JSON Lines")
+	auditLogPath := flags.String("audit-log", "", "Append every action taken (who answered what, when, with what input) to this file as JSON Lines; the audit panel (A) shows earlier runs' entries too")
+	hooksDir := flags.String("hooks", "", "Directory of Starlark hook scripts (*.star, defining hook(event)) run on each incoming event")
+	configPath := flags.String("config", config.DefaultPath(), "Path to the settings file (created when saving from the settings screen)")
+	editMode := flags.String("edit-mode", "", "Input editing style: emacs (readline bindings, default) or vim (also \"edit_mode\" in the settings file)")
+	hyperlinks := flags.Bool("hyperlinks", true, "Render URLs in the payload pane as clickable OSC 8 hyperlinks")
+	notify := flags.String("notify", "", "Comma-separated ways to notify of decisions while the terminal is in the background: bell, osc777 (through the terminal) or desktop (notify-send, osascript) (also \"notify\" in the settings file)")
+	contentPreview := flags.Int("content-preview", tui.ContentPreviewBytes, "Bytes of large Content rendered until L loads the rest (0 renders everything)")
+	strict := flags.Bool("strict", false, "Reject events with unknown or missing fields, reporting them in the errors pane (also \"strict\" in the settings file)")
+	presence := flags.Bool("presence", false, "Share which event you have selected with other monitors and show theirs")
+	operator := flags.String("operator", os.Getenv("USER"), "Name shown to other operators with --presence and --chat, and counted as one approval of four-eyes actions")
+	chat := flags.Bool("chat", false, "Chat with other monitors' operators in a chat pane (C shows it, @ writes)")
+	watch := flags.String("watch", "", "Comma-separated watch expressions pinned above the list, e.g. data.tests_passed,cost=sum(data.cost_usd) (added to \"watches\" in the settings file)")
+	redact := flags.String("redact", "", "Comma-separated Data key globs masked in the payload pane and exports, e.g. *_token,password (added to \"redact\" in the settings file)")
+	maxLag := flags.Int("max-lag", 100, "Warn when the monitor is more than this many events behind the JetStream stream")
+	lanesFlag := flags.String("lanes", "off", "Attribute rows to their producer (the event's source): off, color (tagged rows) or gutter (plus swim lanes); m cycles it")
+	minSeverity := flags.String("min-severity", "", "Hide events below this severity: debug, info, warn, error or critical (S cycles it)")
+	reorderWindow := flags.Duration("reorder-window", 0, "Put events arriving up to this far behind the newest in timestamp order (e.g. 2s; 0 keeps delivery order)")
+	snapshotInterval := flags.Duration("snapshot-interval", 5*time.Second, "How often pending decisions and drafts are snapshotted for crash recovery (0 disables)")
+	ephemeral := flags.Bool("ephemeral", false, "Only show events published after connecting, without replaying history from JetStream")
+	historyLast := flags.Int("history", 200, "Events replayed from JetStream on startup (the stream holding --subject is created if missing)")
+	historySince := flags.String("history-since", "", "Replay events since a time instead: RFC 3339, YYYY-MM-DD or a duration ago (e.g. 2h)")
+	tailFile := flags.String("tail", "", "Also follow a JSON Lines file for events (like tail -f), alongside NATS")
+	session := flags.String("session", "", "Attach to a session's events (agneto.<session>.events), or \"all\" for every session, instead of --subject")
+	pickSession := flags.Bool("sessions", false, "Start with a screen listing the active sessions to attach to one or all")
+	subject := flags.String("subject", "", "Subject events are read from and responses published to (default \""+defaultSubject+"\", also \"subject\" in the settings file)")
+	workspace := flags.String("workspace", "", "Use a named workspace: a settings file in ~/.config/agneto/workspaces, saved with w (a new one starts from --config)")
+	listWorkspaces := flags.Bool("list-workspaces", false, "List saved workspaces and exit")
+	archiveOn := flags.Bool("archive", false, "Record every received event to rolling JSON Lines segments (see --archive-dir)")
+	closedPanesDir := flags.String("closed-panes-dir", "", "Directory closed ephemeral panes' events are archived to (default ~/.config/agneto/closed-panes/<instance>)")
+	archiveDir := flags.String("archive-dir", "", "Directory of archive segments (default ~/.config/agneto/archive/<instance>)")
+	archiveMaxMB := flags.Int64("archive-max-mb", archive.DefaultMaxBytes>>20, "Close the archive segment once it reaches this many MiB (0: no size limit)")
+	archiveMaxAge := flags.Duration("archive-max-age", archive.DefaultMaxAge, "Close the archive segment once it is this old (0: no age limit)")
+	archiveUpload := flags.String("archive-upload", "", "Upload closed segments to s3://bucket/prefix, removing them locally (implies --archive; credentials from the AWS_* environment)")
+	metricsAddr := flags.String("metrics-addr", "", "Serve Prometheus metrics (event throughput, pending decisions, response times) on this address at /metrics, e.g. :9464")
+	messagesTemplate := flags.Bool("messages-template", false, "Print the English message catalog as JSON, to start a translation from, and exit")
+	natsconn.AddFlags(flags)
+	if err := cli.Parse(flags, args); err != nil {
+		return err
+	}
+
+	if *messagesTemplate {
+		data, err := i18n.Template()
+		if err != nil {
+			return fmt.Errorf("Failed to build the message catalog: %v", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if *listWorkspaces {
+		names, err := config.Workspaces()
+		if err != nil {
+			return fmt.Errorf("Failed to list workspaces: %v", err)
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	}
+
+	// Load persistent settings; a new workspace starts as a copy of the settings file
+	loadPath := *configPath
+	if *workspace != "" {
+		path, err := config.WorkspacePath(*workspace)
+		if err != nil {
+			return fmt.Errorf("--workspace: %v", err)
+		}
+		if config.Exists(path) {
+			loadPath = path
+		}
+		*configPath = path
+	}
+	cfg, err := config.Load(loadPath)
+	if err != nil {
+		return fmt.Errorf("Failed to load --config: %v", err)
+	}
+
+	// The flag overrides the settings file, and is kept when saving it (w)
+	if *subject != "" {
+		cfg.Subject = *subject
+	}
+	*subject = cfg.Subject
+	if *subject == "" {
+		*subject = defaultSubject
+	}
+
+	// Sessions pick the subject for this run only; saving settings keeps the configured one
+	switch {
+	case *session != "" && *pickSession:
+		return errors.New("--session and --sessions are exclusive")
+	case *session == "all":
+		*subject = events.AllSessionsSubject
+	case *session != "":
+		if err := events.ValidateSession(*session); err != nil {
+			return fmt.Errorf("--session: %v", err)
+		}
+		*subject = events.SessionSubject(*session)
+	}
+	// UI language; a catalog the operator asked for must exist, one guessed from LANG may not
+	locale, explicit := i18n.Detect(cfg.Locale)
+	localeDir := cfg.LocaleDir
+	if localeDir == "" {
+		localeDir = i18n.DefaultDir()
+	}
+	catalog, err := i18n.Open(localeDir, locale)
+	if err != nil && (explicit || !errors.Is(err, fs.ErrNotExist)) {
+		return fmt.Errorf("Failed to load the %s message catalog: %v", locale, err)
+	}
+	i18n.Use(catalog)
+
+	if cfg.Theme != "" {
+		if err := tui.ApplyTheme(cfg.Theme); err != nil {
+			return fmt.Errorf("Invalid theme in %s: %v", *configPath, err)
+		}
+	}
+	if cfg.Colors != nil {
+		tui.SetThemeColors(*cfg.Colors)
+	}
+
+	// Flags and $NATS_URL override the settings file
+	if cfg.Server != "" && os.Getenv(natsconn.EnvURL) == "" {
+		os.Setenv(natsconn.EnvURL, cfg.Server)
+	}
+	if cfg.MaxEvents > 0 && !cli.Given(flags, "max-events") {
+		*maxEvents = cfg.MaxEvents
+	}
+	if cfg.KeepPerType > 0 && !cli.Given(flags, "keep-per-type") {
+		*keepPerType = cfg.KeepPerType
+	}
+
+	keys, err := newKeymap(cfg.Keys)
+	if err != nil {
+		return fmt.Errorf("Invalid keys in %s: %v", *configPath, err)
+	}
+
+	if err := validateQuickPublish(cfg.QuickPublish, keys); err != nil {
+		return fmt.Errorf("Invalid settings in %s: %v", *configPath, err)
+	}
+
+	// The flag overrides the settings file
+	if *editMode == "" {
+		*editMode = cfg.EditMode
+	}
+	switch *editMode {
+	case "", editModeEmacs:
+		*editMode = editModeEmacs
+	case editModeVim:
+	default:
+		return fmt.Errorf("Invalid --edit-mode %q (want emacs or vim)", *editMode)
+	}
+	if *notify == "" {
+		*notify = cfg.Notify
+	}
+	notifyMethods, err := parseNotify(*notify)
+	if err != nil {
+		return fmt.Errorf("Invalid --notify: %v", err)
+	}
+
+	if *minSeverity != "" {
+		if *minSeverity, err = events.ParseSeverity(*minSeverity); err != nil {
+			return fmt.Errorf("--min-severity: %v", err)
+		}
+	}
+
+	lanes, err := tui.ParseLanes(*lanesFlag)
+	if err != nil {
+		return fmt.Errorf("--lanes: %v", err)
+	}
+
+	tui.ContentPreviewBytes = *contentPreview
+	tui.Hyperlinks = *hyperlinks
+
+	// Secrets producers embed in Data are masked wherever events are shown
+	patterns := append([]string(nil), cfg.Redact...)
+	for _, pattern := range strings.Split(*redact, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if err := tui.SetRedactPatterns(patterns); err != nil {
+		return fmt.Errorf("Invalid redaction: %v", err)
+	}
+
+	// Payload renderers the settings file maps event types to
+	if err := tui.ApplyRendererRules(cfg.Renderers); err != nil {
+		return fmt.Errorf("Invalid renderers setting: %v", err)
+	}
+
+	// Watch expressions from the settings file, then the flag's
+	watchList := append([]tui.Watch(nil), cfg.Watches...)
+	for _, spec := range strings.Split(*watch, ",") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		w, err := tui.ParseWatch(spec)
+		if err != nil {
+			return fmt.Errorf("--watch: %v", err)
+		}
+		watchList = append(watchList, w)
+	}
+	watches, err := tui.NewWatches(watchList)
+	if err != nil {
+		return fmt.Errorf("Invalid watches in %s: %v", *configPath, err)
+	}
+
+	// Bookmarks persist per instance
+	bookmarksPath := config.BookmarksPath(*instance)
+	bookmarks, err := config.LoadBookmarks(bookmarksPath)
+	if err != nil {
+		return fmt.Errorf("Failed to load bookmarks: %v", err)
+	}
+
+	// Text typed into inputs left unsubmitted persists per instance too
+	keptInputsPath := config.KeptInputsPath(*instance)
+	keptInputs, err := config.LoadKeptInputs(keptInputsPath)
+	if err != nil {
+		log.Printf("Ignoring kept input text: %v", err)
+	}
+
+	// A snapshot left behind means the last run crashed; it's offered for restore
+	snapshotPath := config.SnapshotPath(*instance)
+	crashed, err := config.LoadSnapshot(snapshotPath)
+	if err != nil {
+		log.Printf("Ignoring the crash-recovery snapshot: %v", err)
+	}
+
+	// Ephemeral panes' events are kept when they close
+	if *closedPanesDir == "" {
+		*closedPanesDir = defaultClosedPanesDir(*instance)
+	}
+
+	// Responses go through a durable outbox so broker outages can't lose them
+	if *outboxDir == "" {
+		*outboxDir = outbox.DefaultDir(*instance)
+	}
+	ob, err := outbox.Open(*outboxDir)
+	if err != nil {
+		return fmt.Errorf("Failed to open outbox: %v", err)
+	}
+
+	// Load the transformation pipeline if configured
+	var pipeline *transform.Pipeline
+	if *transformFile != "" {
+		var err error
+		pipeline, err = transform.Load(*transformFile)
+		if err != nil {
+			return fmt.Errorf("Failed to load --transform: %v", err)
+		}
+	}
+
+	// Hook scripts are picked up from the directory as they change
+	var runner *hooks.Runner
+	if *hooksDir != "" {
+		runner, err = hooks.New(*hooksDir)
+		if err != nil {
+			return fmt.Errorf("Failed to use --hooks: %v", err)
+		}
+	}
+
+	// Auto-respond rules answer matching decisions without the operator
+	var rules *policy.Policy
+	var decisions *policy.Log
+	if *policyFile != "" {
+		if rules, err = policy.Load(*policyFile); err != nil {
+			return fmt.Errorf("Failed to load --policy: %v", err)
+		}
+		if *policyLogPath != "" {
+			if decisions, err = policy.OpenLog(*policyLogPath); err != nil {
+				return fmt.Errorf("Failed to open --policy-log: %v", err)
+			}
+		}
+	}
+
+	// History replay window; --history-since takes precedence over --history
+	history := historyOptions{ephemeral: *ephemeral, last: *historyLast}
+	if history.since, err = export.ParseTime(*historySince, time.Now()); err != nil {
+		return fmt.Errorf("--history-since: %v", err)
+	}
+
+	// Initialize model with pane manager and action manager
+	paneManager := tui.NewPaneManager(*maxEvents)
+	paneManager.SetKeepPerType(*keepPerType)
+	paneManager.SetReorder(*reorderWindow)
+	paneManager.Routes = cfg.Routes
+	paneManager.MaxPanes = *maxPanes
+	if err := validatePanes(cfg.Panes); err != nil {
+		return err
+	}
+	paneManager.SetOptions(cfg.Panes)
+
+	// Strict mode reports schema drift in a dedicated pane
+	if *strict || cfg.Strict {
+		paneManager.AddPane(events.ErrorsPane, i18n.T("pane.errors"), 20)
+	}
+
+	m := model{
+		paneManager:     paneManager,
+		transform:       pipeline,
+		policy:          rules,
+		policyLog:       decisions,
+		audit:           tui.NewAuditLog(),
+		actionManager:   tui.NewActionManager(),
+		consumedActions: make(map[string]bool),
+		drafts:          make(map[string]*inputDraft),
+		escalations:     make(map[string]escalationState),
+		deadlines:       make(map[string]time.Time),
+		approvals:       make(map[string]*approvalState),
+		usage:           tui.NewUsageTotals(),
+		closedPanesDir:  *closedPanesDir,
+		instance:        *instance,
+		exportDir:       *exportDir,
+		workspace:       *workspace,
+		operator:        *operator,
+		subject:         *subject,
+		history:         history,
+		lag:             lagState{max: uint64(max(0, *maxLag))},
+		payloadScroll:   &tui.PayloadScroll{},
+		payloadFold:     &tui.PayloadFold{},
+		activePane:      paneManager.DefaultPane,
+		filter:          cfg.Filter,
+		mutes:           cfg.Mutes,
+		hiddenTypes:     make(tui.TypeSet),
+		minSeverity:     *minSeverity,
+		lanes:           lanes,
+		watches:         watches,
+		config:          cfg,
+		configPath:      *configPath,
+		keys:            keys,
+		types:           tui.NewTypeRegistry(cfg.Types),
+		strict:          *strict || cfg.Strict,
+		outbox:          ob,
+		outboxQueued:    ob.Len(),
+		bookmarks:       bookmarks,
+		editor:          editor{mode: *editMode},
+		loadedContent:   make(map[string]bool),
+		lifecycles:      make(tui.Lifecycles),
+		bookmarksPath:   bookmarksPath,
+		keptInputs:      keptInputs,
+		conn:            connectionState{events: make(chan connEventMsg, connEventBuffer)},
+		approvalSecret:  events.ApprovalSecret(),
+	}
+
+	// Operator chat gets a pane of its own
+	if *chat {
+		paneManager.AddPane(events.ChatPane, i18n.T("pane.chat"), 100)
+		m.chat = &chatState{}
+	}
+	m.paneDirectory = &paneDirectory{}
+	m.paneDirectory.update(paneManager)
+
+	// Replay mode: load the recording and start at its end
+	if *fromFile != "" {
+		recorded, err := readRecording(*fromFile)
+		if err != nil {
+			return err
+		}
+		m.replay = &replayState{file: *fromFile, events: recorded, speed: 1}
+		m.initialized = true
+		if *replaySpeed > 0 {
+			m.replay.speed = min(max(*replaySpeed, minReplaySpeed), maxReplaySpeed)
+			m.replay.playing = true
+		} else {
+			m.scrub(len(recorded))
+		}
+	} else {
+		// Live mode: every source feeds the bus, the TUI is its sink
+		m.bus = monitor.New(monitor.DefaultBuffer)
+		m.eventChan = make(monitor.ChanSink)
+		if *archiveOn || *archiveUpload != "" {
+			if m.archive, err = openArchive(*archiveDir, *instance, *archiveUpload, archive.Policy{MaxBytes: *archiveMaxMB << 20, MaxAge: *archiveMaxAge}); err != nil {
+				return err
+			}
+			// Ahead of the TUI, so events are recorded even while it waits on a decision
+			m.bus.AddSink(m.archive)
+		}
+		m.bus.AddSink(m.eventChan)
+		m.bus.Start()
+		m.dedup = monitor.NewDedup(monitor.DefaultDedupWindow)
+		m.keptInputsPath = keptInputsPath
+		if runner != nil {
+			m.hookWorker = startHookWorker(runner)
+		}
+		if *auditLogPath != "" {
+			if m.audit, err = tui.OpenAuditLog(*auditLogPath); err != nil {
+				return fmt.Errorf("Failed to open --audit-log: %v", err)
+			}
+		}
+		if m.quota, err = newQuota(cfg.Quota, *quota, *quotaMode); err != nil {
+			return err
+		}
+
+		if len(notifyMethods) > 0 {
+			m.notifier = &notifier{methods: notifyMethods}
+		}
+
+		if *metricsAddr != "" {
+			registry := metrics.NewRegistry()
+			m.metrics = newMonitorMetrics(registry)
+			if _, err := registry.Serve(*metricsAddr); err != nil {
+				return fmt.Errorf("Failed to serve --metrics-addr: %v", err)
+			}
+		}
+
+		if *presence {
+			if *operator == "" {
+				return errors.New("--presence needs --operator (or $USER)")
+			}
+			m.presence = &presenceState{operator: *operator, peers: make(map[string]events.Presence)}
+		}
+
+		if *pickSession {
+			m.sessionPicker = &sessionPicker{sessions: make(map[string]events.SessionAnnouncement)}
+		}
+
+		if *snapshotInterval > 0 {
+			m.snapshot = &snapshotState{path: snapshotPath, interval: *snapshotInterval}
+		}
+		if crashed != nil && len(crashed.Pending) > 0 {
+			m.restoreOffer = crashed
+		}
+
+		if *tailFile != "" {
+			stop, err := monitor.TailSource{Path: *tailFile}.Start(m.bus)
+			if err != nil {
+				return fmt.Errorf("Failed to follow --tail: %v", err)
+			}
+			m.stopSources = append(m.stopSources, stop)
+			m.tailFile = *tailFile
+		}
+	}
+
+	// Start Bubbletea program with alt screen
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if m.notifier != nil {
+		options = append(options, tea.WithReportFocus()) // Nothing is sent while the terminal has focus
+	}
+	p := tea.NewProgram(m, options...)
+	final, err := p.Run()
+	if err != nil {
+		return err // A panic keeps the snapshot for the next start
+	}
+
+	// Text still being typed is kept for when the requests ask again
+	if fm, ok := final.(model); ok && fm.replay == nil {
+		fm.keepOpenInputs()
+	}
+
+	m.policyLog.Close()
+	m.audit.Close()
+	if m.archive != nil {
+		uploaded, err := m.archive.Close()
+		if err != nil {
+			log.Printf("Archive: %v (closed segments stay local and are retried on the next start)", err)
+		} else if uploaded > 0 {
+			log.Printf("Archive: uploaded %d segment(s)", uploaded)
+		}
+	}
+
+	// A clean live exit has nothing to recover, unless the restore was left undecided
+	if fm, ok := final.(model); ok && fm.err == nil && fm.restoreOffer == nil && fm.replay == nil {
+		if err := config.RemoveSnapshot(snapshotPath); err != nil {
+			log.Printf("Failed to remove the crash-recovery snapshot: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/durch/agneto/v2/pkg/transport"
+	"github.com/durch/agneto/v2/pkg/tui"
+	"github.com/google/uuid"
+)
+
+// testSubject is the subject the test model watches and answers on
+const testSubject = "agneto.test.events"
+
+// newTestModel returns a model connected to an in-memory transport, with its
+// outbox in a temporary directory, set up as main does without flags
+func newTestModel(t *testing.T) model {
+	t.Helper()
+	ob, err := outbox.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := transport.NewMemory()
+	t.Cleanup(bus.Close)
+
+	paneManager := tui.NewPaneManager(100)
+	return model{
+		paneManager:     paneManager,
+		transport:       bus,
+		durable:         transport.Publisher{Transport: bus},
+		outbox:          ob,
+		subject:         testSubject,
+		audit:           tui.NewAuditLog(),
+		actionManager:   tui.NewActionManager(),
+		consumedActions: make(map[string]bool),
+		drafts:          make(map[string]*inputDraft),
+		escalations:     make(map[string]escalationState),
+		deadlines:       make(map[string]time.Time),
+		approvals:       make(map[string]*approvalState),
+		usage:           tui.NewUsageTotals(),
+		payloadScroll:   &tui.PayloadScroll{},
+		payloadFold:     &tui.PayloadFold{},
+		activePane:      paneManager.DefaultPane,
+		hiddenTypes:     make(tui.TypeSet),
+		types:           tui.NewTypeRegistry(nil),
+		loadedContent:   make(map[string]bool),
+		lifecycles:      make(tui.Lifecycles),
+		width:           160,
+		height:          50,
+	}
+}
+
+// noDebounce lets a test press action keys in quick succession
+func noDebounce(t *testing.T) {
+	previous := tui.ActionDebounce
+	t.Cleanup(func() { tui.ActionDebounce = previous })
+	tui.ActionDebounce = 0
+}
+
+// update feeds a message to the model
+func update(m model, msg tea.Msg) (model, tea.Cmd) {
+	next, cmd := m.Update(msg)
+	return next.(model), cmd
+}
+
+// deliver hands the model a live event, as if it arrived on the subject
+func deliver(t *testing.T, m model, event events.Event) model {
+	t.Helper()
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	m, _ = update(m, eventReceivedMsg(event))
+	return m
+}
+
+// press presses a key
+func press(m model, key string) (model, tea.Cmd) {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	switch key {
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	return update(m, msg)
+}
+
+// run runs a command and feeds back the messages it produces within a
+// moment, as the program would (commands still waiting, e.g. ticks, are dropped)
+func run(m model, cmd tea.Cmd) model {
+	if cmd == nil {
+		return m
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-done:
+	case <-time.After(100 * time.Millisecond):
+		return m
+	}
+	switch msg := msg.(type) {
+	case nil:
+	case tea.BatchMsg:
+		for _, c := range msg {
+			m = run(m, c)
+		}
+	case tea.QuitMsg:
+	default:
+		m, cmd = update(m, msg)
+		m = run(m, cmd)
+	}
+	return m
+}
+
+// decision returns an event with a button action on each key
+func decision(eventType string, keys ...string) events.Event {
+	event := events.Event{ID: uuid.New().String(), Type: eventType, Message: eventType + "?"}
+	for _, key := range keys {
+		event.Actions = append(event.Actions, events.Action{
+			ID: "on-" + key, Label: "On " + key, Key: key,
+			Event: events.Event{Type: eventType + "_" + key},
+		})
+	}
+	return event
+}
+
+// responses subscribes to the responses published on the test subject
+func responses(t *testing.T, m model) func() []string {
+	t.Helper()
+	got := make(chan string, 16)
+	if _, err := m.transport.Subscribe(testSubject, func(msg transport.Msg) {
+		if event, err := events.FromJSON(msg.Data); err == nil {
+			got <- event.Type
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return func() []string {
+		var types []string
+		for {
+			select {
+			case eventType := <-got:
+				types = append(types, eventType)
+			case <-time.After(50 * time.Millisecond):
+				return types
+			}
+		}
+	}
+}
+
+func TestRepeatedActionKeyAnswersOnce(t *testing.T) {
+	m := newTestModel(t)
+	published := responses(t, m)
+	m = deliver(t, m, decision("deploy", "s", "x"))
+
+	// Key repeat delivers the same key again before the response is queued
+	m, first := press(m, "s")
+	m, second := press(m, "s")
+	if second != nil {
+		t.Fatal("the repeated press published again")
+	}
+	if m.stateFilter != "" {
+		t.Fatalf("the repeated press reached the state filter shortcut (filter %q)", m.stateFilter)
+	}
+	m = run(m, first)
+	if got := published(); len(got) != 1 || got[0] != "deploy_s" {
+		t.Fatalf("published %v, want one deploy_s", got)
+	}
+	if m.actionManager.InFlight() {
+		t.Fatal("the answered action is still in flight")
+	}
+}
+
+func TestConsumedDecisionIgnoresKeys(t *testing.T) {
+	m := newTestModel(t)
+	published := responses(t, m)
+	event := decision("deploy", "y", "n")
+	m = deliver(t, m, event)
+	m.consumedActions[event.ID] = true // Answered, its response not yet settled
+
+	m, cmd := press(m, "y")
+	if cmd != nil {
+		t.Fatal("a consumed decision published again")
+	}
+	if m.actionManager.InFlight() {
+		t.Fatal("the ignored press left an action in flight")
+	}
+	if !m.actionManager.HasActions() {
+		t.Fatal("the ignored press cleared the decision's actions")
+	}
+	if got := published(); len(got) != 0 {
+		t.Fatalf("published %v, want nothing", got)
+	}
+}
+
+func TestNextDecisionIgnoresKeysWithinDebounce(t *testing.T) {
+	m := newTestModel(t)
+	published := responses(t, m)
+	m = deliver(t, m, decision("first", "y"))
+	m = deliver(t, m, decision("second", "y"))
+
+	m, cmd := press(m, "y")
+	m = run(m, cmd)
+	if m.activeEventID() == "" {
+		t.Fatal("the queued decision didn't become active")
+	}
+	// Held down, the key would answer the next decision too
+	m, cmd = press(m, "y")
+	m = run(m, cmd)
+	if got := published(); len(got) != 1 || got[0] != "first_y" {
+		t.Fatalf("published %v, want only first_y", got)
+	}
+
+	noDebounce(t)
+	m, cmd = press(m, "y")
+	run(m, cmd)
+	if got := published(); len(got) != 1 || got[0] != "second_y" {
+		t.Fatalf("published %v, want second_y once the debounce passed", got)
+	}
+}
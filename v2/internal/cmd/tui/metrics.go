@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/metrics"
+)
+
+// monitorMetrics are what the monitor exposes to Prometheus with --metrics-addr
+type monitorMetrics struct {
+	received  *metrics.Counter   // Events listed, by type and pane
+	dropped   *metrics.Counter   // Events not listed, by reason
+	pending   *metrics.Gauge     // Decisions waiting for a response
+	outbox    *metrics.Gauge     // Responses waiting in the outbox
+	responses *metrics.Histogram // Seconds from a decision's arrival to its response, by type
+}
+
+// Reasons events are dropped, the dropped counter's label
+const (
+	dropDuplicate = "duplicate" // Idempotency key seen before
+	dropQuota     = "quota"     // Producer over its quota
+	dropFiltered  = "filtered"  // A transform rule or hook dropped it
+)
+
+// newMonitorMetrics registers the monitor's metrics
+func newMonitorMetrics(r *metrics.Registry) *monitorMetrics {
+	return &monitorMetrics{
+		received:  r.Counter("agneto_events_received_total", "Events listed, by type and pane.", "type", "pane"),
+		dropped:   r.Counter("agneto_events_dropped_total", "Events not listed: duplicate, quota or filtered.", "reason"),
+		pending:   r.Gauge("agneto_pending_actions", "Decisions waiting for a response, queued and drafts included."),
+		outbox:    r.Gauge("agneto_outbox_queued", "Responses waiting in the outbox for the broker."),
+		responses: r.Histogram("agneto_action_response_seconds", "Time from a decision's arrival to its response, by event type.", metrics.LatencyBuckets, "type"),
+	}
+}
+
+// countEvent counts an event listed in a pane
+func (mm *monitorMetrics) countEvent(eventType, pane string) {
+	if mm != nil {
+		mm.received.Inc(eventType, pane)
+	}
+}
+
+// countDrop counts an event that wasn't listed
+func (mm *monitorMetrics) countDrop(reason string) {
+	if mm != nil {
+		mm.dropped.Inc(reason)
+	}
+}
+
+// updateGauges brings the gauges up to date; called every tick
+func (m model) updateGauges() {
+	if m.metrics == nil {
+		return
+	}
+	m.metrics.pending.Set(float64(len(m.pendingDecisions())))
+	m.metrics.outbox.Set(float64(m.outboxQueued))
+}
+
+// observeResponse records how long a decision waited for its response
+func (m model) observeResponse(eventID string) {
+	if m.metrics == nil || eventID == "" {
+		return
+	}
+	name, index, ok := m.locateEvent(eventID)
+	if !ok {
+		return
+	}
+	pane := m.paneManager.GetPane(name)
+	if arrived := pane.ArrivedAt(index); !arrived.IsZero() {
+		m.metrics.responses.Observe(time.Since(arrived).Seconds(), pane.Events[index].Type)
+	}
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// Notification methods (--notify, "notify" in the settings file)
+const (
+	notifyBell    = "bell"    // Terminal bell: most terminals flag the tab or window, or beep
+	notifyOSC777  = "osc777"  // Desktop notification sent through the terminal (foot, WezTerm, Ghostty, urxvt)
+	notifyDesktop = "desktop" // notify-send on Linux, osascript on macOS: the desktop of the machine the TUI runs on
+)
+
+// notifyInterval is the least time between notifications, so a burst of decisions notifies once
+const notifyInterval = 10 * time.Second
+
+// notifyTimeout bounds running notify-send or osascript
+const notifyTimeout = 5 * time.Second
+
+// maxNotifyBody bounds the event text shown in a notification
+const maxNotifyBody = 200
+
+// notifyFailedMsg is sent when a notification couldn't be shown
+type notifyFailedMsg struct{ err error }
+
+// notifier tells the operator about decisions that block the stream while
+// they look elsewhere
+// AIDEV-NOTE: The terminal reports focus changes (tea.WithReportFocus), and
+// nothing is sent while it has focus. Terminals that don't report focus are
+// taken to be unfocused, so they are always notified
+type notifier struct {
+	methods []string
+	focused bool      // The terminal reported having focus
+	last    time.Time // When the last notification went out
+}
+
+// parseNotify parses a comma-separated list of notification methods
+func parseNotify(s string) ([]string, error) {
+	var methods []string
+	for _, method := range strings.Split(s, ",") {
+		switch method = strings.TrimSpace(method); method {
+		case "":
+		case notifyBell, notifyOSC777, notifyDesktop:
+			methods = append(methods, method)
+		default:
+			return nil, fmt.Errorf("unknown notification %q (want %s, %s or %s)", method, notifyBell, notifyOSC777, notifyDesktop)
+		}
+	}
+	return methods, nil
+}
+
+// setFocus records the terminal gaining or losing focus
+func (n *notifier) setFocus(focused bool) {
+	if n != nil {
+		n.focused = focused
+	}
+}
+
+// decision returns the command notifying of a decision that arrived, or
+// nil when notifications are off, the terminal has focus or one just went out
+func (n *notifier) decision(event events.Event, now time.Time) tea.Cmd {
+	if n == nil || n.focused || now.Sub(n.last) < notifyInterval {
+		return nil
+	}
+	n.last = now
+	methods := n.methods
+	title, body := notificationText(event)
+	return func() tea.Msg {
+		var errs []error
+		for _, method := range methods {
+			if err := notify(method, title, body); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", method, err))
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			return notifyFailedMsg{err}
+		}
+		return nil
+	}
+}
+
+// notificationText returns the title and body notifying of a decision
+func notificationText(event events.Event) (string, string) {
+	title := i18n.T("notify.title")
+	if source := event.EffectiveSource(); source != "" {
+		title += " (" + source + ")"
+	}
+	body := event.Message
+	if body == "" {
+		body = event.Type
+	}
+	body = strings.Join(strings.Fields(body), " ")
+	if runes := []rune(body); len(runes) > maxNotifyBody {
+		body = string(runes[:maxNotifyBody-1]) + "…"
+	}
+	return title, body
+}
+
+// notify shows a notification one way
+func notify(method, title, body string) error {
+	switch method {
+	case notifyBell:
+		_, err := os.Stdout.WriteString("\a")
+		return err
+	case notifyOSC777:
+		_, err := os.Stdout.WriteString(osc777(title, body))
+		return err
+	case notifyDesktop:
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.CommandContext(ctx, "osascript",
+				"-e", "on run argv", "-e", "display notification (item 2 of argv) with title (item 1 of argv)", "-e", "end run",
+				title, body)
+		case "windows":
+			return errors.New("not supported on Windows (try osc777)")
+		default:
+			cmd = exec.CommandContext(ctx, "notify-send", "--app-name=agneto", title, body)
+		}
+		return cmd.Run() // Output discarded: it would garble the screen
+	}
+	return fmt.Errorf("unknown notification %q", method)
+}
+
+// osc777 returns the escape sequence asking the terminal for a desktop
+// notification, wrapped for tmux to pass it through
+// Fields are separated by semicolons, so the title can't contain any
+func osc777(title, body string) string {
+	clean := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r < 0x20 || r == 0x7f {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	title = strings.ReplaceAll(clean(title), ";", ",")
+	return tmuxPassthrough("\x1b]777;notify;" + title + ";" + clean(body) + "\x07")
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// outboxFlushedMsg is sent when an outbox retry finishes
+type outboxFlushedMsg struct {
+	sent   int
+	queued int // Entries still waiting
+	err    error
+}
+
+// publishDurably queues a payload in the outbox and tries to deliver the queue
+// Returns err only if the payload couldn't be queued; a failed delivery is
+// returned as deferred, since the outbox retries it
+// AIDEV-NOTE: Publishing through the outbox (instead of nc.Publish) keeps
+// responses in order behind any that are still waiting for the broker
+func publishDurably(ob *outbox.Outbox, pub outbox.Publisher, subject string, data []byte) (deferred error, err error) {
+	if subject, err = sessionSubject(subject, data); err != nil {
+		return nil, err
+	}
+	if _, err := ob.Enqueue(subject, data); err != nil {
+		return nil, fmt.Errorf("outbox: %w", err)
+	}
+	_, deferred = ob.Flush(pub)
+	return deferred, nil
+}
+
+// publishReply also sends a response to the reply subject of the event it answers
+// AIDEV-NOTE: Reply inboxes only exist while the producer waits, so replies go
+// out directly instead of through the outbox or edge stream, where an entry
+// for a gone inbox would be retried forever. The response is on the events
+// subject either way; a failure here only costs the producer its fast path
+func publishReply(t transport.Transport, action events.Action, data []byte) {
+	if t == nil || action.ReplyTo == "" {
+		return
+	}
+	t.Publish(transport.Msg{Subject: action.ReplyTo, Data: data})
+}
+
+// retryOutbox returns a command delivering queued responses, if any are waiting
+func (m *model) retryOutbox() tea.Cmd {
+	if m.outboxQueued == 0 || m.transport == nil || m.flushingOutbox {
+		return nil
+	}
+	m.flushingOutbox = true
+
+	ob, pub := m.outbox, m.durable
+	return func() tea.Msg {
+		sent, err := ob.Flush(pub)
+		return outboxFlushedMsg{sent: sent, queued: ob.Len(), err: err}
+	}
+}
+
+// noteDeferred records a response that's waiting in the outbox
+func (m *model) noteDeferred(deferred error) {
+	m.outboxQueued = m.outbox.Len()
+	if deferred != nil {
+		m.status = i18n.T("outbox.queued", deferred)
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// tickMsg is sent periodically so time-based UI (pending ages) stays current
+type tickMsg time.Time
+
+// tickCmd schedules the next tickMsg
+func tickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// pendingDecision is an event waiting for the operator to respond
+type pendingDecision struct {
+	pane      string    // Pane holding the event
+	index     int       // Event index within the pane
+	since     time.Time // When the decision started waiting
+	escalated bool      // Also handed to alternate approvers
+}
+
+// pendingDecisions returns all decisions still waiting for a response, oldest first
+// AIDEV-NOTE: Completion-aware - entries disappear as soon as the action is
+// published or input is submitted/cancelled, so the reminder never goes stale
+func (m model) pendingDecisions() []pendingDecision {
+	var pending []pendingDecision
+	if m.blockingEventIndex != nil {
+		decision := pendingDecision{
+			pane:  m.blockingPane,
+			index: *m.blockingEventIndex,
+			since: m.blockingSince,
+		}
+		if event := m.blockingEvent(); event != nil {
+			_, decision.escalated = m.escalations[event.ID]
+		}
+		pending = append(pending, decision)
+	}
+
+	// So are decisions queued behind the active one
+	for _, queued := range m.actionManager.Queued() {
+		pane, index, ok := m.locateEvent(queued.EventID)
+		if !ok {
+			continue
+		}
+		_, escalated := m.escalations[queued.EventID]
+		pending = append(pending, pendingDecision{pane: pane, index: index, since: queued.Since, escalated: escalated})
+	}
+
+	// Drafts set aside are still unanswered
+	for _, id := range m.draftIDs() {
+		pane, index, ok := m.locateEvent(id)
+		if !ok {
+			continue
+		}
+		_, escalated := m.escalations[id]
+		pending = append(pending, pendingDecision{pane: pane, index: index, since: m.drafts[id].since, escalated: escalated})
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].since.Before(pending[j].since) })
+	return pending
+}
+
+// pendingIDs returns the IDs of the events of every decision still waiting
+// for a response: the active one, the queued ones and the drafts
+func (m model) pendingIDs() []string {
+	var ids []string
+	if id := m.activeEventID(); id != "" {
+		ids = append(ids, id)
+	}
+	for _, queued := range m.actionManager.Queued() {
+		ids = append(ids, queued.EventID)
+	}
+	for id := range m.drafts {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// activeEventID returns the ID of the event waiting for a response ("" for none)
+func (m model) activeEventID() string {
+	if event := m.blockingEvent(); event != nil {
+		return event.ID
+	}
+	return ""
+}
+
+// blockingEvent returns the event waiting for a response, if any
+func (m model) blockingEvent() *events.Event {
+	if m.blockingEventIndex == nil {
+		return nil
+	}
+	return m.paneManager.GetEventByIndex(m.blockingPane, *m.blockingEventIndex)
+}
+
+// blockingQuestion returns the question carried by the blocking event, if any
+func (m model) blockingQuestion() *events.Question {
+	event := m.blockingEvent()
+	if event == nil {
+		return nil
+	}
+	return event.Question
+}
+
+// jumpToOldestPending brings the oldest pending decision into view and selects it
+func (m *model) jumpToOldestPending() {
+	pending := m.pendingDecisions()
+	if len(pending) == 0 {
+		return
+	}
+	oldest := pending[0]
+	if event := m.paneManager.GetEventByIndex(oldest.pane, oldest.index); event != nil {
+		m.jumpTo(event.ID)
+	}
+	m.status = ""
+}
+
+// renderPendingReminder renders the sticky status bar reminder of pending decisions
+// Stays visible regardless of scrolling, filtering or the pane being shown
+func renderPendingReminder(pending []pendingDecision, now time.Time) string {
+	if len(pending) == 0 {
+		return ""
+	}
+
+	id := "pending.one"
+	if len(pending) > 1 {
+		id = "pending.many"
+	}
+	age := now.Sub(pending[0].since)
+
+	escalated := ""
+	if pending[0].escalated {
+		escalated = i18n.T("pending.escalated")
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214")).
+		Bold(true).
+		Render(i18n.T(id, len(pending), formatAge(age), escalated))
+}
+
+// formatAge formats a duration coarsely for status display (e.g. "45s", "4m", "2h")
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// answerUnattended answers the event just routed without the operator: with
+// the action a hook chose, otherwise with the one a --policy rule picks
+// Returns nil when neither answers it, leaving it to the operator
+// Like hooks, policies only see live events
+func (m *model) answerUnattended(event events.Event, hookResponse string) tea.Cmd {
+	if cmd := m.autoRespond(event, hookResponse); cmd != nil {
+		return cmd
+	}
+	if m.transport == nil {
+		return nil
+	}
+	decision, ok := m.policy.Decide(event)
+	if !ok {
+		return nil
+	}
+	m.status = i18n.T("policy.status", decision.String())
+	if err := m.policyLog.Record(decision); err != nil {
+		m.status += " " + i18n.T("policy.not_logged", err)
+	}
+	if event.ID == m.activeEventID() {
+		m.actionManager.ClearAll()
+		m.actionManager.MarkInFlight()
+	}
+	return publishActionResponseCmd(m.transport, m.outbox, m.durable, m.subject, event.ID, decision.Action)
+}
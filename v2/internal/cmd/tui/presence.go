@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// presenceState tracks this operator's announced cursor and the cursors of others
+type presenceState struct {
+	operator string
+	sub      transport.Subscription
+	msgChan  chan transport.Msg
+	peers    map[string]events.Presence // By Peer(); own announcements excluded
+	sent     events.Presence            // Last announcement (At is when it was sent)
+}
+
+// presenceReadyMsg is sent when the presence subscription is ready
+type presenceReadyMsg struct {
+	sub     transport.Subscription
+	msgChan chan transport.Msg
+}
+
+// presenceMsg is sent when another monitor announces its cursor
+type presenceMsg struct{ presence events.Presence }
+
+// subscribeToPresence subscribes to cursor announcements
+func subscribeToPresence(t transport.Transport) tea.Cmd {
+	return func() tea.Msg {
+		msgChan := make(chan transport.Msg, 64)
+		sub, err := transport.Chan(t, events.PresenceSubject, msgChan)
+		if err != nil {
+			return errMsg{err}
+		}
+		return presenceReadyMsg{sub: sub, msgChan: msgChan}
+	}
+}
+
+// waitForPresence waits for the next valid announcement
+// Like control commands, presence keeps flowing while the event stream is blocked
+func waitForPresence(msgChan chan transport.Msg) tea.Cmd {
+	return func() tea.Msg {
+		for msg := range msgChan {
+			if p, err := events.PresenceFromJSON(msg.Data); err == nil {
+				return presenceMsg{presence: *p}
+			}
+		}
+		return nil
+	}
+}
+
+// notePresence records another monitor's cursor
+func (m *model) notePresence(p events.Presence) {
+	if m.presence == nil || p.Peer() == m.ownPresence().Peer() {
+		return
+	}
+	if p.Leaving {
+		delete(m.presence.peers, p.Peer())
+		return
+	}
+	m.presence.peers[p.Peer()] = p
+}
+
+// ownPresence returns this monitor's current cursor
+func (m model) ownPresence() events.Presence {
+	p := events.Presence{
+		Operator: m.presence.operator,
+		Instance: m.instance,
+		EventID:  m.selectedEventID(),
+		Pane:     m.activePane,
+	}
+	if m.inputMode && m.blockingEventIndex != nil && m.activePane == m.blockingPane && m.selectedEventIndex == *m.blockingEventIndex {
+		p.Drafting = true
+	}
+	return p
+}
+
+// announcePresence publishes the cursor when it moved, or as a heartbeat, and forgets silent peers
+// Presence is ephemeral, so it is published directly rather than through the outbox
+func (m *model) announcePresence() {
+	if m.presence == nil || m.transport == nil {
+		return
+	}
+	now := time.Now()
+	for peer, p := range m.presence.peers {
+		if p.Expired(now) {
+			delete(m.presence.peers, peer)
+		}
+	}
+
+	p := m.ownPresence()
+	last := m.presence.sent
+	moved := p.EventID != last.EventID || p.Pane != last.Pane || p.Drafting != last.Drafting
+	if !moved && now.Sub(last.At) < events.PresenceInterval {
+		return
+	}
+	p.At = now
+	if data, err := json.Marshal(p); err == nil && m.transport.Publish(transport.Msg{Subject: events.PresenceSubject, Data: data}) == nil {
+		m.presence.sent = p
+	}
+}
+
+// leavePresence tells other monitors to drop this cursor
+func (m *model) leavePresence() {
+	if m.presence == nil || m.transport == nil {
+		return
+	}
+	p := m.ownPresence()
+	p.Leaving = true
+	p.At = time.Now()
+	if data, err := json.Marshal(p); err == nil {
+		m.transport.Publish(transport.Msg{Subject: events.PresenceSubject, Data: data})
+		transport.Flush(m.transport, time.Second)
+	}
+	if m.presence.sub != nil {
+		m.presence.sub.Unsubscribe()
+	}
+}
+
+// peersOn returns the operators whose cursor is on an event, drafting ones marked with ✎
+func (m model) peersOn(eventID string) []string {
+	if m.presence == nil {
+		return nil
+	}
+	var names []string
+	for _, p := range m.presence.peers {
+		if p.EventID != eventID {
+			continue
+		}
+		if p.Drafting {
+			names = append(names, "✎"+p.Operator)
+		} else {
+			names = append(names, "◆"+p.Operator)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// onlineOperators returns the other operators with a live cursor
+func (m model) onlineOperators() string {
+	if m.presence == nil {
+		return ""
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, p := range m.presence.peers {
+		if !seen[p.Operator] {
+			seen[p.Operator] = true
+			names = append(names, p.Operator)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,19 @@
+package tui
+
+import (
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// loadFullContent renders the selected event's whole Content instead of its preview
+func (m *model) loadFullContent() {
+	event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex)
+	if event == nil {
+		return
+	}
+	if _, hidden := tui.ContentPreview(event.Content); hidden == 0 {
+		return
+	}
+	m.loadedContent[event.ID] = true
+	m.status = i18n.T("preview.loaded", len(event.Content)/1024)
+}
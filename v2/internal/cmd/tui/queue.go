@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// queueDecision puts an event's actions behind the active decision
+// The stream keeps flowing; tab brings the decision forward
+func (m *model) queueDecision(event events.Event) {
+	m.actionManager.Enqueue(tui.QueuedDecision{
+		EventID: event.ID,
+		Actions: event.Actions,
+		Since:   time.Now(),
+	})
+	m.status = i18n.T("queue.queued", event.Message, m.actionManager.QueueLen())
+}
+
+// activateDecision makes a queued decision the active one and selects its event
+// Returns false if the event is no longer in memory
+func (m *model) activateDecision(decision tui.QueuedDecision) bool {
+	pane, index, ok := m.locateEvent(decision.EventID)
+	if !ok {
+		m.status = i18n.T("queue.evicted", shortID(decision.EventID))
+		return false
+	}
+	m.actionManager.RegisterActions(decision.Actions, index)
+	m.blockingEventIndex = &index
+	m.blockingPane = pane
+	m.blockingSince = decision.Since
+	m.focusPane(pane)
+	m.selectedEventIndex = index
+	return true
+}
+
+// promoteQueued activates the oldest queued decision once the active one is settled
+func (m *model) promoteQueued() {
+	if m.blockingEventIndex != nil {
+		return
+	}
+	for {
+		decision, ok := m.actionManager.Next()
+		if !ok || m.activateDecision(decision) {
+			return
+		}
+	}
+}
+
+// switchDecision puts the active decision at the back of the queue and
+// activates the next one (tab)
+// Returns false if there's nothing to switch to
+func (m *model) switchDecision() bool {
+	event := m.blockingEvent()
+	if event == nil || m.inputMode || m.actionManager.InFlight() || m.actionManager.QueueLen() == 0 {
+		return false
+	}
+	m.actionManager.Enqueue(tui.QueuedDecision{
+		EventID: event.ID,
+		Actions: event.Actions,
+		Since:   m.blockingSince,
+	})
+	m.actionManager.ClearAll()
+	m.blockingEventIndex = nil
+	m.promoteQueued()
+	return true
+}
+
+// routeEvent routes an event to its pane, keeping the active decision and
+// the selection on their events: evicting older events shifts the indices of
+// the ones after them, and a late arrival inserted before them does too
+func (m *model) routeEvent(event events.Event) *tui.Pane {
+	// A later event of a stream grows the stream's entry in place: nothing moves
+	if pane := m.paneManager.ContinueStream(event); pane != nil {
+		return pane
+	}
+
+	active := m.activeEventID()
+	onDecision := active != "" && m.selectedEventID() == active
+	panes := len(m.paneManager.Panes)
+	// Floods never evict unanswered decisions, including one arriving now
+	held := m.pendingIDs()
+	if len(event.Actions) > 0 {
+		held = append(held, event.ID)
+	}
+	m.paneManager.Hold(held)
+	pane := m.paneManager.RouteEvent(event)
+	m.trackDecision(active)
+	if len(m.paneManager.Panes) > panes {
+		m.paneDirectory.update(m.paneManager)
+		m.status = i18n.T("status.new_pane", pane.Name)
+	}
+
+	switch {
+	case onDecision && m.blockingEventIndex != nil && m.blockingPane == m.activePane:
+		m.selectedEventIndex = *m.blockingEventIndex // Stay on the decision being answered
+	case pane != nil && pane.Name == m.activePane && pane.LastAdded >= 0 &&
+		pane.LastAdded <= m.selectedEventIndex && pane.LastAdded < len(pane.Events)-1:
+		m.selectedEventIndex = min(m.selectedEventIndex+1, len(pane.Events)-1)
+	}
+	return pane
+}
+
+// trackDecision finds the active decision again after an event was routed
+func (m *model) trackDecision(eventID string) {
+	if eventID == "" || m.blockingEventIndex == nil {
+		return
+	}
+	pane, index, ok := m.locateEvent(eventID)
+	if !ok {
+		m.status = i18n.T("queue.dropped", shortID(eventID))
+		m.actionManager.ClearAll()
+		m.blockingEventIndex = nil
+		m.inputMode = false
+		m.inputAction = nil
+		m.promoteQueued()
+		return
+	}
+	m.blockingEventIndex = &index
+	m.blockingPane = pane
+}
+
+// isQueued reports whether an event's decision waits in the queue
+func (m model) isQueued(eventID string) bool {
+	for _, decision := range m.actionManager.Queued() {
+		if decision.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
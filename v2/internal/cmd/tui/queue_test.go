@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+func TestActionKeysTakePrecedenceOverShortcuts(t *testing.T) {
+	m := newTestModel(t)
+	published := responses(t, m)
+
+	// Without a decision, s cycles the state filter
+	m, _ = press(m, "s")
+	if m.stateFilter == "" {
+		t.Fatal("s didn't cycle the state filter")
+	}
+	for m.stateFilter != "" {
+		m, _ = press(m, "s")
+	}
+
+	// A producer's action on s wins
+	m = deliver(t, m, decision("deploy", "s"))
+	m, cmd := press(m, "s")
+	m = run(m, cmd)
+	if m.stateFilter != "" {
+		t.Fatalf("s cycled the state filter to %q instead of answering", m.stateFilter)
+	}
+	if got := published(); len(got) != 1 || got[0] != "deploy_s" {
+		t.Fatalf("published %v, want deploy_s", got)
+	}
+}
+
+func TestNAnswersNoUnlessSearching(t *testing.T) {
+	m := newTestModel(t)
+	published := responses(t, m)
+	for i := 0; i < 3; i++ {
+		m = deliver(t, m, events.Event{Type: "build", Message: fmt.Sprintf("build %d", i)})
+	}
+
+	// While searching, n jumps to the next match
+	search, err := tui.NewSearch("build", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.search = search
+	m.selectedEventIndex = 0
+	m, _ = press(m, "n")
+	if m.selectedEventIndex != 1 {
+		t.Fatalf("n selected event %d, want the next match (1)", m.selectedEventIndex)
+	}
+
+	// Otherwise it answers a yes/no question's "No"
+	m.search = nil
+	m = deliver(t, m, decision("deploy", "y", "n"))
+	m, cmd := press(m, "n")
+	run(m, cmd)
+	if got := published(); len(got) != 1 || got[0] != "deploy_n" {
+		t.Fatalf("published %v, want deploy_n", got)
+	}
+}
+
+func TestQueuedDecisionsSurviveEviction(t *testing.T) {
+	noDebounce(t)
+	m := newTestModel(t)
+	published := responses(t, m)
+	for _, pane := range m.paneManager.Panes {
+		pane.MaxEvents = 3
+	}
+
+	first, second := decision("first", "y"), decision("second", "y")
+	m = deliver(t, m, first)
+	m = deliver(t, m, second)
+	// Enough noise to evict both decisions from a pane of 3 events
+	for i := 0; i < 10; i++ {
+		m = deliver(t, m, events.Event{Type: "log", Message: fmt.Sprintf("line %d", i)})
+	}
+
+	if got := m.activeEventID(); got != first.ID {
+		t.Fatalf("active decision is %q, want the first (%s)", got, first.ID)
+	}
+	m, cmd := press(m, "y")
+	m = run(m, cmd)
+	if got := m.activeEventID(); got != second.ID {
+		t.Fatalf("after answering, active decision is %q, want the queued one (%s)", got, second.ID)
+	}
+	m, cmd = press(m, "y")
+	run(m, cmd)
+	if got := published(); len(got) != 2 || got[0] != "first_y" || got[1] != "second_y" {
+		t.Fatalf("published %v, want first_y then second_y", got)
+	}
+}
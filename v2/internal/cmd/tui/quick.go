@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/outbox"
+)
+
+// reservedKeys are normal-mode keys quick publish keys can't take over
+var reservedKeys = map[string]bool{
+	"q": true, "ctrl+c": true, "up": true, "down": true, "k": true, "j": true,
+	"enter": true, "P": true, ",": true, "v": true, "b": true, "'": true,
+	"ctrl+o": true, "tab": true, "s": true, "L": true, "left": true, "right": true,
+	"shift+left": true, "shift+right": true, "home": true, "end": true, "o": true, "?": true,
+	"X": true, "esc": true, "J": true, "C": true, "@": true, "pgup": true, "pgdown": true, "ctrl+d": true, "ctrl+u": true, "M": true,
+	"shift+up": true, "shift+down": true, "T": true, "A": true,
+	"+": true, "-": true, "S": true, "m": true, "/": true, "n": true, "N": true, "]": true, "[": true, ":": true, "g": true, "$": true, "y": true, "c": true,
+}
+
+// quickPublishedMsg is sent when a quick publish event was queued (and, unless deferred, published)
+type quickPublishedMsg struct {
+	label    string
+	deferred error
+}
+
+// validateQuickPublish checks quick publish keys from the settings file
+// against the built-in keys, as remapped by keys
+func validateQuickPublish(quick []events.Action, keys keymap) error {
+	seen := make(map[string]bool)
+	for i, q := range quick {
+		switch {
+		case q.Key == "":
+			return fmt.Errorf("quick_publish[%d]: missing 'key'", i)
+		case q.Event.Type == "":
+			return fmt.Errorf("quick_publish[%d]: missing 'event.type'", i)
+		case keys.reserved(q.Key):
+			return fmt.Errorf("quick_publish[%d]: key %q is a built-in shortcut", i, q.Key)
+		case seen[q.Key]:
+			return fmt.Errorf("quick_publish[%d]: key %q is bound twice", i, q.Key)
+		}
+		seen[q.Key] = true
+	}
+	return nil
+}
+
+// quickPublishFor returns the quick publish binding for a key
+func (m model) quickPublishFor(key string) (events.Action, bool) {
+	for _, q := range m.config.QuickPublish {
+		if q.Key == key {
+			return q, true
+		}
+	}
+	return events.Action{}, false
+}
+
+// publishQuickCmd publishes a quick publish event through the outbox
+// Unlike action responses it doesn't answer anything, so blocking state is untouched
+func publishQuickCmd(ob *outbox.Outbox, pub outbox.Publisher, subject string, q events.Action) tea.Cmd {
+	return func() tea.Msg {
+		data, err := q.Response().ToJSON()
+		if err != nil {
+			return errMsg{err}
+		}
+		deferred, err := publishDurably(ob, pub, subject, data)
+		if err != nil {
+			return errMsg{err}
+		}
+		return quickPublishedMsg{label: q.Label, deferred: deferred}
+	}
+}
+
+// renderQuickKeys renders the quick publish keys as a hint line
+func renderQuickKeys(quick []events.Action) string {
+	if len(quick) == 0 {
+		return ""
+	}
+	hints := make([]string, 0, len(quick))
+	for _, q := range quick {
+		label := q.Label
+		if label == "" {
+			label = q.Event.Type
+		}
+		hints = append(hints, fmt.Sprintf("%s: %s", q.Key, label))
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("243")).
+		Render(i18n.T("actions.quick_publish") + strings.Join(hints, " · "))
+}
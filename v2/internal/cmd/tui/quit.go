@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/outbox"
+)
+
+// abandonedMsg is sent when abandoned responses were queued
+// Ones the broker didn't take yet stay in the outbox for the next start
+type abandonedMsg struct{}
+
+// requestQuit quits, or asks first if decisions are unanswered or responses unsent
+// Replays have nothing to answer or send
+func (m model) requestQuit() (tea.Model, tea.Cmd) {
+	if m.replay == nil {
+		entries, _ := m.outbox.List()
+		if len(m.pendingDecisions()) > 0 || len(entries) > 0 {
+			m.quitConfirmOpen = true
+			m.quitUnsent = entries
+			return m, nil
+		}
+	}
+	m.closeConnections()
+	return m, tea.Quit
+}
+
+// updateQuitConfirm handles keys on the quit confirmation
+func (m model) updateQuitConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "a", "enter":
+		// Answer now
+		m.quitConfirmOpen = false
+		m.jumpToOldestPending()
+
+	case "x":
+		// Abandon unanswered decisions, then quit
+		var abandoned []events.Event
+		for _, p := range m.pendingDecisions() {
+			if event := m.paneManager.GetEventByIndex(p.pane, p.index); event != nil {
+				abandoned = append(abandoned, events.NewAbandoned(*event, m.instance))
+			}
+		}
+		if len(abandoned) == 0 || m.transport == nil {
+			m.closeConnections()
+			return m, tea.Quit
+		}
+		return m, publishAbandonedCmd(m.outbox, m.durable, m.subject, abandoned)
+
+	case "Q", "ctrl+c":
+		// Quit anyway
+		m.closeConnections()
+		return m, tea.Quit
+
+	case "esc":
+		m.quitConfirmOpen = false
+	}
+	return m, nil
+}
+
+// publishAbandonedCmd publishes abandoned responses through the outbox
+func publishAbandonedCmd(ob *outbox.Outbox, pub outbox.Publisher, subject string, abandoned []events.Event) tea.Cmd {
+	return func() tea.Msg {
+		for _, event := range abandoned {
+			data, err := event.ToJSON()
+			if err != nil {
+				return errMsg{err}
+			}
+			if _, err := publishDurably(ob, pub, subject, data); err != nil {
+				return errMsg{err}
+			}
+		}
+		return abandonedMsg{}
+	}
+}
+
+// renderQuitConfirm renders the list of unanswered decisions and unsent responses
+func (m model) renderQuitConfirm(width int) string {
+	label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+
+	var content strings.Builder
+	content.WriteString(label.Render(i18n.T("quit.title")))
+	content.WriteString("\n\n")
+
+	pending := m.pendingDecisions()
+	if len(pending) > 0 {
+		content.WriteString(i18n.T("quit.pending", len(pending)) + "\n")
+		for _, p := range pending {
+			event := m.paneManager.GetEventByIndex(p.pane, p.index)
+			if event == nil {
+				continue
+			}
+			content.WriteString(fmt.Sprintf("  • %s  %s %s\n", event.Type, event.Message,
+				dim.Render(i18n.T("quit.waiting", formatAge(time.Since(p.since))))))
+		}
+		content.WriteString("\n")
+	}
+
+	if len(m.quitUnsent) > 0 {
+		content.WriteString(i18n.T("quit.unsent", len(m.quitUnsent), m.outbox.Dir()) + "\n")
+		for _, entry := range m.quitUnsent {
+			summary := entry.Subject
+			if event, err := events.FromJSON(entry.Data); err == nil {
+				summary = fmt.Sprintf("%s  %s", event.Type, event.Message)
+			}
+			content.WriteString(fmt.Sprintf("  • %s %s\n", summary,
+				dim.Render(i18n.T("quit.attempts", entry.Attempts))))
+		}
+		content.WriteString(dim.Render("  " + i18n.T("quit.retried")))
+		content.WriteString("\n\n")
+	}
+
+	var options []string
+	if len(pending) > 0 {
+		options = append(options, i18n.T("quit.answer"), i18n.T("quit.abandon"))
+	}
+	options = append(options, i18n.T("quit.anyway"), i18n.T("quit.cancel"))
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(strings.Join(options, " | ")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/monitor"
+)
+
+// newQuota creates the limiter from the settings file's quota and the flags,
+// which override its rate and mode (nil when no producer is limited)
+func newQuota(configured *monitor.Quota, rate int, mode string) (*monitor.Limiter, error) {
+	var q monitor.Quota
+	if configured != nil {
+		q = *configured
+	}
+	if rate > 0 {
+		q.Rate = rate
+	}
+	if mode != "" {
+		q.Mode = mode
+	}
+	if err := q.Validate(); err != nil {
+		return nil, fmt.Errorf("Invalid quota: %v", err)
+	}
+	if q.Rate == 0 && len(q.Sources) == 0 {
+		return nil, nil
+	}
+	return monitor.NewLimiter(q), nil
+}
+
+// admitEvent applies the producer quota to a live event, reporting whether it is shown
+// Events waiting for an operator are always shown
+func (m *model) admitEvent(event events.Event) bool {
+	// Streamed chunks grow one entry rather than bury others, and dropping one would garble the output
+	exempt := len(event.Actions) > 0 || event.Question != nil || event.IsStreamed()
+	keep, change := m.quota.Admit(event.EffectiveSource(), exempt, time.Now())
+	if change != nil {
+		m.noteQuota(*change)
+	}
+	return keep
+}
+
+// sweepQuota reports producers that went quiet while throttled
+func (m *model) sweepQuota() {
+	for _, change := range m.quota.Sweep(time.Now()) {
+		m.noteQuota(change)
+	}
+}
+
+// noteQuota lists a warning when a producer goes over its quota, and a notice when it is back under
+func (m *model) noteQuota(change monitor.QuotaChange) {
+	event := events.NewQuotaRecovered(change.Source, change.Rate, change.Dropped)
+	if change.Throttled {
+		event = events.NewQuotaExceeded(change.Source, change.Rate, change.Mode, m.quota.Action())
+	}
+	m.routeEvent(event)
+	m.status = event.Message
+}
+
+// renderQuota renders the producers currently throttled ("" when none are)
+func (m model) renderQuota() string {
+	throttled := m.quota.Throttled()
+	if len(throttled) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(throttled))
+	for _, p := range throttled {
+		source := p.Source
+		if source == "" {
+			source = "(no source)"
+		}
+		parts = append(parts, i18n.T("header.throttled_producer", source, p.Mode, p.Dropped))
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214")).
+		Render(i18n.T("header.throttled", strings.Join(parts, ", "))) + "\n"
+}
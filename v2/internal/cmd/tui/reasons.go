@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// reasonPicker collects reason codes for an action before its response is published
+type reasonPicker struct {
+	action   events.Action
+	cursor   int
+	selected map[int]bool
+}
+
+// openReasonPicker starts picking reasons for an action that offers them
+func (m *model) openReasonPicker(action events.Action) {
+	m.reasons = &reasonPicker{action: action, selected: make(map[int]bool)}
+}
+
+// updateReasonPicker handles keys in the reason picker
+// Space or 1-9 toggle a reason, Enter publishes, Esc goes back to the buttons
+func (m model) updateReasonPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.reasons
+	codes := p.action.Reasons
+	key := msg.String()
+
+	switch key {
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(codes)-1 {
+			p.cursor++
+		}
+	case " ", "space":
+		p.selected[p.cursor] = !p.selected[p.cursor]
+	case "enter":
+		var picked []string
+		for i, code := range codes {
+			if p.selected[i] {
+				picked = append(picked, code)
+			}
+		}
+		m.reasons = nil
+		if m.transport == nil {
+			m.reofferActions()
+			return m, nil
+		}
+		return m, publishActionResponseCmd(m.transport, m.outbox, m.durable, m.subject, m.activeEventID(), p.action.WithReasons(picked))
+	case "esc":
+		m.reasons = nil
+		m.reofferActions()
+	default:
+		if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= len(codes) && n <= 9 {
+			p.cursor = n - 1
+			p.selected[p.cursor] = !p.selected[p.cursor]
+		}
+	}
+	return m, nil
+}
+
+// reofferActions gives the blocking decision its buttons back when the picker
+// closes without publishing; triggering the action cleared them
+func (m *model) reofferActions() {
+	m.actionManager.Settle()
+	if event := m.blockingEvent(); event != nil {
+		m.actionManager.RegisterActions(event.Actions, *m.blockingEventIndex)
+	}
+}
+
+// renderReasonPicker renders the reason codes with their selection state
+func (m model) renderReasonPicker(width int) string {
+	p := m.reasons
+	label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+
+	var content strings.Builder
+	content.WriteString(label.Render(i18n.T("reasons.title", p.action.Label)))
+	content.WriteString("\n\n")
+	for i, code := range p.action.Reasons {
+		cursor := "  "
+		if i == p.cursor {
+			cursor = "> "
+		}
+		box := "[ ]"
+		if p.selected[i] {
+			box = "[x]"
+		}
+		number := " "
+		if i < 9 {
+			number = strconv.Itoa(i + 1)
+		}
+		content.WriteString(fmt.Sprintf("%s%s %s %s\n", cursor, number, box, code))
+	}
+	content.WriteString("\n")
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(i18n.T("reasons.help", p.action.Label)))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
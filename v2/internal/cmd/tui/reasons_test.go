@@ -0,0 +1,27 @@
+package tui
+
+import "testing"
+
+func TestReasonPickerKeepsActionsWithoutTransport(t *testing.T) {
+	for _, key := range []string{"esc", "enter"} {
+		t.Run(key, func(t *testing.T) {
+			m := newTestModel(t)
+			event := decision("deploy", "y", "n")
+			event.Actions[0].Reasons = []string{"tested", "urgent"}
+			m = deliver(t, m, event)
+			m, _ = press(m, "y")
+			if m.reasons == nil {
+				t.Fatal("pressing an action with reasons didn't open the picker")
+			}
+
+			m.transport = nil // Disconnected while picking
+			m, _ = press(m, key)
+			if m.reasons != nil {
+				t.Fatalf("%s left the picker open", key)
+			}
+			if got := len(m.actionManager.GetActiveActions()); got != 2 {
+				t.Errorf("after %s the decision offers %d actions, want both again", key, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// replayBarWidth is the width of the scrubber's position bar
+const replayBarWidth = 30
+
+// Playback speed bounds (multiples of the recorded pace)
+const (
+	minReplaySpeed = 0.125
+	maxReplaySpeed = 1024
+)
+
+// maxReplayDelay caps the wait between two played events, so idle stretches
+// of a recording don't stall the playback
+const maxReplayDelay = 5 * time.Second
+
+// replayState holds a recorded session opened with --from-file
+type replayState struct {
+	file    string
+	events  []events.Event
+	pos     int     // Number of events applied (0 shows the empty start)
+	speed   float64 // Playback speed, 1 being the recorded pace
+	playing bool
+	gen     int // Identifies the pending step; bumped to drop it on pause or speed changes
+}
+
+// replayStepMsg plays the next recorded event
+type replayStepMsg struct{ gen int }
+
+// prepareEvent applies the transformation pipeline, synthesizes question actions
+// and updates the watches
+// Returns false if the pipeline dropped the event
+func (m *model) prepareEvent(event events.Event) (events.Event, bool) {
+	// Apply the transformation pipeline (redact, rewrite, drop) before routing
+	event, keep := m.transform.Apply(event)
+	if !keep {
+		return event, false
+	}
+
+	// Questions without explicit actions get answer actions synthesized from their schema
+	if event.Question != nil {
+		if err := event.Question.Validate(); err != nil {
+			m.status = i18n.T("replay.bad_question", event.ID, err)
+			event.Question = nil
+		} else if len(event.Actions) == 0 {
+			event.Actions = event.Question.Actions(event.ID)
+		}
+	}
+
+	// Responses answer this event, on its reply subject too if the producer waits on one
+	event.AddressReplies()
+
+	// Orchestrators rename panes at runtime; the event stays listed as a record of it
+	m.paneManager.ApplyTitle(event)
+	m.watches.Observe(event)
+	return event, true
+}
+
+// scrub moves the replay position by delta events and rebuilds the panes
+// AIDEV-NOTE: Pane state is reconstructed by replaying events from the start
+// rather than undoing, so retention and routing behave exactly as they did live.
+// A single step forward (playback, →) just routes the next event, which is
+// the same thing without the rebuild
+func (m *model) scrub(delta int) {
+	r := m.replay
+	from := r.pos
+	r.pos += delta
+	if r.pos < 0 {
+		r.pos = 0
+	}
+	if r.pos > len(r.events) {
+		r.pos = len(r.events)
+	}
+
+	if r.pos != from+1 {
+		m.paneManager.Reset()
+		m.watches.Reset()
+		m.actionManager.ClearAll()
+		m.blockingEventIndex = nil
+		from = 0
+	}
+
+	var last *events.Event
+	for _, recorded := range r.events[from:r.pos] {
+		event, keep := m.prepareEvent(recorded)
+		if !keep {
+			continue
+		}
+		pane := m.paneManager.RouteEvent(event)
+		if pane == nil || pane.LastAdded < 0 {
+			continue
+		}
+		index := pane.LastAdded
+		last = &pane.Events[index]
+		m.activePane = pane.Name
+		m.selectedEventIndex = index
+	}
+
+	// Show the buttons the operator was offered if the newest event asked for a decision
+	// (there's no connection, so they can't be pressed)
+	if last != nil && from > 0 {
+		m.actionManager.ClearAll()
+		m.blockingEventIndex = nil
+	}
+	if last != nil && len(last.Actions) > 0 {
+		index := m.selectedEventIndex
+		m.actionManager.RegisterActions(last.Actions, index)
+		m.blockingEventIndex = &index
+		m.blockingPane = m.activePane
+		m.blockingSince = last.Timestamp
+	}
+}
+
+// replayNext schedules the next recorded event, after the time that separated
+// it from the previous one at the playback speed
+func (m *model) replayNext() tea.Cmd {
+	r := m.replay
+	if !r.playing {
+		return nil
+	}
+	if r.pos >= len(r.events) {
+		r.playing = false
+		m.status = i18n.T("replay.finished")
+		return nil
+	}
+
+	var delay time.Duration
+	if r.pos > 0 {
+		gap := r.events[r.pos].Timestamp.Sub(r.events[r.pos-1].Timestamp)
+		delay = min(max(0, time.Duration(float64(gap)/r.speed)), maxReplayDelay)
+	}
+	gen := r.gen
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return replayStepMsg{gen: gen}
+	})
+}
+
+// updateReplayPlayback handles the playback keys of a replay
+// space plays or pauses (from the start once at the end), < and > halve and
+// double the speed; ok is false for other keys
+func (m *model) updateReplayPlayback(key string) (cmd tea.Cmd, ok bool) {
+	r := m.replay
+	switch key {
+	case " ":
+		r.playing = !r.playing
+		if r.playing && r.pos >= len(r.events) {
+			m.scrub(-r.pos)
+		}
+	case "<":
+		r.speed = max(minReplaySpeed, r.speed/2)
+	case ">":
+		r.speed = min(maxReplaySpeed, r.speed*2)
+	default:
+		return nil, false
+	}
+	m.status = i18n.T("replay.paused", r.speed)
+	if r.playing {
+		m.status = i18n.T("replay.playing", r.speed)
+	}
+	r.gen++
+	return m.replayNext(), true
+}
+
+// renderScrubber renders the replay position bar
+func renderScrubber(r *replayState) string {
+	filled := 0
+	if len(r.events) > 0 {
+		filled = r.pos * replayBarWidth / len(r.events)
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", replayBarWidth-filled)
+
+	at := "start"
+	if r.pos > 0 {
+		at = r.events[r.pos-1].Timestamp.Format("2006-01-02 15:04:05")
+	}
+
+	state := "⏸"
+	if r.playing {
+		state = "▶"
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(i18n.T("replay.bar", state, bar, r.pos, len(r.events), at, r.speed))
+}
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// searchBar is the open / bar
+type searchBar struct {
+	input    textinput.Model
+	regex    bool        // Query is a regular expression
+	filter   bool        // Only list matches (tab switches to highlighting them)
+	err      error       // Why the query doesn't compile; the last valid search stays applied
+	previous *tui.Search // Restored when the bar is cancelled
+}
+
+// openSearch opens the / bar, starting from the current search if any
+func (m *model) openSearch() tea.Cmd {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Width = 60
+	bar := &searchBar{filter: true, previous: m.search}
+	if m.search != nil {
+		input.SetValue(m.search.Query)
+		bar.regex, bar.filter = m.search.Regex, m.search.Filter
+	}
+	input.Focus()
+	m.searchBar = bar
+	return textinput.Blink
+}
+
+// updateSearch handles keys while the / bar is open
+// The search applies as it is typed; enter keeps it, esc restores the previous one
+func (m model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	bar := m.searchBar
+	switch msg.String() {
+	case "esc":
+		m.search = bar.previous
+		m.searchBar = nil
+		return m, nil
+	case "enter":
+		m.searchBar = nil
+		if m.search == nil {
+			m.status = i18n.T("search.cleared")
+			return m, nil
+		}
+		if event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex); event == nil || !m.search.Matches(*event) {
+			m.jumpToMatch(1)
+		}
+		m.status = i18n.T("search.matches", m.countMatches(), m.search)
+		return m, nil
+	case "ctrl+r":
+		bar.regex = !bar.regex
+	case "tab":
+		bar.filter = !bar.filter
+	default:
+		var cmd tea.Cmd
+		bar.input, cmd = bar.input.Update(msg)
+		m.applySearch()
+		return m, cmd
+	}
+	m.applySearch()
+	return m, nil
+}
+
+// applySearch compiles the bar's query into the list's search
+func (m *model) applySearch() {
+	bar := m.searchBar
+	bar.err = nil
+	query := bar.input.Value()
+	if query == "" {
+		m.search = nil
+		return
+	}
+	search, err := tui.NewSearch(query, bar.regex, bar.filter)
+	if err != nil {
+		bar.err = err
+		return
+	}
+	m.search = search
+}
+
+// countMatches counts the active pane's events matching the search
+func (m model) countMatches() int {
+	pane := m.paneManager.GetPane(m.activePane)
+	if pane == nil || m.search == nil {
+		return 0
+	}
+	count := 0
+	for _, idx := range pane.VisibleIndices(m.listFilter()) {
+		if m.search.Matches(pane.Events[idx]) {
+			count++
+		}
+	}
+	return count
+}
+
+// jumpToMatch selects the next listed match after the selection (dir 1) or
+// the previous one (dir -1), wrapping around the list
+func (m *model) jumpToMatch(dir int) {
+	if m.search == nil {
+		m.status = i18n.T("search.none")
+		return
+	}
+	pane := m.paneManager.GetPane(m.activePane)
+	if pane == nil {
+		return
+	}
+	visible := pane.VisibleIndices(m.listFilter())
+	n := len(visible)
+
+	// Start from the selection, or just past the newest when it isn't listed
+	pos := n
+	for i, idx := range visible {
+		if idx == m.selectedEventIndex {
+			pos = i
+			break
+		}
+	}
+	if pos == n && dir > 0 {
+		pos = -1
+	}
+
+	for step := 1; step <= n; step++ {
+		next := ((pos+dir*step)%n + n) % n
+		if m.search.Matches(pane.Events[visible[next]]) {
+			m.selectedEventIndex = visible[next]
+			m.status = i18n.T("search.match", m.search)
+			if (dir > 0 && next <= pos) || (dir < 0 && next >= pos) {
+				m.status += " " + i18n.T("search.wrapped")
+			}
+			return
+		}
+	}
+	m.status = i18n.T("search.no_match", m.search)
+}
+
+// renderSearchBar renders the / bar for the header ("" while closed)
+func (m model) renderSearchBar() string {
+	bar := m.searchBar
+	if bar == nil {
+		return ""
+	}
+	mode := []string{i18n.T("search.substring"), i18n.T("search.filter")}
+	if bar.regex {
+		mode[0] = i18n.T("search.regex")
+	}
+	if !bar.filter {
+		mode[1] = i18n.T("search.highlight")
+	}
+	line := i18n.T("search.bar", bar.input.View(), strings.Join(mode, ", "))
+	if bar.err != nil {
+		line += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(bar.err.Error())
+	}
+	return line + "\n"
+}
@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// sessionPicker is the startup screen listing active sessions (--sessions)
+type sessionPicker struct {
+	sessions map[string]events.SessionAnnouncement // Latest sighting by session
+	cursor   int
+	subs     []transport.Subscription
+	msgChan  chan transport.Msg
+}
+
+// sessionsReadyMsg is sent when the picker listens for sessions
+type sessionsReadyMsg struct {
+	subs    []transport.Subscription
+	msgChan chan transport.Msg
+}
+
+// sessionSeenMsg reports a session announced, or seen through one of its events
+type sessionSeenMsg struct{ session events.SessionAnnouncement }
+
+// subscribeToSessions listens for session announcements and for events on any
+// session subject, so sessions whose producers don't announce are found too
+func subscribeToSessions(t transport.Transport) tea.Cmd {
+	return func() tea.Msg {
+		msgChan := make(chan transport.Msg, 64)
+		var subs []transport.Subscription
+		for _, subject := range []string{events.SessionsSubject, events.AllSessionsSubject} {
+			sub, err := transport.Chan(t, subject, msgChan)
+			if err != nil {
+				for _, s := range subs {
+					s.Unsubscribe()
+				}
+				return errMsg{err}
+			}
+			subs = append(subs, sub)
+		}
+		return sessionsReadyMsg{subs: subs, msgChan: msgChan}
+	}
+}
+
+// waitForSession waits for the next sighting of a session
+func waitForSession(msgChan chan transport.Msg) tea.Cmd {
+	return func() tea.Msg {
+		for msg := range msgChan {
+			if msg.Subject == events.SessionsSubject {
+				if a, err := events.SessionAnnouncementFromJSON(msg.Data); err == nil {
+					return sessionSeenMsg{session: *a}
+				}
+				continue
+			}
+			// agneto.<session>.events
+			session := strings.TrimSuffix(strings.TrimPrefix(msg.Subject, "agneto."), ".events")
+			seen := events.SessionAnnouncement{Session: session, At: time.Now()}
+			var event events.Event
+			if json.Unmarshal(msg.Data, &event) == nil {
+				seen.Producer, seen.Message = event.EffectiveSource(), event.Message
+			}
+			return sessionSeenMsg{session: seen}
+		}
+		return nil
+	}
+}
+
+// noteSession records a sighting, keeping what earlier ones knew about the producer
+func (p *sessionPicker) noteSession(seen events.SessionAnnouncement) {
+	if known, ok := p.sessions[seen.Session]; ok && seen.Producer == "" {
+		seen.Producer = known.Producer
+	}
+	p.sessions[seen.Session] = seen
+}
+
+// active returns the sessions seen within events.SessionTTL, by name (so the cursor stays put)
+func (p *sessionPicker) active() []events.SessionAnnouncement {
+	now := time.Now()
+	var list []events.SessionAnnouncement
+	for _, s := range p.sessions {
+		if !s.Expired(now) {
+			list = append(list, s)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Session < list[j].Session
+	})
+	return list
+}
+
+// updateSessionPicker handles keys on the session picker
+func (m model) updateSessionPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	picker := m.sessionPicker
+	list := picker.active()
+	switch msg.String() {
+	case "up", "k":
+		picker.cursor = max(0, picker.cursor-1)
+	case "down", "j":
+		picker.cursor = max(0, min(len(list)-1, picker.cursor+1))
+	case "enter":
+		if len(list) == 0 {
+			return m, nil
+		}
+		return m.attachSession(list[min(picker.cursor, len(list)-1)].Session)
+	case "a":
+		return m.attachSession("")
+	case "q", "ctrl+c":
+		return m.requestQuit()
+	}
+	return m, nil
+}
+
+// attachSession closes the picker and starts listening to a session ("" for all of them)
+func (m model) attachSession(session string) (tea.Model, tea.Cmd) {
+	for _, sub := range m.sessionPicker.subs {
+		sub.Unsubscribe()
+	}
+	m.sessionPicker = nil
+	m.subject = events.AllSessionsSubject
+	m.status = i18n.T("sessions.attached_all")
+	if session != "" {
+		m.subject = events.SessionSubject(session)
+		m.status = i18n.T("sessions.attached", session)
+	}
+	return m, m.startSubscriptions()
+}
+
+// startSubscriptions subscribes to the events subject and everything else a live monitor listens to
+func (m model) startSubscriptions() tea.Cmd {
+	cmds := []tea.Cmd{subscribeToEvents(m.nc, m.bus, m.subject, m.instance, m.history), subscribeToControl(m.transport, m.instance), answerPaneDiscovery(m.transport, m.paneDirectory), subscribeToApprovals(m.transport)}
+	if m.presence != nil {
+		cmds = append(cmds, subscribeToPresence(m.transport))
+	}
+	if m.chat != nil {
+		cmds = append(cmds, subscribeToChat(m.transport))
+	}
+	return tea.Batch(cmds...)
+}
+
+// sessionSubject returns the subject to publish a response on
+// Attached to every session, a response goes to the session of the event it
+// answers; nothing can be published to the wildcard subject itself
+func sessionSubject(subject string, data []byte) (string, error) {
+	if !events.IsWildcardSubject(subject) {
+		return subject, nil
+	}
+	var event struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil || event.SessionID == "" {
+		return "", fmt.Errorf("attached to %s, only responses to a session's events can be published", subject)
+	}
+	return events.SessionSubject(event.SessionID), nil
+}
+
+// renderSessionPicker renders the session picker
+func (m model) renderSessionPicker(width int) string {
+	label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+
+	var content strings.Builder
+	content.WriteString(label.Render(i18n.T("sessions.title")))
+	content.WriteString("\n\n")
+	if m.transport == nil {
+		content.WriteString(i18n.T("connecting") + "\n")
+	} else if list := m.sessionPicker.active(); len(list) == 0 {
+		content.WriteString(dim.Render(i18n.T("sessions.none", events.SessionsSubject)))
+		content.WriteString("\n")
+	} else {
+		cursor := min(m.sessionPicker.cursor, len(list)-1)
+		for i, s := range list {
+			line := s.Session
+			if s.Producer != "" {
+				line += " " + dim.Render(i18n.T("sessions.by", s.Producer))
+			}
+			line += " " + dim.Render(i18n.T("sessions.ago", time.Since(s.At).Round(time.Second)))
+			if s.Message != "" {
+				line += "  " + s.Message
+			}
+			if i == cursor {
+				line = "> " + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255")).Render(s.Session) + strings.TrimPrefix(line, s.Session)
+			} else {
+				line = "  " + line
+			}
+			content.WriteString(line + "\n")
+		}
+	}
+	content.WriteString("\n")
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(i18n.T("sessions.help")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
@@ -0,0 +1,322 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/config"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// Settings screen fields, in display order
+const (
+	settingRoutes = iota
+	settingFilter
+	settingMutes
+	settingTheme
+	settingCount
+)
+
+// settingLabels are the message IDs of the settings fields' display names
+var settingLabels = [settingCount]string{
+	settingRoutes: "settings.routes",
+	settingFilter: "settings.filter",
+	settingMutes:  "settings.mutes",
+	settingTheme:  "settings.theme",
+}
+
+// settingHints are the message IDs explaining the edit format of each field
+var settingHints = [settingCount]string{
+	settingRoutes: "settings.routes_hint",
+	settingFilter: "settings.filter_hint",
+	settingMutes:  "settings.mutes_hint",
+	settingTheme:  "settings.theme_hint",
+}
+
+// listFilter returns the filter applied to the event list
+func (m model) listFilter() tui.ListFilter {
+	return tui.ListFilter{
+		Text:        m.filter,
+		Mutes:       m.mutes,
+		Hidden:      m.hiddenTypes,
+		State:       m.stateFilter,
+		Lifecycles:  m.lifecycles,
+		MinSeverity: m.minSeverity,
+		Search:      m.search,
+	}
+}
+
+// openSettings shows the settings screen
+func (m *model) openSettings() {
+	m.settingsOpen = true
+	m.settingsEditing = false
+	m.status = ""
+}
+
+// updateSettings handles keys while the settings screen is open
+// AIDEV-NOTE: Every committed edit takes effect immediately; "w" only
+// persists the current state to the config file
+func (m model) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.settingsEditing {
+		switch msg.String() {
+		case "enter":
+			if m.settingsSaveAs {
+				m.saveWorkspace(strings.TrimSpace(m.settingsInput.Value()))
+				m.settingsEditing, m.settingsSaveAs = false, false
+				return m, nil
+			}
+			if err := m.applySetting(m.settingsCursor, m.settingsInput.Value()); err != nil {
+				m.status = i18n.T("settings.error", err)
+				return m, nil
+			}
+			m.settingsEditing = false
+			m.status = i18n.T("settings.updated", strings.ToLower(i18n.T(settingLabels[m.settingsCursor])))
+		case "esc":
+			m.settingsEditing, m.settingsSaveAs = false, false
+		default:
+			var cmd tea.Cmd
+			m.settingsInput, cmd = m.settingsInput.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m.requestQuit()
+	case "esc", ",", "q":
+		m.settingsOpen = false
+	case "up", "k":
+		if m.settingsCursor > 0 {
+			m.settingsCursor--
+		}
+	case "down", "j":
+		if m.settingsCursor < settingCount-1 {
+			m.settingsCursor++
+		}
+	case "left", "h":
+		if m.settingsCursor == settingTheme {
+			m.cycleTheme(-1)
+		}
+	case "right", "l":
+		if m.settingsCursor == settingTheme {
+			m.cycleTheme(1)
+		}
+	case "enter":
+		if m.settingsCursor == settingTheme {
+			m.cycleTheme(1)
+			return m, nil
+		}
+		input := textinput.New()
+		input.Prompt = "> "
+		input.SetValue(m.settingValue(m.settingsCursor))
+		input.CursorEnd()
+		input.Width = 60
+		input.Focus()
+		m.settingsInput = input
+		m.settingsEditing = true
+		return m, textinput.Blink
+	case "W":
+		input := textinput.New()
+		input.Prompt = i18n.T("settings.save_as_prompt")
+		input.SetValue(m.workspace)
+		input.CursorEnd()
+		input.Width = 40
+		input.Focus()
+		m.settingsInput = input
+		m.settingsEditing, m.settingsSaveAs = true, true
+		return m, textinput.Blink
+	case "w":
+		if err := m.config.Save(m.configPath); err != nil {
+			m.status = i18n.T("settings.save_failed", err)
+		} else {
+			m.status = i18n.T("settings.saved", m.configPath)
+		}
+	}
+	return m, nil
+}
+
+// settingValue renders a field's current value in its edit format
+func (m model) settingValue(field int) string {
+	switch field {
+	case settingRoutes:
+		parts := make([]string, 0, len(m.paneManager.Routes))
+		for _, route := range m.paneManager.Routes {
+			parts = append(parts, route.Type+"="+route.Pane)
+		}
+		return strings.Join(parts, ", ")
+	case settingFilter:
+		return m.filter
+	case settingMutes:
+		return strings.Join(m.mutes, ", ")
+	case settingTheme:
+		return tui.CurrentTheme()
+	}
+	return ""
+}
+
+// applySetting parses an edited value and applies it to the running monitor and the config
+func (m *model) applySetting(field int, value string) error {
+	switch field {
+	case settingRoutes:
+		routes, err := parseRoutes(value, m.paneManager)
+		if err != nil {
+			return err
+		}
+		m.paneManager.Routes = routes
+		m.config.Routes = routes
+	case settingFilter:
+		m.filter = strings.TrimSpace(value)
+		m.config.Filter = m.filter
+		m.moveSelection(0)
+	case settingMutes:
+		mutes, err := parseGlobs(value)
+		if err != nil {
+			return err
+		}
+		m.mutes = mutes
+		m.config.Mutes = mutes
+		m.moveSelection(0)
+	}
+	return nil
+}
+
+// saveWorkspace saves the current settings as a named workspace and switches to it,
+// so later saves (w) go to the workspace
+func (m *model) saveWorkspace(name string) {
+	path, err := config.WorkspacePath(name)
+	if err == nil {
+		err = m.config.Save(path)
+	}
+	if err != nil {
+		m.status = i18n.T("settings.error", err)
+		return
+	}
+	m.workspace, m.configPath = name, path
+	m.status = i18n.T("settings.saved_workspace", name)
+}
+
+// cycleTheme switches to the next (or previous) built-in theme
+func (m *model) cycleTheme(delta int) {
+	names := tui.ThemeNames()
+	current := 0
+	for i, name := range names {
+		if name == tui.CurrentTheme() {
+			current = i
+		}
+	}
+	next := names[(current+delta+len(names))%len(names)]
+	tui.ApplyTheme(next)
+	m.config.Theme = next
+}
+
+// parseRoutes parses "glob=pane, glob=pane" into routing rules
+func parseRoutes(value string, pm *tui.PaneManager) ([]tui.Route, error) {
+	var routes []tui.Route
+	for _, part := range splitList(value) {
+		glob, pane, ok := strings.Cut(part, "=")
+		glob, pane = strings.TrimSpace(glob), strings.TrimSpace(pane)
+		if !ok || glob == "" || pane == "" {
+			return nil, fmt.Errorf("route %q: expected type-glob=pane", part)
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("route %q: invalid glob: %w", part, err)
+		}
+		if pm.GetPane(pane) == nil && (!pm.CanCreate() || events.ValidPaneName(pane) != nil) {
+			return nil, fmt.Errorf("route %q: unknown pane %q (have %s)", part, pane, strings.Join(pm.PaneNames(), ", "))
+		}
+		routes = append(routes, tui.Route{Type: glob, Pane: pane})
+	}
+	return routes, nil
+}
+
+// parseGlobs parses a comma-separated list of type globs
+func parseGlobs(value string) ([]string, error) {
+	globs := splitList(value)
+	for _, glob := range globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+	return globs, nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// renderSettings renders the settings screen
+func (m model) renderSettings(width int) string {
+	var content strings.Builder
+
+	content.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("99")).
+		Render(i18n.T("settings.title")))
+	content.WriteString("\n")
+	source := m.configPath
+	if m.workspace != "" {
+		source = i18n.T("settings.workspace", m.workspace, m.configPath)
+	}
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("243")).
+		Render(source))
+	content.WriteString("\n\n")
+
+	for field := 0; field < settingCount; field++ {
+		cursor := "  "
+		labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+		if field == m.settingsCursor {
+			cursor = "> "
+			labelStyle = labelStyle.Bold(true).Foreground(lipgloss.Color("255"))
+		}
+		content.WriteString(cursor + labelStyle.Render(fmt.Sprintf("%-14s", i18n.T(settingLabels[field]))))
+
+		if m.settingsEditing && !m.settingsSaveAs && field == m.settingsCursor {
+			content.WriteString(m.settingsInput.View())
+		} else {
+			value := m.settingValue(field)
+			if value == "" {
+				value = i18n.T("settings.none")
+			}
+			content.WriteString(value)
+		}
+		content.WriteString("\n")
+		content.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("243")).
+			Render("                " + i18n.T(settingHints[field])))
+		content.WriteString("\n\n")
+	}
+
+	instructions := i18n.T("settings.help")
+	if m.settingsSaveAs {
+		content.WriteString(m.settingsInput.View())
+		content.WriteString("\n\n")
+		instructions = i18n.T("settings.help_save_as")
+	} else if m.settingsEditing {
+		instructions = i18n.T("settings.help_editing")
+	}
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(instructions))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// cycleMinSeverity steps the severity threshold through all → info → warn → error → critical
+func (m *model) cycleMinSeverity() {
+	next := ""
+	switch m.minSeverity {
+	case "", events.SeverityDebug:
+		next = events.SeverityInfo
+	case events.SeverityInfo:
+		next = events.SeverityWarn
+	case events.SeverityWarn:
+		next = events.SeverityError
+	case events.SeverityError:
+		next = events.SeverityCritical
+	}
+	m.minSeverity = next
+	m.moveSelection(0)
+
+	if next == "" {
+		m.status = i18n.T("severity.all")
+	} else {
+		m.status = i18n.T("severity.min", next)
+	}
+}
@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/config"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// snapshotState tracks the periodic crash-recovery snapshot (--snapshot-interval)
+type snapshotState struct {
+	path     string
+	interval time.Duration
+	last     []byte    // State last written, without its time, to skip unchanged writes
+	savedAt  time.Time // When it was last written
+}
+
+// captureSnapshot collects the decision context a crash would lose:
+// the active decision, queued decisions and drafts (with their text), and
+// which events were answered
+func (m model) captureSnapshot() config.Snapshot {
+	var snapshot config.Snapshot
+	if event := m.blockingEvent(); event != nil {
+		pending := config.PendingSnapshot{Event: *event, Since: m.blockingSince, Active: !m.inputMode}
+		if m.inputMode && m.inputAction != nil {
+			action := *m.inputAction
+			pending.Input = &action
+			pending.Text = savedInputText(action, m.textarea, m.inputChoice)
+		}
+		snapshot.Pending = append(snapshot.Pending, pending)
+	}
+	for _, queued := range m.actionManager.Queued() {
+		if pane, index, ok := m.locateEvent(queued.EventID); ok {
+			event := m.paneManager.GetEventByIndex(pane, index)
+			snapshot.Pending = append(snapshot.Pending, config.PendingSnapshot{Event: *event, Since: queued.Since})
+		}
+	}
+	for _, id := range m.draftIDs() {
+		if pane, index, ok := m.locateEvent(id); ok {
+			draft := m.drafts[id]
+			action := draft.action
+			snapshot.Pending = append(snapshot.Pending, config.PendingSnapshot{
+				Event: *m.paneManager.GetEventByIndex(pane, index),
+				Since: draft.since,
+				Input: &action,
+				Text:  draft.text(),
+			})
+		}
+	}
+
+	// Only answers to events still in memory matter
+	for id := range m.consumedActions {
+		if _, _, ok := m.locateEvent(id); ok {
+			snapshot.Consumed = append(snapshot.Consumed, id)
+		}
+	}
+	sort.Strings(snapshot.Consumed)
+	return snapshot
+}
+
+// saveSnapshot writes the snapshot if the interval passed and the state changed
+// Nothing is written while a crashed session's snapshot is still on offer
+func (m *model) saveSnapshot() {
+	s := m.snapshot
+	if s == nil || m.restoreOffer != nil || time.Since(s.savedAt) < s.interval {
+		return
+	}
+	snapshot := m.captureSnapshot()
+	state, err := json.Marshal(snapshot)
+	if err != nil || string(state) == string(s.last) {
+		return
+	}
+	snapshot.SavedAt = time.Now()
+	if err := config.SaveSnapshot(s.path, snapshot); err != nil {
+		m.status = i18n.T("snapshot.failed", err)
+		return
+	}
+	s.last = state
+	s.savedAt = snapshot.SavedAt
+}
+
+// updateRestoreOffer handles keys on the crash recovery prompt
+func (m model) updateRestoreOffer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "r", "enter":
+		return m, m.restoreSnapshot()
+
+	case "d", "esc":
+		// Start afresh; the next snapshot replaces the old one
+		m.restoreOffer = nil
+		m.status = i18n.T("snapshot.discarded")
+
+	case "q", "ctrl+c":
+		// The snapshot stays for the next start
+		return m.requestQuit()
+	}
+	return m, nil
+}
+
+// restoreSnapshot brings back the crashed session's pending decisions
+// Events missing from memory are routed again; decisions are queued behind
+// any that arrived meanwhile, and inputs come back as drafts with their text
+func (m *model) restoreSnapshot() tea.Cmd {
+	snapshot := m.restoreOffer
+	m.restoreOffer = nil
+
+	for _, id := range snapshot.Consumed {
+		m.consumedActions[id] = true
+		m.lifecycles.Set(id, tui.LifecycleResponded)
+	}
+
+	var cmds []tea.Cmd
+	restored := 0
+	for _, pending := range snapshot.Pending {
+		event := pending.Event
+		if m.consumedActions[event.ID] || m.drafts[event.ID] != nil || m.isQueued(event.ID) || m.activeEventID() == event.ID {
+			continue // Answered or pending again already
+		}
+		if _, _, ok := m.locateEvent(event.ID); !ok {
+			if m.routeEvent(event) == nil {
+				continue
+			}
+			if m.dedup != nil {
+				m.dedup.Seen(event.IdempotencyKey) // Not again from history
+			}
+		}
+
+		if pending.Input != nil {
+			action := *pending.Input
+			ta := m.newInputTextarea(action)
+			choice := restoreInputText(action, &ta, pending.Text)
+			ta.Blur()
+			m.drafts[event.ID] = &inputDraft{
+				eventID:  event.ID,
+				action:   action,
+				since:    pending.Since,
+				textarea: ta,
+				editor:   editor{mode: m.editor.mode},
+				choice:   choice,
+			}
+		} else {
+			m.actionManager.Enqueue(tui.QueuedDecision{EventID: event.ID, Actions: event.Actions, Since: pending.Since})
+		}
+		cmds = append(cmds, m.scheduleDecisionTimers(event, pending.Since))
+		restored++
+	}
+	m.promoteQueued()
+
+	m.status = i18n.T("snapshot.restored", restored, snapshot.SavedAt.Format("15:04:05"))
+	return tea.Batch(cmds...)
+}
+
+// renderRestoreOffer renders the crash recovery prompt
+func (m model) renderRestoreOffer(width int) string {
+	label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	snapshot := m.restoreOffer
+
+	var content strings.Builder
+	content.WriteString(label.Render(i18n.T("snapshot.title")))
+	content.WriteString("\n\n")
+	content.WriteString(i18n.T("snapshot.intro", snapshot.SavedAt.Format("2006-01-02 15:04:05"), len(snapshot.Pending)) + "\n")
+	for _, pending := range snapshot.Pending {
+		detail := i18n.T("snapshot.waiting_since", pending.Since.Format("15:04:05"))
+		if pending.Input != nil {
+			detail = i18n.T("snapshot.input_typed", len([]rune(pending.Text)))
+		}
+		content.WriteString(fmt.Sprintf("  • %s  %s %s\n", pending.Event.Type, pending.Event.Message, dim.Render(detail)))
+	}
+	content.WriteString("\n")
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(i18n.T("snapshot.help")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// timeoutDueMsg is sent when an actionable event's timeout expires
+type timeoutDueMsg struct{ eventID string }
+
+// scheduleDecisionTimers arms an actionable event's escalation and timeout
+// since is when the decision started waiting (earlier for restored decisions)
+func (m *model) scheduleDecisionTimers(event events.Event, since time.Time) tea.Cmd {
+	return tea.Batch(m.scheduleEscalation(event), m.scheduleTimeout(event, since))
+}
+
+// scheduleTimeout arms the timer taking an event's default action, if it has one
+func (m *model) scheduleTimeout(event events.Event, since time.Time) tea.Cmd {
+	timeout, ok := event.ActionTimeout()
+	if !ok {
+		return nil
+	}
+	if err := event.ValidateTimeout(); err != nil {
+		m.status = i18n.T("timeout.invalid", shortID(event.ID), err)
+		return nil
+	}
+
+	deadline := since.Add(timeout)
+	m.deadlines[event.ID] = deadline
+	eventID := event.ID
+	return tea.Tick(max(time.Until(deadline), 0), func(time.Time) tea.Msg {
+		return timeoutDueMsg{eventID: eventID}
+	})
+}
+
+// takeDefaultAction answers an event with its default action if it still waits
+// on this monitor, as the active decision, in the queue or as a draft
+func (m *model) takeDefaultAction(eventID string) tea.Cmd {
+	delete(m.deadlines, eventID)
+	active := m.activeEventID() == eventID
+	_, drafted := m.drafts[eventID]
+	if m.consumedActions[eventID] || !active && !drafted && !m.isQueued(eventID) {
+		return nil // Answered meanwhile
+	}
+	pane, index, _ := m.locateEvent(eventID)
+	event := m.paneManager.GetEventByIndex(pane, index)
+	if event == nil || m.transport == nil {
+		return nil
+	}
+	action, ok := event.DefaultAction()
+	if !ok {
+		return nil
+	}
+
+	m.status = i18n.T("status.timed_out", shortID(eventID), action.Label)
+	delete(m.drafts, eventID)
+	m.actionManager.Remove(eventID)
+	if active {
+		// The response settles the decision like a key press would (see actionExecutedMsg)
+		m.inputMode = false
+		m.inputAction = nil
+		m.actionManager.ClearAll()
+		m.actionManager.MarkInFlight()
+	}
+	return publishActionResponseCmd(m.transport, m.outbox, m.durable, m.subject, eventID, action)
+}
+
+// renderCountdown renders the time left to answer the active decision before
+// its default action is taken ("" if it waits forever)
+func (m model) renderCountdown() string {
+	deadline, ok := m.deadlines[m.activeEventID()]
+	if !ok || m.actionManager.InFlight() {
+		return ""
+	}
+	event := m.blockingEvent()
+	action, ok := event.DefaultAction()
+	if !ok {
+		return ""
+	}
+	left := max(time.Until(deadline).Round(time.Second), 0)
+	color := lipgloss.Color("214")
+	if left <= 10*time.Second {
+		color = lipgloss.Color("196")
+	}
+	return "  " + lipgloss.NewStyle().
+		Bold(true).
+		Foreground(color).
+		Render(i18n.T("actions.countdown", int(left/time.Second), action.Label))
+}
@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// payloadTree is the full-screen JSON tree viewer of the selected event
+// Its folds and cursor are m.payloadFold, shared with the payload pane, so
+// the two show the same tree and take the same keys
+type payloadTree struct {
+	eventID string
+}
+
+// openPayloadTree shows the selected event's Data as a navigable tree
+// Values are redacted as in the payload pane
+func (m *model) openPayloadTree() {
+	event := m.selectedEvent()
+	if event == nil {
+		m.status = i18n.T("status.no_event_selected")
+		return
+	}
+	if _, ok := m.payloadFold.Selected(event); !ok {
+		m.status = i18n.T("tree.no_data")
+		return
+	}
+	m.tree = &payloadTree{eventID: event.ID}
+}
+
+// treeEvent returns the selected event when the payload pane shows it as a
+// tree, for the tree keys to act on, else nil
+func (m model) treeEvent() *events.Event {
+	if event := m.selectedEvent(); tui.TreeShown(event) {
+		return event
+	}
+	return nil
+}
+
+// updatePayloadTree handles keys in the tree viewer: the payload pane's tree
+// keys, with the plain arrows and j/k free to move as well
+func (m model) updatePayloadTree(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	event := m.selectedEvent()
+	if event == nil || event.ID != m.tree.eventID {
+		m.tree = nil // Evicted while open
+		return m, nil
+	}
+	switch msg.String() {
+	case "ctrl+c":
+		return m.requestQuit()
+	case "esc", "q", "J":
+		m.tree = nil
+	case "up", "k", "shift+up":
+		m.payloadFold.Move(event, -1)
+	case "down", "j", "shift+down":
+		m.payloadFold.Move(event, 1)
+	case "left", "h":
+		m.payloadFold.Collapse(event)
+	case "right", "l":
+		m.payloadFold.Expand(event)
+	case "home", "g":
+		m.payloadFold.Jump(event, false)
+	case "end", "G":
+		m.payloadFold.Jump(event, true)
+	case "y":
+		return m, m.copyTreeNodeCmd(event)
+	}
+	return m, nil
+}
+
+// copyTreeNodeCmd copies the path and value of the tree node under the cursor
+func (m model) copyTreeNodeCmd(event *events.Event) tea.Cmd {
+	node, ok := m.payloadFold.Selected(event)
+	if !ok {
+		return nil
+	}
+	return copyNodeCmd(node)
+}
+
+// copyNodeCmd copies a node's path and value, e.g. data.files[2].path = "main.go"
+func copyNodeCmd(node tui.PayloadNode) tea.Cmd {
+	return func() tea.Msg {
+		value, err := json.Marshal(node.Value)
+		if err != nil {
+			return yankDoneMsg{status: i18n.T("copy.failed", err)}
+		}
+		text := fmt.Sprintf("%s = %s", node.Path, value)
+		if _, err := writeClipboard(text); err != nil {
+			// No clipboard (headless or SSH): show it so it can be copied from the screen
+			return yankDoneMsg{status: text}
+		}
+		return yankDoneMsg{status: i18n.T("tree.copied", node.Path)}
+	}
+}
+
+// renderPayloadTree renders the tree around the cursor, as the payload pane
+// draws it
+func (m model) renderPayloadTree(width, height int) string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	event := tui.Redacted(*m.selectedEvent())
+	node, _ := m.payloadFold.Selected(&event)
+	lines, cursor := tui.PayloadFoldLines(&event, m.payloadFold, width-8)
+
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99")).Render(i18n.T("tree.title")))
+	content.WriteString("  ")
+	content.WriteString(dim.Render(node.Path))
+	content.WriteString("\n\n")
+
+	// Keep the cursor in view
+	rows := max(1, height-6)
+	start := 0
+	if cursor >= rows {
+		start = cursor - rows + 1
+	}
+	for _, line := range lines[start:min(start+rows, len(lines))] {
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(i18n.T("tree.help")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// pressKey presses a special key, e.g. tea.KeyShiftDown
+func pressKey(m model, key tea.KeyType) model {
+	m, _ = update(m, tea.KeyMsg{Type: key})
+	return m
+}
+
+func TestTreeViewerSharesThePaneFolds(t *testing.T) {
+	m := newTestModel(t)
+	m = deliver(t, m, events.Event{Type: "build", Data: map[string]interface{}{
+		"a":     "first",
+		"items": []interface{}{map[string]interface{}{"name": "x"}},
+	}})
+	m.selectedEventIndex = 0
+
+	// In the pane: down to items, then into it
+	m = pressKey(m, tea.KeyShiftDown)
+	m = pressKey(m, tea.KeyRight)
+	if m.payloadFold.Cursor != "data.items[0]" {
+		t.Fatalf("pane cursor at %q, want data.items[0]", m.payloadFold.Cursor)
+	}
+
+	// The viewer opens on the same node and unfolds it with the same key
+	m, _ = press(m, "J")
+	if m.tree == nil {
+		t.Fatal("J didn't open the tree viewer")
+	}
+	if m.payloadFold.Cursor != "data.items[0]" {
+		t.Fatalf("viewer cursor at %q, want the pane's", m.payloadFold.Cursor)
+	}
+	m = pressKey(m, tea.KeyRight)
+	m = pressKey(m, tea.KeyDown)
+	if m.payloadFold.Cursor != "data.items[0].name" {
+		t.Fatalf("viewer cursor at %q, want data.items[0].name", m.payloadFold.Cursor)
+	}
+
+	// Back in the pane, the unfolded node and the cursor stay
+	m, _ = press(m, "esc")
+	if m.tree != nil {
+		t.Fatal("esc didn't close the viewer")
+	}
+	if node, ok := m.payloadFold.Selected(m.selectedEvent()); !ok || node.Path != "data.items[0].name" {
+		t.Fatalf("pane cursor on %q after the viewer, want data.items[0].name", node.Path)
+	}
+	if m, cmd := press(m, "y"); cmd == nil || m.tree != nil {
+		t.Fatal("y in the pane didn't copy the node under the cursor")
+	}
+}
+
+func TestTreeKeysIgnoreContentEvents(t *testing.T) {
+	m := newTestModel(t)
+	m = deliver(t, m, events.Event{Type: "plan", Content: "# Plan", Data: map[string]interface{}{"a": map[string]interface{}{"b": 1}}})
+	m.selectedEventIndex = 0
+
+	// The pane shows Content, so its tree keys do nothing
+	m = pressKey(m, tea.KeyShiftDown)
+	if m.payloadFold.Cursor != "" {
+		t.Fatalf("shift+down moved a tree the pane doesn't show (cursor %q)", m.payloadFold.Cursor)
+	}
+
+	// The viewer still browses the data
+	m, _ = press(m, "J")
+	m = pressKey(m, tea.KeyDown)
+	if m.payloadFold.Cursor != "data.a.b" {
+		t.Fatalf("viewer cursor at %q, want data.a.b", m.payloadFold.Cursor)
+	}
+}
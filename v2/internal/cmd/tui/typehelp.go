@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// openTypeHelp shows what the selected event's type means
+func (m *model) openTypeHelp() {
+	if m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex) == nil {
+		m.status = i18n.T("status.no_event_selected")
+		return
+	}
+	m.typeHelpOpen = true
+}
+
+// updateTypeHelp handles keys on the type help screen: o opens the docs, anything else closes it
+func (m model) updateTypeHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.typeHelpOpen = false
+	if msg.String() != "o" {
+		return m, nil
+	}
+	event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex)
+	if event == nil {
+		return m, nil
+	}
+	if info, ok := m.types.Lookup(event.Type); ok && info.Docs != "" {
+		return m, openURLCmd(info.Docs, 1, 1)
+	}
+	m.status = i18n.T("typehelp.no_docs", event.Type)
+	return m, nil
+}
+
+// renderTypeHelp renders the type help screen for the selected event
+func (m model) renderTypeHelp(width int) string {
+	event := m.paneManager.GetEventByIndex(m.activePane, m.selectedEventIndex)
+	if event == nil {
+		return ""
+	}
+	info, known := m.types.Lookup(event.Type)
+
+	label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+
+	var content strings.Builder
+	content.WriteString(label.Render(event.Type))
+	content.WriteString("\n\n")
+
+	if known {
+		content.WriteString(info.Description)
+	} else {
+		content.WriteString(dim.Render(i18n.T("typehelp.undocumented", m.configPath)))
+	}
+	content.WriteString("\n\n")
+
+	content.WriteString(label.Render(i18n.T("typehelp.expected")))
+	content.WriteString("\n")
+	content.WriteString(tui.ExpectedResponse(info, *event))
+	content.WriteString("\n\n")
+
+	// Renames list the pane's title history
+	if name, _, ok := event.PaneTitle(); ok {
+		if pane := m.paneManager.GetPane(name); pane != nil && len(pane.Titles) > 0 {
+			content.WriteString(label.Render(i18n.T("typehelp.titles", name)))
+			content.WriteString("\n")
+			for _, change := range pane.Titles {
+				content.WriteString(fmt.Sprintf("%s  %q → %q\n", change.At.Format("15:04:05"), change.From, change.To))
+			}
+			content.WriteString("\n")
+		}
+	}
+
+	instructions := i18n.T("typehelp.help")
+	if known && info.Docs != "" {
+		content.WriteString(label.Render(i18n.T("typehelp.docs")))
+		content.WriteString("\n")
+		content.WriteString(tui.Linkify(info.Docs))
+		content.WriteString("\n\n")
+		instructions = i18n.T("typehelp.help_docs")
+	}
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(instructions))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// countUsage adds an event's usage to the running totals
+func (m *model) countUsage(event events.Event) {
+	if event.Usage == nil {
+		return
+	}
+	if !m.usage.Add(event, time.Now()) {
+		m.status = i18n.T("status.usage_invalid", shortID(event.ID))
+	}
+}
+
+// toggleUsage shows the usage totals, or goes back
+func (m *model) toggleUsage() {
+	m.usageOpen = !m.usageOpen
+}
+
+// updateUsage handles keys on the usage screen: r resets the totals, anything else closes it
+func (m model) updateUsage(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "r" {
+		m.usage.Reset()
+		m.status = i18n.T("status.usage_reset")
+		return m, nil
+	}
+	m.usageOpen = false
+	return m, nil
+}
+
+// renderUsageSummary renders the header line of the spend so far, once any event reported usage
+func (m model) renderUsageSummary() string {
+	if m.usage.Len() == 0 {
+		return ""
+	}
+	sum := m.usage.Sum()
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("243")).
+		Render(i18n.T("header.usage", tui.FormatCost(sum.Usage.CostUSD), tui.FormatTokens(sum.Usage.Tokens()), m.usage.Len())) + "\n"
+}
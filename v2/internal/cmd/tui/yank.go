@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// yankFormat selects how yanked events are concatenated
+type yankFormat int
+
+const (
+	yankJSON     yankFormat = iota // JSON Lines
+	yankMarkdown                   // Markdown document
+)
+
+// yankDoneMsg is sent when a yank finished (successfully or not)
+type yankDoneMsg struct{ status string }
+
+// visualRange returns the inclusive event index range of the visual selection
+func (m model) visualRange() (int, int) {
+	start, end := m.visualAnchor, m.selectedEventIndex
+	if start > end {
+		start, end = end, start
+	}
+	return start, end
+}
+
+// visualEvents returns the listed events inside the visual selection, oldest first
+func (m model) visualEvents() []events.Event {
+	pane := m.paneManager.GetPane(m.activePane)
+	if pane == nil {
+		return nil
+	}
+	start, end := m.visualRange()
+
+	var selected []events.Event
+	for _, idx := range pane.VisibleIndices(m.listFilter()) {
+		if idx >= start && idx <= end {
+			selected = append(selected, pane.Events[idx])
+		}
+	}
+	return selected
+}
+
+// yankEventsCmd copies events to the clipboard in the given format
+// If no clipboard is available (e.g. headless or SSH session), the text is
+// written to a file in the temp directory instead
+func yankEventsCmd(evts []events.Event, format yankFormat) tea.Cmd {
+	return func() tea.Msg {
+		if len(evts) == 0 {
+			return yankDoneMsg{status: i18n.T("yank.empty")}
+		}
+		evts = tui.RedactedAll(evts) // Same masking as on screen
+
+		var text, ext string
+		switch format {
+		case yankMarkdown:
+			text, ext = tui.FormatEventsMarkdown(evts), ".md"
+		default:
+			var err error
+			text, err = tui.FormatEventsJSON(evts)
+			if err != nil {
+				return yankDoneMsg{status: i18n.T("yank.failed", err)}
+			}
+			ext = ".jsonl"
+		}
+
+		if where, err := writeClipboard(text); err == nil {
+			return yankDoneMsg{status: i18n.T("yank.done", len(evts), where)}
+		}
+
+		// Clipboard unavailable - fall back to a file
+		path := filepath.Join(os.TempDir(), fmt.Sprintf("agneto-yank-%s%s", time.Now().Format("20060102-150405"), ext))
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			return yankDoneMsg{status: i18n.T("yank.failed", err)}
+		}
+		return yankDoneMsg{status: i18n.T("yank.file", len(evts), path)}
+	}
+}
+
+// renderVisualInstructions renders instructions for visual mode
+func renderVisualInstructions(count int) string {
+	var result strings.Builder
+
+	indicator := lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color("24")).
+		Foreground(lipgloss.Color("255")).
+		Padding(0, 1).
+		Render(i18n.T("visual.mode", count))
+	result.WriteString(indicator)
+	result.WriteString("  ")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(i18n.T("visual.help"))
+	result.WriteString(instructions)
+
+	return lipgloss.NewStyle().
+		MarginTop(1).
+		Render(result.String())
+}
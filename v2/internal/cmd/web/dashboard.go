@@ -0,0 +1,561 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/durch/agneto/v2/pkg/tui"
+	"github.com/nats-io/nats.go"
+)
+
+// clientBuffer is how many messages a browser may fall behind by before it is
+// disconnected (it reconnects and starts over from a snapshot)
+const clientBuffer = 256
+
+// pingInterval is how often idle browsers are pinged, so proxies keep the
+// connection and dead ones are noticed
+const pingInterval = 30 * time.Second
+
+// maxOperator bounds the operator names browsers give
+const maxOperator = 64
+
+// maxSettled bounds the answered and half-approved decisions remembered
+// before those no longer shown in any pane are forgotten
+const maxSettled = 4096
+
+// answer records who settled a decision, and how
+type answer struct {
+	By    string    `json:"by,omitempty"`  // Operator, when the response names one
+	Via   string    `json:"via,omitempty"` // Where it was given (web, slack, ...)
+	Label string    `json:"label"`         // The action, else the response's message
+	At    time.Time `json:"at"`
+}
+
+// paneView is a pane as browsers show it
+type paneView struct {
+	Name   string         `json:"name"`
+	Title  string         `json:"title"`
+	Events []events.Event `json:"events"`
+}
+
+// message is what the dashboard sends browsers
+// Ops: snapshot (everything, on connect), event (added or grown, at Index of
+// Pane), title (a pane renamed), answered (Answer nil: unsettled again),
+// approvals (an event's half approvals), ok and error (a response's outcome)
+type message struct {
+	Op        string                         `json:"op"`
+	Subject   string                         `json:"subject,omitempty"`
+	MaxEvents int                            `json:"max_events,omitempty"`
+	Panes     []paneView                     `json:"panes,omitempty"`
+	Answered  map[string]answer              `json:"answered,omitempty"`
+	Approvals map[string]map[string][]string `json:"approvals,omitempty"`
+	Pane      string                         `json:"pane,omitempty"`
+	Title     string                         `json:"title,omitempty"`
+	Index     int                            `json:"index,omitempty"`
+	Event     *events.Event                  `json:"event,omitempty"`
+	EventID   string                         `json:"event_id,omitempty"`
+	Answer    *answer                        `json:"answer,omitempty"`
+	Approvers map[string][]string            `json:"approvers,omitempty"`
+	Message   string                         `json:"message,omitempty"`
+}
+
+// request is what browsers send: an operator triggering an event's action
+type request struct {
+	Op       string          `json:"op"` // respond
+	EventID  string          `json:"event_id"`
+	ActionID string          `json:"action_id"` // events.Action.ApprovalID
+	Operator string          `json:"operator"`
+	Input    json.RawMessage `json:"input,omitempty"`   // Input actions: the answer (text, option or bool)
+	Reasons  []string        `json:"reasons,omitempty"` // Reason codes picked from the action's
+}
+
+// client is a connected browser
+type client struct {
+	ws   *wsConn
+	send chan []byte // Closed when the client is dropped
+}
+
+// dashboard keeps the panes browsers are shown and publishes their responses
+// AIDEV-NOTE: The panes are the TUI's own (routes, pane settings, streams,
+// redaction), so the dashboard shows events where the terminal would. Answers
+// follow the Slack forwarder: the first response wins, four-eyes approvals are
+// announced on events.ApprovalsSubject, and responses go through the outbox
+type dashboard struct {
+	subject   string
+	pub       outbox.Publisher // Delivers outbox entries (nc, or the edge stream)
+	nc        *nats.Conn       // Sends replies to producers' reply subjects
+	outbox    *outbox.Outbox
+	maxEvents int
+	secret    []byte // Signs and checks approvals (nil: unsigned, see events.ApprovalSecretEnv)
+
+	mu        sync.Mutex
+	panes     *tui.PaneManager
+	dedup     *monitor.Dedup
+	answered  map[string]answer              // Settled decisions, by event ID
+	approvals map[string]map[string][]string // Operators who approved four-eyes actions, by event and action ID
+	clients   map[*client]bool
+}
+
+// handleEvent shows an event, settling the decision it answers if any
+func (d *dashboard) handleEvent(event events.Event) {
+	// Questions without explicit actions get answer actions, as in the TUI
+	if event.Question != nil {
+		if err := event.Question.Validate(); err != nil {
+			event.Question = nil
+		} else if len(event.Actions) == 0 {
+			event.Actions = event.Question.Actions(event.ID)
+		}
+	}
+	event.AddressReplies()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dedup.Seen(event.IdempotencyKey) {
+		return
+	}
+	if requestID, ok := d.answers(event); ok {
+		by, _ := event.Data["responded_by"].(string)
+		via, _ := event.Data["responded_via"].(string)
+		d.settle(requestID, answer{By: by, Via: via, Label: event.Message, At: event.Timestamp})
+	}
+
+	pane := d.panes.ContinueStream(event)
+	if pane == nil {
+		pane = d.panes.RouteEvent(event)
+	}
+	if pane != nil && pane.LastAdded >= 0 {
+		shown := tui.Redacted(pane.Events[pane.LastAdded])
+		d.broadcast(message{Op: "event", Pane: pane.Name, Title: pane.Title, Index: pane.LastAdded, Event: &shown})
+	}
+	if d.panes.ApplyTitle(event) {
+		name, _, _ := event.PaneTitle()
+		d.broadcast(message{Op: "title", Pane: name, Title: d.panes.GetPane(name).Title})
+	}
+}
+
+// answers returns the decision an event answers: the one its data names
+// (escalations, Slack, other dashboards), else the one it was caused by if it
+// is the response of one of that event's actions (monitors)
+func (d *dashboard) answers(event events.Event) (string, bool) {
+	if id, ok := event.AnsweredEventID(); ok {
+		return id, true
+	}
+	request := d.find(event.CausationID)
+	if request == nil || !event.Answers(*request) {
+		return "", false
+	}
+	for _, action := range request.Actions {
+		if action.Event.Type == event.Type {
+			return request.ID, true
+		}
+	}
+	return "", false
+}
+
+// handleApproval records a half approval announced by any monitor
+func (d *dashboard) handleApproval(a events.Approval) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if a.Final {
+		if _, ok := d.approvals[a.EventID]; ok {
+			delete(d.approvals, a.EventID)
+			d.broadcast(message{Op: "approvals", EventID: a.EventID})
+		}
+		return
+	}
+	if _, settled := d.answered[a.EventID]; settled || d.find(a.EventID) == nil {
+		return
+	}
+	byAction := d.approvals[a.EventID]
+	if byAction == nil {
+		byAction = make(map[string][]string)
+		d.approvals[a.EventID] = byAction
+	}
+	if slices.Contains(byAction[a.ActionID], a.Operator) {
+		return
+	}
+	byAction[a.ActionID] = append(byAction[a.ActionID], a.Operator)
+	d.broadcast(message{Op: "approvals", EventID: a.EventID, Approvers: byAction})
+}
+
+// settle records a decision as answered, unless it already was
+// Call with d.mu held
+func (d *dashboard) settle(eventID string, a answer) bool {
+	if _, ok := d.answered[eventID]; ok {
+		return false
+	}
+	d.answered[eventID] = a
+	delete(d.approvals, eventID)
+	d.prune()
+	d.broadcast(message{Op: "answered", EventID: eventID, Answer: &a})
+	return true
+}
+
+// prune forgets settled and half-approved decisions no pane shows anymore,
+// once there are more than maxSettled
+// Call with d.mu held
+func (d *dashboard) prune() {
+	if len(d.answered)+len(d.approvals) <= maxSettled {
+		return
+	}
+	shown := make(map[string]bool)
+	for _, name := range d.panes.PaneNames() {
+		for _, event := range d.panes.GetPane(name).Events {
+			shown[event.ID] = true
+		}
+	}
+	for id := range d.answered {
+		if !shown[id] {
+			delete(d.answered, id)
+		}
+	}
+	for id := range d.approvals {
+		if !shown[id] {
+			delete(d.approvals, id)
+		}
+	}
+}
+
+// find returns the shown event with an ID, or nil
+// Call with d.mu held
+func (d *dashboard) find(id string) *events.Event {
+	if id == "" {
+		return nil
+	}
+	for _, name := range d.panes.PaneNames() {
+		pane := d.panes.GetPane(name)
+		for i := range pane.Events {
+			if pane.Events[i].ID == id {
+				return &pane.Events[i]
+			}
+		}
+	}
+	return nil
+}
+
+// respond publishes the response of the action a browser triggered, or
+// records a four-eyes approval short of the count
+// Returns what to tell the operator
+func (d *dashboard) respond(req request) (string, error) {
+	operator := strings.TrimSpace(req.Operator)
+	if operator == "" {
+		return "", errors.New("enter your name first, so the response says who answered")
+	}
+	if utf8.RuneCountInString(operator) > maxOperator {
+		return "", fmt.Errorf("names are at most %d characters", maxOperator)
+	}
+
+	// One-shot: the first response wins (for four-eyes actions, the approval completing them)
+	d.mu.Lock()
+	event := d.find(req.EventID)
+	if event == nil {
+		d.mu.Unlock()
+		return "", errors.New("the event is no longer shown")
+	}
+	if a, ok := d.answered[event.ID]; ok {
+		d.mu.Unlock()
+		return "", fmt.Errorf("already answered: %s", describe(a))
+	}
+	var action events.Action
+	found := false
+	for _, a := range event.Actions {
+		if a.ApprovalID() == req.ActionID {
+			action, found = a, true
+			break
+		}
+	}
+	if !found {
+		d.mu.Unlock()
+		return "", fmt.Errorf("unknown action %q", req.ActionID)
+	}
+	key, value, err := inputValue(*event, action, req.Input)
+	if err != nil {
+		d.mu.Unlock()
+		return "", err
+	}
+	if len(req.Reasons) > 0 {
+		for _, code := range req.Reasons {
+			if !slices.Contains(action.Reasons, code) {
+				d.mu.Unlock()
+				return "", fmt.Errorf("%q isn't a reason of %s", code, action.Label)
+			}
+		}
+		action = action.WithReasons(req.Reasons)
+	}
+
+	eventID, needed := event.ID, action.RequiredApprovals()
+	if needed > 1 {
+		byAction := d.approvals[eventID]
+		if byAction == nil {
+			byAction = make(map[string][]string)
+			d.approvals[eventID] = byAction
+		}
+		approvers := byAction[action.ApprovalID()]
+		if slices.Contains(approvers, operator) {
+			d.mu.Unlock()
+			return "", fmt.Errorf("you already approved %s (%d/%d): it needs a different approver", action.Label, len(approvers), needed)
+		}
+		approvers = append(slices.Clone(approvers), operator)
+		if len(approvers) < needed {
+			byAction[action.ApprovalID()] = approvers
+			d.broadcast(message{Op: "approvals", EventID: eventID, Approvers: byAction})
+			d.mu.Unlock()
+			d.publishApproval(events.Approval{EventID: eventID, ActionID: action.ApprovalID(), Operator: operator, Needed: needed, At: time.Now()})
+			return fmt.Sprintf("Approved %s (%d/%d): it needs another approver", action.Label, len(approvers), needed), nil
+		}
+		action = action.WithApprovers(approvers)
+	}
+	d.settle(eventID, answer{By: operator, Via: "web", Label: action.Label, At: time.Now()})
+	d.mu.Unlock()
+
+	if needed > 1 {
+		defer d.publishApproval(events.Approval{EventID: eventID, Operator: operator, Final: true, At: time.Now()})
+	}
+
+	response := action.Response()
+	data := make(map[string]interface{}, len(response.Data)+4)
+	for k, v := range response.Data {
+		data[k] = v
+	}
+	if key != "" {
+		data[key] = value
+	}
+	data[events.AnswersEventIDKey] = eventID
+	data["responded_via"] = "web"
+	data["responded_by"] = operator
+	response.Data = data
+
+	payload, err := response.ToJSON()
+	if err == nil {
+		_, err = d.outbox.Enqueue(d.subject, payload)
+	}
+	if err != nil {
+		log.Printf("failed to queue response to %s: %v", eventID, err)
+		d.mu.Lock()
+		delete(d.answered, eventID)
+		d.broadcast(message{Op: "answered", EventID: eventID})
+		d.mu.Unlock()
+		return "", fmt.Errorf("failed to record %s: %v", action.Label, err)
+	}
+	if _, err := d.outbox.Flush(d.pub); err != nil {
+		log.Printf("outbox: response queued, will retry: %v", err)
+	}
+	if action.ReplyTo != "" {
+		d.nc.Publish(action.ReplyTo, payload) // Inboxes don't outlive the producer, so not through the outbox
+	}
+	return fmt.Sprintf("Sent %s", action.Label), nil
+}
+
+// inputValue checks the answer given to an input action and returns the
+// response Data key and value it is published under
+// Questions publish a typed answer instead of raw text, as in the TUI
+func inputValue(event events.Event, action events.Action, raw json.RawMessage) (string, interface{}, error) {
+	if !action.IsInput() {
+		return "", nil, nil
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil, fmt.Errorf("%s needs an answer (%s)", action.Label, action.InputHint())
+	}
+	switch action.InputType {
+	case events.InputConfirm:
+		var yes bool
+		if err := json.Unmarshal(raw, &yes); err != nil {
+			return "", nil, fmt.Errorf("%s needs yes or no", action.Label)
+		}
+		return events.InputKey, yes, nil
+	case events.InputSelect:
+		var option string
+		if err := json.Unmarshal(raw, &option); err != nil || action.OptionIndex(option) < 0 {
+			return "", nil, fmt.Errorf("%s needs %s", action.Label, action.InputHint())
+		}
+		return events.InputKey, option, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return "", nil, fmt.Errorf("%s needs text", action.Label)
+	}
+	if action.InputType == events.InputLine {
+		text = strings.Join(strings.Fields(text), " ") // Pasted line breaks
+	}
+	if err := action.CheckLength(text); err != nil {
+		return "", nil, err
+	}
+	if event.Question != nil {
+		value, err := event.Question.ParseAnswer(text)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid answer: %v", err)
+		}
+		return "answer", value, nil
+	}
+	return events.InputKey, text, nil
+}
+
+// describe tells who settled a decision, and how
+func describe(a answer) string {
+	text := a.Label
+	if a.By != "" {
+		text += " by " + a.By
+	}
+	if a.Via != "" {
+		text += " (" + a.Via + ")"
+	}
+	return text
+}
+
+// publishApproval announces an approval on events.ApprovalsSubject, through the outbox
+func (d *dashboard) publishApproval(approval events.Approval) {
+	payload, err := approval.Sign(d.secret).ToJSON()
+	if err == nil {
+		_, err = d.outbox.Enqueue(events.ApprovalsSubject, payload)
+	}
+	if err != nil {
+		log.Printf("failed to queue approval of %s: %v", approval.EventID, err)
+		return
+	}
+	if _, err := d.outbox.Flush(d.pub); err != nil {
+		log.Printf("outbox: approval queued, will retry: %v", err)
+	}
+}
+
+// serve sends a browser the panes, then their changes, and handles its
+// responses until it goes away
+func (d *dashboard) serve(ws *wsConn) {
+	c := &client{ws: ws, send: make(chan []byte, clientBuffer)}
+	d.mu.Lock()
+	snapshot, err := json.Marshal(d.snapshot())
+	if err == nil {
+		c.send <- snapshot
+		d.clients[c] = true
+	}
+	d.mu.Unlock()
+	if err != nil {
+		ws.close()
+		return
+	}
+	go c.writeLoop()
+	defer d.drop(c)
+
+	for {
+		data, err := ws.read()
+		if err != nil {
+			return
+		}
+		var req request
+		if err := json.Unmarshal(data, &req); err != nil || req.Op != "respond" {
+			d.tell(c, message{Op: "error", Message: "unknown request"})
+			continue
+		}
+		text, err := d.respond(req)
+		if err != nil {
+			d.tell(c, message{Op: "error", EventID: req.EventID, Message: err.Error()})
+		} else {
+			d.tell(c, message{Op: "ok", EventID: req.EventID, Message: text})
+		}
+	}
+}
+
+// snapshot returns everything a browser shows
+// Call with d.mu held
+func (d *dashboard) snapshot() message {
+	m := message{
+		Op:        "snapshot",
+		Subject:   d.subject,
+		MaxEvents: d.maxEvents,
+		Answered:  d.answered,
+		Approvals: d.approvals,
+	}
+	for _, name := range d.panes.PaneNames() {
+		pane := d.panes.GetPane(name)
+		evts := make([]events.Event, len(pane.Events))
+		for i, event := range pane.Events {
+			evts[i] = tui.Redacted(event)
+		}
+		m.Panes = append(m.Panes, paneView{Name: pane.Name, Title: pane.Title, Events: evts})
+	}
+	return m
+}
+
+// broadcast sends a message to every browser
+// Browsers too far behind are dropped; they reconnect for a fresh snapshot
+// Call with d.mu held
+func (d *dashboard) broadcast(m message) {
+	if len(d.clients) == 0 {
+		return
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	for c := range d.clients {
+		select {
+		case c.send <- data:
+		default:
+			delete(d.clients, c)
+			close(c.send)
+			c.ws.close()
+		}
+	}
+}
+
+// tell sends a message to one browser, if it is still connected
+func (d *dashboard) tell(c *client, m message) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.clients[c] {
+		select {
+		case c.send <- data:
+		default:
+		}
+	}
+}
+
+// drop disconnects a browser
+func (d *dashboard) drop(c *client) {
+	d.mu.Lock()
+	if d.clients[c] {
+		delete(d.clients, c)
+		close(c.send)
+	}
+	d.mu.Unlock()
+	c.ws.close()
+}
+
+// writeLoop writes the client's messages, pinging it while idle
+// After a failed write the connection is closed and the rest discarded
+func (c *client) writeLoop() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	failed := false
+	for {
+		select {
+		case data, ok := <-c.send:
+			if !ok {
+				return
+			}
+			if !failed && c.ws.writeText(data) != nil {
+				failed = true
+				c.ws.close()
+			}
+		case <-ticker.C:
+			if !failed && c.ws.writeFrame(opPing, nil) != nil {
+				failed = true
+				c.ws.close()
+			}
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package web
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/internal/cli"
+	"github.com/durch/agneto/v2/pkg/config"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/health"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/durch/agneto/v2/pkg/tui"
+	"github.com/nats-io/nats.go"
+)
+
+// EnvToken is the token browsers must present (unset: no authentication)
+const EnvToken = "AGNETO_WEB_TOKEN"
+
+// tokenCookie keeps the token in the browser once a ?token= link was opened
+const tokenCookie = "agneto_web_token"
+
+//go:embed index.html
+var indexHTML []byte
+
+// Run runs the web command with its arguments
+func Run(args []string) error {
+	fs := cli.NewFlagSet("web")
+	listen := fs.String("listen", "127.0.0.1:8090", "Address of the dashboard's HTTP server")
+	subject := fs.String("subject", "test.events", "Subject to monitor and publish responses on (default: the settings file's, if it names one)")
+	configPath := fs.String("config", config.DefaultPath(), "Path of the settings file whose routes, panes and redaction the dashboard uses")
+	maxEvents := fs.Int("max-events", 200, "Events kept per pane; the oldest are dropped first")
+	allowOrigin := fs.String("allow-origin", "", "Another origin pages may open the dashboard's WebSocket from (default: its own only)")
+	outboxDir := fs.String("outbox", outbox.DefaultDir("web"), "Directory of the durable outbox for responses")
+	maxLag := fs.Int("max-lag", 1000, "Events waiting to be shown before /readyz fails")
+	maxBacklog := fs.Int("max-backlog", 100, "Queued outbox responses before /readyz fails")
+	natsconn.AddFlags(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("Failed to load settings: %v", err)
+	}
+	if cfg.Subject != "" && !cli.Given(fs, "subject") {
+		*subject = cfg.Subject
+	}
+	if cfg.Server != "" && os.Getenv(natsconn.EnvURL) == "" {
+		os.Setenv(natsconn.EnvURL, cfg.Server)
+	}
+	if cfg.MaxEvents > 0 && !cli.Given(fs, "max-events") {
+		*maxEvents = cfg.MaxEvents
+	}
+	if err := tui.SetRedactPatterns(cfg.Redact); err != nil {
+		return fmt.Errorf("Invalid redaction: %v", err)
+	}
+	for _, o := range cfg.Panes {
+		if err := o.Validate(); err != nil {
+			return fmt.Errorf("Invalid panes setting: %v", err)
+		}
+	}
+
+	ob, err := outbox.Open(*outboxDir)
+	if err != nil {
+		return fmt.Errorf("Failed to open outbox: %v", err)
+	}
+
+	settings := natsconn.Load()
+	nc, err := settings.Connect("agneto-web")
+	if err != nil {
+		return fmt.Errorf("%v (run the doctor command for diagnostics)", err)
+	}
+	defer nc.Close()
+	pub, err := settings.Publisher(nc)
+	if err != nil {
+		return fmt.Errorf("Failed to use the edge stream: %v", err)
+	}
+
+	panes := tui.NewPaneManager(*maxEvents)
+	panes.Routes = cfg.Routes
+	panes.SetOptions(cfg.Panes)
+	d := &dashboard{
+		subject:   *subject,
+		pub:       pub,
+		nc:        nc,
+		outbox:    ob,
+		maxEvents: *maxEvents,
+		secret:    events.ApprovalSecret(),
+		panes:     panes,
+		dedup:     monitor.NewDedup(monitor.DefaultDedupWindow),
+		answered:  make(map[string]answer),
+		approvals: make(map[string]map[string][]string),
+		clients:   make(map[*client]bool),
+	}
+
+	sub, err := nc.Subscribe(*subject, func(msg *nats.Msg) {
+		evts, err := events.DecodeAll(msg.Header.Get(events.ContentTypeHeader), msg.Data)
+		if err != nil {
+			return
+		}
+		for _, event := range evts {
+			d.handleEvent(event)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe to %s: %v", *subject, err)
+	}
+	if _, err := nc.Subscribe(events.ApprovalsSubject, func(msg *nats.Msg) {
+		approval, err := events.ApprovalFromJSON(msg.Data)
+		if err != nil {
+			return
+		}
+		if err := approval.Verify(d.secret); err != nil {
+			log.Printf("ignoring approval by %q of %s: %v", approval.Operator, approval.EventID, err)
+			return
+		}
+		d.handleApproval(*approval)
+	}); err != nil {
+		return fmt.Errorf("Failed to subscribe to %s: %v", events.ApprovalsSubject, err)
+	}
+
+	// Retry responses stuck in the outbox
+	go func() {
+		for range time.Tick(5 * time.Second) {
+			if ob.Len() > 0 {
+				if _, err := ob.Flush(pub); err != nil {
+					log.Printf("outbox: %v", err)
+				}
+			}
+		}
+	}()
+
+	s := &server{dashboard: d, token: os.Getenv(EnvToken), allowOrigin: *allowOrigin}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/ws", s.handleWS)
+	probes := &health.Probes{
+		Live: []health.Check{health.NATSOpen(nc)},
+		Ready: []health.Check{
+			health.NATSConnected(nc),
+			health.ConsumerLag(sub, *maxLag),
+			health.OutboxBacklog(ob, *maxBacklog),
+		},
+	}
+	probes.Register(mux)
+
+	auth := "no authentication: set " + EnvToken + " to require a token"
+	if s.token != "" {
+		auth = "open http://" + *listen + "/?token=... once to sign a browser in"
+	}
+	log.Printf("Web dashboard for %s on http://%s (%s)", *subject, *listen, auth)
+	server := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return server.ListenAndServe()
+}
+
+// server serves the dashboard page and its WebSocket
+type server struct {
+	dashboard   *dashboard
+	token       string
+	allowOrigin string
+}
+
+// handleIndex serves the dashboard page
+// A valid ?token= is kept in a cookie and dropped from the address bar, so
+// it doesn't linger in the browser history
+func (s *server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "missing or wrong token: open the dashboard with ?token=", http.StatusUnauthorized)
+		return
+	}
+	if given := r.URL.Query().Get("token"); given != "" && s.token != "" {
+		http.SetCookie(w, &http.Cookie{
+			Name:     tokenCookie,
+			Value:    given,
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+			Secure:   r.TLS != nil,
+		})
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'")
+	w.Write(indexHTML)
+}
+
+// handleWS upgrades a browser's connection and serves it until it goes away
+func (s *server) handleWS(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "missing or wrong token", http.StatusUnauthorized)
+		return
+	}
+	ws, err := upgradeWS(w, r, s.allowOrigin)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.dashboard.serve(ws)
+}
+
+// authorized checks the request's token: a bearer header, ?token= or the cookie
+func (s *server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		given = r.URL.Query().Get("token")
+	}
+	if given == "" {
+		if cookie, err := r.Cookie(tokenCookie); err == nil {
+			given = cookie.Value
+		}
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) == 1
+}
@@ -0,0 +1,93 @@
+// Package agent keeps a NATS connection open for short-lived publishers:
+// `agneto agent` serves a unix socket, and publishers that find it send their
+// messages through it instead of connecting (and handshaking TLS) every time.
+//
+// The protocol is JSON, one Request and one Reply per line, over a
+// connection that may carry any number of requests.
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// EnvSocket overrides the socket path agents listen on and publishers look for
+const EnvSocket = "AGNETO_AGENT_SOCK"
+
+// Request operations
+const (
+	OpPing    = "ping"    // Check the agent serves the caller's NATS server
+	OpPublish = "publish" // Publish Messages and flush
+	OpPanes   = "panes"   // Ask running monitors for their pane names
+)
+
+// FlushTimeout bounds how long a publish waits for the server to take the messages
+const FlushTimeout = 5 * time.Second
+
+// DialTimeout bounds how long a publisher tries to reach the agent before connecting itself
+const DialTimeout = 200 * time.Millisecond
+
+// Message is a message sent through the agent
+type Message struct {
+	Subject string           `json:"subject"`
+	Header  transport.Header `json:"header,omitempty"`
+	Data    []byte           `json:"data"`
+}
+
+// Request asks the agent to do one operation
+type Request struct {
+	Op       string        `json:"op"`
+	URL      string        `json:"url"`                // NATS URL the caller would connect to; the agent refuses requests for another server
+	Messages []Message     `json:"messages,omitempty"` // OpPublish
+	Timeout  time.Duration `json:"timeout,omitempty"`  // OpPanes: how long answers are collected
+}
+
+// Reply is the agent's answer to a request
+type Reply struct {
+	Error string   `json:"error,omitempty"`
+	Panes []string `json:"panes,omitempty"` // OpPanes
+}
+
+// DefaultSocket returns the agent's socket path: $AGNETO_AGENT_SOCK, else
+// agneto-agent.sock in $XDG_RUNTIME_DIR, else a per-user name in the temp directory
+func DefaultSocket() string {
+	if path := os.Getenv(EnvSocket); path != "" {
+		return path
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "agneto-agent.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("agneto-agent-%d.sock", os.Getuid()))
+}
+
+// NewMessage wraps a message for the agent
+func NewMessage(msg transport.Msg) Message {
+	return Message{Subject: msg.Subject, Header: msg.Header, Data: msg.Data}
+}
+
+// msg turns the message back into one to publish
+func (m Message) msg() transport.Msg {
+	return transport.Msg{Subject: m.Subject, Header: m.Header, Data: m.Data}
+}
+
+// err returns the error a reply carries
+func (r Reply) err() error {
+	if r.Error == "" {
+		return nil
+	}
+	return fmt.Errorf("agent: %s", r.Error)
+}
+
+// encode serializes a request or reply as a line
+func encode(v interface{}) ([]byte, error) {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// Client sends requests to a running agent
+type Client struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	url    string
+}
+
+// Dial connects to the agent listening on path, for publishing to the NATS server at url
+// Fails quickly when none is, so the caller can connect to NATS itself
+func Dial(path, url string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, DialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, reader: bufio.NewReader(conn), url: url}, nil
+}
+
+// do sends a request and reads its reply
+func (c *Client) do(req Request, timeout time.Duration) (Reply, error) {
+	req.URL = c.url
+	line, err := encode(req)
+	if err != nil {
+		return Reply{}, err
+	}
+	c.conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := c.conn.Write(line); err != nil {
+		return Reply{}, fmt.Errorf("agent: %w", err)
+	}
+	answer, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return Reply{}, fmt.Errorf("agent: %w", err)
+	}
+	var reply Reply
+	if err := json.Unmarshal(answer, &reply); err != nil {
+		return Reply{}, fmt.Errorf("agent: invalid reply: %w", err)
+	}
+	return reply, reply.err()
+}
+
+// Ping checks the agent is connected to the caller's NATS server
+func (c *Client) Ping() error {
+	_, err := c.do(Request{Op: OpPing}, time.Second)
+	return err
+}
+
+// Publish publishes messages through the agent's connection, returning once
+// the server has taken them all
+func (c *Client) Publish(msgs ...transport.Msg) error {
+	req := Request{Op: OpPublish}
+	for _, msg := range msgs {
+		req.Messages = append(req.Messages, NewMessage(msg))
+	}
+	_, err := c.do(req, FlushTimeout+time.Second)
+	return err
+}
+
+// DiscoverPanes asks running monitors for their pane names, as client.DiscoverPanes does
+func (c *Client) DiscoverPanes(timeout time.Duration) ([]string, error) {
+	reply, err := c.do(Request{Op: OpPanes, Timeout: timeout}, timeout+time.Second)
+	return reply.Panes, err
+}
+
+// Close closes the connection to the agent
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
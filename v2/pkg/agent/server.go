@@ -0,0 +1,140 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/durch/agneto/v2/pkg/client"
+	"github.com/durch/agneto/v2/pkg/metrics"
+	"github.com/durch/agneto/v2/pkg/transport"
+	"github.com/nats-io/nats.go"
+)
+
+// maxRequestSize bounds a request line (a broadcast of large events)
+const maxRequestSize = 16 << 20
+
+// Server answers publishers' requests over its NATS connection
+type Server struct {
+	t   transport.Transport
+	url string // URL the connection was made with
+
+	// Prometheus metrics (nil until Instrument)
+	requests  *metrics.Counter
+	published *metrics.Counter
+}
+
+// Instrument registers the server's metrics: requests by op and result, and messages published
+func (s *Server) Instrument(r *metrics.Registry) {
+	s.requests = r.Counter("agneto_agent_requests_total", "Publisher requests, by op and result (ok or error).", "op", "result")
+	s.published = r.Counter("agneto_agent_messages_published_total", "Messages published for publishers.")
+}
+
+// NewServer creates a server publishing on nc, connected to url
+func NewServer(nc *nats.Conn, url string) *Server {
+	return &Server{t: transport.NATS{Conn: nc}, url: url}
+}
+
+// Listen listens on a unix socket only the current user can use
+// A socket left behind by an agent that is gone is replaced; one that still
+// answers means an agent is already running
+func Listen(path string) (net.Listener, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, DialTimeout); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("an agent is already listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// Serve answers connections until the listener is closed
+func (s *Server) Serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn answers a connection's requests in order
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64<<10), maxRequestSize)
+	for scanner.Scan() {
+		var req Request
+		reply := Reply{}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			reply.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			reply = s.handle(req)
+		}
+		result := "ok"
+		if reply.Error != "" {
+			result = "error"
+		}
+		s.requests.Inc(req.Op, result)
+		line, err := encode(reply)
+		if err == nil {
+			_, err = conn.Write(line)
+		}
+		if err != nil {
+			log.Printf("agent: %v", err)
+			return
+		}
+	}
+}
+
+// handle runs a request
+func (s *Server) handle(req Request) Reply {
+	if req.URL != s.url {
+		return Reply{Error: fmt.Sprintf("connected to %s, not %s", s.url, req.URL)}
+	}
+	switch req.Op {
+	case OpPing:
+		return Reply{}
+	case OpPublish:
+		for _, m := range req.Messages {
+			if err := s.t.Publish(m.msg()); err != nil {
+				return Reply{Error: fmt.Sprintf("%s: %v", m.Subject, err)}
+			}
+			s.published.Inc()
+		}
+		if err := transport.Flush(s.t, FlushTimeout); err != nil {
+			return Reply{Error: err.Error()}
+		}
+		return Reply{}
+	case OpPanes:
+		timeout := req.Timeout
+		if timeout <= 0 {
+			timeout = client.DefaultDiscoveryTimeout
+		}
+		panes, err := client.DiscoverPanes(s.t, timeout)
+		if err != nil {
+			return Reply{Error: err.Error()}
+		}
+		return Reply{Panes: panes}
+	}
+	return Reply{Error: fmt.Sprintf("unknown op %q", req.Op)}
+}
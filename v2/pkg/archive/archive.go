@@ -0,0 +1,263 @@
+// Package archive records received events to disk as rolling JSON Lines
+// segments, and moves closed segments to S3-compatible cold storage.
+//
+// The open segment is <start>.jsonl.open; when it grows past the policy's
+// size or age it is renamed to <start>.jsonl and a new one is started.
+// Closed segments are what gets uploaded, and are removed locally once the
+// upload succeeded, so long-running monitors keep full history without
+// unbounded local disk use. Every segment reads back with events.ReadJSONL.
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults for Policy
+const (
+	DefaultMaxBytes = 64 << 20 // 64 MiB
+	DefaultMaxAge   = 24 * time.Hour
+)
+
+// Segment file suffixes
+const (
+	segmentSuffix = ".jsonl"
+	openSuffix    = ".jsonl.open"
+)
+
+// segmentTimeFormat names segments by the time they were started (sortable, no colons)
+const segmentTimeFormat = "20060102T150405.000Z"
+
+// Policy decides when the open segment is closed; zero fields never roll over
+type Policy struct {
+	MaxBytes int64         // Close the segment once it holds this many bytes
+	MaxAge   time.Duration // Close the segment once it is this old (checked on write)
+}
+
+// Writer appends events to the open segment, rolling over by Policy
+// It is not safe for concurrent use
+type Writer struct {
+	dir    string
+	policy Policy
+	file   *os.File
+	path   string
+	size   int64
+	opened time.Time
+}
+
+// DefaultDir returns the archive directory of a monitor instance
+// (~/.config/agneto/archive/<instance>)
+func DefaultDir(instance string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join("agneto-archive", instance)
+	}
+	return filepath.Join(dir, "agneto", "archive", instance)
+}
+
+// OpenWriter opens (creating if needed) the archive in dir
+// Segments left open by a previous run are closed first, so they are uploaded too
+func OpenWriter(dir string, policy Policy) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	leftover, err := filepath.Glob(filepath.Join(dir, "*"+openSuffix))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range leftover {
+		if err := closeSegment(path); err != nil {
+			return nil, err
+		}
+	}
+	return &Writer{dir: dir, policy: policy}, nil
+}
+
+// Dir returns the archive directory
+func (w *Writer) Dir() string {
+	return w.dir
+}
+
+// Write appends a JSON event payload to the open segment as one line, starting a segment if needed
+// Returns the path of the segment the write closed, if it rolled over
+func (w *Writer) Write(payload []byte) (closed string, err error) {
+	var line bytes.Buffer
+	if err := json.Compact(&line, payload); err != nil {
+		return "", fmt.Errorf("not a JSON payload: %w", err)
+	}
+	line.WriteByte('\n')
+
+	if w.file != nil && w.due(time.Now()) {
+		if closed, err = w.Close(); err != nil {
+			return "", err
+		}
+	}
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return closed, err
+		}
+	}
+
+	n, err := w.file.Write(line.Bytes())
+	w.size += int64(n)
+	return closed, err
+}
+
+// Close closes the open segment, if any, and returns its path for upload
+func (w *Writer) Close() (string, error) {
+	if w.file == nil {
+		return "", nil
+	}
+	path := w.path
+	err := w.file.Close()
+	w.file, w.path, w.size = nil, "", 0
+	if err != nil {
+		return "", err
+	}
+	if err := closeSegment(path); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, ".open"), nil
+}
+
+// due reports whether the open segment should be closed before the next write
+func (w *Writer) due(now time.Time) bool {
+	return (w.policy.MaxBytes > 0 && w.size >= w.policy.MaxBytes) ||
+		(w.policy.MaxAge > 0 && now.Sub(w.opened) >= w.policy.MaxAge)
+}
+
+// open starts a new segment named by the current time
+func (w *Writer) open() error {
+	w.opened = time.Now()
+	w.path = filepath.Join(w.dir, w.opened.UTC().Format(segmentTimeFormat)+openSuffix)
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	return nil
+}
+
+// closeSegment renames an open segment to its closed name
+func closeSegment(path string) error {
+	return os.Rename(path, strings.TrimSuffix(path, ".open"))
+}
+
+// Closed returns the closed segments in dir that are still stored locally, oldest first
+func Closed(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Segments returns every segment in dir, closed or still open, oldest first
+func Segments(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Uploader moves a closed segment to cold storage (satisfied by *S3)
+type Uploader interface {
+	Upload(ctx context.Context, segment string) error
+}
+
+// UploadClosed uploads the closed segments in dir, oldest first, removing
+// each local copy once it is stored
+// Stops at the first failure, leaving it and later segments for the next call
+// Returns how many segments were uploaded
+func UploadClosed(ctx context.Context, up Uploader, dir string) (int, error) {
+	segments, err := Closed(dir)
+	if err != nil {
+		return 0, err
+	}
+	for i, segment := range segments {
+		if err := up.Upload(ctx, segment); err != nil {
+			return i, fmt.Errorf("uploading %s: %w", filepath.Base(segment), err)
+		}
+		if err := os.Remove(segment); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(segments), nil
+}
+
+// Open opens a recorded session for replay: a local JSON Lines file or
+// segment, a local archive directory (its segments in order, as one
+// recording), or an s3://bucket/key object (credentials as for S3FromEnv)
+func Open(ctx context.Context, location string) (io.ReadCloser, error) {
+	if !IsS3URL(location) {
+		if info, err := os.Stat(location); err == nil && info.IsDir() {
+			return openDir(location)
+		}
+		return os.Open(location)
+	}
+	bucket, key, err := ParseS3URL(location)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return nil, fmt.Errorf("%s names no object (replay one segment at a time)", location)
+	}
+	store, err := S3FromEnv("s3://" + bucket)
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(ctx, key)
+}
+
+// segmentsReader reads the segments of an archive directory one after the other
+type segmentsReader struct {
+	io.Reader
+	files []*os.File
+}
+
+// Close closes every segment
+func (r *segmentsReader) Close() error {
+	var err error
+	for _, f := range r.files {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// openDir opens the segments of an archive directory as one recording
+// Every segment ends with a newline, so their lines join up
+func openDir(dir string) (io.ReadCloser, error) {
+	paths, err := Segments(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no archive segments in %s", dir)
+	}
+	r := &segmentsReader{}
+	readers := make([]io.Reader, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		r.files = append(r.files, f)
+		readers = append(readers, f)
+	}
+	r.Reader = io.MultiReader(readers...)
+	return r, nil
+}
@@ -0,0 +1,95 @@
+package archive
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Found is an event found in a directory of JSON Lines files
+type Found struct {
+	Event events.Event
+	File  string // File it was recorded in
+}
+
+// Find looks for events matching a reference (see events.Event.MatchesRef)
+// in the JSON Lines files of dirs - archive segments, open or closed, and
+// closed pane archives - newest file first
+// Segments already moved to cold storage aren't searched
+// Lines that aren't events are skipped; missing dirs are no error
+func Find(dirs []string, ref string) ([]Found, error) {
+	type file struct {
+		path     string
+		modified int64
+	}
+	var files []file
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasSuffix(name, segmentSuffix) && !strings.HasSuffix(name, openSuffix) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue // Uploaded and removed meanwhile
+			}
+			files = append(files, file{path: filepath.Join(dir, name), modified: info.ModTime().UnixNano()})
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].modified > files[j].modified })
+
+	var found []Found
+	seen := make(map[string]bool)
+	for _, f := range files {
+		matched, err := findInFile(f.path, ref)
+		if err != nil {
+			return found, err
+		}
+		for _, event := range matched {
+			if !seen[event.ID] {
+				seen[event.ID] = true
+				found = append(found, Found{Event: event, File: f.path})
+			}
+		}
+	}
+	return found, nil
+}
+
+// findInFile returns the events in a JSON Lines file matching ref
+func findInFile(path, ref string) ([]events.Event, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil // Closed (renamed) or uploaded since it was listed
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var matched []events.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		event, err := events.FromJSON(scanner.Bytes())
+		if err != nil {
+			continue // Not an event, or the line being written
+		}
+		if event.MatchesRef(ref) {
+			matched = append(matched, *event)
+		}
+	}
+	return matched, scanner.Err()
+}
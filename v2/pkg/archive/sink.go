@@ -0,0 +1,93 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/monitor"
+)
+
+// uploadTimeout bounds one round of uploads
+const uploadTimeout = 5 * time.Minute
+
+// Sink archives every message on a monitor bus in bus order, uploading
+// segments to Store as they close
+type Sink struct {
+	writer *Writer
+	store  Uploader // nil keeps closed segments local
+
+	mu        sync.Mutex // Guards writer and err
+	err       error      // Failures since the last Err call
+	uploading sync.Mutex // One upload round at a time
+}
+
+// NewSink creates a sink writing to w and uploading to store (nil: no upload)
+func NewSink(w *Writer, store Uploader) *Sink {
+	return &Sink{writer: w, store: store}
+}
+
+// Dir returns the directory segments are written to
+func (s *Sink) Dir() string {
+	return s.writer.Dir()
+}
+
+// Deliver implements monitor.Sink
+// Payloads that aren't JSON are skipped (strict mode reports them), as are
+// events replayed from JetStream history, which were archived on arrival
+func (s *Sink) Deliver(msg monitor.Message) {
+	if msg.Source == "history" {
+		return
+	}
+	s.mu.Lock()
+	closed, err := s.writer.Write(msg.Data)
+	if err != nil {
+		s.err = errors.Join(s.err, err)
+	}
+	s.mu.Unlock()
+
+	if closed != "" && s.store != nil {
+		go s.Upload()
+	}
+}
+
+// Upload uploads every closed segment still stored locally
+// Segments from earlier failed rounds or previous runs are retried too
+func (s *Sink) Upload() (int, error) {
+	if s.store == nil {
+		return 0, nil
+	}
+	s.uploading.Lock()
+	defer s.uploading.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+	n, err := UploadClosed(ctx, s.store, s.writer.Dir())
+	if err != nil {
+		s.mu.Lock()
+		s.err = errors.Join(s.err, err)
+		s.mu.Unlock()
+	}
+	return n, err
+}
+
+// Err returns the write and upload failures since the last call, clearing them
+func (s *Sink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.err
+	s.err = nil
+	return err
+}
+
+// Close closes the open segment and uploads what is left
+func (s *Sink) Close() (int, error) {
+	s.mu.Lock()
+	_, err := s.writer.Close()
+	s.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	return s.Upload()
+}
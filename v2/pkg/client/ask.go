@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// Response is the answer to a request: the response event and the request's
+// action it came from
+type Response struct {
+	events.Event
+	Action *events.Action // The request's action with the response's type (nil if none has it)
+}
+
+// Input returns the value an input action was answered with (Data["input"]):
+// a string for text and select inputs, a bool for confirm inputs
+func (r Response) Input() (interface{}, bool) {
+	value, ok := r.Data[events.InputKey]
+	return value, ok
+}
+
+// Awaiter collects the response to a request from its reply subject and the
+// subjects it was published to
+// Subscribe with Await before publishing the request, so a fast answer isn't missed
+type Awaiter struct {
+	request   events.Event
+	expected  map[string]bool   // Response types of the request's actions
+	responses chan events.Event // Responses to the request; only the first counts
+	subs      []transport.Subscription
+}
+
+// Await subscribes for the response to request on request.ReplyTo (if set) and subjects
+// Responses are paired by correlation ID; ones without it (from monitors
+// predating reply subjects) are matched by the types of the request's actions
+// AIDEV-NOTE: Messages are matched in the subscription handler, so a burst of
+// unrelated events on a busy subject never takes the place of the response
+func Await(t transport.Transport, request events.Event, subjects ...string) (*Awaiter, error) {
+	a := &Awaiter{
+		request:   request,
+		expected:  make(map[string]bool),
+		responses: make(chan events.Event, 1),
+	}
+	for _, action := range request.Actions {
+		a.expected[action.Event.Type] = true
+	}
+
+	if request.ReplyTo != "" {
+		subjects = append([]string{request.ReplyTo}, subjects...)
+	}
+	for _, subject := range subjects {
+		sub, err := t.Subscribe(subject, a.handle)
+		if err != nil {
+			a.Close()
+			return nil, fmt.Errorf("subscribing to %s: %w", subject, err)
+		}
+		a.subs = append(a.subs, sub)
+	}
+	return a, nil
+}
+
+// handle keeps a message if it is a response to the request
+func (a *Awaiter) handle(msg transport.Msg) {
+	event, err := events.Decode(msg.Header.Get(events.ContentTypeHeader), msg.Data)
+	if err != nil {
+		return
+	}
+	legacy := event.CorrelationID == "" && a.expected[event.Type]
+	if !event.Answers(a.request) && !legacy {
+		return
+	}
+	select {
+	case a.responses <- *event:
+	default: // A response is already waiting
+	}
+}
+
+// Wait returns the first response to the request, or ctx's error
+func (a *Awaiter) Wait(ctx context.Context) (Response, error) {
+	select {
+	case event := <-a.responses:
+		return a.response(event), nil
+	case <-ctx.Done():
+		return Response{}, fmt.Errorf("waiting for a response to %s: %w", a.request.ID, ctx.Err())
+	}
+}
+
+// Close unsubscribes
+func (a *Awaiter) Close() {
+	for _, sub := range a.subs {
+		sub.Unsubscribe()
+	}
+	a.subs = nil
+}
+
+// response pairs a response event with the request's action of its type
+func (a *Awaiter) response(event events.Event) Response {
+	response := Response{Event: event}
+	for i := range a.request.Actions {
+		if a.request.Actions[i].Event.Type == event.Type {
+			response.Action = &a.request.Actions[i]
+			break
+		}
+	}
+	return response
+}
+
+// Ask publishes an event with actions and waits for the operator's response
+// Like Request it sets a reply inbox, and it also watches the publisher's
+// subject, so monitors that don't answer on reply subjects are heard too
+func (p *Publisher) Ask(ctx context.Context, event events.Event) (Response, error) {
+	return p.ask(ctx, event, p.Subject)
+}
+
+// ask publishes the request and waits for its response on a fresh inbox and subjects
+func (p *Publisher) ask(ctx context.Context, event events.Event, subjects ...string) (Response, error) {
+	Prepare(&event)
+	event.ReplyTo = transport.NewInbox()
+	awaiter, err := Await(p.Transport, event, subjects...)
+	if err != nil {
+		return Response{}, err
+	}
+	defer awaiter.Close()
+
+	if _, err := p.Publish(ctx, event); err != nil {
+		return Response{}, err
+	}
+	return awaiter.Wait(ctx)
+}
+
+// Subscribe dispatches every event on the publisher's subject to handler until ctx is done
+// The returned subscriber reports handler errors on its Errors channel
+func (p *Publisher) Subscribe(ctx context.Context, handler func(context.Context, events.Event) error) (*Subscriber, error) {
+	sub := NewSubscriberWithTransport(p.Transport, p.Subject)
+	if err := sub.OnEvent("*", handler); err != nil {
+		return nil, err
+	}
+	if err := sub.Start(ctx); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
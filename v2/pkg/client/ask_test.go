@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+func TestAwaitResponseSurvivesBusySubject(t *testing.T) {
+	bus := transport.NewMemory()
+	defer bus.Close()
+	request := events.Event{
+		ID:      "req-1",
+		ReplyTo: transport.NewInbox(),
+		Actions: []events.Action{{ID: "yes", Key: "y", Event: events.Event{Type: "deploy_yes"}}},
+	}
+	a, err := Await(bus, request, "agneto.events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	// A burst of unrelated traffic nobody reads, then the answer
+	for i := 0; i < 500; i++ {
+		msg, err := NewMsg("agneto.events", events.Event{ID: fmt.Sprint(i), Type: "log", CorrelationID: "other"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bus.Publish(msg)
+	}
+	answer := request.Actions[0].Response()
+	answer.CorrelationID = request.Correlation()
+	msg, err := NewMsg(request.ReplyTo, answer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish(msg)
+	time.Sleep(50 * time.Millisecond) // Let everything be delivered before anyone reads
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	response, err := a.Wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.ID != answer.ID || response.Action == nil || response.Action.ID != "yes" {
+		t.Fatalf("got response %s (action %v), want %s to yes", response.ID, response.Action, answer.ID)
+	}
+}
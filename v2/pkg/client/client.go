@@ -0,0 +1,242 @@
+// Package client is a small SDK for orchestrators publishing events to
+// monitors and handling the responses.
+//
+// Every event gets an idempotency key before its first publish attempt, so
+// retrying a publish that failed (or only looked failed, e.g. a flush timeout
+// after the server already had the message) can't create a logical
+// duplicate: monitors drop repeated keys, and JetStream streams deduplicate
+// on the Nats-Msg-Id header the key is copied into.
+//
+// A publisher for a session (NewSession) publishes on the session's subject,
+// tags every event with the session ID and announces the session so
+// monitors can list it.
+//
+// Publisher.Ask publishes a request with actions and waits for the
+// operator's response, as the publisher command does.
+//
+// Publisher.Stream publishes output as it is produced, which monitors grow
+// one entry with instead of listing every chunk; Publisher.PublishBatch
+// publishes a burst of events in one message.
+//
+// On the consuming side, Subscriber dispatches events to handlers
+// registered by type, decoding their data into the handler's own struct.
+//
+// Events are JSON unless Publisher.ContentType asks for protobuf, which is
+// faster for large Content; consumers decode by the Content-Type header.
+//
+// Everything goes through a transport.Transport: New and friends take a NATS
+// connection, NewWithTransport any other backend.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transport"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+)
+
+// MsgIDHeader is the header JetStream deduplicates on
+const MsgIDHeader = nats.MsgIdHdr
+
+// Defaults for Publisher
+const (
+	DefaultRetries      = 3
+	DefaultBackoff      = 200 * time.Millisecond
+	DefaultFlushTimeout = 2 * time.Second
+)
+
+// Publisher publishes events to a subject, retrying failed publishes
+type Publisher struct {
+	Transport    transport.Transport
+	Subject      string
+	Retries      int           // Attempts after the first failed one
+	Backoff      time.Duration // Wait before the first retry, doubled for each further retry
+	FlushTimeout time.Duration // How long to wait for the backend to take each attempt
+	Source       string        // Stamped on events without a Source (e.g. the agent's name)
+	Session      string        // Stamped on events without a SessionID, and announced (see NewSession)
+	ContentType  string        // events.ContentTypeProto publishes protobuf (see events.ToProto); default JSON
+
+	announceMu  sync.Mutex
+	announcedAt time.Time
+}
+
+// New creates a publisher on a NATS connection with default retry settings
+func New(nc *nats.Conn, subject string) *Publisher {
+	return NewWithTransport(transport.NATS{Conn: nc}, subject)
+}
+
+// NewWithTransport creates a publisher on any transport with default retry settings
+func NewWithTransport(t transport.Transport, subject string) *Publisher {
+	return &Publisher{
+		Transport:    t,
+		Subject:      subject,
+		Retries:      DefaultRetries,
+		Backoff:      DefaultBackoff,
+		FlushTimeout: DefaultFlushTimeout,
+	}
+}
+
+// NewSession creates a publisher for a session: events go to its subject
+// (agneto.<session>.events) tagged with the session ID, and the session is
+// announced on events.SessionsSubject while publishing
+func NewSession(nc *nats.Conn, session string) (*Publisher, error) {
+	if err := events.ValidateSession(session); err != nil {
+		return nil, err
+	}
+	p := New(nc, events.SessionSubject(session))
+	p.Session = session
+	return p, nil
+}
+
+// Prepare fills in a missing ID, timestamp and idempotency key
+// The key defaults to the event ID; producers that rebuild an event when
+// retrying at a higher level should set a key derived from what the event
+// means instead (e.g. "plan-approval/task-42")
+func Prepare(event *events.Event) {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.IdempotencyKey == "" {
+		event.IdempotencyKey = event.ID
+	}
+}
+
+// NewMsg serializes an event into a message carrying its idempotency key as Nats-Msg-Id
+func NewMsg(subject string, event events.Event) (transport.Msg, error) {
+	return EncodeMsg(subject, event, "")
+}
+
+// EncodeMsg is NewMsg in the encoding a Content-Type names (JSON when empty)
+// Protobuf messages carry the Content-Type header consumers decode them by
+func EncodeMsg(subject string, event events.Event, contentType string) (transport.Msg, error) {
+	data, err := event.Encode(contentType)
+	if err != nil {
+		return transport.Msg{}, err
+	}
+	msg := transport.NewMsg(subject, data)
+	if events.IsProto(contentType) {
+		msg.Header.Set(events.ContentTypeHeader, events.ContentTypeProto)
+	}
+	if event.IdempotencyKey != "" {
+		msg.Header.Set(MsgIDHeader, event.IdempotencyKey)
+	}
+	return msg, nil
+}
+
+// Publish prepares the event and publishes it, retrying with the same key on failure
+// Returns the event as published; ctx cancels waiting between retries
+func (p *Publisher) Publish(ctx context.Context, event events.Event) (events.Event, error) {
+	if event.Source == "" {
+		event.Source = p.Source
+	}
+	if event.SessionID == "" {
+		event.SessionID = p.Session
+	}
+	Prepare(&event)
+	msg, err := EncodeMsg(p.Subject, event, p.ContentType)
+	if err != nil {
+		return event, err
+	}
+	if err := p.send(ctx, msg, event.IdempotencyKey); err != nil {
+		return event, err
+	}
+	p.announce(event)
+	return event, nil
+}
+
+// PublishBatch prepares the events and publishes them in one envelope (see
+// events.Batch), retrying with the same batch ID on failure
+// Envelopes are JSON whatever ContentType says; returns the batch as published
+func (p *Publisher) PublishBatch(ctx context.Context, evts []events.Event) (events.Batch, error) {
+	prepared := make([]events.Event, len(evts))
+	for i, event := range evts {
+		if event.Source == "" {
+			event.Source = p.Source
+		}
+		if event.SessionID == "" {
+			event.SessionID = p.Session
+		}
+		Prepare(&event)
+		prepared[i] = event
+	}
+	batch := events.NewBatch(prepared)
+	if err := batch.Validate(); err != nil {
+		return batch, err
+	}
+	data, err := batch.ToJSON()
+	if err != nil {
+		return batch, err
+	}
+	msg := transport.NewMsg(p.Subject, data)
+	msg.Header.Set(MsgIDHeader, batch.ID)
+	if err := p.send(ctx, msg, batch.ID); err != nil {
+		return batch, err
+	}
+	p.announce(prepared[len(prepared)-1])
+	return batch, nil
+}
+
+// send publishes a message, retrying with backoff; key names it in errors
+func (p *Publisher) send(ctx context.Context, msg transport.Msg, key string) error {
+	backoff := p.Backoff
+	var errs []error
+	for attempt := 0; attempt <= p.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				errs = append(errs, ctx.Err())
+				return fmt.Errorf("publish %s cancelled after %d attempt(s): %w", key, attempt, errors.Join(errs...))
+			}
+			backoff *= 2
+		}
+		err := p.Transport.Publish(msg)
+		if err == nil && p.FlushTimeout > 0 {
+			err = transport.Flush(p.Transport, p.FlushTimeout)
+		}
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("publish %s failed after %d attempt(s): %w", key, len(errs), errors.Join(errs...))
+}
+
+// announce tells monitors about the publisher's session, at most every events.SessionInterval
+// Announcing is best effort: a monitor that misses it still sees the events
+func (p *Publisher) announce(event events.Event) {
+	if p.Session == "" || event.SessionID != p.Session {
+		return
+	}
+	p.announceMu.Lock()
+	due := time.Since(p.announcedAt) >= events.SessionInterval
+	if due {
+		p.announcedAt = time.Now()
+	}
+	p.announceMu.Unlock()
+	if !due {
+		return
+	}
+	if data, err := events.NewSessionAnnouncement(event).ToJSON(); err == nil {
+		p.Transport.Publish(transport.Msg{Subject: events.SessionsSubject, Data: data})
+	}
+}
+
+// Request publishes an event with actions and waits for the response to it
+// The event's ReplyTo is set to a fresh inbox that monitors answer on, and
+// only a response carrying the event's correlation ID is accepted, so
+// concurrent requests never take each other's answers (see Ask to also
+// hear monitors that don't answer on reply subjects)
+func (p *Publisher) Request(ctx context.Context, event events.Event) (events.Event, error) {
+	response, err := p.ask(ctx, event)
+	return response.Event, err
+}
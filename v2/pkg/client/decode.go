@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Validator is implemented by decoded types that check their own values
+// Decode, Ask and On call it after decoding, failing with a *ValidationError
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError reports decoded data its type's Validate rejected
+type ValidationError struct {
+	EventID string
+	Type    string
+	Err     error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s event %s: %v", e.Type, e.EventID, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// decodeData decodes an event's Data into v through JSON (so struct tags
+// apply), then validates it if v implements Validator
+func decodeData(event events.Event, v interface{}) error {
+	data, err := json.Marshal(event.Data)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return fmt.Errorf("decoding %s data into %T: %w", event.Type, v, err)
+	}
+	if validator, ok := v.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return &ValidationError{EventID: event.ID, Type: event.Type, Err: err}
+		}
+	}
+	return nil
+}
+
+// Decode decodes the response's Data into v, a pointer to a struct (or any
+// JSON-decodable value), and validates it if v implements Validator
+// Input answers are under "input" and typed question answers under "answer":
+//
+//	var answer struct {
+//		Target string   `json:"input"`
+//		Reasons []string `json:"reason_codes"`
+//	}
+//	err := response.Decode(&answer)
+func (r Response) Decode(v interface{}) error {
+	return decodeData(r.Event, v)
+}
+
+// Ask publishes an event with actions, waits for the operator's response
+// (see Publisher.Ask) and decodes its Data into a T
+// The response is returned as well, e.g. to tell which action was taken;
+// on a decoding or *ValidationError it is still set
+func Ask[T any](ctx context.Context, p *Publisher, event events.Event) (T, Response, error) {
+	var value T
+	response, err := p.Ask(ctx, event)
+	if err != nil {
+		return value, response, err
+	}
+	err = response.Decode(&value)
+	return value, response, err
+}
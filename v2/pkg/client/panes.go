@@ -0,0 +1,52 @@
+package client
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transport"
+)
+
+// DefaultDiscoveryTimeout is how long DiscoverPanes collects answers
+const DefaultDiscoveryTimeout = 300 * time.Millisecond
+
+// DiscoverPanes asks running monitors for their pane names
+// Every monitor answers, so answers are collected until timeout; the union
+// of their panes is returned, sorted (empty when no monitor is running)
+func DiscoverPanes(t transport.Transport, timeout time.Duration) ([]string, error) {
+	inbox := transport.NewInbox()
+	answers := make(chan transport.Msg, 64)
+	sub, err := transport.Chan(t, inbox, answers)
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+	if err := t.Publish(transport.Msg{Subject: events.PanesSubject, Reply: inbox}); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	deadline := time.After(timeout)
+	for collecting := true; collecting; {
+		select {
+		case msg := <-answers:
+			var names []string
+			if json.Unmarshal(msg.Data, &names) == nil {
+				for _, name := range names {
+					seen[name] = true
+				}
+			}
+		case <-deadline:
+			collecting = false // Every monitor that will answer has
+		}
+	}
+
+	panes := make([]string, 0, len(seen))
+	for name := range seen {
+		panes = append(panes, name)
+	}
+	sort.Strings(panes)
+	return panes, nil
+}
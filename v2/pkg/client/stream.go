@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/google/uuid"
+)
+
+// Stream publishes output as it is produced (e.g. an LLM's tokens), which
+// monitors show as one growing entry rather than an event per chunk
+// (see events/stream.go)
+// Not safe for concurrent use: chunks must be published in order
+type Stream struct {
+	p       *Publisher
+	event   events.Event // The stream's first event, the template of later ones
+	started bool
+	closed  bool
+}
+
+// Stream starts a stream shaped like event (type, pane, message, data):
+// the first Write or Close publishes it with the text as its Content
+// The event's StreamID defaults to a fresh one
+func (p *Publisher) Stream(event events.Event) (*Stream, error) {
+	if len(event.Actions) > 0 || event.Question != nil {
+		return nil, fmt.Errorf("events with actions or a question can't be streamed")
+	}
+	if event.StreamID == "" {
+		event.StreamID = uuid.New().String()
+	}
+	event.Delta, event.Final = false, false
+	return &Stream{p: p, event: event}, nil
+}
+
+// ID returns the stream ID its events share
+func (s *Stream) ID() string {
+	return s.event.StreamID
+}
+
+// Write publishes a chunk of output, appended to the stream's Content
+func (s *Stream) Write(ctx context.Context, text string) error {
+	return s.publish(ctx, text, false)
+}
+
+// Close publishes the last chunk of output (may be empty) and finishes the stream
+func (s *Stream) Close(ctx context.Context, text string) error {
+	return s.publish(ctx, text, true)
+}
+
+// publish sends a chunk: the first one is the stream's event itself, later
+// ones are deltas carrying just the text
+func (s *Stream) publish(ctx context.Context, text string, final bool) error {
+	if s.closed {
+		return fmt.Errorf("stream %s is closed", s.event.StreamID)
+	}
+	event := s.event
+	if s.started {
+		// Type and pane route the chunk should a monitor have missed the stream's start
+		event = events.Event{
+			Type:      s.event.Type,
+			Pane:      s.event.Pane,
+			Source:    s.event.Source,
+			SessionID: s.event.SessionID,
+			StreamID:  s.event.StreamID,
+			Delta:     true,
+		}
+	}
+	event.Content, event.Final = text, final
+	if _, err := s.p.Publish(ctx, event); err != nil {
+		return err
+	}
+	s.started, s.closed = true, final
+	return nil
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transport"
+	"github.com/nats-io/nats.go"
+)
+
+// DefaultErrorBuffer is the capacity of Subscriber.Errors
+const DefaultErrorBuffer = 64
+
+// HandlerError reports an event a handler failed on (or that couldn't be decoded for it)
+type HandlerError struct {
+	EventID string
+	Type    string
+	Err     error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("handling %s event %s: %v", e.Type, e.EventID, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// handler is a registered callback on raw events
+type handler struct {
+	pattern string // Type glob (e.g. "plan.*")
+	fn      func(context.Context, events.Event) error
+}
+
+// Subscriber dispatches events from a subject to handlers registered by type
+// Handler failures are reported on Errors instead of stopping the subscription
+type Subscriber struct {
+	Transport transport.Transport
+	Subject   string
+	Errors    chan error // Handler errors; dropped when nobody reads and the buffer is full
+
+	mu       sync.RWMutex
+	handlers []handler
+}
+
+// NewSubscriber creates a subscriber for a subject on a NATS connection
+func NewSubscriber(nc *nats.Conn, subject string) *Subscriber {
+	return NewSubscriberWithTransport(transport.NATS{Conn: nc}, subject)
+}
+
+// NewSubscriberWithTransport creates a subscriber for a subject on any transport
+func NewSubscriberWithTransport(t transport.Transport, subject string) *Subscriber {
+	return &Subscriber{
+		Transport: t,
+		Subject:   subject,
+		Errors:    make(chan error, DefaultErrorBuffer),
+	}
+}
+
+// OnEvent registers a handler for events whose type matches a glob
+// Every matching handler runs, in registration order
+func (s *Subscriber) OnEvent(pattern string, fn func(context.Context, events.Event) error) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid type pattern %q: %w", pattern, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler{pattern: pattern, fn: fn})
+	return nil
+}
+
+// On registers a typed handler: the event's Data is decoded into a T
+// (through JSON, so struct tags apply, and validated if *T is a Validator)
+// before the handler runs
+// The full event is available from the context with EventFrom
+//
+//	client.On(sub, "plan.ready", func(ctx context.Context, plan PlanReady) error { ... })
+func On[T any](s *Subscriber, pattern string, fn func(context.Context, T) error) error {
+	return s.OnEvent(pattern, func(ctx context.Context, event events.Event) error {
+		var value T
+		if err := decodeData(event, &value); err != nil {
+			return err
+		}
+		return fn(ctx, value)
+	})
+}
+
+// Start subscribes and dispatches events until ctx is done
+// Messages that aren't events are reported on Errors
+func (s *Subscriber) Start(ctx context.Context) error {
+	sub, err := s.Transport.Subscribe(s.Subject, func(msg transport.Msg) {
+		if msg.Header.Get(events.ProbeHeader) != "" {
+			return
+		}
+		contentType := msg.Header.Get(events.ContentTypeHeader)
+		payloads := [][]byte{msg.Data}
+		if batch, ok := events.SplitBatch(msg.Data); ok && !events.IsProto(contentType) {
+			payloads = batch // Each event of an envelope, in order
+		}
+		for _, data := range payloads {
+			event, err := events.Decode(contentType, data)
+			if err != nil {
+				s.report(fmt.Errorf("%s: invalid event: %w", msg.Subject, err))
+				continue
+			}
+			s.Dispatch(ctx, *event)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		sub.Unsubscribe()
+	}()
+	return nil
+}
+
+// Dispatch runs every handler matching the event's type
+func (s *Subscriber) Dispatch(ctx context.Context, event events.Event) {
+	s.mu.RLock()
+	handlers := s.handlers
+	s.mu.RUnlock()
+
+	ctx = context.WithValue(ctx, eventKey{}, event)
+	for _, h := range handlers {
+		if ok, _ := path.Match(h.pattern, event.Type); !ok {
+			continue
+		}
+		if err := h.fn(ctx, event); err != nil {
+			s.report(&HandlerError{EventID: event.ID, Type: event.Type, Err: err})
+		}
+	}
+}
+
+// report sends an error without blocking dispatch
+func (s *Subscriber) report(err error) {
+	select {
+	case s.Errors <- err:
+	default:
+	}
+}
+
+// eventKey is the context key of the event being handled
+type eventKey struct{}
+
+// EventFrom returns the event a handler was called for
+func EventFrom(ctx context.Context) (events.Event, bool) {
+	event, ok := ctx.Value(eventKey{}).(events.Event)
+	return event, ok
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Bookmark marks an event the operator wants to return to
+// Enough of the event is kept to list the bookmark even when the event
+// itself is no longer in memory
+type Bookmark struct {
+	EventID   string    `json:"event_id"`
+	Pane      string    `json:"pane"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// BookmarksPath returns the bookmark file of a monitor instance
+// (~/.config/agneto/bookmarks-<instance>.json, next to the settings file)
+func BookmarksPath(instance string) string {
+	return filepath.Join(filepath.Dir(DefaultPath()), "bookmarks-"+instance+".json")
+}
+
+// LoadBookmarks reads bookmarks from path
+// A missing file is not an error and yields no bookmarks
+func LoadBookmarks(path string) ([]Bookmark, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []Bookmark
+	if err := json.Unmarshal(data, &bookmarks); err != nil {
+		return nil, fmt.Errorf("%s: invalid JSON: %w", path, err)
+	}
+	return bookmarks, nil
+}
+
+// SaveBookmarks writes bookmarks to path, creating parent directories as needed
+func SaveBookmarks(path string, bookmarks []Bookmark) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(bookmarks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
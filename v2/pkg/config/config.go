@@ -0,0 +1,181 @@
+// Package config loads and saves the TUI's persistent settings.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// Config holds operator settings that survive restarts
+// Every field is optional; the zero value means built-in defaults
+type Config struct {
+	Subject string `json:"subject,omitempty"` // Subject events are read from and responses published to (default test.events)
+	Server  string `json:"server,omitempty"`  // NATS server URL ($NATS_URL and --server override it)
+
+	MaxEvents   int `json:"max_events,omitempty"`    // Events kept per pane (--max-events overrides it; default 200)
+	KeepPerType int `json:"keep_per_type,omitempty"` // Last events of each type kept past the pane limit (--keep-per-type overrides it)
+
+	Routes []tui.Route       `json:"routes,omitempty"` // Type glob → pane routing rules
+	Panes  []tui.PaneOptions `json:"panes,omitempty"`  // Per-pane settings by name glob (e.g. ephemeral subtask panes)
+	Filter string            `json:"filter,omitempty"` // Initial event list filter
+	Mutes  []string          `json:"mutes,omitempty"`  // Event type globs hidden from the list
+	Theme  string            `json:"theme,omitempty"`  // Built-in theme name
+	Colors *tui.Theme        `json:"colors,omitempty"` // Colors overriding the theme's (ANSI 256 codes or hex; empty keeps the theme's)
+	Keys   map[string]string `json:"keys,omitempty"`   // Remapped keys by command name (e.g. "quit": "ctrl+q"; "" unbinds)
+	Redact []string          `json:"redact,omitempty"` // Data key globs masked on screen and in exports
+
+	Chips    []tui.ChipRule `json:"chips,omitempty"`     // Data keys shown as chips under rows, per type glob
+	MaxChips int            `json:"max_chips,omitempty"` // Chips per row (default 3)
+
+	Renderers []tui.RendererRule `json:"renderers,omitempty"` // Built-in payload renderers by type glob (e.g. progress bars)
+
+	Watches []tui.Watch `json:"watches,omitempty"` // Expressions over event data pinned in the watches panel
+
+	Quota *monitor.Quota `json:"quota,omitempty"` // Per-producer rate limits protecting the list from floods
+
+	Strict   bool   `json:"strict,omitempty"`    // Reject events with unknown or missing fields (see events.DecodeStrict)
+	EditMode string `json:"edit_mode,omitempty"` // Input editing style: "emacs" (default) or "vim"
+	Notify   string `json:"notify,omitempty"`    // Ways to notify of decisions, e.g. "bell,desktop" (--notify overrides it)
+
+	Locale    string `json:"locale,omitempty"`     // UI language, e.g. "de" ($AGNETO_LOCALE overrides it; default from LANG)
+	LocaleDir string `json:"locale_dir,omitempty"` // Directory of <locale>.json message catalogs (default ~/.config/agneto/locales)
+
+	// Keys that publish a predefined event (key, label and the complete event, as in actions)
+	QuickPublish []events.Action `json:"quick_publish,omitempty"`
+
+	// Event type documentation shown with ? (type glob, description, expected response, docs link)
+	Types []tui.TypeInfo `json:"types,omitempty"`
+}
+
+// DefaultPath returns the default config location (~/.config/agneto/config.toml)
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "agneto.toml"
+	}
+	return filepath.Join(dir, "agneto", "config.toml")
+}
+
+// isJSON reports whether path is read and written as JSON, the format
+// settings files had before TOML
+func isJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// legacyPath returns the JSON settings file a TOML one replaced
+func legacyPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+}
+
+// Load reads the config file at path: TOML, with the keys of the json tags
+// above, or JSON for a path ending in .json
+// A missing file is not an error and yields an empty config, unless the
+// JSON file it replaced is still there: it's not read, and not silently
+// ignored either
+// AIDEV-NOTE: TOML goes through a generic map and JSON on its way to and from
+// Config, so the json tags (and the JSON methods of the tui types) are the
+// one definition of every key
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if legacy := legacyPath(path); !isJSON(path) && fileExists(legacy) {
+			return nil, fmt.Errorf("%s is missing but %s exists: settings are TOML now, convert it (or pass --config %s to keep using it)", path, legacy, legacy)
+		}
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if isJSON(path) {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("%s: invalid JSON: %w", path, err)
+		}
+		return &cfg, nil
+	}
+
+	var values map[string]interface{}
+	if err := toml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%s: invalid TOML: %w", path, err)
+	}
+	converted, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := json.Unmarshal(converted, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Save writes the config to path, as TOML unless it ends in .json, creating
+// parent directories as needed
+func (c *Config) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if isJSON(path) {
+		return os.WriteFile(path, append(data, '\n'), 0o644)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var values map[string]interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	encoder := toml.NewEncoder(&buf)
+	encoder.Indent = ""
+	if err := encoder.Encode(tomlValue(values)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// tomlValue converts a value decoded from JSON for the TOML encoder: numbers
+// become integers where they are whole, and nulls, which TOML can't
+// express, are left out (they are what omitted keys decode to anyway)
+func tomlValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, value := range v {
+			if value == nil {
+				delete(v, key)
+			} else {
+				v[key] = tomlValue(value)
+			}
+		}
+	case []interface{}:
+		kept := v[:0]
+		for _, value := range v {
+			if value != nil {
+				kept = append(kept, tomlValue(value))
+			}
+		}
+		return kept
+	}
+	return v
+}
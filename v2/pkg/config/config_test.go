@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+func TestLoadTOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	src := `
+subject = "agents.events"
+max_events = 500
+theme = "light"
+
+[keys]
+quit = "ctrl+q"
+help = ""
+
+[colors]
+border = "#5f87af"
+
+[[routes]]
+type = "review.*"
+pane = "reviews"
+
+[[panes]]
+pane = "subtask-*"
+ephemeral = true
+idle_minutes = 5
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Config{
+		Subject:   "agents.events",
+		MaxEvents: 500,
+		Theme:     "light",
+		Keys:      map[string]string{"quit": "ctrl+q", "help": ""},
+		Colors:    &tui.Theme{Border: "#5f87af"},
+		Routes:    []tui.Route{{Type: "review.*", Pane: "reviews"}},
+		Panes:     []tui.PaneOptions{{Pane: "subtask-*", Ephemeral: true, IdleMinutes: 5}},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("loaded %+v, want %+v", cfg, want)
+	}
+
+	if err := os.WriteFile(path, []byte("max_events = \"many\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "max_events") {
+		t.Fatalf("wrongly typed value: error %v, want one naming the key", err)
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	cfg := &Config{
+		Subject:      "agents.events",
+		MaxEvents:    500,
+		Keys:         map[string]string{"quit": "ctrl+q"},
+		Routes:       []tui.Route{{Type: "review.*", Pane: "reviews"}, {Type: "build.*", Pane: "builds"}},
+		Quota:        &monitor.Quota{Rate: 20, Sources: map[string]int{"noisy": 5}},
+		Chips:        []tui.ChipRule{{Type: "*", Keys: []string{"exit_code"}}},
+		QuickPublish: []events.Action{{ID: "pause", Label: "Pause", Event: events.Event{Type: "ops.pause", Data: map[string]interface{}{"reason": "ops", "minutes": float64(10), "ratio": 0.5}}}},
+	}
+	for _, name := range []string{"config.toml", "config.json"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := cfg.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(loaded, cfg) {
+			t.Fatalf("%s: loaded %+v, want %+v", name, loaded, cfg)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"max_events = 500\n", "[[routes]]\n", "minutes = 10\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("saved TOML lacks %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestLoadRefusesUnconvertedJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if cfg, err := Load(path); err != nil || !reflect.DeepEqual(cfg, &Config{}) {
+		t.Fatalf("missing settings: %+v, %v, want an empty config", cfg, err)
+	}
+
+	legacy := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(legacy, []byte(`{"subject": "agents.events"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !Exists(path) {
+		t.Fatal("settings with only the JSON file reported missing")
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), legacy) {
+		t.Fatalf("error %v, want one naming %s", err, legacy)
+	}
+	cfg, err := Load(legacy)
+	if err != nil || cfg.Subject != "agents.events" {
+		t.Fatalf("JSON settings passed explicitly: %+v, %v", cfg, err)
+	}
+}
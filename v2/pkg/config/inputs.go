@@ -0,0 +1,55 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// KeptInput is text typed into an input that was cancelled or cut off before
+// it was submitted, restored when the same action asks for input again
+type KeptInput struct {
+	Text    string    `json:"text"`
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// KeptInputsPath returns the kept input file of a monitor instance
+// (~/.config/agneto/inputs-<instance>.json, next to the settings file)
+func KeptInputsPath(instance string) string {
+	return filepath.Join(filepath.Dir(DefaultPath()), "inputs-"+instance+".json")
+}
+
+// LoadKeptInputs reads kept inputs from path, keyed by request and action
+// A missing file is not an error and yields no inputs
+func LoadKeptInputs(path string) (map[string]KeptInput, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs map[string]KeptInput
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return nil, fmt.Errorf("%s: invalid JSON: %w", path, err)
+	}
+	return inputs, nil
+}
+
+// SaveKeptInputs writes kept inputs to path, creating parent directories as needed
+// The file holds what operators typed, so only they can read it
+func SaveKeptInputs(path string, inputs map[string]KeptInput) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(inputs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Snapshot is the monitor's decision context, saved periodically so a crash
+// doesn't lose it: the file is removed on a clean exit, so one found at
+// startup means the previous run didn't get there
+// Bookmarks aren't included; they are saved to their own file on every change
+type Snapshot struct {
+	SavedAt  time.Time         `json:"saved_at"`
+	Pending  []PendingSnapshot `json:"pending,omitempty"`  // Decisions waiting for an answer
+	Consumed []string          `json:"consumed,omitempty"` // IDs of events already answered
+}
+
+// PendingSnapshot is a decision waiting for an answer
+// The whole event is kept, since it may not come back from history
+type PendingSnapshot struct {
+	Event  events.Event   `json:"event"`
+	Since  time.Time      `json:"since"`            // When the decision started waiting
+	Active bool           `json:"active,omitempty"` // Its buttons were the ones shown
+	Input  *events.Action `json:"input,omitempty"`  // Text input being answered (open or set aside as a draft)
+	Text   string         `json:"text,omitempty"`   // What had been typed into it
+}
+
+// SnapshotPath returns the snapshot file of a monitor instance
+// (~/.config/agneto/snapshot-<instance>.json, next to the settings file)
+func SnapshotPath(instance string) string {
+	return filepath.Join(filepath.Dir(DefaultPath()), "snapshot-"+instance+".json")
+}
+
+// LoadSnapshot reads a snapshot from path
+// A missing file is not an error and yields nil
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("%s: invalid JSON: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// SaveSnapshot writes a snapshot to path, creating parent directories as needed
+// The file is replaced atomically, so a crash mid-write keeps the previous one
+func SaveSnapshot(path string, snapshot Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RemoveSnapshot deletes the snapshot at path, if any
+func RemoveSnapshot(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WorkspaceDir returns the directory of named workspaces (~/.config/agneto/workspaces)
+// A workspace is a settings file saved under a name, so different projects
+// can keep very different subjects, routing, filters and themes
+func WorkspaceDir() string {
+	return filepath.Join(filepath.Dir(DefaultPath()), "workspaces")
+}
+
+// WorkspacePath returns the settings file of a named workspace
+func WorkspacePath(name string) (string, error) {
+	if err := ValidWorkspaceName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(WorkspaceDir(), name+".toml"), nil
+}
+
+// ValidWorkspaceName rejects names that aren't usable as a file name
+func ValidWorkspaceName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid workspace name %q", name)
+	}
+	return nil
+}
+
+// Workspaces returns the names of the saved workspaces in sorted order
+func Workspaces() ([]string, error) {
+	names, err := filepath.Glob(filepath.Join(WorkspaceDir(), "*.toml"))
+	if err != nil {
+		return nil, err
+	}
+	workspaces := make([]string, 0, len(names))
+	for _, name := range names {
+		workspaces = append(workspaces, strings.TrimSuffix(filepath.Base(name), ".toml"))
+	}
+	sort.Strings(workspaces)
+	return workspaces, nil
+}
+
+// Exists reports whether a settings file exists at path, or the JSON file
+// it replaced (which Load asks to convert)
+func Exists(path string) bool {
+	return fileExists(path) || (!isJSON(path) && fileExists(legacyPath(path)))
+}
+
+// fileExists reports whether anything exists at path
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
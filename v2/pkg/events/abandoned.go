@@ -0,0 +1,32 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TypeAbandoned is published for a decision the operator left unanswered on quit
+const TypeAbandoned = "abandoned"
+
+// AbandonedEventIDKey holds the ID of the abandoned event
+// It differs from AnswersEventIDKey on purpose: the decision is still open,
+// so other approvers (e.g. Slack) keep their buttons
+const AbandonedEventIDKey = "abandoned_event_id"
+
+// NewAbandoned creates the response telling the producer nobody will answer original here
+func NewAbandoned(original Event, instance string) Event {
+	return Event{
+		ID:        uuid.New().String(),
+		Type:      TypeAbandoned,
+		Timestamp: time.Now(),
+		Message:   "Abandoned without an answer: " + original.Message,
+		Pane:      original.Pane,
+		SessionID: original.SessionID,
+		Data: map[string]interface{}{
+			AbandonedEventIDKey: original.ID,
+			"abandoned_type":    original.Type,
+			"instance":          instance,
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Four-eyes approval: an action with Approvals set to 2 (or more) answers its
+// event only once that many distinct operators have triggered it. Each
+// approval short of the count is announced on ApprovalsSubject, so every
+// monitor shows the event as half-approved; the monitor whose operator
+// completes the count publishes the response, naming the approvers under
+// ApprovedByKey, and announces a final approval so the others settle it
+
+// ApprovalsSubject is where monitors announce approvals of four-eyes actions
+const ApprovalsSubject = "agneto.approvals"
+
+// ApprovalSecretEnv names the environment variable holding the secret
+// approvals are signed with, shared by every monitor (see Approval.Sign)
+const ApprovalSecretEnv = "AGNETO_APPROVAL_SECRET"
+
+// ApprovedByKey is the response Data key listing the operators who approved it
+const ApprovedByKey = "approved_by"
+
+// MaxApprovals bounds the approvals an action can require
+const MaxApprovals = 5
+
+// Approval is an operator's trigger of a four-eyes action, published by their monitor
+type Approval struct {
+	EventID  string    `json:"event_id"`
+	ActionID string    `json:"action_id"`       // The action's ID, else its key (see Action.ApprovalID)
+	Operator string    `json:"operator"`        // Who approved
+	Needed   int       `json:"needed"`          // Distinct operators the action requires
+	Final    bool      `json:"final,omitempty"` // The event was answered; its approvals are void
+	At       time.Time `json:"at"`
+	Sig      string    `json:"sig,omitempty"` // HMAC-SHA256 of the fields above, hex (see Sign)
+}
+
+// AIDEV-NOTE: Signing is not a security control. It keeps out publishers on
+// ApprovalsSubject that don't hold the shared secret (a stray script, a
+// misconfigured agent), but every monitor holds it and can approve under any
+// operator name. NATS tells subscribers nothing about who published, so
+// approvals can't be bound to a connection's user; restricting who may
+// publish on ApprovalsSubject with NATS permissions is what actually limits
+// who approves
+
+// ErrUnsigned is returned by Verify for an approval without a valid signature
+var ErrUnsigned = errors.New("approval isn't signed with the approval secret")
+
+// ApprovalSecret returns the secret from ApprovalSecretEnv (nil: approvals are unsigned)
+func ApprovalSecret() []byte {
+	if secret := os.Getenv(ApprovalSecretEnv); secret != "" {
+		return []byte(secret)
+	}
+	return nil
+}
+
+// Sign returns the approval signed with secret; without a secret it is returned as is
+func (a Approval) Sign(secret []byte) Approval {
+	if len(secret) > 0 {
+		a.Sig = a.signature(secret)
+	}
+	return a
+}
+
+// Verify checks the approval was signed with secret; without a secret every approval passes
+func (a Approval) Verify(secret []byte) error {
+	if len(secret) == 0 {
+		return nil
+	}
+	if !hmac.Equal([]byte(a.Sig), []byte(a.signature(secret))) {
+		return ErrUnsigned
+	}
+	return nil
+}
+
+// signature computes the HMAC of the approval's fields, one per line
+func (a Approval) signature(secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strings.Join([]string{
+		a.EventID, a.ActionID, a.Operator, strconv.Itoa(a.Needed),
+		strconv.FormatBool(a.Final), a.At.UTC().Format(time.RFC3339Nano),
+	}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RequiredApprovals returns how many distinct operators must trigger the action (at least 1)
+func (a Action) RequiredApprovals() int {
+	return max(a.Approvals, 1)
+}
+
+// ApprovalID identifies the action among its event's for approvals: its ID, else its key
+func (a Action) ApprovalID() string {
+	if a.ID != "" {
+		return a.ID
+	}
+	return a.Key
+}
+
+// ValidateApprovals checks the required approvals are in range and only
+// asked of buttons (every approver would have to type an input alike)
+func (a Action) ValidateApprovals() error {
+	switch {
+	case a.Approvals < 0 || a.Approvals > MaxApprovals:
+		return fmt.Errorf("'approvals' must be between 0 and %d", MaxApprovals)
+	case a.Approvals > 1 && a.IsInput():
+		return fmt.Errorf("'approvals' only applies to buttons, not inputs")
+	}
+	return nil
+}
+
+// WithApprovers returns a copy of the action whose response names the operators who approved it
+// The action's Data map is shared with the event it came from, so it is copied
+func (a Action) WithApprovers(operators []string) Action {
+	a.Event.Data = withKey(a.Event.Data, ApprovedByKey, append([]string{}, operators...))
+	return a
+}
+
+// ApprovalFromJSON deserializes and validates an approval
+func ApprovalFromJSON(data []byte) (*Approval, error) {
+	var a Approval
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+	if a.EventID == "" || a.Operator == "" && !a.Final {
+		return nil, fmt.Errorf("approval needs event_id and operator")
+	}
+	return &a, nil
+}
+
+// ToJSON serializes the approval
+func (a Approval) ToJSON() ([]byte, error) {
+	return json.Marshal(a)
+}
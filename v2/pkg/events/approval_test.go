@@ -0,0 +1,40 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApprovalSignature(t *testing.T) {
+	secret := []byte("shared")
+	approval := Approval{EventID: "e1", ActionID: "deploy", Operator: "alice", Needed: 2, At: time.Now()}
+
+	signed := approval.Sign(secret)
+	data, err := signed.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	received, err := ApprovalFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := received.Verify(secret); err != nil {
+		t.Fatalf("signed approval rejected after a JSON round trip: %v", err)
+	}
+
+	tests := map[string]Approval{
+		"unsigned":        approval,
+		"other secret":    approval.Sign([]byte("other")),
+		"operator forged": func() Approval { a := signed; a.Operator = "mallory"; return a }(),
+		"count forged":    func() Approval { a := signed; a.Needed = 1; return a }(),
+	}
+	for name, a := range tests {
+		if err := a.Verify(secret); err == nil {
+			t.Errorf("%s approval accepted", name)
+		}
+	}
+
+	if err := approval.Verify(nil); err != nil {
+		t.Fatalf("without a secret, unsigned approvals must pass: %v", err)
+	}
+}
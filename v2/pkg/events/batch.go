@@ -0,0 +1,113 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Batches: producers emitting bursts can publish several events in one
+// message, an envelope with an ID of its own. Monitors unpack it as it
+// arrives and handle each event in order as if it had been published on its
+// own: events keep their IDs, idempotency keys and panes. Batches are JSON
+// only.
+
+// MaxBatch is how many events the publisher puts in one envelope by default,
+// which keeps envelopes of typical events well below NATS' 1MB payload limit
+const MaxBatch = 100
+
+// Batch is an envelope of events published in one message
+type Batch struct {
+	ID        string    `json:"batch_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Events    []Event   `json:"events"`
+}
+
+// NewBatch creates an envelope for events
+func NewBatch(evts []Event) Batch {
+	return Batch{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Events:    evts,
+	}
+}
+
+// Validate checks the envelope has an ID and events that each have an ID and a type
+func (b Batch) Validate() error {
+	if b.ID == "" {
+		return fmt.Errorf("batch: missing batch_id")
+	}
+	if len(b.Events) == 0 {
+		return fmt.Errorf("batch %s: no events", b.ID)
+	}
+	for i, event := range b.Events {
+		if event.ID == "" || event.Type == "" {
+			return fmt.Errorf("batch %s: event %d needs an id and a type", b.ID, i)
+		}
+	}
+	return nil
+}
+
+// ToJSON serializes the envelope to JSON
+func (b Batch) ToJSON() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+// BatchFromJSON deserializes and validates an envelope
+func BatchFromJSON(data []byte) (*Batch, error) {
+	var batch Batch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, err
+	}
+	if err := batch.Validate(); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// SplitBatch returns the events of an envelope as payloads of their own, in
+// order, or false when data isn't an envelope
+// The events are passed on as written, so strict schema checks still see
+// what the producer sent
+func SplitBatch(data []byte) ([][]byte, bool) {
+	if !bytes.Contains(data, []byte(`"batch_id"`)) {
+		return nil, false // Plain events, without decoding them twice
+	}
+	var envelope struct {
+		ID     string            `json:"batch_id"`
+		Events []json.RawMessage `json:"events"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.ID == "" {
+		return nil, false
+	}
+	payloads := make([][]byte, len(envelope.Events))
+	for i, event := range envelope.Events {
+		payloads[i] = event
+	}
+	return payloads, true
+}
+
+// DecodeAll decodes the events a message carries: its event, or each event
+// of a batch envelope (see Decode for contentType)
+func DecodeAll(contentType string, data []byte) ([]Event, error) {
+	payloads, ok := SplitBatch(data)
+	if !ok || IsProto(contentType) {
+		event, err := Decode(contentType, data)
+		if err != nil {
+			return nil, err
+		}
+		return []Event{*event}, nil
+	}
+	evts := make([]Event, 0, len(payloads))
+	for i, payload := range payloads {
+		event, err := FromJSON(payload)
+		if err != nil {
+			return nil, fmt.Errorf("batch event %d: %w", i, err)
+		}
+		evts = append(evts, *event)
+	}
+	return evts, nil
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Emergency stop events, published by operators (or orchestrators) to stop a task
+const (
+	TypeTaskCancel = "task.cancel" // Stop after cleaning up
+	TypeTaskAbort  = "task.abort"  // Stop immediately
+)
+
+// Data keys identifying the task a stop event targets
+// Producers put them on their events; a cancel copies them from the selected event
+const (
+	TaskIDKey    = "task_id"
+	SessionIDKey = "session_id"
+)
+
+// IsTaskStop reports whether the event is a task.cancel or task.abort
+func (e Event) IsTaskStop() bool {
+	return e.Type == TypeTaskCancel || e.Type == TypeTaskAbort
+}
+
+// Task returns a label for the task the event belongs to: its task ID,
+// else its session ID, else "" when the producer set neither
+func (e Event) Task() string {
+	for _, key := range []string{TaskIDKey, SessionIDKey} {
+		if v, ok := e.Data[key]; ok && v != nil && fmt.Sprint(v) != "" {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// NewTaskCancel creates the cancel request for the task the target event belongs to
+// The request is caused by the target (see CausedBy)
+func NewTaskCancel(target Event, operator, instance string) Event {
+	data := map[string]interface{}{
+		"cancel_event_id": target.ID,
+		"operator":        operator,
+		"instance":        instance,
+	}
+	for _, key := range []string{TaskIDKey, SessionIDKey} {
+		if v, ok := target.Data[key]; ok {
+			data[key] = v
+		}
+	}
+	cancel := Event{
+		ID:        uuid.New().String(),
+		Type:      TypeTaskCancel,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("Cancel requested by %s for task %s", operator, target.Task()),
+		Pane:      target.Pane,
+		Data:      data,
+	}
+	cancel.CausedBy(target)
+	return cancel
+}
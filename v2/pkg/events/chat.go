@@ -0,0 +1,55 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatSubject is the sidecar subject operators chat on, apart from the event stream
+const ChatSubject = "agneto.chat"
+
+// TypeChatMessage is the type of operator chat events
+const TypeChatMessage = "chat.message"
+
+// ChatPane is the pane chat messages are listed in
+const ChatPane = "chat"
+
+// NewChatMessage creates a chat line from an operator
+func NewChatMessage(operator, instance, text string) Event {
+	return Event{
+		ID:        uuid.New().String(),
+		Type:      TypeChatMessage,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("%s: %s", operator, text),
+		Pane:      ChatPane,
+		Data: map[string]interface{}{
+			"operator": operator,
+			"instance": instance,
+			"text":     text,
+		},
+	}
+}
+
+// ChatFromJSON deserializes and validates a chat message
+func ChatFromJSON(data []byte) (*Event, error) {
+	event, err := FromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if event.Type != TypeChatMessage {
+		return nil, fmt.Errorf("chat: unexpected event type %q", event.Type)
+	}
+	if event.ChatOperator() == "" {
+		return nil, fmt.Errorf("chat: requires data.operator")
+	}
+	return event, nil
+}
+
+// ChatOperator returns who wrote a chat message ("" if unset)
+func (e Event) ChatOperator() string {
+	operator, _ := e.Data["operator"].(string)
+	return strings.TrimSpace(operator)
+}
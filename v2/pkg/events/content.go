@@ -0,0 +1,24 @@
+package events
+
+import "fmt"
+
+// Content type hints: how monitors render an event's Content
+// These name the Content's format, not the payload encoding (see ContentTypeHeader)
+const (
+	ContentMarkdown = "markdown" // Default: rendered as markdown
+	ContentDiff     = "diff"     // A unified diff (e.g. a patch up for review), colorized
+)
+
+// ValidateContentType checks the content type hint is empty or known
+func (e Event) ValidateContentType() error {
+	switch e.ContentType {
+	case "", ContentMarkdown, ContentDiff:
+		return nil
+	}
+	return fmt.Errorf("unknown content type %q (want %s or %s)", e.ContentType, ContentMarkdown, ContentDiff)
+}
+
+// IsDiff reports whether the event's Content is a unified diff
+func (e Event) IsDiff() bool {
+	return e.ContentType == ContentDiff
+}
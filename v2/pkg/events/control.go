@@ -0,0 +1,84 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ControlSubjectPrefix is the subject prefix for remote control commands
+// A monitor instance listens on ControlSubjectPrefix + "." + instance
+const ControlSubjectPrefix = "agneto.control"
+
+// Control command names understood by the TUI
+const (
+	ControlSelectEvent = "select-event" // Select an event by Index or EventID
+	ControlSwitchTab   = "switch-tab"   // Show the pane named in Pane in the event list
+	ControlSetFilter   = "set-filter"   // Narrow the event list to Filter (empty clears)
+	ControlExport      = "export"       // Write the active pane's events to Path as JSON Lines
+)
+
+// ControlCommand is a command published to a monitor's control subject,
+// letting scripts and external tools (e.g. a Stream Deck) drive the TUI
+type ControlCommand struct {
+	Command string `json:"command"`            // One of the Control* command names
+	Index   *int   `json:"index,omitempty"`    // select-event: event index in the active pane
+	EventID string `json:"event_id,omitempty"` // select-event: event ID (alternative to Index)
+	Pane    string `json:"pane,omitempty"`     // switch-tab: target pane name
+	Filter  string `json:"filter,omitempty"`   // set-filter: filter text
+	Path    string `json:"path,omitempty"`     // export: output file, relative to the monitor's --export-dir
+}
+
+// ControlSubject returns the control subject for a monitor instance
+func ControlSubject(instance string) string {
+	return ControlSubjectPrefix + "." + instance
+}
+
+// ControlCommandFromJSON deserializes and validates a control command
+func ControlCommandFromJSON(data []byte) (*ControlCommand, error) {
+	var cmd ControlCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	switch cmd.Command {
+	case ControlSelectEvent:
+		if cmd.Index == nil && cmd.EventID == "" {
+			return nil, fmt.Errorf("%s: requires 'index' or 'event_id'", cmd.Command)
+		}
+	case ControlSwitchTab:
+		if cmd.Pane == "" {
+			return nil, fmt.Errorf("%s: requires 'pane'", cmd.Command)
+		}
+	case ControlSetFilter:
+		// Empty filter clears it
+	case ControlExport:
+		if cmd.Path == "" {
+			return nil, fmt.Errorf("%s: requires 'path'", cmd.Command)
+		}
+	default:
+		return nil, fmt.Errorf("unknown control command %q", cmd.Command)
+	}
+
+	return &cmd, nil
+}
+
+// TypePaneTitle renames a pane at runtime
+// Published on the event stream by orchestrators, e.g. {"type": "pane.title",
+// "pane": "left", "message": "Planner — task #42"}; data.title and data.pane
+// override message and pane
+const TypePaneTitle = "pane.title"
+
+// PaneTitle returns the pane and new title of a pane.title event
+func (e Event) PaneTitle() (pane, title string, ok bool) {
+	if e.Type != TypePaneTitle {
+		return "", "", false
+	}
+	pane, title = e.Pane, e.Message
+	if p, isString := e.Data["pane"].(string); isString && p != "" {
+		pane = p
+	}
+	if t, isString := e.Data["title"].(string); isString && t != "" {
+		title = t
+	}
+	return pane, title, pane != "" && title != ""
+}
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Data keys linking escalated copies and their responses to the original event
+const (
+	EscalatedFromKey  = "escalated_from"   // On the escalated copy: original event ID
+	AnswersEventIDKey = "answers_event_id" // On responses given outside this monitor (escalations, Slack): original event ID
+)
+
+// Escalation hands an unanswered actionable event to alternate approvers
+// After AfterSeconds without a response, the TUI publishes a copy of the
+// event to Target - a NATS subject watched by another approver group, or an
+// http(s) URL that receives the copy as a JSON POST (webhook)
+type Escalation struct {
+	AfterSeconds int    `json:"after_seconds"`
+	Target       string `json:"target"`
+}
+
+// Validate checks the escalation is well-formed
+func (e *Escalation) Validate() error {
+	if e.AfterSeconds <= 0 {
+		return fmt.Errorf("escalation: 'after_seconds' must be positive")
+	}
+	if e.Target == "" {
+		return fmt.Errorf("escalation: missing 'target'")
+	}
+	return nil
+}
+
+// After returns the escalation delay
+func (e *Escalation) After() time.Duration {
+	return time.Duration(e.AfterSeconds) * time.Second
+}
+
+// IsWebhook reports whether the target is an HTTP endpoint rather than a subject
+func (e *Escalation) IsWebhook() bool {
+	return strings.HasPrefix(e.Target, "http://") || strings.HasPrefix(e.Target, "https://")
+}
+
+// EscalatedCopy returns the copy of the event sent to alternate approvers
+// The copy gets a new ID, references the original in Data, and every action's
+// response event carries AnswersEventIDKey so the original monitor can tell
+// when someone else answered. The copy itself never escalates again.
+func (e Event) EscalatedCopy() Event {
+	escalated := e
+	escalated.ID = uuid.New().String()
+	escalated.CausedBy(e) // Answers pair with the original request
+	escalated.Timestamp = time.Now()
+	escalated.Escalation = nil
+	escalated.Data = withKey(e.Data, EscalatedFromKey, e.ID)
+
+	escalated.Actions = make([]Action, len(e.Actions))
+	for i, action := range e.Actions {
+		action.Event.Data = withKey(action.Event.Data, AnswersEventIDKey, e.ID)
+		escalated.Actions[i] = action
+	}
+	return escalated
+}
+
+// AnsweredEventID returns the original event ID an escalated response answers, if any
+func (e Event) AnsweredEventID() (string, bool) {
+	id, ok := e.Data[AnswersEventIDKey].(string)
+	return id, ok && id != ""
+}
+
+// withKey returns a shallow copy of data with key set
+func withKey(data map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// appendProto appends the escalation's protobuf encoding to b
+func (e Escalation) appendProto(b []byte) []byte {
+	b = appendInt(b, 1, int64(e.AfterSeconds))
+	return appendString(b, 2, e.Target)
+}
+
+// readProto decodes a protobuf escalation into e
+func (e *Escalation) readProto(data []byte) error {
+	r := protoReader{b: data}
+	return r.fields(func(field int, v protoField) error {
+		var err error
+		switch field {
+		case 1:
+			var n int64
+			n, err = v.int()
+			e.AfterSeconds = int(n)
+		case 2:
+			e.Target, err = v.str()
+		}
+		return err
+	})
+}
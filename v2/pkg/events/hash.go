@@ -0,0 +1,41 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ShortHashLen is how many hex digits an event's short hash has
+const ShortHashLen = 7
+
+// MinRefLen is the shortest ID or hash prefix accepted as a reference, so
+// a stray digit doesn't match half the list
+const MinRefLen = 4
+
+// ShortHash returns a short hash of an event ID, the same on every monitor,
+// for people to reference events by whatever the IDs look like
+func ShortHash(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])[:ShortHashLen]
+}
+
+// ShortHash returns the event's short hash
+func (e Event) ShortHash() string {
+	return ShortHash(e.ID)
+}
+
+// MatchesRef reports whether ref names the event: its ID, or a prefix of
+// its ID or short hash at least MinRefLen long (a leading # is ignored)
+func (e Event) MatchesRef(ref string) bool {
+	ref = strings.TrimPrefix(strings.TrimSpace(ref), "#")
+	switch {
+	case ref == "":
+		return false
+	case ref == e.ID:
+		return true
+	case len(ref) < MinRefLen:
+		return false
+	}
+	return strings.HasPrefix(e.ID, ref) || strings.HasPrefix(e.ShortHash(), strings.ToLower(ref))
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Input types (Action.InputType); an action with one opens an input instead of
+// responding on a key press, and its response carries the value in Data[InputKey]
+const (
+	InputMultiline = "multiline" // Free text in a textarea (string)
+	InputLine      = "line"      // A single line of text (string)
+	InputSelect    = "select"    // One of Action.Options (string)
+	InputConfirm   = "confirm"   // Yes or no (bool)
+)
+
+// InputKey is the response Data key holding an input action's value
+const InputKey = "input"
+
+// IsInput reports whether the action opens an input rather than responding directly
+func (a Action) IsInput() bool {
+	return a.InputType != ""
+}
+
+// InputAction returns the event's first action that opens an input
+func (e Event) InputAction() (Action, bool) {
+	for _, action := range e.Actions {
+		if action.IsInput() {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// IsChoiceInput reports whether the action's input is picked rather than typed (select or confirm)
+func (a Action) IsChoiceInput() bool {
+	return a.InputType == InputSelect || a.InputType == InputConfirm
+}
+
+// ValidateInput checks the action's input type and options are usable
+func (a Action) ValidateInput() error {
+	switch a.InputType {
+	case "", InputMultiline, InputLine, InputConfirm:
+		if len(a.Options) > 0 {
+			return fmt.Errorf("options are only used by input_type %q", InputSelect)
+		}
+	case InputSelect:
+		if len(a.Options) == 0 {
+			return fmt.Errorf("input_type %q requires options", InputSelect)
+		}
+		seen := make(map[string]bool, len(a.Options))
+		for _, option := range a.Options {
+			if strings.TrimSpace(option) == "" {
+				return fmt.Errorf("options can't be empty")
+			}
+			if seen[option] {
+				return fmt.Errorf("option %q is listed twice", option)
+			}
+			seen[option] = true
+		}
+	default:
+		return fmt.Errorf("unknown input_type %q (want %s, %s, %s or %s)",
+			a.InputType, InputMultiline, InputLine, InputSelect, InputConfirm)
+	}
+	return nil
+}
+
+// OptionIndex returns the position of an option of a select input, or -1
+func (a Action) OptionIndex(option string) int {
+	for i, o := range a.Options {
+		if o == option {
+			return i
+		}
+	}
+	return -1
+}
+
+// InputHint describes the answer an input action expects, for prompts and listings
+func (a Action) InputHint() string {
+	switch a.InputType {
+	case InputLine:
+		return "one line"
+	case InputSelect:
+		return "one of " + strings.Join(a.Options, ", ")
+	case InputConfirm:
+		return "yes/no"
+	default:
+		return "free text"
+	}
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxJSONLLine bounds a single event line (large Content is common)
+const maxJSONLLine = 16 * 1024 * 1024
+
+// ReadJSONL reads events stored one JSON object per line (blank lines are skipped)
+// This is the format written by the TUI's export command and visual yank
+func ReadJSONL(r io.Reader) ([]Event, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxJSONLLine)
+
+	var evts []Event
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		event, err := FromJSON([]byte(text))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		evts = append(evts, *event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return evts, nil
+}
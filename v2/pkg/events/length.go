@@ -0,0 +1,37 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// InputLength counts an input answer the way length bounds do: characters
+// (not bytes), ignoring surrounding whitespace, plus words for display
+func InputLength(text string) (chars, words int) {
+	text = strings.TrimSpace(text)
+	return utf8.RuneCountInString(text), len(strings.Fields(text))
+}
+
+// CheckLength reports whether an input answer fits the action's MinLength/MaxLength
+func (a Action) CheckLength(text string) error {
+	chars, _ := InputLength(text)
+	switch {
+	case a.MinLength > 0 && chars < a.MinLength:
+		return fmt.Errorf("answer is %d characters, at least %d required", chars, a.MinLength)
+	case a.MaxLength > 0 && chars > a.MaxLength:
+		return fmt.Errorf("answer is %d characters, at most %d allowed", chars, a.MaxLength)
+	}
+	return nil
+}
+
+// ValidateLength checks the action's length bounds are usable
+func (a Action) ValidateLength() error {
+	switch {
+	case a.MinLength < 0 || a.MaxLength < 0:
+		return fmt.Errorf("min_length and max_length can't be negative")
+	case a.MaxLength > 0 && a.MinLength > a.MaxLength:
+		return fmt.Errorf("min_length %d is above max_length %d", a.MinLength, a.MaxLength)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Standard pane names every monitor has
+// Monitors may add more: ErrorsPane in strict mode, ChatPane with chat
+const (
+	PaneLeft  = "left"  // Default pane
+	PaneRight = "right" // Second pane
+)
+
+// StandardPanes lists the standard pane names
+var StandardPanes = []string{PaneLeft, PaneRight}
+
+// PanesSubject is where monitors answer discovery requests with their pane names (a JSON array)
+const PanesSubject = "agneto.panes"
+
+// AnyPane in a discovery answer means the monitor creates a pane for any valid
+// name it doesn't have yet (see ValidPaneName)
+const AnyPane = "*"
+
+// MaxPaneNameLen bounds the names panes can be created with on demand
+const MaxPaneNameLen = 32
+
+// ValidPaneName checks a name a pane can be created with on demand: up to
+// MaxPaneNameLen letters, digits, '-', '_' and '.'
+func ValidPaneName(name string) error {
+	if name == "" || len(name) > MaxPaneNameLen {
+		return fmt.Errorf("pane name %q must be 1 to %d characters", name, MaxPaneNameLen)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return fmt.Errorf("pane name %q may only use letters, digits, '-', '_' and '.'", name)
+		}
+	}
+	return nil
+}
+
+// ValidatePane checks a pane name against the known ones
+// An empty name (the monitor's default pane) is always valid, and so is any
+// valid name when known holds AnyPane; for an unknown one the error suggests
+// the closest known name
+func ValidatePane(pane string, known []string) error {
+	if pane == "" {
+		return nil
+	}
+	if slices.Contains(known, AnyPane) && ValidPaneName(pane) == nil {
+		return nil
+	}
+	known = slices.DeleteFunc(slices.Clone(known), func(name string) bool { return name == AnyPane })
+	best, bestDistance := "", -1
+	for _, name := range known {
+		if name == pane {
+			return nil
+		}
+		if d := editDistance(strings.ToLower(pane), strings.ToLower(name)); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+
+	names := append([]string(nil), known...)
+	sort.Strings(names)
+	names = slices.Compact(names)
+	if best != "" && bestDistance <= max(2, len(pane)/3) {
+		return fmt.Errorf("unknown pane %q - did you mean %q? (known: %s)", pane, best, strings.Join(names, ", "))
+	}
+	return fmt.Errorf("unknown pane %q (known: %s)", pane, strings.Join(names, ", "))
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
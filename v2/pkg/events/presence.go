@@ -0,0 +1,50 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PresenceSubject is where monitors announce which event their operator has selected
+const PresenceSubject = "agneto.presence"
+
+// Presence timing: announcements repeat every PresenceInterval while nothing
+// changes, and a peer silent for PresenceTTL is considered gone
+const (
+	PresenceInterval = 10 * time.Second
+	PresenceTTL      = 30 * time.Second
+)
+
+// Presence is an operator's cursor, published by their monitor
+type Presence struct {
+	Operator string    `json:"operator"`           // Who is looking
+	Instance string    `json:"instance"`           // Which monitor (one operator may run several)
+	EventID  string    `json:"event_id,omitempty"` // Selected event
+	Pane     string    `json:"pane,omitempty"`     // Pane the event is listed in
+	Drafting bool      `json:"drafting,omitempty"` // Typing an answer to the selected event
+	Leaving  bool      `json:"leaving,omitempty"`  // Monitor is quitting; forget this cursor
+	At       time.Time `json:"at"`
+}
+
+// Peer identifies the monitor a presence came from
+func (p Presence) Peer() string {
+	return p.Operator + "@" + p.Instance
+}
+
+// Expired reports whether the presence is older than PresenceTTL
+func (p Presence) Expired(now time.Time) bool {
+	return now.Sub(p.At) > PresenceTTL
+}
+
+// PresenceFromJSON deserializes and validates a presence announcement
+func PresenceFromJSON(data []byte) (*Presence, error) {
+	var p Presence
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	if p.Operator == "" || p.Instance == "" {
+		return nil, fmt.Errorf("presence: requires 'operator' and 'instance'")
+	}
+	return &p, nil
+}
@@ -0,0 +1,671 @@
+package events
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContentTypeHeader is the NATS header naming a payload's encoding
+const ContentTypeHeader = "Content-Type"
+
+// ProbeHeader marks a message that only tests the subject, such as doctor's
+// permission probe; it carries no event and subscribers skip it
+const ProbeHeader = "Agneto-Probe"
+
+// Payload encodings; a message without a Content-Type header is JSON
+const (
+	ContentTypeJSON  = "application/json"
+	ContentTypeProto = "application/x-protobuf"
+)
+
+// maxExactInt is the largest integer a float64 holds exactly
+const maxExactInt = 1 << 53
+
+// IsProto reports whether a Content-Type header value names the protobuf encoding
+func IsProto(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), ContentTypeProto)
+}
+
+// Decode decodes a payload in the encoding its Content-Type header names
+func Decode(contentType string, data []byte) (*Event, error) {
+	if IsProto(contentType) {
+		return FromProto(data)
+	}
+	return FromJSON(data)
+}
+
+// Encode encodes the event for a Content-Type header value (JSON unless it names protobuf)
+func (e Event) Encode(contentType string) ([]byte, error) {
+	if IsProto(contentType) {
+		return e.ToProto()
+	}
+	return e.ToJSON()
+}
+
+// ToProto encodes the event as protobuf (see event.proto)
+// Data values are encoded by their Go type: integers as int_value, floats as
+// number_value; other types as they marshal to JSON
+func (e Event) ToProto() ([]byte, error) {
+	return e.appendProto(nil)
+}
+
+// appendProto appends the event's protobuf encoding to b
+func (e Event) appendProto(b []byte) ([]byte, error) {
+	b = appendString(b, 1, e.ID)
+	b = appendString(b, 2, e.Type)
+	if !e.Timestamp.IsZero() {
+		b = appendInt(b, 3, e.Timestamp.UnixNano())
+		_, offset := e.Timestamp.Zone()
+		b = appendInt(b, 25, int64(offset))
+	}
+	b = appendString(b, 4, e.Message)
+	b = appendString(b, 5, e.IdempotencyKey)
+	b = appendString(b, 6, e.CorrelationID)
+	b = appendString(b, 7, e.CausationID)
+	b = appendString(b, 8, e.ReplyTo)
+	b = appendString(b, 9, e.Pane)
+	b = appendString(b, 10, e.Source)
+	b = appendString(b, 11, e.SessionID)
+	b = appendString(b, 12, e.Severity)
+	b = appendString(b, 13, e.Content)
+
+	var err error
+	if b, err = appendStruct(b, 14, e.Data); err != nil {
+		return nil, fmt.Errorf("data: %w", err)
+	}
+	for i, action := range e.Actions {
+		msg, err := action.appendProto(nil)
+		if err != nil {
+			return nil, fmt.Errorf("actions[%d]: %w", i, err)
+		}
+		b = appendMessage(b, 15, msg)
+	}
+	b = appendInt(b, 18, int64(e.TimeoutSeconds))
+	b = appendString(b, 19, e.DefaultActionID)
+	if e.Usage != nil {
+		b = appendMessage(b, 20, e.Usage.appendProto(nil))
+	}
+	b = appendString(b, 21, e.StreamID)
+	if e.Delta {
+		b = appendVarintField(b, 22, 1)
+	}
+	if e.Final {
+		b = appendVarintField(b, 23, 1)
+	}
+	b = appendString(b, 24, e.ContentType)
+	if e.Question != nil {
+		question, err := e.Question.appendProto(nil)
+		if err != nil {
+			return nil, fmt.Errorf("question: %w", err)
+		}
+		b = appendMessage(b, 26, question)
+	}
+	if e.Escalation != nil {
+		b = appendMessage(b, 27, e.Escalation.appendProto(nil))
+	}
+	return b, nil
+}
+
+// appendProto appends the action's protobuf encoding to b
+func (a Action) appendProto(b []byte) ([]byte, error) {
+	b = appendString(b, 1, a.ID)
+	b = appendString(b, 2, a.Label)
+	b = appendString(b, 3, a.Key)
+	b = appendString(b, 4, a.InputType)
+	for _, option := range a.Options {
+		b = appendMessage(b, 5, []byte(option))
+	}
+	b = appendString(b, 6, a.Style)
+	b = appendInt(b, 7, int64(a.Order))
+	for _, reason := range a.Reasons {
+		b = appendMessage(b, 8, []byte(reason))
+	}
+	b = appendInt(b, 9, int64(a.MinLength))
+	b = appendInt(b, 10, int64(a.MaxLength))
+	b = appendString(b, 11, a.ReplyTo)
+	b = appendInt(b, 13, int64(a.Approvals))
+	event, err := a.Event.appendProto(nil)
+	if err != nil {
+		return nil, fmt.Errorf("event: %w", err)
+	}
+	return appendMessage(b, 12, event), nil
+}
+
+// appendStruct appends a map of Data values as map entries of field, keys in order
+func appendStruct(b []byte, field int, data map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value, err := appendValue(nil, data[k])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		entry := appendMessage(appendMessage(nil, 1, []byte(k)), 2, value)
+		b = appendMessage(b, field, entry)
+	}
+	return b, nil
+}
+
+// appendValue appends a Data value's Value message to b
+// Oneof fields are written even when they hold their zero value
+func appendValue(b []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return appendVarintField(b, 1, 1), nil
+	case bool:
+		if v {
+			return appendVarintField(b, 2, 1), nil
+		}
+		return appendVarintField(b, 2, 0), nil
+	case int:
+		return appendVarintField(b, 3, uint64(v)), nil
+	case int8:
+		return appendVarintField(b, 3, uint64(v)), nil
+	case int16:
+		return appendVarintField(b, 3, uint64(v)), nil
+	case int32:
+		return appendVarintField(b, 3, uint64(v)), nil
+	case int64:
+		return appendVarintField(b, 3, uint64(v)), nil
+	case uint8:
+		return appendVarintField(b, 3, uint64(v)), nil
+	case uint16:
+		return appendVarintField(b, 3, uint64(v)), nil
+	case uint32:
+		return appendVarintField(b, 3, uint64(v)), nil
+	case uint:
+		return appendValue(b, uint64(v))
+	case uint64:
+		if v > math.MaxInt64 {
+			return appendDouble(b, 4, float64(v)), nil
+		}
+		return appendVarintField(b, 3, v), nil
+	case float32:
+		return appendDouble(b, 4, float64(v)), nil
+	case float64:
+		return appendDouble(b, 4, v), nil
+	case string:
+		return appendMessage(b, 5, []byte(v)), nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return appendVarintField(b, 3, uint64(n)), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return appendDouble(b, 4, f), nil
+	case []interface{}:
+		var list []byte
+		for i, item := range v {
+			value, err := appendValue(nil, item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			list = appendMessage(list, 1, value)
+		}
+		return appendMessage(b, 6, list), nil
+	case map[string]interface{}:
+		fields, err := appendStruct(nil, 1, v)
+		if err != nil {
+			return nil, err
+		}
+		return appendMessage(b, 7, fields), nil
+	}
+
+	// Anything else (structs, typed slices and maps) as it marshals to JSON
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return appendValue(b, generic)
+}
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// appendTag appends a field's key
+func appendTag(b []byte, field, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wireType))
+}
+
+// appendVarintField appends a varint field, even a zero one
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	return binary.AppendUvarint(appendTag(b, field, wireVarint), v)
+}
+
+// appendInt appends an int64 field, omitted when zero as proto3 does
+func appendInt(b []byte, field int, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	return appendVarintField(b, field, uint64(v))
+}
+
+// appendString appends a string field, omitted when empty as proto3 does
+func appendString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendMessage(b, field, []byte(s))
+}
+
+// appendMessage appends a length-delimited field (message, string or bytes)
+func appendMessage(b []byte, field int, msg []byte) []byte {
+	b = binary.AppendUvarint(appendTag(b, field, wireBytes), uint64(len(msg)))
+	return append(b, msg...)
+}
+
+// appendDouble appends a double field
+func appendDouble(b []byte, field int, f float64) []byte {
+	return binary.LittleEndian.AppendUint64(appendTag(b, field, wireFixed64), math.Float64bits(f))
+}
+
+// errTruncated reports a payload that ends inside a field
+var errTruncated = errors.New("truncated protobuf payload")
+
+// protoReader reads the fields of a protobuf message
+type protoReader struct {
+	b []byte
+}
+
+// next reads the next field's key; ok is false at the end of the message
+func (r *protoReader) next() (field, wireType int, ok bool, err error) {
+	if len(r.b) == 0 {
+		return 0, 0, false, nil
+	}
+	key, err := r.varint()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	field, wireType = int(key>>3), int(key&7)
+	if field == 0 {
+		return 0, 0, false, fmt.Errorf("invalid protobuf field number 0")
+	}
+	return field, wireType, true, nil
+}
+
+// varint reads a varint
+func (r *protoReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+// bytes reads a length-delimited value
+func (r *protoReader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.b)) {
+		return nil, errTruncated
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v, nil
+}
+
+// fixed reads a fixed-width value of n bytes
+func (r *protoReader) fixed(n int) ([]byte, error) {
+	if len(r.b) < n {
+		return nil, errTruncated
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v, nil
+}
+
+// skip skips a field this version doesn't know
+func (r *protoReader) skip(wireType int) error {
+	var err error
+	switch wireType {
+	case wireVarint:
+		_, err = r.varint()
+	case wireFixed64:
+		_, err = r.fixed(8)
+	case wireBytes:
+		_, err = r.bytes()
+	case wireFixed32:
+		_, err = r.fixed(4)
+	default:
+		err = fmt.Errorf("unsupported protobuf wire type %d", wireType)
+	}
+	return err
+}
+
+// protoField is a decoded field value, by wire type
+type protoField struct {
+	wireType int
+	varint   uint64
+	bytes    []byte
+}
+
+// fields calls fn with every field of a message, reading values by wire type
+// Fields fn doesn't know are skipped
+func (r *protoReader) fields(fn func(field int, v protoField) error) error {
+	for {
+		field, wireType, ok, err := r.next()
+		if err != nil || !ok {
+			return err
+		}
+		v := protoField{wireType: wireType}
+		switch wireType {
+		case wireVarint:
+			v.varint, err = r.varint()
+		case wireFixed64:
+			var b []byte
+			if b, err = r.fixed(8); err == nil {
+				v.varint = binary.LittleEndian.Uint64(b)
+			}
+		case wireBytes:
+			v.bytes, err = r.bytes()
+		default:
+			if err := r.skip(wireType); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(field, v); err != nil {
+			return fmt.Errorf("field %d: %w", field, err)
+		}
+	}
+}
+
+// want checks a field has the wire type its schema declares
+func (v protoField) want(wireType int) error {
+	if v.wireType != wireType {
+		return fmt.Errorf("wire type %d, want %d", v.wireType, wireType)
+	}
+	return nil
+}
+
+// str returns a string field
+func (v protoField) str() (string, error) {
+	return string(v.bytes), v.want(wireBytes)
+}
+
+// int returns an int64 field
+func (v protoField) int() (int64, error) {
+	return int64(v.varint), v.want(wireVarint)
+}
+
+// FromProto decodes a protobuf event (see event.proto)
+// Data values decode to the types FromJSON produces - float64 for numbers -
+// except integers beyond ±2^53, which stay int64 rather than be rounded
+// The timestamp keeps its zone offset, as from JSON
+func FromProto(data []byte) (*Event, error) {
+	var e Event
+	if err := e.readProto(data); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// readProto decodes a protobuf event into e
+func (e *Event) readProto(data []byte) error {
+	r := protoReader{b: data}
+	offset := 0 // Applied once the timestamp is read, whichever comes first
+	err := r.fields(func(field int, v protoField) error {
+		var err error
+		switch field {
+		case 1:
+			e.ID, err = v.str()
+		case 2:
+			e.Type, err = v.str()
+		case 3:
+			var nanos int64
+			if nanos, err = v.int(); err == nil && nanos != 0 {
+				e.Timestamp = time.Unix(0, nanos).UTC()
+			}
+		case 4:
+			e.Message, err = v.str()
+		case 5:
+			e.IdempotencyKey, err = v.str()
+		case 6:
+			e.CorrelationID, err = v.str()
+		case 7:
+			e.CausationID, err = v.str()
+		case 8:
+			e.ReplyTo, err = v.str()
+		case 9:
+			e.Pane, err = v.str()
+		case 10:
+			e.Source, err = v.str()
+		case 11:
+			e.SessionID, err = v.str()
+		case 12:
+			e.Severity, err = v.str()
+		case 13:
+			e.Content, err = v.str()
+		case 14:
+			if e.Data == nil {
+				e.Data = make(map[string]interface{})
+			}
+			err = readEntry(v, e.Data)
+		case 15:
+			var action Action
+			if err = v.want(wireBytes); err == nil {
+				err = action.readProto(v.bytes)
+			}
+			e.Actions = append(e.Actions, action)
+		case 18:
+			var n int64
+			n, err = v.int()
+			e.TimeoutSeconds = int(n)
+		case 19:
+			e.DefaultActionID, err = v.str()
+		case 20:
+			if err = v.want(wireBytes); err == nil {
+				e.Usage = &Usage{}
+				err = e.Usage.readProto(v.bytes)
+			}
+		case 21:
+			e.StreamID, err = v.str()
+		case 22:
+			e.Delta, err = v.varint != 0, v.want(wireVarint)
+		case 23:
+			e.Final, err = v.varint != 0, v.want(wireVarint)
+		case 24:
+			e.ContentType, err = v.str()
+		case 25:
+			var n int64
+			n, err = v.int()
+			offset = int(n)
+		case 26:
+			if err = v.want(wireBytes); err == nil {
+				e.Question = &Question{}
+				err = e.Question.readProto(v.bytes)
+			}
+		case 27:
+			if err = v.want(wireBytes); err == nil {
+				e.Escalation = &Escalation{}
+				err = e.Escalation.readProto(v.bytes)
+			}
+		}
+		return err
+	})
+	if err == nil && offset != 0 && !e.Timestamp.IsZero() {
+		e.Timestamp = e.Timestamp.In(zoneAt(e.Timestamp, offset))
+	}
+	return err
+}
+
+// zoneAt returns the location FromJSON gives a time with this zone offset:
+// Local when it's the local offset at that time, else a fixed zone
+// Zone names (CET, EST) aren't on the wire, as in RFC 3339
+func zoneAt(t time.Time, offset int) *time.Location {
+	if _, local := t.In(time.Local).Zone(); local == offset {
+		return time.Local
+	}
+	return time.FixedZone("", offset)
+}
+
+// readProto decodes a protobuf action into a
+func (a *Action) readProto(data []byte) error {
+	r := protoReader{b: data}
+	return r.fields(func(field int, v protoField) error {
+		var err error
+		var n int64
+		switch field {
+		case 1:
+			a.ID, err = v.str()
+		case 2:
+			a.Label, err = v.str()
+		case 3:
+			a.Key, err = v.str()
+		case 4:
+			a.InputType, err = v.str()
+		case 5:
+			var option string
+			option, err = v.str()
+			a.Options = append(a.Options, option)
+		case 6:
+			a.Style, err = v.str()
+		case 7:
+			n, err = v.int()
+			a.Order = int(n)
+		case 8:
+			var reason string
+			reason, err = v.str()
+			a.Reasons = append(a.Reasons, reason)
+		case 9:
+			n, err = v.int()
+			a.MinLength = int(n)
+		case 10:
+			n, err = v.int()
+			a.MaxLength = int(n)
+		case 11:
+			a.ReplyTo, err = v.str()
+		case 13:
+			n, err = v.int()
+			a.Approvals = int(n)
+		case 12:
+			if err = v.want(wireBytes); err == nil {
+				err = a.Event.readProto(v.bytes)
+			}
+		}
+		return err
+	})
+}
+
+// readEntry decodes a map entry of Values into m
+func readEntry(v protoField, m map[string]interface{}) error {
+	if err := v.want(wireBytes); err != nil {
+		return err
+	}
+	var key string
+	var value interface{}
+	r := protoReader{b: v.bytes}
+	err := r.fields(func(field int, v protoField) error {
+		var err error
+		switch field {
+		case 1:
+			key, err = v.str()
+		case 2:
+			if err = v.want(wireBytes); err == nil {
+				value, err = readValue(v.bytes)
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	m[key] = value
+	return nil
+}
+
+// readValue decodes a Value message
+func readValue(data []byte) (interface{}, error) {
+	var value interface{}
+	r := protoReader{b: data}
+	err := r.fields(func(field int, v protoField) error {
+		switch field {
+		case 1:
+			value = nil
+			return v.want(wireVarint)
+		case 2:
+			value = v.varint != 0
+			return v.want(wireVarint)
+		case 3:
+			n, err := v.int()
+			if n >= -maxExactInt && n <= maxExactInt {
+				value = float64(n)
+			} else {
+				value = n
+			}
+			return err
+		case 4:
+			value = math.Float64frombits(v.varint)
+			return v.want(wireFixed64)
+		case 5:
+			s, err := v.str()
+			value = s
+			return err
+		case 6:
+			if err := v.want(wireBytes); err != nil {
+				return err
+			}
+			list := []interface{}{}
+			lr := protoReader{b: v.bytes}
+			err := lr.fields(func(field int, item protoField) error {
+				if field != 1 {
+					return nil
+				}
+				if err := item.want(wireBytes); err != nil {
+					return err
+				}
+				v, err := readValue(item.bytes)
+				list = append(list, v)
+				return err
+			})
+			value = list
+			return err
+		case 7:
+			if err := v.want(wireBytes); err != nil {
+				return err
+			}
+			fields := map[string]interface{}{}
+			sr := protoReader{b: v.bytes}
+			err := sr.fields(func(field int, entry protoField) error {
+				if field != 1 {
+					return nil
+				}
+				return readEntry(entry, fields)
+			})
+			value = fields
+			return err
+		}
+		return nil
+	})
+	return value, err
+}
@@ -0,0 +1,285 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// protoSamples are events covering every field of the protobuf encoding
+func protoSamples() map[string]Event {
+	at := time.Date(2026, 3, 1, 9, 30, 15, 123456789, time.UTC)
+	return map[string]Event{
+		"empty":   {},
+		"minimal": {ID: "e1", Type: "log", Message: "hello"},
+		"utc":     {ID: "e2", Type: "log", Timestamp: at},
+		"zoned":   {ID: "e3", Type: "log", Timestamp: at.In(time.FixedZone("", 5*3600+30*60))},
+		"west":    {ID: "e4", Type: "log", Timestamp: at.In(time.FixedZone("", -7*3600))},
+		"local":   {ID: "e5", Type: "log", Timestamp: at.In(time.Local)},
+		"full": {
+			ID: "e6", Type: "deploy", Timestamp: at, Message: "Deploy?",
+			IdempotencyKey: "k", CorrelationID: "c", CausationID: "p", ReplyTo: "replies",
+			Pane: "right", Source: "agent", SessionID: "s1", Severity: SeverityWarn,
+			Content: "# Plan\n\n- ✓ build", ContentType: ContentDiff,
+			Data: map[string]interface{}{
+				"count":  float64(3),
+				"ratio":  0.25,
+				"name":   "api",
+				"ok":     true,
+				"none":   nil,
+				"files":  []interface{}{"a.go", float64(2), map[string]interface{}{"lines": float64(10)}},
+				"nested": map[string]interface{}{"empty": map[string]interface{}{}, "list": []interface{}{}},
+			},
+			Actions: []Action{
+				{ID: "yes", Label: "Yes", Key: "y", Style: ActionStylePrimary, Order: 1, Approvals: 2,
+					Reasons: []string{"tested"}, ReplyTo: "other",
+					Event: Event{Type: "deploy_yes", Data: map[string]interface{}{"choice": float64(1)}}},
+				{ID: "why", Label: "Why", InputType: InputSelect, Options: []string{"a", "b"}, MinLength: 1, MaxLength: 9,
+					Event: Event{Type: "deploy_why"}},
+			},
+			Question:        &Question{Prompt: "How many?", Kind: AnswerNumber, Default: float64(2)},
+			Escalation:      &Escalation{AfterSeconds: 60, Target: "oncall"},
+			TimeoutSeconds:  30,
+			DefaultActionID: "yes",
+			Usage:           &Usage{InputTokens: 10, OutputTokens: 20, CostUSD: 0.5, DurationMS: 1500, Model: "m"},
+			StreamID:        "st", Delta: true, Final: true,
+		},
+		"enum":  {ID: "e7", Type: TypeQuestion, Question: &Question{Prompt: "Which?", Kind: AnswerEnum, Options: []string{"a", "b"}, Default: "b"}},
+		"plain": {ID: "e8", Type: TypeQuestion, Question: &Question{Prompt: "Why?", Kind: AnswerString}},
+	}
+}
+
+// viaJSON returns the event as FromJSON decodes its JSON encoding
+func viaJSON(t testing.TB, e Event) *Event {
+	t.Helper()
+	data, err := e.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+// viaProto returns the event as FromProto decodes its protobuf encoding
+func viaProto(t testing.TB, e Event) *Event {
+	t.Helper()
+	data, err := e.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := FromProto(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+// sameTimestamp checks two timestamps are the same instant in the same zone
+// offset and clears them, leaving the rest to be compared as values
+func sameTimestamp(t *testing.T, got, want *time.Time) {
+	t.Helper()
+	_, gotOffset := got.Zone()
+	_, wantOffset := want.Zone()
+	if !got.Equal(*want) || gotOffset != wantOffset {
+		t.Errorf("timestamp = %v, want %v", *got, *want)
+	}
+	*got, *want = time.Time{}, time.Time{}
+}
+
+func TestProtoRoundTripMatchesJSON(t *testing.T) {
+	for name, event := range protoSamples() {
+		t.Run(name, func(t *testing.T) {
+			got, want := viaProto(t, event), viaJSON(t, event)
+			sameTimestamp(t, &got.Timestamp, &want.Timestamp)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("protobuf round trip differs from JSON\n got: %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestProtoEncodesQuestionFieldByField(t *testing.T) {
+	data, err := protoSamples()["enum"].ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A consumer generated from event.proto reads Question without JSON
+	var question []byte
+	r := protoReader{b: data}
+	if err := r.fields(func(field int, v protoField) error {
+		if field == 26 {
+			question = v.bytes
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	got := map[int][]string{}
+	r = protoReader{b: question}
+	if err := r.fields(func(field int, v protoField) error {
+		got[field] = append(got[field], string(v.bytes))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[int][]string{1: {"Which?"}, 2: {"enum"}, 3: {"a", "b"}, 4: {string(appendMessage(nil, 5, []byte("b")))}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("question fields = %q, want %q", got, want)
+	}
+}
+
+func TestProtoKeepsTimestampZone(t *testing.T) {
+	zone := time.FixedZone("", -(3*3600 + 30*60))
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, zone)
+	got := viaProto(t, Event{Timestamp: at}).Timestamp
+	if _, offset := got.Zone(); !got.Equal(at) || offset != -(3*3600+30*60) {
+		t.Fatalf("timestamp = %v, want %v", got, at)
+	}
+	if got.Format(time.RFC3339) != "2026-03-01T09:30:00-03:30" {
+		t.Fatalf("timestamp formats as %s", got.Format(time.RFC3339))
+	}
+}
+
+func TestProtoKeepsLargeIntegers(t *testing.T) {
+	big := int64(1)<<53 + 1
+	got := viaProto(t, Event{Data: map[string]interface{}{"big": big, "small": 7}})
+	if got.Data["big"] != big {
+		t.Errorf("big = %#v, want int64 %d", got.Data["big"], big)
+	}
+	if got.Data["small"] != float64(7) {
+		t.Errorf("small = %#v, want float64 7 as from JSON", got.Data["small"])
+	}
+}
+
+func TestFromProtoRejectsTruncated(t *testing.T) {
+	data, err := protoSamples()["full"].ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{1, len(data) / 2, len(data) - 1} {
+		if _, err := FromProto(data[:n]); err == nil {
+			t.Errorf("decoding the first %d of %d bytes succeeded", n, len(data))
+		}
+	}
+}
+
+// FuzzFromProto checks the decoder never panics, and that whatever it
+// accepts encodes back to the same bytes once normalized by a round trip
+func FuzzFromProto(f *testing.F) {
+	for _, event := range protoSamples() {
+		data, err := event.ToProto()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		event, err := FromProto(data)
+		if err != nil {
+			return
+		}
+		first, err := event.ToProto()
+		if err != nil {
+			return // e.g. a question whose JSON doesn't marshal back
+		}
+		again, err := FromProto(first)
+		if err != nil {
+			t.Fatalf("decoding a re-encoded event: %v", err)
+		}
+		second, err := again.ToProto()
+		if err != nil {
+			t.Fatalf("re-encoding a decoded event: %v", err)
+		}
+		if !bytes.Equal(first, second) {
+			t.Fatalf("encoding isn't stable across a round trip:\n%x\n%x", first, second)
+		}
+	})
+}
+
+// benchEvent is a decision with 64 KiB of markdown content, mixed data and
+// two actions, the kind of event protobuf is meant for
+func benchEvent() Event {
+	line := "| step | status | took |\n|------|--------|------|\n| build \"api\" | ok ✓ | 1.2s |\n"
+	content := strings.Repeat(line, 64*1024/len(line)+1)[:64*1024]
+
+	data := make(map[string]interface{}, 20)
+	for i := 0; i < 20; i++ {
+		switch i % 4 {
+		case 0:
+			data[fmt.Sprintf("count_%d", i)] = float64(i * 1000)
+		case 1:
+			data[fmt.Sprintf("ratio_%d", i)] = float64(i) / 7
+		case 2:
+			data[fmt.Sprintf("name_%d", i)] = fmt.Sprintf("worker-%d", i)
+		default:
+			data[fmt.Sprintf("files_%d", i)] = []interface{}{"a.go", "b.go", map[string]interface{}{"lines": float64(i)}}
+		}
+	}
+
+	event := Event{
+		ID:        "5f0c6c1e-3b7a-4f43-9a55-2d0c1f7e8a90",
+		Type:      "plan.review",
+		Timestamp: time.Now(),
+		Message:   "Plan ready for review",
+		Content:   content,
+		Data:      data,
+	}
+	for i := 0; i < 2; i++ {
+		event.Actions = append(event.Actions, Action{
+			ID:    fmt.Sprintf("action-%d", i),
+			Label: fmt.Sprintf("Action %d", i),
+			Key:   fmt.Sprintf("%d", i+1),
+			Event: Event{Type: "plan.response", Data: map[string]interface{}{"choice": i}},
+		})
+	}
+	return event
+}
+
+// benchCodecs are the encodings compared by the benchmarks
+var benchCodecs = []struct {
+	name   string
+	encode func(Event) ([]byte, error)
+	decode func([]byte) (*Event, error)
+}{
+	{"json", Event.ToJSON, FromJSON},
+	{"proto", Event.ToProto, FromProto},
+}
+
+func BenchmarkEncode(b *testing.B) {
+	event := benchEvent()
+	for _, c := range benchCodecs {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.encode(event); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	event := benchEvent()
+	for _, c := range benchCodecs {
+		payload, err := c.encode(event)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				if _, err := c.decode(payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
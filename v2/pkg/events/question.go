@@ -0,0 +1,223 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Question event types
+const (
+	TypeQuestion       = "question"        // Event carrying a Question
+	TypeQuestionAnswer = "question.answer" // Response event carrying the typed answer
+)
+
+// AnswerKind is the schema of a question's answer
+type AnswerKind string
+
+// Supported answer kinds
+const (
+	AnswerEnum   AnswerKind = "enum"   // One of Options
+	AnswerString AnswerKind = "string" // Free text
+	AnswerNumber AnswerKind = "number" // float64
+	AnswerBool   AnswerKind = "bool"   // true/false
+)
+
+// maxEnumOptions is the number of enum options that can be bound to keys 1-9
+const maxEnumOptions = 9
+
+// Question asks the operator for a typed answer
+// The TUI publishes a TypeQuestionAnswer event whose Data holds "question_id"
+// and "answer" (string, float64 or bool according to Kind), so orchestrators
+// can parse responses reliably instead of interpreting free text
+type Question struct {
+	Prompt  string      `json:"prompt"`            // Question text shown to the operator
+	Kind    AnswerKind  `json:"kind"`              // Answer schema
+	Options []string    `json:"options,omitempty"` // Allowed answers (enum only)
+	Default interface{} `json:"default,omitempty"` // Optional default answer (used on Enter or empty input)
+}
+
+// NewQuestion creates a question with the given prompt and answer kind
+func NewQuestion(prompt string, kind AnswerKind) *Question {
+	return &Question{Prompt: prompt, Kind: kind}
+}
+
+// WithOptions sets the allowed answers of an enum question
+func (q *Question) WithOptions(options ...string) *Question {
+	q.Options = options
+	return q
+}
+
+// WithDefault sets the default answer
+func (q *Question) WithDefault(value interface{}) *Question {
+	q.Default = value
+	return q
+}
+
+// Validate checks the question is well-formed
+func (q *Question) Validate() error {
+	if q.Prompt == "" {
+		return fmt.Errorf("question: missing 'prompt'")
+	}
+	switch q.Kind {
+	case AnswerEnum:
+		if len(q.Options) == 0 {
+			return fmt.Errorf("question: enum requires 'options'")
+		}
+		if len(q.Options) > maxEnumOptions {
+			return fmt.Errorf("question: enum supports at most %d options, got %d", maxEnumOptions, len(q.Options))
+		}
+	case AnswerString, AnswerNumber, AnswerBool:
+	default:
+		return fmt.Errorf("question: unknown kind %q", q.Kind)
+	}
+	if q.Default != nil {
+		if _, err := q.ParseAnswer(fmt.Sprint(q.Default)); err != nil {
+			return fmt.Errorf("question: invalid default: %w", err)
+		}
+	}
+	return nil
+}
+
+// ParseAnswer converts answer text into the typed value for the question's kind
+func (q *Question) ParseAnswer(text string) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	if text == "" && q.Default != nil {
+		text = fmt.Sprint(q.Default)
+	}
+
+	switch q.Kind {
+	case AnswerEnum:
+		for _, option := range q.Options {
+			if option == text {
+				return option, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not one of %s", text, strings.Join(q.Options, ", "))
+	case AnswerNumber:
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		return value, nil
+	case AnswerBool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not true or false", text)
+		}
+		return value, nil
+	default:
+		if text == "" {
+			return nil, fmt.Errorf("answer is empty")
+		}
+		return text, nil
+	}
+}
+
+// AnswerData builds the Data payload of an answer event
+func AnswerData(questionID string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"question_id": questionID,
+		"answer":      value,
+	}
+}
+
+// Actions synthesizes the actions that answer the question:
+// enum options on keys 1-9, bool on y/n, string and number via text input
+func (q *Question) Actions(questionID string) []Action {
+	answer := func(value interface{}) Event {
+		return Event{
+			Type:    TypeQuestionAnswer,
+			Message: fmt.Sprintf("Answered: %v", value),
+			Data:    AnswerData(questionID, value),
+		}
+	}
+
+	switch q.Kind {
+	case AnswerEnum:
+		actions := make([]Action, 0, len(q.Options))
+		for i, option := range q.Options {
+			actions = append(actions, Action{
+				ID:    fmt.Sprintf("option-%d", i+1),
+				Label: option,
+				Key:   strconv.Itoa(i + 1),
+				Event: answer(option),
+			})
+		}
+		return actions
+	case AnswerBool:
+		return []Action{
+			{ID: "yes", Label: "Yes", Key: "y", Event: answer(true)},
+			{ID: "no", Label: "No", Key: "n", Event: answer(false)},
+		}
+	default:
+		return []Action{{
+			ID:        "answer",
+			Label:     q.Prompt,
+			InputType: InputMultiline,
+			Event: Event{
+				Type: TypeQuestionAnswer,
+				Data: map[string]interface{}{"question_id": questionID},
+			},
+		}}
+	}
+}
+
+// Event builds a question event ready to publish, with answer actions attached
+func (q *Question) Event(pane string) Event {
+	id := uuid.New().String()
+	return Event{
+		ID:        id,
+		Type:      TypeQuestion,
+		Timestamp: time.Now(),
+		Message:   q.Prompt,
+		Pane:      pane,
+		Question:  q,
+		Actions:   q.Actions(id),
+	}
+}
+
+// appendProto appends the question's protobuf encoding to b
+func (q Question) appendProto(b []byte) ([]byte, error) {
+	b = appendString(b, 1, q.Prompt)
+	b = appendString(b, 2, string(q.Kind))
+	for _, option := range q.Options {
+		b = appendMessage(b, 3, []byte(option))
+	}
+	if q.Default == nil {
+		return b, nil
+	}
+	value, err := appendValue(nil, q.Default)
+	if err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	return appendMessage(b, 4, value), nil
+}
+
+// readProto decodes a protobuf question into q
+func (q *Question) readProto(data []byte) error {
+	r := protoReader{b: data}
+	return r.fields(func(field int, v protoField) error {
+		var err error
+		switch field {
+		case 1:
+			q.Prompt, err = v.str()
+		case 2:
+			var kind string
+			kind, err = v.str()
+			q.Kind = AnswerKind(kind)
+		case 3:
+			var option string
+			option, err = v.str()
+			q.Options = append(q.Options, option)
+		case 4:
+			if err = v.want(wireBytes); err == nil {
+				q.Default, err = readValue(v.bytes)
+			}
+		}
+		return err
+	})
+}
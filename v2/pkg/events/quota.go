@@ -0,0 +1,55 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Quota events, synthesized by a monitor limiting producers' event rates
+const (
+	TypeQuotaExceeded  = "quota.exceeded"  // A producer went over its events per second
+	TypeQuotaRecovered = "quota.recovered" // It came back under
+)
+
+// NewQuotaExceeded reports a producer sending more than rate events per second
+// mode is what the monitor does with the excess: "sample" or "mute"
+func NewQuotaExceeded(source string, rate int, mode, action string) Event {
+	return Event{
+		ID:        uuid.New().String(),
+		Type:      TypeQuotaExceeded,
+		Timestamp: time.Now(),
+		Severity:  SeverityWarn,
+		Message:   fmt.Sprintf("%s is sending more than %d events/s: %s", quotaProducer(source), rate, action),
+		Data: map[string]interface{}{
+			"producer": source,
+			"rate":     rate,
+			"mode":     mode,
+		},
+	}
+}
+
+// NewQuotaRecovered reports a producer back under its rate, with the events dropped meanwhile
+func NewQuotaRecovered(source string, rate, dropped int) Event {
+	return Event{
+		ID:        uuid.New().String(),
+		Type:      TypeQuotaRecovered,
+		Timestamp: time.Now(),
+		Severity:  SeverityInfo,
+		Message:   fmt.Sprintf("%s is back under %d events/s (%d events dropped)", quotaProducer(source), rate, dropped),
+		Data: map[string]interface{}{
+			"producer": source,
+			"rate":     rate,
+			"dropped":  dropped,
+		},
+	}
+}
+
+// quotaProducer names a producer in messages; events without a source share one quota
+func quotaProducer(source string) string {
+	if source == "" {
+		return "a producer without a source"
+	}
+	return fmt.Sprintf("producer %q", source)
+}
@@ -0,0 +1,50 @@
+package events
+
+// Request-reply pairing: a producer that waits for an answer sets ReplyTo to
+// an inbox it listens on, and every response carries the request's
+// correlation ID, so concurrent producers can't take each other's answers
+// even when their actions publish the same response types
+//
+// Causation: every event a monitor publishes because of another event (a
+// response, an escalated copy, a cancel) carries that event's ID in
+// CausationID. The correlation ID is shared by a whole exchange; causation
+// IDs link each step to the one before it
+
+// Correlation returns the ID responses to the event carry in CorrelationID:
+// the producer's own, else the event ID
+func (e Event) Correlation() string {
+	if e.CorrelationID != "" {
+		return e.CorrelationID
+	}
+	return e.ID
+}
+
+// CausedBy records that the event was published because of cause: it joins
+// cause's correlation and names cause in CausationID
+// It stays in cause's session unless it names its own
+func (e *Event) CausedBy(cause Event) {
+	e.CorrelationID = cause.Correlation()
+	e.CausationID = cause.ID
+	if e.SessionID == "" {
+		e.SessionID = cause.SessionID
+	}
+}
+
+// AddressReplies stamps every action's response with the event's correlation
+// and causation IDs and, unless the action names its own, the event's reply
+// subject
+// Monitors call it on receipt, so whichever way an action is triggered its
+// response answers this event
+func (e *Event) AddressReplies() {
+	for i := range e.Actions {
+		e.Actions[i].Event.CausedBy(*e)
+		if e.Actions[i].ReplyTo == "" {
+			e.Actions[i].ReplyTo = e.ReplyTo
+		}
+	}
+}
+
+// Answers reports whether the event is a response to request
+func (e Event) Answers(request Event) bool {
+	return e.CorrelationID != "" && e.CorrelationID == request.Correlation() && e.ID != request.ID
+}
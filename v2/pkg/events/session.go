@@ -0,0 +1,95 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sessions: a producer run (e.g. one orchestrator job) tags its events with a
+// SessionID and publishes them on SessionSubject(id), so a monitor can attach
+// to one session, or to all of them through AllSessionsSubject. Producers
+// announce their sessions on SessionsSubject, which is how monitors list the
+// active ones
+
+// SessionsSubject is where producers announce the sessions they publish to
+const SessionsSubject = "agneto.sessions"
+
+// AllSessionsSubject carries the events of every session
+const AllSessionsSubject = "agneto.*.events"
+
+// Session timing: a producer announces its session at most every
+// SessionInterval while publishing, and a session silent for SessionTTL is
+// no longer listed as active
+const (
+	SessionInterval = 10 * time.Second
+	SessionTTL      = 5 * time.Minute
+)
+
+// SessionSubject returns the subject a session's events flow on (agneto.<session>.events)
+func SessionSubject(session string) string {
+	return "agneto." + session + ".events"
+}
+
+// ValidateSession checks a session ID can be used as a subject token
+func ValidateSession(session string) error {
+	if session == "" {
+		return fmt.Errorf("session ID is empty")
+	}
+	if strings.ContainsAny(session, ".*> \t\r\n") {
+		return fmt.Errorf("session ID %q may not contain '.', '*', '>' or whitespace", session)
+	}
+	return nil
+}
+
+// IsWildcardSubject reports whether a subject matches several (contains * or >)
+// Nothing can be published to one
+func IsWildcardSubject(subject string) bool {
+	for _, token := range strings.Split(subject, ".") {
+		if token == "*" || token == ">" {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionAnnouncement says a producer is publishing to a session
+type SessionAnnouncement struct {
+	Session  string    `json:"session"`
+	Producer string    `json:"producer,omitempty"` // Source of the announcing producer
+	Message  string    `json:"message,omitempty"`  // Message of its latest event, hinting what the session is doing
+	At       time.Time `json:"at"`
+}
+
+// NewSessionAnnouncement announces that event was just published to its session
+func NewSessionAnnouncement(event Event) SessionAnnouncement {
+	return SessionAnnouncement{
+		Session:  event.SessionID,
+		Producer: event.EffectiveSource(),
+		Message:  event.Message,
+		At:       time.Now(),
+	}
+}
+
+// Expired reports whether the announcement is older than SessionTTL
+func (a SessionAnnouncement) Expired(now time.Time) bool {
+	return now.Sub(a.At) > SessionTTL
+}
+
+// ToJSON serializes the announcement
+func (a SessionAnnouncement) ToJSON() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+// SessionAnnouncementFromJSON deserializes and validates a session announcement
+func SessionAnnouncementFromJSON(data []byte) (*SessionAnnouncement, error) {
+	var a SessionAnnouncement
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+	if err := ValidateSession(a.Session); err != nil {
+		return nil, fmt.Errorf("session announcement: %w", err)
+	}
+	return &a, nil
+}
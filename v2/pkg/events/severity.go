@@ -0,0 +1,75 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severities (Event.Severity), from least to most severe
+const (
+	SeverityDebug    = "debug"
+	SeverityInfo     = "info"
+	SeverityWarn     = "warn"
+	SeverityError    = "error"
+	SeverityCritical = "critical"
+)
+
+// Severities lists the severities in increasing order
+var Severities = []string{SeverityDebug, SeverityInfo, SeverityWarn, SeverityError, SeverityCritical}
+
+// severityAliases maps other common spellings to a severity
+var severityAliases = map[string]string{
+	"trace":   SeverityDebug,
+	"warning": SeverityWarn,
+	"err":     SeverityError,
+	"fatal":   SeverityCritical,
+}
+
+// NormalizeSeverity returns the severity a name stands for (case-insensitive,
+// accepting aliases like "warning" and "fatal"), or "" if it isn't one
+func NormalizeSeverity(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := severityAliases[name]; ok {
+		return alias
+	}
+	for _, s := range Severities {
+		if s == name {
+			return s
+		}
+	}
+	return ""
+}
+
+// ParseSeverity is NormalizeSeverity for user input, failing on unknown names
+func ParseSeverity(name string) (string, error) {
+	if s := NormalizeSeverity(name); s != "" {
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (want %s)", name, strings.Join(Severities, ", "))
+}
+
+// SeverityRank orders severities: 0 for debug up to 4 for critical, -1 if unknown
+func SeverityRank(severity string) int {
+	severity = NormalizeSeverity(severity)
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// EffectiveSeverity returns the event's severity: Severity if set, otherwise
+// Data["severity"] (set by producers and transform rules predating the field),
+// otherwise info
+func (e Event) EffectiveSeverity() string {
+	if s := NormalizeSeverity(e.Severity); s != "" {
+		return s
+	}
+	if v, ok := e.Data["severity"].(string); ok {
+		if s := NormalizeSeverity(v); s != "" {
+			return s
+		}
+	}
+	return SeverityInfo
+}
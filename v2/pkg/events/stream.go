@@ -0,0 +1,71 @@
+package events
+
+import "fmt"
+
+// Streamed output: agents producing text token by token publish it as a
+// stream of events sharing a StreamID. Monitors show the stream as one entry:
+// the first event adds it, and later ones grow it in place:
+//
+//   - Delta events append their Content to the entry's
+//   - Other events replace the entry's Content (a snapshot of the whole output)
+//   - A Final event closes the stream; events with its ID after that start a new entry
+//
+// Message, Data and Usage of later events are merged into the entry too (see
+// WithChunk). Decisions are never streamed: an event with actions or a
+// question always gets a row of its own.
+
+// IsStreamed reports whether the event is part of a stream
+func (e Event) IsStreamed() bool {
+	return e.StreamID != ""
+}
+
+// ValidateStream checks Delta and Final are only set on streamed events, and
+// that decisions aren't streamed
+func (e Event) ValidateStream() error {
+	if e.StreamID == "" {
+		if e.Delta || e.Final {
+			return fmt.Errorf("delta and final need a stream ID")
+		}
+		return nil
+	}
+	if len(e.Actions) > 0 || e.Question != nil {
+		return fmt.Errorf("events with actions or a question can't be streamed")
+	}
+	return nil
+}
+
+// WithChunk returns the stream entry e grown by a later event of its stream:
+// Content appended (Delta) or replaced, Message replaced when the chunk has
+// one, Data keys merged with the chunk's winning and usage added up
+// The entry keeps its ID and timestamp; the Data map is copied, not shared
+func (e Event) WithChunk(chunk Event) Event {
+	if chunk.Delta {
+		e.Content += chunk.Content
+	} else {
+		e.Content = chunk.Content
+	}
+	if chunk.Message != "" {
+		e.Message = chunk.Message
+	}
+	if len(chunk.Data) > 0 {
+		data := make(map[string]interface{}, len(e.Data)+len(chunk.Data))
+		for k, v := range e.Data {
+			data[k] = v
+		}
+		for k, v := range chunk.Data {
+			data[k] = v
+		}
+		e.Data = data
+	}
+	if chunk.Usage != nil {
+		var usage Usage
+		if e.Usage != nil {
+			usage = *e.Usage
+		}
+		usage = usage.Add(*chunk.Usage)
+		e.Usage = &usage
+	}
+	e.Delta = false
+	e.Final = chunk.Final
+	return e
+}
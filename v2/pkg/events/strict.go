@@ -0,0 +1,97 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema violation events
+const (
+	TypeSchemaViolation = "schema.violation" // Synthesized by a strict monitor for payloads that break the schema
+	ErrorsPane          = "errors"           // Pane violations are routed to
+)
+
+// SchemaError reports a payload that decodes leniently but breaks the strict schema
+type SchemaError struct {
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return "schema violation: " + strings.Join(e.Problems, "; ")
+}
+
+// DecodeStrict deserializes an event, rejecting unknown fields and missing required fields
+// The leniently decoded event is returned alongside a *SchemaError so callers can
+// still show it; a nil event means the payload isn't a usable event at all
+func DecodeStrict(data []byte) (*Event, error) {
+	event, err := FromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var strict Event
+	if err := dec.Decode(&strict); err != nil {
+		problems = append(problems, strings.TrimPrefix(err.Error(), "json: "))
+	}
+	problems = append(problems, event.MissingFields()...)
+
+	if len(problems) > 0 {
+		return event, &SchemaError{Problems: problems}
+	}
+	return event, nil
+}
+
+// MissingFields lists required fields that are empty, including those of actions
+func (e Event) MissingFields() []string {
+	var missing []string
+	require := func(field string, empty bool) {
+		if empty {
+			missing = append(missing, fmt.Sprintf("missing %q", field))
+		}
+	}
+
+	require("id", e.ID == "")
+	require("type", e.Type == "")
+	require("timestamp", e.Timestamp.IsZero())
+	require("message", e.Message == "" && e.Content == "")
+
+	for i, action := range e.Actions {
+		prefix := fmt.Sprintf("actions[%d].", i)
+		require(prefix+"id", action.ID == "")
+		require(prefix+"label", action.Label == "")
+		require(prefix+"key", action.Key == "" && action.InputType == "")
+		require(prefix+"event.type", action.Event.Type == "")
+	}
+	return missing
+}
+
+// NewSchemaViolation builds the event shown in the errors pane for a rejected payload
+// The original event, if it decoded at all, is kept in Data for inspection
+func NewSchemaViolation(subject string, raw []byte, original *Event, err error) Event {
+	data := map[string]interface{}{
+		"subject": subject,
+		"error":   err.Error(),
+	}
+	if original != nil {
+		data["event_id"] = original.ID
+		data["event_type"] = original.Type
+	}
+
+	return Event{
+		ID:        uuid.New().String(),
+		Type:      TypeSchemaViolation,
+		Timestamp: time.Now(),
+		Message:   err.Error(),
+		Pane:      ErrorsPane,
+		Content:   string(raw),
+		Data:      data,
+	}
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// RespondedViaTimeout is the response Data value of "responded_via" for default
+// actions taken because nobody answered in time
+const RespondedViaTimeout = "timeout"
+
+// ActionTimeout returns how long the event waits for an answer before its
+// default action is taken (false when it waits forever)
+func (e Event) ActionTimeout() (time.Duration, bool) {
+	if e.TimeoutSeconds <= 0 || e.DefaultActionID == "" {
+		return 0, false
+	}
+	return time.Duration(e.TimeoutSeconds) * time.Second, true
+}
+
+// ValidateTimeout checks the timeout and default action go together and the
+// default is one of the event's buttons (inputs need someone to fill them in)
+func (e Event) ValidateTimeout() error {
+	switch {
+	case e.TimeoutSeconds == 0 && e.DefaultActionID == "":
+		return nil
+	case e.TimeoutSeconds < 0:
+		return fmt.Errorf("'timeout_seconds' can't be negative")
+	case e.TimeoutSeconds == 0:
+		return fmt.Errorf("'default_action_id' needs 'timeout_seconds'")
+	case e.DefaultActionID == "":
+		return fmt.Errorf("'timeout_seconds' needs 'default_action_id'")
+	}
+	action, ok := e.DefaultAction()
+	if !ok {
+		return fmt.Errorf("default action %q is not one of the event's actions", e.DefaultActionID)
+	}
+	if action.IsInput() {
+		return fmt.Errorf("default action %q opens an input; it must be a button", e.DefaultActionID)
+	}
+	if action.RequiredApprovals() > 1 {
+		return fmt.Errorf("default action %q needs %d approvals; it can't be taken without them", e.DefaultActionID, action.RequiredApprovals())
+	}
+	return nil
+}
+
+// DefaultAction returns the action taken on timeout, its response marked as
+// given by the timeout rather than an operator
+func (e Event) DefaultAction() (Action, bool) {
+	for _, action := range e.Actions {
+		if action.ID == e.DefaultActionID {
+			return action.RespondedVia(RespondedViaTimeout), true
+		}
+	}
+	return Action{}, false
+}
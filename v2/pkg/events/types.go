@@ -2,29 +2,113 @@ package events
 
 import (
 	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Event represents a basic event in the system
 type Event struct {
-	ID        string                 `json:"id"`
-	Type      string                 `json:"type"`
-	Timestamp time.Time              `json:"timestamp"`
-	Message   string                 `json:"message"`
-	Pane      string                 `json:"pane,omitempty"`    // Target pane: "left", "right", or empty for default
-	Content   string                 `json:"content,omitempty"` // Raw text/markdown content for display (no preprocessing)
-	Data      map[string]interface{} `json:"data,omitempty"`    // Arbitrary payload data (formatted as JSON if Content is empty)
-	Actions   []Action               `json:"actions,omitempty"` // Optional actions (dynamic buttons)
+	ID              string                 `json:"id"`
+	Type            string                 `json:"type"`
+	Timestamp       time.Time              `json:"timestamp"`
+	Message         string                 `json:"message"`
+	IdempotencyKey  string                 `json:"idempotency_key,omitempty"`   // Same key = same logical event; monitors drop repeats
+	CorrelationID   string                 `json:"correlation_id,omitempty"`    // On responses: the request's correlation (see reply.go)
+	CausationID     string                 `json:"causation_id,omitempty"`      // On responses: the ID of the event answered (see reply.go)
+	ReplyTo         string                 `json:"reply_to,omitempty"`          // Subject responses to this event are also published to (see reply.go)
+	Pane            string                 `json:"pane,omitempty"`              // Target pane: "left", "right", or empty for default
+	Source          string                 `json:"source,omitempty"`            // Optional: producer that published the event (e.g. an agent name)
+	SessionID       string                 `json:"session_id,omitempty"`        // Optional: producer run the event belongs to (see session.go)
+	Severity        string                 `json:"severity,omitempty"`          // Optional: debug, info (default), warn, error or critical (see severity.go)
+	Content         string                 `json:"content,omitempty"`           // Raw text/markdown content for display (no preprocessing)
+	ContentType     string                 `json:"content_type,omitempty"`      // Optional: how Content is rendered, "markdown" (default) or "diff" (see content.go)
+	Data            map[string]interface{} `json:"data,omitempty"`              // Arbitrary payload data (formatted as JSON if Content is empty)
+	Actions         []Action               `json:"actions,omitempty"`           // Optional actions (dynamic buttons)
+	Question        *Question              `json:"question,omitempty"`          // Optional typed question (see question.go)
+	Escalation      *Escalation            `json:"escalation,omitempty"`        // Optional hand-off to alternate approvers (see escalation.go)
+	TimeoutSeconds  int                    `json:"timeout_seconds,omitempty"`   // Optional: answer with DefaultActionID after this long unanswered (see timeout.go)
+	DefaultActionID string                 `json:"default_action_id,omitempty"` // Action taken when TimeoutSeconds runs out
+	Usage           *Usage                 `json:"usage,omitempty"`             // Optional: tokens, cost and duration of producing the event (see usage.go)
+	StreamID        string                 `json:"stream_id,omitempty"`         // Optional: events with the same stream ID make one growing entry (see stream.go)
+	Delta           bool                   `json:"delta,omitempty"`             // Streamed: Content is appended to the stream's, not a replacement
+	Final           bool                   `json:"final,omitempty"`             // Streamed: the last event of the stream
 }
 
 // Action represents a user action that can be triggered (e.g., button press)
 // When triggered, the complete Event is published (with ID and Timestamp added by TUI)
 type Action struct {
-	ID        string `json:"id"`                   // Unique action ID
-	Label     string `json:"label"`                // Button display text (e.g., "Approve")
-	Key       string `json:"key"`                  // Keyboard shortcut (e.g., "a") - ignored when InputType is set
-	InputType string `json:"input_type,omitempty"` // Optional: "multiline" triggers textarea input mode
-	Event     Event  `json:"event"`                // Complete event to publish when action is triggered
+	ID        string   `json:"id"`                   // Unique action ID
+	Label     string   `json:"label"`                // Button display text (e.g., "Approve")
+	Key       string   `json:"key"`                  // Keyboard shortcut (e.g., "a") - ignored when InputType is set
+	InputType string   `json:"input_type,omitempty"` // Optional: "multiline", "line", "select" or "confirm" opens an input (see input.go)
+	Options   []string `json:"options,omitempty"`    // Choices of a "select" input
+	Style     string   `json:"style,omitempty"`      // Optional visual emphasis: "primary" (default), "danger" or "neutral"
+	Order     int      `json:"order,omitempty"`      // Optional position hint: buttons render by ascending order
+	Reasons   []string `json:"reasons,omitempty"`    // Optional reason codes offered in a picker when triggered
+	MinLength int      `json:"min_length,omitempty"` // Optional: fewest characters an input answer may have
+	MaxLength int      `json:"max_length,omitempty"` // Optional: most characters an input answer may have (see length.go)
+	ReplyTo   string   `json:"reply_to,omitempty"`   // Subject the response is also published to (defaults to the event's ReplyTo)
+	Approvals int      `json:"approvals,omitempty"`  // Optional: distinct operators who must trigger it, 2 for four-eyes (see approval.go)
+	Event     Event    `json:"event"`                // Complete event to publish when action is triggered
+}
+
+// ReasonCodesKey is the response Data key holding the reason codes picked for an action
+const ReasonCodesKey = "reason_codes"
+
+// Action styles
+const (
+	ActionStylePrimary = "primary" // Default emphasis
+	ActionStyleDanger  = "danger"  // Destructive or irreversible (e.g. Reject, Abort)
+	ActionStyleNeutral = "neutral" // De-emphasized (e.g. Skip, Later)
+)
+
+// ValidActionStyle reports whether style is empty or a known action style
+func ValidActionStyle(style string) bool {
+	switch style {
+	case "", ActionStylePrimary, ActionStyleDanger, ActionStyleNeutral:
+		return true
+	}
+	return false
+}
+
+// Response returns the event to publish when the action is triggered:
+// the action's complete event with a fresh ID and timestamp
+func (a Action) Response() Event {
+	response := a.Event
+	response.ID = uuid.New().String()
+	response.Timestamp = time.Now()
+	return response
+}
+
+// WithReasons returns a copy of the action whose response carries the picked reason codes
+// The action's Data map is shared with the event it came from, so it is copied
+func (a Action) WithReasons(codes []string) Action {
+	data := make(map[string]interface{}, len(a.Event.Data)+1)
+	for k, v := range a.Event.Data {
+		data[k] = v
+	}
+	data[ReasonCodesKey] = append([]string{}, codes...)
+	a.Event.Data = data
+	return a
+}
+
+// RespondedVia returns a copy of the action whose response names what gave
+// it in "responded_via" (a timeout, a hook), when it wasn't the operator
+func (a Action) RespondedVia(via string) Action {
+	a.Event.Data = withKey(a.Event.Data, "responded_via", via)
+	return a
+}
+
+// SortActions orders actions by their Order hint, keeping the producer's
+// order among actions with the same hint
+func SortActions(actions []Action) {
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].Order < actions[j].Order
+	})
 }
 
 // ToJSON serializes the event to JSON
@@ -41,3 +125,126 @@ func FromJSON(data []byte) (*Event, error) {
 	}
 	return &event, nil
 }
+
+// Field looks up a value by dotted path: "id", "type", "timestamp", "message",
+// "pane", "severity", "source", "session_id", "content", "correlation_id",
+// "causation_id", "stream_id", or "data.<key>[.<nested>...]" into the Data
+// payload; "severity" and "source" are the effective values
+// Array items are indexed as in "data.files[2].path"; keys that aren't plain
+// identifiers can be quoted, as in `data["cost (usd)"]`
+// Returns false if the path doesn't resolve
+func (e Event) Field(path string) (interface{}, bool) {
+	switch path {
+	case "id":
+		return e.ID, true
+	case "type":
+		return e.Type, true
+	case "timestamp":
+		return e.Timestamp, true
+	case "message":
+		return e.Message, true
+	case "pane":
+		return e.Pane, true
+	case "severity":
+		return e.EffectiveSeverity(), true
+	case "source":
+		return e.EffectiveSource(), true
+	case "session_id":
+		return e.SessionID, true
+	case "content":
+		return e.Content, true
+	case "correlation_id":
+		return e.CorrelationID, true
+	case "causation_id":
+		return e.CausationID, true
+	case "stream_id":
+		return e.StreamID, true
+	}
+
+	rest, ok := strings.CutPrefix(path, "data")
+	if !ok || rest == "" || (rest[0] != '.' && rest[0] != '[') {
+		return nil, false
+	}
+	steps, ok := parsePath(rest)
+	if !ok {
+		return nil, false
+	}
+	var current interface{} = e.Data
+	for _, step := range steps {
+		switch key := step.(type) {
+		case string:
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = obj[key]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := current.([]interface{})
+			if !ok || key < 0 || key >= len(arr) {
+				return nil, false
+			}
+			current = arr[key]
+		}
+	}
+	return current, true
+}
+
+// parsePath splits ".a.b[2][\"c d\"]" into object keys (string) and array indices (int)
+func parsePath(path string) ([]interface{}, bool) {
+	var steps []interface{}
+	for path != "" {
+		switch path[0] {
+		case '.':
+			end := strings.IndexAny(path[1:], ".[") + 1
+			if end == 0 {
+				end = len(path)
+			}
+			if end == 1 {
+				return nil, false // Empty key
+			}
+			steps = append(steps, path[1:end])
+			path = path[end:]
+		case '[':
+			end := strings.Index(path, "]")
+			if strings.HasPrefix(path, "[\"") {
+				// Quoted keys may contain ] - find the closing quote first
+				var key string
+				dec := json.NewDecoder(strings.NewReader(path[1:]))
+				if err := dec.Decode(&key); err != nil {
+					return nil, false
+				}
+				after := 1 + int(dec.InputOffset())
+				if after >= len(path) || path[after] != ']' {
+					return nil, false
+				}
+				steps = append(steps, key)
+				path = path[after+1:]
+				continue
+			}
+			if end < 0 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(path[1:end])
+			if err != nil {
+				return nil, false
+			}
+			steps = append(steps, index)
+			path = path[end+1:]
+		default:
+			return nil, false
+		}
+	}
+	return steps, true
+}
+
+// EffectiveSource returns the event's producer: Source if set, otherwise
+// Data["source"] (producers predating the field), otherwise ""
+func (e Event) EffectiveSource() string {
+	if e.Source != "" {
+		return e.Source
+	}
+	source, _ := e.Data["source"].(string)
+	return source
+}
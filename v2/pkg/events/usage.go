@@ -0,0 +1,87 @@
+package events
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Usage is what producing an event cost: the tokens an LLM call took, its
+// price and how long it ran
+// Each event reports its own usage, not a running total; monitors add them up
+type Usage struct {
+	InputTokens  int64   `json:"input_tokens,omitempty"`
+	OutputTokens int64   `json:"output_tokens,omitempty"`
+	CostUSD      float64 `json:"cost_usd,omitempty"`
+	DurationMS   int64   `json:"duration_ms,omitempty"`
+	Model        string  `json:"model,omitempty"` // Optional: the model that was called
+}
+
+// Validate checks no amount is negative
+func (u Usage) Validate() error {
+	if u.InputTokens < 0 || u.OutputTokens < 0 || u.CostUSD < 0 || u.DurationMS < 0 {
+		return fmt.Errorf("usage amounts can't be negative")
+	}
+	return nil
+}
+
+// Tokens returns the input and output tokens together
+func (u Usage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens
+}
+
+// Duration returns how long the call ran
+func (u Usage) Duration() time.Duration {
+	return time.Duration(u.DurationMS) * time.Millisecond
+}
+
+// Add returns the sum of two usages
+// The model is kept while the two agree, and dropped once they differ
+func (u Usage) Add(other Usage) Usage {
+	model := u.Model
+	if other.Model != model {
+		model = ""
+		if u == (Usage{}) {
+			model = other.Model
+		}
+	}
+	return Usage{
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+		CostUSD:      u.CostUSD + other.CostUSD,
+		DurationMS:   u.DurationMS + other.DurationMS,
+		Model:        model,
+	}
+}
+
+// appendProto appends the usage's protobuf encoding to b
+func (u Usage) appendProto(b []byte) []byte {
+	b = appendInt(b, 1, u.InputTokens)
+	b = appendInt(b, 2, u.OutputTokens)
+	if u.CostUSD != 0 {
+		b = appendDouble(b, 3, u.CostUSD)
+	}
+	b = appendInt(b, 4, u.DurationMS)
+	return appendString(b, 5, u.Model)
+}
+
+// readProto decodes a protobuf usage into u
+func (u *Usage) readProto(data []byte) error {
+	r := protoReader{b: data}
+	return r.fields(func(field int, v protoField) error {
+		var err error
+		switch field {
+		case 1:
+			u.InputTokens, err = v.int()
+		case 2:
+			u.OutputTokens, err = v.int()
+		case 3:
+			u.CostUSD, err = math.Float64frombits(v.varint), v.want(wireFixed64)
+		case 4:
+			u.DurationMS, err = v.int()
+		case 5:
+			u.Model, err = v.str()
+		}
+		return err
+	})
+}
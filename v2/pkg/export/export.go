@@ -0,0 +1,199 @@
+// Package export writes events as CSV or JSON Lines for spreadsheets and notebooks.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Output formats
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// DefaultFields are the columns written when no fields are selected for CSV
+var DefaultFields = []string{"id", "type", "timestamp", "pane", "message"}
+
+// Options selects and shapes the exported events
+type Options struct {
+	Format string    // FormatCSV or FormatJSONL
+	Fields []string  // Field paths (see events.Event.Field); JSONL writes whole events when empty
+	Types  []string  // Type globs to include (empty includes all)
+	Since  time.Time // Only events at or after Since (zero: no lower bound)
+	Until  time.Time // Only events before Until (zero: no upper bound)
+}
+
+// Validate checks the options
+func (o Options) Validate() error {
+	switch o.Format {
+	case FormatCSV, FormatJSONL:
+	default:
+		return fmt.Errorf("unknown format %q (want %s or %s)", o.Format, FormatCSV, FormatJSONL)
+	}
+	for _, glob := range o.Types {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid type glob %q: %w", glob, err)
+		}
+	}
+	if !o.Since.IsZero() && !o.Until.IsZero() && !o.Until.After(o.Since) {
+		return fmt.Errorf("time range is empty: until is not after since")
+	}
+	return nil
+}
+
+// Matches reports whether an event passes the type and time filters
+func (o Options) Matches(event events.Event) bool {
+	if !o.Since.IsZero() && event.Timestamp.Before(o.Since) {
+		return false
+	}
+	if !o.Until.IsZero() && !event.Timestamp.Before(o.Until) {
+		return false
+	}
+	if len(o.Types) == 0 {
+		return true
+	}
+	for _, glob := range o.Types {
+		if ok, _ := path.Match(glob, event.Type); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Writer writes matching events in the selected format
+type Writer struct {
+	opts    Options
+	csv     *csv.Writer
+	buf     *bufio.Writer
+	header  bool // CSV header written
+	written int
+}
+
+// NewWriter returns a writer for opts
+func NewWriter(w io.Writer, opts Options) (*Writer, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.Format == FormatCSV && len(opts.Fields) == 0 {
+		opts.Fields = DefaultFields
+	}
+
+	ew := &Writer{opts: opts}
+	if opts.Format == FormatCSV {
+		ew.csv = csv.NewWriter(w)
+	} else {
+		ew.buf = bufio.NewWriter(w)
+	}
+	return ew, nil
+}
+
+// Write writes the event if it passes the filters
+func (w *Writer) Write(event events.Event) error {
+	if !w.opts.Matches(event) {
+		return nil
+	}
+	w.written++
+
+	if w.csv != nil {
+		if !w.header {
+			w.header = true
+			if err := w.csv.Write(w.opts.Fields); err != nil {
+				return err
+			}
+		}
+		row := make([]string, len(w.opts.Fields))
+		for i, field := range w.opts.Fields {
+			if value, ok := event.Field(field); ok {
+				row[i] = formatValue(value)
+			}
+		}
+		return w.csv.Write(row)
+	}
+
+	var data []byte
+	var err error
+	if len(w.opts.Fields) == 0 {
+		data, err = event.ToJSON()
+	} else {
+		// Selected fields keyed by path; missing fields are null
+		obj := make(map[string]interface{}, len(w.opts.Fields))
+		for _, field := range w.opts.Fields {
+			value, _ := event.Field(field)
+			obj[field] = value
+		}
+		data, err = json.Marshal(obj)
+	}
+	if err != nil {
+		return err
+	}
+	w.buf.Write(data)
+	return w.buf.WriteByte('\n')
+}
+
+// Written returns the number of events written so far
+func (w *Writer) Written() int {
+	return w.written
+}
+
+// Flush writes buffered output
+func (w *Writer) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return w.buf.Flush()
+}
+
+// formatValue renders a field value as a CSV cell
+// Objects and arrays are written as JSON so they survive a round trip
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ParseTime parses a time bound: RFC 3339, a date (2006-01-02), or a
+// duration before now (e.g. "2h" means two hours ago)
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want RFC 3339, YYYY-MM-DD or a duration like 2h)", s)
+}
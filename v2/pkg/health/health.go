@@ -0,0 +1,109 @@
+// Package health serves liveness and readiness probes for long-running
+// components, so they can run under Kubernetes (or any supervisor that
+// polls HTTP endpoints).
+//
+// /healthz fails only when restarting the process would help (e.g. the NATS
+// connection was closed for good); /readyz also fails while the component
+// can't do useful work yet (disconnected, falling behind, backlog piling up).
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/nats-io/nats.go"
+)
+
+// Probe paths
+const (
+	LivePath  = "/healthz"
+	ReadyPath = "/readyz"
+)
+
+// Check is a named condition; Run returns nil when it holds
+type Check struct {
+	Name string
+	Run  func() error
+}
+
+// Probes holds the checks behind each endpoint
+// Readiness also runs the liveness checks
+type Probes struct {
+	Live  []Check
+	Ready []Check
+}
+
+// Register adds the probe endpoints to mux
+func (p *Probes) Register(mux *http.ServeMux) {
+	mux.HandleFunc(LivePath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, p.Live)
+	})
+	mux.HandleFunc(ReadyPath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, append(append([]Check(nil), p.Live...), p.Ready...))
+	})
+}
+
+// serve runs checks and answers 200 if all pass, 503 otherwise, with a JSON report
+func serve(w http.ResponseWriter, checks []Check) {
+	status := http.StatusOK
+	report := make(map[string]string, len(checks))
+	for _, c := range checks {
+		if err := c.Run(); err != nil {
+			status = http.StatusServiceUnavailable
+			report[c.Name] = err.Error()
+		} else {
+			report[c.Name] = "ok"
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// NATSOpen fails once the connection is closed (it won't reconnect by itself)
+func NATSOpen(nc *nats.Conn) Check {
+	return Check{Name: "nats", Run: func() error {
+		if nc.IsClosed() {
+			return errors.New("connection closed")
+		}
+		return nil
+	}}
+}
+
+// NATSConnected fails while the connection is down or reconnecting
+func NATSConnected(nc *nats.Conn) Check {
+	return Check{Name: "nats_connected", Run: func() error {
+		if !nc.IsConnected() {
+			return fmt.Errorf("not connected (%s)", nc.Status())
+		}
+		return nil
+	}}
+}
+
+// ConsumerLag fails when more than max messages wait on a subscription
+func ConsumerLag(sub *nats.Subscription, max int) Check {
+	return Check{Name: "consumer_lag", Run: func() error {
+		pending, _, err := sub.Pending()
+		if err != nil {
+			return err
+		}
+		if pending > max {
+			return fmt.Errorf("%d messages pending (max %d)", pending, max)
+		}
+		return nil
+	}}
+}
+
+// OutboxBacklog fails when more than max entries wait in the outbox
+func OutboxBacklog(ob *outbox.Outbox, max int) Check {
+	return Check{Name: "outbox_backlog", Run: func() error {
+		if n := ob.Len(); n > max {
+			return fmt.Errorf("%d entries queued (max %d)", n, max)
+		}
+		return nil
+	}}
+}
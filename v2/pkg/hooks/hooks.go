@@ -0,0 +1,269 @@
+// Package hooks runs user scripts on incoming events: computing derived
+// fields, answering trivial prompts automatically and raising alerts, for
+// customisation beyond what static transformation rules can express.
+//
+// Every .star file in the hooks directory is a hook: a Starlark script
+// defining hook(event), which gets the event as a dict and may return a
+// Result as a dict. Hooks run in file name order, each seeing the fields
+// derived by the ones before it.
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	starlarkjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// DefaultTimeout bounds how long a single hook may run
+const DefaultTimeout = 500 * time.Millisecond
+
+// Ext is the file extension of hook scripts
+const Ext = ".star"
+
+// Result is what a hook returns; every field is optional
+type Result struct {
+	Data    map[string]interface{} `json:"data,omitempty"`    // Merged into Event.Data
+	Drop    bool                   `json:"drop,omitempty"`    // Discard the event
+	Respond string                 `json:"respond,omitempty"` // ID of an action to trigger automatically
+	Alert   string                 `json:"alert,omitempty"`   // Shown in the status bar
+}
+
+// predeclared is all a hook can reach beyond the Starlark built-ins
+var predeclared = starlark.StringDict{"json": starlarkjson.Module}
+
+// Runner runs the hooks in a directory
+// AIDEV-NOTE: Hooks run in an embedded Starlark interpreter, which is the
+// sandbox: scripts have no filesystem, network, clock or environment
+// access, load() is refused and print() goes nowhere, so all a hook can do
+// is compute over the event it's given. Timeout cancels runaway loops; memory
+// isn't bounded. Module globals are frozen once the script has run, so hooks
+// keep no state between events. The directory is read on every event and
+// scripts recompiled when they change, so added, edited and removed scripts
+// take effect immediately without a restart
+type Runner struct {
+	Dir     string
+	Timeout time.Duration
+
+	mu       sync.Mutex         // Guards compiled
+	compiled map[string]*script // By path
+}
+
+// script is a compiled hook and the file version it was compiled from
+type script struct {
+	modTime time.Time
+	size    int64
+	hook    starlark.Callable // Nil when the script failed to load
+	err     error
+}
+
+// New creates a runner for the hooks in dir
+func New(dir string) (*Runner, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &Runner{Dir: dir, Timeout: DefaultTimeout}, nil
+}
+
+// Scripts returns the hooks currently in the directory, in run order
+// Hidden files, directories and files without the .star extension are skipped
+func (r *Runner) Scripts() ([]string, error) {
+	entries, err := os.ReadDir(r.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var scripts []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != Ext {
+			continue
+		}
+		scripts = append(scripts, filepath.Join(r.Dir, entry.Name()))
+	}
+	sort.Strings(scripts)
+	return scripts, nil
+}
+
+// Run passes the event through every hook
+// Returns the event with derived fields merged and the combined result:
+// Drop if any hook dropped it, the first Respond, and every Alert
+// A failing hook is skipped and reported in the error; the others still run
+// The input event is never modified; Data is copied before merging
+func (r *Runner) Run(event events.Event) (events.Event, Result, error) {
+	var combined Result
+	if r == nil {
+		return event, combined, nil
+	}
+
+	scripts, err := r.Scripts()
+	if err != nil {
+		return event, combined, err
+	}
+
+	var errs []error
+	var alerts []string
+	copied := false
+	for _, script := range scripts {
+		result, err := r.runScript(script, event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(script), err))
+			continue
+		}
+
+		if len(result.Data) > 0 {
+			if !copied {
+				data := make(map[string]interface{}, len(event.Data)+len(result.Data))
+				for k, v := range event.Data {
+					data[k] = v
+				}
+				event.Data = data
+				copied = true
+			}
+			for k, v := range result.Data {
+				event.Data[k] = v
+			}
+		}
+		if combined.Respond == "" {
+			combined.Respond = result.Respond
+		}
+		if result.Alert != "" {
+			alerts = append(alerts, result.Alert)
+		}
+		if result.Drop {
+			combined.Drop = true
+			break
+		}
+	}
+
+	combined.Alert = strings.Join(alerts, "; ")
+	return event, combined, errors.Join(errs...)
+}
+
+// runScript runs one hook on the event and decodes what it returned
+func (r *Runner) runScript(path string, event events.Event) (Result, error) {
+	var result Result
+	input, err := event.ToJSON()
+	if err != nil {
+		return result, err
+	}
+
+	thread, stop := r.thread(path)
+	defer stop()
+	hook, err := r.load(thread, path)
+	if err != nil {
+		return result, err
+	}
+
+	decoded, err := starlark.Call(thread, starlarkjson.Module.Members["decode"], starlark.Tuple{starlark.String(input)}, nil)
+	if err != nil {
+		return result, err
+	}
+	returned, err := starlark.Call(thread, hook, starlark.Tuple{decoded}, nil)
+	if err != nil {
+		return result, evalError(err)
+	}
+	if returned == starlark.None {
+		return result, nil
+	}
+	encoded, err := starlark.Call(thread, starlarkjson.Module.Members["encode"], starlark.Tuple{returned}, nil)
+	if err != nil {
+		return result, fmt.Errorf("invalid result: %w", evalError(err))
+	}
+	if err := json.Unmarshal([]byte(encoded.(starlark.String)), &result); err != nil {
+		return result, fmt.Errorf("invalid result: %s is not a dict of data, drop, respond and alert", returned.Type())
+	}
+	return result, nil
+}
+
+// thread creates the sandboxed thread running one hook, cancelled after the
+// timeout; stop releases its timer
+func (r *Runner) thread(path string) (*starlark.Thread, func()) {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	thread := &starlark.Thread{
+		Name:  filepath.Base(path),
+		Print: func(*starlark.Thread, string) {},
+		Load: func(*starlark.Thread, string) (starlark.StringDict, error) {
+			return nil, errors.New("load is not available to hooks")
+		},
+	}
+	timer := time.AfterFunc(timeout, func() {
+		thread.Cancel(fmt.Sprintf("timed out after %s", timeout))
+	})
+	return thread, func() { timer.Stop() }
+}
+
+// load returns the hook function of the script at path, compiling it
+// again if the file changed since it was last run
+func (r *Runner) load(thread *starlark.Thread, path string) (starlark.Callable, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.compiled[path]; ok && s.modTime.Equal(info.ModTime()) && s.size == info.Size() {
+		return s.hook, s.err
+	}
+
+	s := &script{modTime: info.ModTime(), size: info.Size()}
+	s.hook, s.err = compile(thread, path)
+	if r.compiled == nil {
+		r.compiled = make(map[string]*script)
+	}
+	r.compiled[path] = s
+	return s.hook, s.err
+}
+
+// compile runs a script's top level and returns its hook function
+func compile(thread *starlark.Thread, path string) (starlark.Callable, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, path, src, predeclared)
+	if err != nil {
+		return nil, evalError(err)
+	}
+	globals.Freeze()
+	hook, ok := globals["hook"].(starlark.Callable)
+	if !ok {
+		return nil, errors.New("no hook(event) function defined")
+	}
+	return hook, nil
+}
+
+// evalError reduces a Starlark error to its line and message, leaving out
+// the path and traceback, which don't fit the status bar
+func evalError(err error) error {
+	var evalErr *starlark.EvalError
+	var syntaxErr syntax.Error
+	switch {
+	case errors.As(err, &evalErr):
+		for i := range evalErr.CallStack {
+			if line := evalErr.CallStack.At(i).Pos.Line; line > 0 { // Built-ins have no position
+				return fmt.Errorf("line %d: %s", line, evalErr.Msg)
+			}
+		}
+		return errors.New(evalErr.Msg)
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("line %d: %s", syntaxErr.Pos.Line, syntaxErr.Msg)
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// writeHook writes a hook script into dir
+func writeHook(t *testing.T, dir, name, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunChainsHooks(t *testing.T) {
+	dir := t.TempDir()
+	writeHook(t, dir, "10-derive.star", `
+def hook(event):
+    return {"data": {"files": len(event["data"]["paths"])}}
+`)
+	writeHook(t, dir, "20-approve.star", `
+def hook(event):
+    if event["data"]["files"] == 1:
+        return {"respond": "approve", "alert": "one file: " + json.encode(event["data"]["paths"])}
+`)
+	writeHook(t, dir, "notes.txt", "not a hook")
+	runner, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := events.Event{ID: "e1", Type: "plan.review", Data: map[string]interface{}{"paths": []interface{}{"main.go"}}}
+	hooked, result, err := runner.Run(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hooked.Data["files"] != float64(1) || len(event.Data) != 1 {
+		t.Fatalf("derived data %v (input %v), want files merged into a copy", hooked.Data, event.Data)
+	}
+	if result.Respond != "approve" || result.Alert != `one file: ["main.go"]` || result.Drop {
+		t.Fatalf("result %+v", result)
+	}
+}
+
+func TestRunIsSandboxed(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want string
+	}{
+		"load":        {`load("os.star", "os")` + "\ndef hook(event):\n    pass\n", "load is not available"},
+		"no function": {"x = 1\n", "no hook(event) function"},
+		"syntax":      {"def hook(event)\n", "want ':'"},
+		"runtime":     {"def hook(event):\n    return event['missing']\n", "line 2: key \"missing\" not in dict"},
+		"result":      {"def hook(event):\n    return 'approve'\n", "invalid result"},
+		"timeout":     {"def hook(event):\n    for i in range(1 << 40):\n        pass\n", "timed out"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeHook(t, dir, "hook.star", tt.src)
+			runner := &Runner{Dir: dir, Timeout: 50 * time.Millisecond}
+			_, _, err := runner.Run(events.Event{ID: "e1", Type: "t"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunReloadsChangedScripts(t *testing.T) {
+	dir := t.TempDir()
+	writeHook(t, dir, "alert.star", "def hook(event):\n    return {'alert': 'first'}\n")
+	runner, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, result, _ := runner.Run(events.Event{ID: "e1"}); result.Alert != "first" {
+		t.Fatalf("alert %q, want first", result.Alert)
+	}
+
+	writeHook(t, dir, "alert.star", "def hook(event):\n    return {'alert': 'second', 'drop': True}\n")
+	if _, result, _ := runner.Run(events.Event{ID: "e2"}); result.Alert != "second" || !result.Drop {
+		t.Fatalf("result %+v after editing the script, want the new one", result)
+	}
+}
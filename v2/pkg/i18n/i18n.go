@@ -0,0 +1,198 @@
+// Package i18n translates the TUI's strings: each one has a message ID, an
+// English text built in (see messages.go) and, in a locale's catalog, a
+// translation. Catalogs are JSON objects of ID to text, loaded from
+// <dir>/<locale>.json; IDs a catalog leaves out stay in English.
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// EnvLocale picks the locale, ahead of the settings file and the LC_*/LANG variables
+const EnvLocale = "AGNETO_LOCALE"
+
+// English is the built-in locale
+const English = "en"
+
+// Catalog is a locale's translations
+type Catalog struct {
+	Locale   string
+	messages map[string]string
+}
+
+// current is the catalog T translates with (nil: English)
+var current *Catalog
+
+// Use makes T translate with a catalog; nil goes back to English
+func Use(c *Catalog) {
+	current = c
+}
+
+// Locale returns the locale T translates to
+func Locale() string {
+	if current == nil {
+		return English
+	}
+	return current.Locale
+}
+
+// T returns the message in the current locale, formatted with args as by fmt.Sprintf
+// Messages without args are returned as written, so they don't escape '%'
+func T(id string, args ...interface{}) string {
+	text, ok := "", false
+	if current != nil {
+		text, ok = current.messages[id]
+	}
+	if !ok {
+		if text, ok = messages[id]; !ok {
+			return id
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// verbPattern matches fmt verbs, with their argument index, flags, width and precision
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*(\d+|\*)?(\.\d+)?([a-zA-Z%])`)
+
+// verbs returns the verbs a format consumes arguments with, sorted
+func verbs(format string) []string {
+	var found []string
+	for _, m := range verbPattern.FindAllStringSubmatch(format, -1) {
+		if m[4] != "%" {
+			found = append(found, m[4])
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+// Parse reads a catalog, checking every ID is known and every translation
+// takes the same arguments as the English text (reordered with %[n]d if need be)
+func Parse(locale string, data []byte) (*Catalog, error) {
+	var translated map[string]string
+	if err := json.Unmarshal(data, &translated); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	for _, id := range sortedKeys(translated) {
+		english, ok := messages[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown message %q", id)
+		}
+		if want, got := verbs(english), verbs(translated[id]); !slices.Equal(want, got) {
+			return nil, fmt.Errorf("message %q: takes %s, the English text %s", id, verbList(got), verbList(want))
+		}
+	}
+	return &Catalog{Locale: locale, messages: translated}, nil
+}
+
+// verbList describes verbs for errors
+func verbList(verbs []string) string {
+	if len(verbs) == 0 {
+		return "no arguments"
+	}
+	return "%" + strings.Join(verbs, " %")
+}
+
+// Load reads a catalog file
+func Load(locale, path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(locale, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Open loads the catalog for a locale from dir, falling back from the most
+// specific name to the language: de_DE.UTF-8, de_DE, de
+// English needs no catalog and yields nil
+func Open(dir, locale string) (*Catalog, error) {
+	var err error
+	for _, name := range candidates(locale) {
+		if name == English {
+			return nil, nil
+		}
+		var c *Catalog
+		c, err = Load(name, filepath.Join(dir, name+".json"))
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return c, err
+		}
+	}
+	return nil, err
+}
+
+// candidates returns the catalog names to try for a locale, most specific first
+func candidates(locale string) []string {
+	names := []string{locale}
+	if base, _, ok := strings.Cut(locale, "."); ok {
+		names = append(names, base)
+		locale = base
+	}
+	if lang, _, ok := strings.Cut(locale, "_"); ok {
+		names = append(names, lang)
+	}
+	return names
+}
+
+// Detect picks the locale: $AGNETO_LOCALE, else the settings file's, else
+// the first of LC_ALL, LC_MESSAGES and LANG
+// explicit is false when the locale only came from the LC_*/LANG variables,
+// whose catalogs may well not exist
+func Detect(configured string) (locale string, explicit bool) {
+	if locale := os.Getenv(EnvLocale); locale != "" {
+		return locale, true
+	}
+	if configured != "" {
+		return configured, true
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		switch locale := os.Getenv(name); {
+		case locale == "":
+			continue
+		case locale == "C", locale == "POSIX", strings.HasPrefix(locale, "C."):
+			return English, false
+		default:
+			return locale, false
+		}
+	}
+	return English, false
+}
+
+// DefaultDir returns where catalogs are looked for (~/.config/agneto/locales)
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "locales"
+	}
+	return filepath.Join(dir, "agneto", "locales")
+}
+
+// Template returns the English catalog as JSON, the starting point of a translation
+func Template() ([]byte, error) {
+	return json.MarshalIndent(messages, "", "  ")
+}
+
+// sortedKeys returns a map's keys in order, so errors are deterministic
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
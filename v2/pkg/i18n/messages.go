@@ -0,0 +1,357 @@
+package i18n
+
+// messages are the English texts, by message ID
+// IDs are grouped by where the text is shown: the main screen (header, pane,
+// list, payload, actions, input, visual, pending and status) and then each
+// panel or feature
+var messages = map[string]string{
+	"error":      "Error: %v",
+	"connecting": "Connecting to NATS...",
+
+	"header.title":              "=== Agneto Split-Pane Monitor ===",
+	"header.title_workspace":    "=== Agneto Split-Pane Monitor [%s] ===",
+	"header.replaying":          "Replaying %s (read-only) | ↑/↓ or j/k: navigate | q: quit",
+	"header.listening":          "Listening for events on %s | NATS %s | control: %s | ↑/↓ or j/k: navigate | q: quit",
+	"header.from_history":       " (%d from history)",
+	"header.bus_queued":         "%d event(s) queued on the bus",
+	"header.dropped":            "%d chat, presence, approval, session or control message(s) dropped (arriving faster than handled)",
+	"header.outbox":             "Outbox: %d response(s) waiting for the broker",
+	"header.also_here":          "Also here: %s (◆ selected, ✎ drafting an answer)",
+	"header.strict":             "Strict schema mode | %d violation(s) in the %s pane",
+	"header.usage":              "Spend: %s | %s tokens from %d producer(s) | $: details",
+	"header.throttled":          "Flood protection: %s",
+	"header.throttled_producer": "%s (%s, %d dropped)",
+	"header.panes":              "Panes:",
+	"header.panes_hint":         "] and [ cycle",
+
+	"pane.left":    "Left Pane",
+	"pane.right":   "Right Pane",
+	"pane.created": "%s Pane",
+	"pane.errors":  "Schema Violations",
+	"pane.chat":    "Operator Chat",
+
+	"list.empty":         "(no events yet)",
+	"list.no_match":      "(no events match filter)",
+	"list.filter":        "%s (filter: %s)",
+	"list.hidden_types":  " [%d hidden types]",
+	"list.newer":         "  ↓ %d newer (End)",
+	"list.new_separator": " %d new ",
+
+	"payload.title":           "Event Payload",
+	"payload.title_scrolled":  "Event Payload (lines %d-%d of %d, ctrl+u/d)",
+	"payload.none_selected":   "(no event selected)",
+	"payload.empty":           "(no payload data)",
+	"payload.prompt_cut":      "… (prompt cut to leave room for the input)",
+	"payload.header_renderer": "Type: %s | Time: %s | #%s | %s (M: default)",
+	"payload.header_content":  "Type: %s | Time: %s | #%s | %s\n\n",
+	"payload.type":            "Type: %s\n",
+	"payload.message":         "Message: %s\n",
+	"payload.time":            "Time: %s\n",
+	"payload.id":              "ID: %s (#%s)\n",
+	"payload.header":          "Type: %s | Time: %s | #%s",
+	"payload.fold_hint":       " | ←/→: fold | y: copy path",
+
+	"actions.none":            "(no actions available)",
+	"actions.required":        "⚠️  Event #%d requires action  ",
+	"actions.required_queued": "⚠️  Event #%d requires action (+%d queued, tab: next)  ",
+	"actions.in_flight":       "⏳ publishing response...",
+	"actions.quick_publish":   "  Quick publish: ",
+	"actions.countdown":       "⏱ %ds → %s",
+	"actions.approvals":       "(%d/%d approvals)",
+
+	"input.mode":           "📝 INPUT MODE: %s",
+	"input.select":         "↑/↓: choose | Enter or 1-9: submit | Esc: cancel | Tab: set aside / next draft",
+	"input.confirm":        "y: yes | n: no | Esc: cancel | Tab: set aside / next draft",
+	"input.line":           "Enter: submit | Esc: cancel | Tab: set aside / next draft | Ctrl+Z/Ctrl+R: undo/redo",
+	"input.multiline":      "Alt+Enter or Ctrl+M: submit | Esc: cancel | Tab: set aside / next draft | Ctrl+Z/Ctrl+R: undo/redo",
+	"input.counter":        "%d chars, %d words",
+	"input.counter_range":  " (%d-%d chars)",
+	"input.counter_min":    " (min %d chars)",
+	"input.counter_max":    " (max %d chars)",
+	"input.yes_no":         "[y] Yes    [n] No",
+	"input.more_lines":     "↑ %d more line(s) above | ↓ %d more below",
+	"input.default_prompt": "Enter your response below:",
+
+	"visual.mode": "VISUAL: %d events",
+	"visual.help": "j/k: extend | y: yank JSON | Y: yank markdown | Esc: cancel",
+
+	"pending.one":       "⏳ %d pending decision (oldest %s%s) | P: jump to oldest",
+	"pending.many":      "⏳ %d pending decisions (oldest %s%s) | P: jump to oldest",
+	"pending.escalated": ", escalated",
+
+	"goto.placeholder":    "event ID or short hash",
+	"goto.help":           "(enter: jump, esc: cancel)",
+	"goto.found":          "jumped to event %s",
+	"goto.found_hidden":   "jumped to event %s (hidden by the list filter)",
+	"goto.ambiguous":      "%q matches %d events; type more of it",
+	"goto.searching":      "%q isn't in any pane; searching the archives...",
+	"goto.archived":       "event %s was evicted; showing it from %s",
+	"goto.archived_title": "Archived event #%s (%s)",
+	"goto.archived_hint":  "j/k, ctrl+u/d: scroll | any other key: close",
+	"goto.failed":         "searching the archives failed: %v",
+	"goto.not_found":      "no event matches %q (in the panes or the local archives)",
+
+	"status.new_pane":             "new pane %q (] and [ cycle panes)",
+	"status.pane":                 "pane: %s",
+	"status.pane_closed":          "pane %q closed after going idle",
+	"status.pane_closed_archived": "pane %q closed after going idle; its events are in %s",
+	"status.pane_archive_failed":  "pane %q is idle but stays open: archiving it failed: %v",
+	"status.timed_out":            "event %s timed out: answered %s",
+	"status.approval_no_operator": "%s needs a second operator's approval, and approvals need --operator (or $USER)",
+	"status.approval_repeated":    "you already approved %s (%d/%d); it waits for another operator",
+	"status.approved_partly":      "approved %s (%d/%d); waiting for another operator",
+	"status.approval_from":        "%s approved event %s (%d/%d)",
+	"status.approval_unsigned":    "ignored an approval by %q of event %s: not signed with $AGNETO_APPROVAL_SECRET",
+	"status.usage_reset":          "usage totals reset",
+	"status.usage_invalid":        "event %s reports negative usage; not counted",
+	"status.no_event_selected":    "no event selected",
+	"status.history_unavailable":  "history replay unavailable (%v), showing new events only",
+	"status.submit_failed":        "can't submit: %v",
+	"status.invalid_answer":       "invalid answer: %v",
+	"status.input_cancelled":      "input cancelled - your text is kept (enter on the request reopens it)",
+	"status.zoom":                 "zoom: %s",
+	"status.lanes":                "producer lanes: %s",
+	"status.notify_failed":        "notification failed: %v",
+	"status.control_error":        "control: %v",
+	"status.duplicate":            "dropped duplicate %s (idempotency key %s)",
+	"status.cancel_queued":        "broker unavailable (%v) - cancel for task %s queued in outbox, retrying",
+	"status.cancel_published":     "published cancel for task %s",
+	"status.publish_queued":       "broker unavailable (%v) - %q queued in outbox, retrying",
+	"status.published":            "published %q",
+	"status.archive_error":        "archive: %v",
+	"status.outbox_delivered":     "outbox: delivered %d queued response(s)",
+	"status.escalation_failed":    "escalation failed: %v",
+	"status.escalated":            "escalated event %s to %s",
+	"status.draft_for_decision":   "input set aside as a draft for the new decision",
+
+	"usage.title":    "Usage by session and producer (since the monitor started)",
+	"usage.none":     "No event reported usage yet. Producers add it as \"usage\": input_tokens, output_tokens, cost_usd, duration_ms.",
+	"usage.session":  "Session",
+	"usage.producer": "Producer",
+	"usage.events":   "Events",
+	"usage.input":    "Input",
+	"usage.output":   "Output",
+	"usage.cost":     "Cost",
+	"usage.duration": "Duration",
+	"usage.total":    "Total",
+	"usage.help":     "r: reset | any other key: close",
+
+	"quit.title":    "Quit with work outstanding?",
+	"quit.pending":  "Unanswered decisions (%d):",
+	"quit.waiting":  "(waiting %s)",
+	"quit.unsent":   "Responses not yet delivered (%d, kept in %s):",
+	"quit.attempts": "(%d attempt(s))",
+	"quit.retried":  "Undelivered responses are retried on the next start.",
+	"quit.answer":   "a: answer now",
+	"quit.abandon":  "x: abandon (publish abandoned responses) and quit",
+	"quit.anyway":   "Q: quit anyway",
+	"quit.cancel":   "Esc: cancel",
+
+	"settings.routes":          "Routing rules",
+	"settings.filter":          "Filter",
+	"settings.mutes":           "Muted types",
+	"settings.theme":           "Theme",
+	"settings.routes_hint":     "type-glob=pane, ... (first match wins)",
+	"settings.filter_hint":     "substring of type or message",
+	"settings.mutes_hint":      "type-glob, ... (hidden from the list)",
+	"settings.theme_hint":      "Enter or ←/→ to cycle",
+	"settings.updated":         "settings: %s updated",
+	"settings.save_failed":     "settings: save failed: %v",
+	"settings.saved":           "settings: saved to %s",
+	"settings.saved_workspace": "settings: saved workspace %[1]s (start it with --workspace %[1]s)",
+	"settings.error":           "settings: %v",
+	"settings.save_as_prompt":  "Save as workspace> ",
+	"settings.title":           "Settings",
+	"settings.workspace":       "workspace %s (%s)",
+	"settings.none":            "(none)",
+	"settings.help":            "↑/↓: move | Enter: edit | w: save to config | W: save as workspace | Esc: close",
+	"settings.help_save_as":    "Enter: save | Esc: cancel",
+	"settings.help_editing":    "Enter: apply | Esc: discard edit",
+
+	"sessions.attached_all": "attached to all sessions",
+	"sessions.attached":     "attached to session %s",
+	"sessions.title":        "Attach to a session",
+	"sessions.none":         "No active sessions yet. They appear as their producers publish (announced on %s).",
+	"sessions.by":           "by %s",
+	"sessions.ago":          "%s ago",
+	"sessions.help":         "↑/↓: select | enter: attach | a: all sessions | q: quit",
+
+	"snapshot.failed":        "snapshot failed: %v",
+	"snapshot.discarded":     "discarded the crashed session's snapshot",
+	"snapshot.restored":      "restored %d pending decision(s) from the snapshot of %s",
+	"snapshot.title":         "Restore the previous session?",
+	"snapshot.intro":         "The last run ended without quitting. Its snapshot from %s holds %d pending decision(s):",
+	"snapshot.waiting_since": "(waiting since %s)",
+	"snapshot.input_typed":   "(input, %d chars typed)",
+	"snapshot.help":          "r: restore | d: discard | q: quit (keeps the snapshot)",
+
+	"tree.no_data": "selected event has no payload data",
+	"tree.copied":  "copied %s",
+	"tree.title":   "Payload",
+	"tree.help":    "↑/↓: move | ←/→: fold/unfold | g/G: first/last | y: copy path and value | Esc: close",
+
+	"typehelp.no_docs":        "no docs link for %s",
+	"typehelp.undocumented":   "Not documented yet - add it under \"types\" in %s",
+	"typehelp.expected":       "Expected response",
+	"typehelp.titles":         "Title history of the %s pane",
+	"typehelp.help":           "any key: close",
+	"typehelp.docs":           "Docs",
+	"typehelp.help_docs":      "o: open docs | any other key: close",
+	"typehelp.answer_options": "Answer %q with one of: %s",
+	"typehelp.answer":         "Answer %q (%s)",
+	"typehelp.choose":         "Choose: %s",
+	"typehelp.informational":  "No response - informational.",
+
+	"reasons.title": "%s - why?",
+	"reasons.help":  "Space/1-9: toggle | Enter: publish %s | Esc: back",
+
+	"audit.write_failed":  "failed to write the audit log: %v",
+	"audit.empty":         "no actions taken yet",
+	"audit.evicted":       "event %s is no longer in memory",
+	"audit.export_failed": "audit export failed: %v",
+	"audit.exported":      "exported %d actions to %s",
+	"audit.title":         "Audit log",
+	"audit.count":         "%d actions",
+	"audit.approval":      "[approval %s]",
+	"audit.line":          "%s  %-16s  %s  on %s %q",
+	"audit.event":         "event %s (#%d in %s)",
+	"audit.action":        "action %s → %s",
+	"audit.input":         "input: %s",
+	"audit.reasons":       "reasons: %s",
+	"audit.approvers":     "approved by: %s",
+	"audit.deferred":      "publishing failed at first; the response was retried from the outbox",
+	"audit.help":          "↑/↓: move | enter: show event | w: export JSON | Esc: close",
+
+	"legend.no_events": "no events yet",
+	"legend.title":     "Event types",
+	"legend.count":     "%d types, %d hidden",
+	"legend.muted":     "(muted in settings)",
+	"legend.help":      "↑/↓: move | space: hide/show | o: only this | a: show all | Esc: close",
+
+	"yank.empty":  "yank: nothing selected",
+	"yank.failed": "yank failed: %v",
+	"yank.done":   "yanked %d events to the %s",
+	"yank.file":   "yanked %d events to %s (clipboard unavailable)",
+
+	"bookmark.removed":      "removed bookmark on %q",
+	"bookmark.added":        "bookmarked %q (%d bookmarks, ' to cycle)",
+	"bookmark.save_failed":  "failed to save bookmarks: %v",
+	"bookmark.none":         "no bookmarks (b to add)",
+	"bookmark.jumped":       "bookmark: %s",
+	"bookmark.no_other":     "no other bookmarked events in memory",
+	"bookmark.jump_evicted": "jump target is no longer in memory",
+	"bookmark.jump":         "jump %d/%d",
+
+	"cancel.no_event":         "cancel: no event selected",
+	"cancel.not_connected":    "cancel: not connected",
+	"cancel.arm":              "press X again within %s to cancel task %s",
+	"cancel.banner_cancelled": "⛔ TASK CANCELLED%s at %s: %s",
+	"cancel.banner_aborted":   "⛔ TASK ABORTED%s at %s: %s",
+	"cancel.dismiss":          "(esc: dismiss)",
+
+	"chat.disabled":      "chat: start the monitor with --chat",
+	"chat.not_connected": "chat: not connected",
+	"chat.prompt":        "chat (%s)> ",
+	"chat.send_failed":   "chat: send failed: %v",
+	"chat.unread":        "💬 %d unread chat message(s) | C: show chat, @: reply\n",
+
+	"copy.failed_long": "copy failed: %v (too long for the terminal clipboard; v then y writes a file)",
+	"copy.done":        "copied the %s of #%s to the %s",
+	"copy.failed":      "copy failed: %v",
+
+	"connection.reconnected":  "reconnected to NATS after %s",
+	"connection.resubscribed": "stream consumer lost while disconnected, resubscribed",
+	"connection.disconnected": "⚠ NATS disconnected%s - reconnecting for %s; no events arrive and responses wait in the outbox",
+	"connection.closed":       "✗ NATS connection closed%s - restart the monitor to reconnect",
+
+	"control.no_index":       "control: no event at index %d",
+	"control.selected_index": "control: selected event #%d",
+	"control.selected":       "control: selected event %s",
+	"control.not_found":      "control: event %s not found in %s pane",
+	"control.unknown_pane":   "control: unknown pane %q",
+	"control.pane":           "control: switched to %s pane",
+	"control.filter_cleared": "control: filter cleared",
+	"control.filter":         "control: filter set to %q",
+	"control.export_failed":  "control: export failed: %v",
+	"control.exported":       "control: exported %d events to %s",
+	"control.export_off":     "export is disabled (start the monitor with --export-dir to allow it)",
+	"control.export_path":    "%q must be a relative path inside the export directory",
+
+	"draft.stashed":            "input request %q set aside as a draft (tab switches drafts)",
+	"draft.answer_first":       "answer the pending decision before returning to a draft",
+	"draft.evicted":            "draft for %s dropped: the event is no longer in memory",
+	"draft.set_aside":          "input set aside as a draft - select it and press enter to continue",
+	"draft.save_failed":        "failed to save typed input: %v",
+	"draft.restored":           "restored the text you typed for this input before",
+	"draft.answer_first_input": "answer the pending decision before returning to this input",
+
+	"escalation.invalid":  "ignoring escalation on event %s: %v",
+	"escalation.answered": "event %s was answered elsewhere",
+
+	"hook.behind":         "hooks are %d events behind - events paused until they catch up",
+	"hook.failed":         "hook failed on %s: %v",
+	"hook.needs_approval": "hook chose %s for %s, which needs %d operators' approval; left to them",
+	"hook.answered":       "hook answered %s with %s",
+	"hook.unknown_action": "hook chose unknown action %q for %s",
+
+	"lifecycle.all":   "showing events in every state",
+	"lifecycle.state": "showing %s events (s to cycle)",
+
+	"link.none":       "no links in the selected event",
+	"link.failed":     "open %s failed: %v",
+	"link.opened":     "opened %s",
+	"link.opened_nth": "opened link %d/%d: %s (o opens the next)",
+
+	"search.cleared":   "search cleared",
+	"search.matches":   "%d match(es) for %s (n/N: next/previous)",
+	"search.none":      "no search (/ to search)",
+	"search.match":     "match for %s",
+	"search.no_match":  "no match for %s",
+	"search.wrapped":   "(wrapped)",
+	"search.bar":       "%s  [%s] (enter: done, tab: filter/highlight, ctrl+r: regex, esc: cancel)",
+	"search.substring": "substring",
+	"search.regex":     "regex",
+	"search.filter":    "filter",
+	"search.highlight": "highlight only",
+
+	"replay.bad_question": "ignoring question in event %s: %v",
+	"replay.finished":     "replay finished (space: play again)",
+	"replay.paused":       "replay paused (%gx)",
+	"replay.playing":      "replay playing at %gx",
+	"replay.bar":          "%s %s %d/%d  %s  %gx  space: play/pause | </>: speed | ←/→: step | shift+←/→: 10 | home/end",
+
+	"queue.queued":  "decision %q queued (%d waiting, tab switches)",
+	"queue.evicted": "queued decision %s dropped: the event is no longer in memory",
+	"queue.dropped": "decision %s dropped: the event was evicted",
+
+	"timeout.invalid": "ignoring timeout on event %s: %v",
+
+	"severity.all": "showing events of every severity",
+	"severity.min": "showing %s events and above (S to cycle)",
+
+	"lag.unknown": "Stream lag unknown: %v",
+	"lag.behind":  "⚠ %d event(s) behind the stream (%d pending, %d unacked) - what you see may be stale",
+	"lag.line":    "Stream lag: %d pending, %d unacked\n",
+
+	"preview.loaded": "loaded full content (%d KB)",
+	"preview.hidden": "(+%d lines",
+	"preview.load":   ", press L to load",
+
+	"outbox.queued": "broker unavailable (%v) - response queued in outbox, retrying",
+
+	"badge.answered_elsewhere": "[answered elsewhere]",
+	"badge.escalated":          "[escalated]",
+	"badge.draft":              "[draft]",
+	"badge.queued":             "[queued]",
+	"badge.streaming":          "[streaming]",
+
+	"notify.title": "Agneto: decision waiting",
+
+	"question.header":         "Question | Time: %s\n\n",
+	"question.default":        " (default, Enter)",
+	"question.answer":         "Answer: %s",
+	"question.answer_default": " (default: %v)",
+
+	"policy.status":     "policy: %s",
+	"policy.not_logged": "(not logged: %v)",
+}
@@ -0,0 +1,269 @@
+// Package metrics exposes counters, gauges and histograms for Prometheus to
+// scrape, in its text exposition format (version 0.0.4).
+//
+// AIDEV-NOTE: Written by hand rather than with client_golang, which the tree
+// doesn't vendor. It covers what the commands need: metrics with labels,
+// registered up front and rendered in registration order.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Path is where the metrics are served
+const Path = "/metrics"
+
+// ContentType is the text exposition format's media type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// LatencyBuckets are histogram bounds, in seconds, for how long people take to respond
+var LatencyBuckets = []float64{1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800, 3600}
+
+// Registry holds a component's metrics
+// Safe for concurrent use: metrics are updated from event loops and read by scrapes
+type Registry struct {
+	mu      sync.Mutex
+	metrics []*metric
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// metric kinds, as named in the format's TYPE lines
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// metric is a named family of series, one per combination of label values
+type metric struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64 // Histograms: upper bounds, ascending
+	series  map[string]*series
+}
+
+// series is a metric's value for one set of label values
+type series struct {
+	values []string
+	value  float64  // Counter or gauge value; histogram sum
+	counts []uint64 // Histograms: observations per bucket (not cumulative)
+	count  uint64   // Histograms: observations
+}
+
+// add registers a metric
+func (r *Registry) add(m *metric) *metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.metrics {
+		if existing.name == m.name {
+			panic(fmt.Sprintf("metrics: %s registered twice", m.name))
+		}
+	}
+	m.series = make(map[string]*series)
+	r.metrics = append(r.metrics, m)
+	return m
+}
+
+// get returns the series for label values, creating it
+// The caller holds r.mu
+func (m *metric) get(values []string) *series {
+	if len(values) != len(m.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", m.name, len(m.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s := m.series[key]
+	if s == nil {
+		s = &series{values: append([]string(nil), values...)}
+		if m.kind == kindHistogram {
+			s.counts = make([]uint64, len(m.buckets))
+		}
+		m.series[key] = s
+	}
+	return s
+}
+
+// Counter is a value that only goes up
+type Counter struct {
+	r *Registry
+	m *metric
+}
+
+// Counter registers a counter with the given label names
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r: r, m: r.add(&metric{name: name, help: help, kind: kindCounter, labels: labels})}
+}
+
+// Inc adds one to the series for the label values
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds v (not negative) to the series for the label values
+// A nil counter does nothing, so callers needn't check metrics are enabled
+func (c *Counter) Add(v float64, values ...string) {
+	if c == nil || v < 0 {
+		return
+	}
+	c.r.mu.Lock()
+	defer c.r.mu.Unlock()
+	c.m.get(values).value += v
+}
+
+// Gauge is a value that goes up and down
+type Gauge struct {
+	r *Registry
+	m *metric
+}
+
+// Gauge registers a gauge with the given label names
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r: r, m: r.add(&metric{name: name, help: help, kind: kindGauge, labels: labels})}
+}
+
+// Set sets the series for the label values
+// A nil gauge does nothing
+func (g *Gauge) Set(v float64, values ...string) {
+	if g == nil {
+		return
+	}
+	g.r.mu.Lock()
+	defer g.r.mu.Unlock()
+	g.m.get(values).value = v
+}
+
+// Histogram counts observations into buckets
+type Histogram struct {
+	r *Registry
+	m *metric
+}
+
+// Histogram registers a histogram with the given bucket upper bounds and label names
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	return &Histogram{r: r, m: r.add(&metric{name: name, help: help, kind: kindHistogram, labels: labels, buckets: bounds})}
+}
+
+// Observe records a value in the series for the label values
+// A nil histogram does nothing
+func (h *Histogram) Observe(v float64, values ...string) {
+	if h == nil {
+		return
+	}
+	h.r.mu.Lock()
+	defer h.r.mu.Unlock()
+	s := h.m.get(values)
+	s.value += v
+	s.count++
+	if i := sort.SearchFloat64s(h.m.buckets, v); i < len(s.counts) {
+		s.counts[i]++
+	}
+}
+
+// WriteTo writes every metric in the text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	var b strings.Builder
+	for _, m := range r.metrics {
+		m.write(&b)
+	}
+	r.mu.Unlock()
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// write renders a metric's HELP and TYPE lines and its series, sorted by label values
+func (m *metric) write(b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n", m.name, escapeHelp(m.help))
+	fmt.Fprintf(b, "# TYPE %s %s\n", m.name, m.kind)
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := m.series[key]
+		if m.kind != kindHistogram {
+			fmt.Fprintf(b, "%s%s %s\n", m.name, m.labelSet(s.values, "", ""), formatValue(s.value))
+			continue
+		}
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(b, "%s_bucket%s %d\n", m.name, m.labelSet(s.values, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", m.name, m.labelSet(s.values, "le", "+Inf"), s.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", m.name, m.labelSet(s.values, "", ""), formatValue(s.value))
+		fmt.Fprintf(b, "%s_count%s %d\n", m.name, m.labelSet(s.values, "", ""), s.count)
+	}
+}
+
+// labelSet renders {name="value",...}, with an extra label if extra isn't empty
+func (m *metric) labelSet(values []string, extra, extraValue string) string {
+	if len(values) == 0 && extra == "" {
+		return ""
+	}
+	pairs := make([]string, 0, len(values)+1)
+	for i, value := range values {
+		pairs = append(pairs, m.labels[i]+`="`+labelEscaper.Replace(value)+`"`)
+	}
+	if extra != "" {
+		pairs = append(pairs, extra+`="`+extraValue+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelEscaper escapes backslashes, quotes and newlines in label values
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeHelp escapes backslashes and newlines in HELP text
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// formatValue renders a sample value
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Handler serves the metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		r.WriteTo(w)
+	})
+}
+
+// Serve listens on addr and serves the metrics at Path in the background
+// Returns once listening, so a bad address is reported at startup
+func (r *Registry) Serve(addr string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(Path, r.Handler())
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	return srv, nil
+}
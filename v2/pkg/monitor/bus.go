@@ -0,0 +1,158 @@
+// Package monitor is the internal event bus between event sources and the
+// parts of a monitor that consume them.
+//
+// Sources (a NATS subscription, a tailed file, ...) publish raw payloads onto
+// the bus; sinks receive them in a single global order. Keeping decoding out
+// of the sources means every source gets the same strict-mode checks,
+// transforms and routing downstream.
+package monitor
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultBuffer is the number of messages the bus holds before sources block
+const DefaultBuffer = 64
+
+// Message is a raw event payload on the bus
+type Message struct {
+	Seq        uint64    // Bus order, starting at 1
+	Source     string    // Source name (e.g. "nats", "tail")
+	Subject    string    // Where the payload came from (NATS subject, file path)
+	Data       []byte    // JSON event payload
+	ReceivedAt time.Time // When the bus accepted the message
+}
+
+// Sink consumes messages in bus order
+// Deliver may block; the bus applies backpressure to sources meanwhile
+type Sink interface {
+	Deliver(msg Message)
+}
+
+// ChanSink delivers messages to a channel
+type ChanSink chan Message
+
+// Deliver sends msg on the channel
+func (c ChanSink) Deliver(msg Message) {
+	c <- msg
+}
+
+// Stats is a snapshot of bus counters
+type Stats struct {
+	Received  map[string]uint64 // Messages accepted, by source
+	Delivered uint64            // Messages handed to every sink
+	Queued    int               // Messages waiting in the buffer
+}
+
+// Bus orders messages from any number of sources and fans them out to sinks
+type Bus struct {
+	in    chan Message
+	done  chan struct{}
+	sinks []Sink
+
+	pubMu sync.Mutex // Held while publishing so Seq order is queue order
+	seq   uint64
+
+	mu        sync.Mutex
+	received  map[string]uint64
+	delivered uint64
+	closed    bool
+}
+
+// New creates a bus buffering up to buffer messages (DefaultBuffer if <= 0)
+func New(buffer int) *Bus {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	return &Bus{
+		in:       make(chan Message, buffer),
+		done:     make(chan struct{}),
+		received: make(map[string]uint64),
+	}
+}
+
+// AddSink registers a sink; call before Start
+func (b *Bus) AddSink(s Sink) {
+	b.sinks = append(b.sinks, s)
+}
+
+// Start begins delivering messages to the sinks
+func (b *Bus) Start() {
+	go b.dispatch()
+}
+
+// Publish queues a message from source, blocking while the buffer is full
+// Returns false once the bus is closed
+func (b *Bus) Publish(source, subject string, data []byte) bool {
+	b.pubMu.Lock()
+	defer b.pubMu.Unlock()
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return false
+	}
+	b.seq++
+	b.received[source]++
+	b.mu.Unlock()
+
+	msg := Message{
+		Seq:        b.seq,
+		Source:     source,
+		Subject:    subject,
+		Data:       data,
+		ReceivedAt: time.Now(),
+	}
+	select {
+	case b.in <- msg:
+		return true
+	case <-b.done:
+		return false
+	}
+}
+
+// Stats returns the current counters
+func (b *Bus) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	received := make(map[string]uint64, len(b.received))
+	for source, n := range b.received {
+		received[source] = n
+	}
+	return Stats{
+		Received:  received,
+		Delivered: b.delivered,
+		Queued:    len(b.in),
+	}
+}
+
+// Close stops the bus; blocked publishers return false
+// Messages still queued are discarded
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.done)
+}
+
+// dispatch hands each message to every sink in order
+func (b *Bus) dispatch() {
+	for {
+		select {
+		case msg := <-b.in:
+			for _, s := range b.sinks {
+				s.Deliver(msg)
+			}
+			b.mu.Lock()
+			b.delivered++
+			b.mu.Unlock()
+		case <-b.done:
+			return
+		}
+	}
+}
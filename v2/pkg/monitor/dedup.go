@@ -0,0 +1,47 @@
+package monitor
+
+// DefaultDedupWindow is how many recent idempotency keys a Dedup remembers
+const DefaultDedupWindow = 4096
+
+// Dedup remembers recent idempotency keys to drop repeated events
+// Only the last window keys are kept, so memory stays bounded; a retry
+// arriving after that many newer events is not recognized
+type Dedup struct {
+	window int
+	keys   map[string]bool
+	order  []string // Ring of remembered keys, oldest at next
+	next   int
+}
+
+// NewDedup creates a Dedup remembering window keys (DefaultDedupWindow if <= 0)
+func NewDedup(window int) *Dedup {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	return &Dedup{
+		window: window,
+		keys:   make(map[string]bool, window),
+		order:  make([]string, 0, window),
+	}
+}
+
+// Seen reports whether key was seen before, remembering it if not
+// Empty keys are never duplicates
+func (d *Dedup) Seen(key string) bool {
+	if key == "" {
+		return false
+	}
+	if d.keys[key] {
+		return true
+	}
+
+	if len(d.order) < d.window {
+		d.order = append(d.order, key)
+	} else {
+		delete(d.keys, d.order[d.next])
+		d.order[d.next] = key
+		d.next = (d.next + 1) % d.window
+	}
+	d.keys[key] = true
+	return false
+}
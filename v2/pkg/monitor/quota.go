@@ -0,0 +1,219 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Quota modes: what happens to a producer's events past its rate
+const (
+	QuotaSample = "sample" // Keep one in SampleEvery
+	QuotaMute   = "mute"   // Drop them all for MuteSeconds
+)
+
+// Quota defaults
+const (
+	DefaultSampleEvery = 10
+	DefaultMuteSeconds = 30
+)
+
+// quotaWindow is the interval rates are counted over
+const quotaWindow = time.Second
+
+// Quota limits how many events per second each producer (the events' source)
+// gets through, so an agent stuck in a logging loop can't bury everything else
+type Quota struct {
+	Rate        int            `json:"rate"`                   // Events per second per producer (0: unlimited)
+	Mode        string         `json:"mode,omitempty"`         // Past the rate: "sample" (default) or "mute"
+	SampleEvery int            `json:"sample_every,omitempty"` // sample: keep one in this many (default 10)
+	MuteSeconds int            `json:"mute_seconds,omitempty"` // mute: drop everything for at least this long (default 30)
+	Sources     map[string]int `json:"sources,omitempty"`      // Rates of particular producers, overriding Rate (0: unlimited)
+}
+
+// Validate checks the quota's mode and numbers
+func (q Quota) Validate() error {
+	switch q.Mode {
+	case "", QuotaSample, QuotaMute:
+	default:
+		return fmt.Errorf("invalid quota mode %q (want %s or %s)", q.Mode, QuotaSample, QuotaMute)
+	}
+	if q.Rate < 0 || q.SampleEvery < 0 || q.MuteSeconds < 0 {
+		return fmt.Errorf("quota rate, sample_every and mute_seconds can't be negative")
+	}
+	for source, rate := range q.Sources {
+		if rate < 0 {
+			return fmt.Errorf("quota rate of %q can't be negative", source)
+		}
+	}
+	return nil
+}
+
+// QuotaChange reports a producer going over its quota, or back under it
+type QuotaChange struct {
+	Source    string
+	Throttled bool   // true: went over; false: back under
+	Rate      int    // The producer's rate
+	Mode      string // What is done with its excess events
+	Dropped   int    // Events dropped while throttled (when back under)
+}
+
+// ThrottledProducer is a producer currently over its quota
+type ThrottledProducer struct {
+	Source  string
+	Mode    string
+	Dropped int
+}
+
+// producerQuota is a producer's count in the current window
+type producerQuota struct {
+	windowStart time.Time
+	count       int       // Events in the window starting at windowStart
+	lastCount   int       // Events in the window before (0 if it was further back)
+	throttled   bool      // Over quota since the window it went over in
+	mutedUntil  time.Time // mute: earliest time the producer may come back
+	seen        int       // sample: events since throttled, to keep one in SampleEvery
+	dropped     int       // Events dropped since throttled
+}
+
+// Limiter applies a quota to incoming events
+// Not safe for concurrent use: monitors call it from their event loop
+type Limiter struct {
+	quota     Quota
+	producers map[string]*producerQuota
+}
+
+// NewLimiter creates a limiter for a validated quota
+func NewLimiter(q Quota) *Limiter {
+	if q.Mode == "" {
+		q.Mode = QuotaSample
+	}
+	if q.SampleEvery <= 0 {
+		q.SampleEvery = DefaultSampleEvery
+	}
+	if q.MuteSeconds <= 0 {
+		q.MuteSeconds = DefaultMuteSeconds
+	}
+	return &Limiter{quota: q, producers: make(map[string]*producerQuota)}
+}
+
+// Action describes what is done with a throttled producer's events
+func (l *Limiter) Action() string {
+	if l.quota.Mode == QuotaMute {
+		return fmt.Sprintf("muted for at least %ds", l.quota.MuteSeconds)
+	}
+	return fmt.Sprintf("keeping 1 in %d of its events", l.quota.SampleEvery)
+}
+
+// rate returns a producer's events per second (0: unlimited)
+func (l *Limiter) rate(source string) int {
+	if rate, ok := l.quota.Sources[source]; ok {
+		return rate
+	}
+	return l.quota.Rate
+}
+
+// Admit counts an event from source and reports whether it is let through,
+// and whether the producer just went over (or came back under) its quota
+// Exempt events (decisions waiting for an operator) are counted but always let through
+// A nil limiter lets everything through
+func (l *Limiter) Admit(source string, exempt bool, now time.Time) (bool, *QuotaChange) {
+	if l == nil {
+		return true, nil
+	}
+	rate := l.rate(source)
+	if rate <= 0 {
+		return true, nil
+	}
+	p := l.producers[source]
+	if p == nil {
+		p = &producerQuota{windowStart: now}
+		l.producers[source] = p
+	}
+
+	var change *QuotaChange
+	if elapsed := now.Sub(p.windowStart); elapsed >= quotaWindow {
+		p.lastCount = p.count
+		if elapsed >= 2*quotaWindow {
+			p.lastCount = 0 // A whole window went by without events
+		}
+		p.windowStart, p.count = now, 0
+		if p.throttled && p.lastCount <= rate && !now.Before(p.mutedUntil) {
+			change = l.recover(source, p)
+		}
+	}
+	p.count++
+
+	if !p.throttled && p.count > rate {
+		p.throttled, p.seen, p.dropped = true, 0, 0
+		p.mutedUntil = now.Add(time.Duration(l.quota.MuteSeconds) * time.Second)
+		if l.quota.Mode != QuotaMute {
+			p.mutedUntil = time.Time{}
+		}
+		change = &QuotaChange{Source: source, Throttled: true, Rate: rate, Mode: l.quota.Mode}
+	}
+	if !p.throttled || exempt {
+		return true, change
+	}
+	if l.quota.Mode == QuotaSample {
+		p.seen++
+		if (p.seen-1)%l.quota.SampleEvery == 0 {
+			return true, change
+		}
+	}
+	p.dropped++
+	return false, change
+}
+
+// recover takes a producer out of throttling
+func (l *Limiter) recover(source string, p *producerQuota) *QuotaChange {
+	change := &QuotaChange{Source: source, Rate: l.rate(source), Mode: l.quota.Mode, Dropped: p.dropped}
+	p.throttled, p.seen, p.dropped = false, 0, 0
+	return change
+}
+
+// Sweep brings back producers that went quiet while throttled, which Admit
+// only notices when their next event arrives; call it periodically
+func (l *Limiter) Sweep(now time.Time) []QuotaChange {
+	if l == nil {
+		return nil
+	}
+	var changes []QuotaChange
+	for _, source := range l.sources() {
+		p := l.producers[source]
+		quiet := now.Sub(p.windowStart) >= 2*quotaWindow ||
+			now.Sub(p.windowStart) >= quotaWindow && p.count <= l.rate(source)
+		if p.throttled && quiet && !now.Before(p.mutedUntil) {
+			changes = append(changes, *l.recover(source, p))
+		}
+		// Producers quiet for long are forgotten, so memory stays bounded
+		if !p.throttled && now.Sub(p.windowStart) >= time.Minute {
+			delete(l.producers, source)
+		}
+	}
+	return changes
+}
+
+// Throttled returns the producers currently over their quota, by source
+func (l *Limiter) Throttled() []ThrottledProducer {
+	if l == nil {
+		return nil
+	}
+	var throttled []ThrottledProducer
+	for _, source := range l.sources() {
+		if p := l.producers[source]; p.throttled {
+			throttled = append(throttled, ThrottledProducer{Source: source, Mode: l.quota.Mode, Dropped: p.dropped})
+		}
+	}
+	return throttled
+}
+
+// sources returns the tracked producers in order
+func (l *Limiter) sources() []string {
+	sources := make([]string, 0, len(l.producers))
+	for source := range l.producers {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
@@ -0,0 +1,353 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transport"
+	"github.com/nats-io/nats.go"
+)
+
+// Source feeds messages onto a bus
+// Start returns once the source is receiving; stop releases it
+type Source interface {
+	Name() string
+	Start(bus *Bus) (stop func(), err error)
+}
+
+// TransportSource publishes every message on a subject of a transport to the bus
+type TransportSource struct {
+	Transport transport.Transport
+	Subject   string
+	Label     string // Source name (default "live")
+}
+
+// Name returns the label
+func (s TransportSource) Name() string {
+	if s.Label == "" {
+		return "live"
+	}
+	return s.Label
+}
+
+// Start subscribes to the subject
+// AIDEV-NOTE: The handler blocks while the bus is full; the transport then
+// queues up to the subscription's pending limits before dropping messages
+func (s TransportSource) Start(bus *Bus) (func(), error) {
+	sub, err := s.Transport.Subscribe(s.Subject, func(msg transport.Msg) {
+		if msg.Header.Get(events.ProbeHeader) != "" {
+			return
+		}
+		publish(bus, s.Name(), msg.Subject, msg.Header.Get(events.ContentTypeHeader), msg.Data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() { sub.Unsubscribe() }, nil
+}
+
+// NATSSource publishes every message on a NATS subject to the bus
+type NATSSource struct {
+	Conn    *nats.Conn
+	Subject string
+}
+
+// Name returns "nats"
+func (s NATSSource) Name() string {
+	return "nats"
+}
+
+// Start subscribes to the subject
+func (s NATSSource) Start(bus *Bus) (func(), error) {
+	return TransportSource{Transport: transport.NATS{Conn: s.Conn}, Subject: s.Subject, Label: s.Name()}.Start(bus)
+}
+
+// publish puts a message's event on the bus, or each event of a batch
+// envelope in order (see events.Batch)
+// Returns false when the bus closed before it accepted them all
+func publish(bus *Bus, source, subject, contentType string, data []byte) bool {
+	data = payload(contentType, data)
+	batch, ok := events.SplitBatch(data)
+	if !ok {
+		return bus.Publish(source, subject, data)
+	}
+	for _, event := range batch {
+		if !bus.Publish(source, subject, event) {
+			return false
+		}
+	}
+	return true
+}
+
+// payload returns a message's event as JSON, converting protobuf payloads
+// (see events.ContentTypeProto) so sinks only ever see JSON
+// A payload that doesn't decode is passed on as is, for sinks to report
+func payload(contentType string, data []byte) []byte {
+	if !events.IsProto(contentType) {
+		return data
+	}
+	event, err := events.FromProto(data)
+	if err != nil {
+		return data
+	}
+	converted, err := event.ToJSON()
+	if err != nil {
+		return data
+	}
+	return converted
+}
+
+// DefaultTailInterval is how often a TailSource checks its file for new lines
+const DefaultTailInterval = 500 * time.Millisecond
+
+// TailSource follows a JSON Lines file like tail -f, publishing each new line
+// Lines already in the file when it starts are skipped; a truncated or
+// replaced file is read again from the start
+type TailSource struct {
+	Path     string
+	Interval time.Duration // Defaults to DefaultTailInterval
+}
+
+// Name returns "tail"
+func (s TailSource) Name() string {
+	return "tail"
+}
+
+// Start opens the file and follows it from its current end
+func (s TailSource) Start(bus *Bus) (func(), error) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultTailInterval
+	}
+
+	done := make(chan struct{})
+	go s.follow(bus, f, offset, interval, done)
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }, nil
+}
+
+// follow polls the file, publishing complete lines
+func (s TailSource) follow(bus *Bus, f *os.File, offset int64, interval time.Duration, done chan struct{}) {
+	defer func() { f.Close() }() // f changes when the file is reopened
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var partial []byte
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		// Start over when the file was truncated or replaced (log rotation)
+		if info, err := os.Stat(s.Path); err == nil {
+			current, _ := f.Stat()
+			if current == nil || !os.SameFile(info, current) || info.Size() < offset {
+				if next, err := os.Open(s.Path); err == nil {
+					f.Close()
+					f, offset, partial = next, 0, nil
+				}
+			}
+		}
+
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			continue
+		}
+		r := bufio.NewReader(f)
+		for {
+			line, err := r.ReadBytes('\n')
+			offset += int64(len(line))
+			if err != nil {
+				// Keep an incomplete last line until the writer finishes it
+				partial = append(partial, line...)
+				break
+			}
+			line = bytes.TrimSpace(append(partial, line...))
+			partial = nil
+			if len(line) == 0 {
+				continue
+			}
+			if !publish(bus, s.Name(), s.Path, "", line) {
+				return
+			}
+		}
+	}
+}
+
+// Lag is how far a stream consumer is behind its stream
+type Lag struct {
+	Pending    uint64 // Stored messages not delivered yet
+	AckPending int    // Delivered but not acknowledged yet (still on their way to the bus)
+}
+
+// Behind returns the number of stored messages the monitor hasn't taken yet
+func (l Lag) Behind() uint64 {
+	return l.Pending + uint64(l.AckPending)
+}
+
+// LagReporter is implemented by sources reading from a JetStream stream
+// Lag asks the server, so call it off the UI goroutine
+type LagReporter interface {
+	Lag() (Lag, error)
+}
+
+// consumerLag reads a subscription's consumer lag from the server
+func consumerLag(sub *nats.Subscription) (Lag, error) {
+	if sub == nil {
+		return Lag{}, errors.New("not started")
+	}
+	info, err := sub.ConsumerInfo()
+	if err != nil {
+		return Lag{}, err
+	}
+	return Lag{Pending: info.NumPending, AckPending: info.NumAckPending}, nil
+}
+
+// JetStreamSource reads a subject through a durable consumer on a stream
+// Unlike NATSSource it resumes after a disconnect or restart where it left
+// off, so events stored while the monitor was away are not missed
+type JetStreamSource struct {
+	JS      nats.JetStreamContext
+	Stream  string // Stream holding the subject
+	Subject string
+	Durable string // Consumer name; one per monitor instance
+
+	sub *nats.Subscription
+}
+
+// Name returns "jetstream"
+func (s *JetStreamSource) Name() string {
+	return "jetstream"
+}
+
+// Start binds to the stream; a new consumer starts with the next event
+// Messages are acknowledged once the bus accepted them
+func (s *JetStreamSource) Start(bus *Bus) (func(), error) {
+	sub, err := s.JS.Subscribe(s.Subject, func(msg *nats.Msg) {
+		if msg.Header.Get(events.ProbeHeader) != "" {
+			msg.Ack()
+			return
+		}
+		if publish(bus, s.Name(), msg.Subject, msg.Header.Get(events.ContentTypeHeader), msg.Data) {
+			msg.Ack()
+		}
+	}, nats.BindStream(s.Stream), nats.Durable(s.Durable), nats.DeliverNew(), nats.ManualAck())
+	if err != nil {
+		return nil, err
+	}
+	s.sub = sub
+	// AIDEV-NOTE: Unsubscribing would delete the durable consumer and with it
+	// the position to resume from; closing the connection keeps it
+	return func() {}, nil
+}
+
+// Lag reports how far the durable consumer is behind the stream
+func (s *JetStreamSource) Lag() (Lag, error) {
+	return consumerLag(s.sub)
+}
+
+// DefaultHistoryStream is the stream a HistorySource creates when no stream
+// holds its subject yet
+const DefaultHistoryStream = "AGNETO_EVENTS"
+
+// DefaultHistoryMaxMsgs bounds the stream a HistorySource creates
+const DefaultHistoryMaxMsgs = 10000
+
+// HistorySource replays recent events from a JetStream stream, then keeps
+// delivering new ones, so a monitor started late still sees what led up to now
+// Replayed messages are published as source "history", later ones as "nats"
+// The consumer is ephemeral: every start replays the same window again
+type HistorySource struct {
+	JS      nats.JetStreamContext
+	Subject string
+	Stream  string    // Stream to create if none holds Subject (default DefaultHistoryStream)
+	Last    int       // Replay the last Last messages of the stream (0: only new ones)
+	Since   time.Time // Replay from this time instead of Last, when set
+
+	sub *nats.Subscription
+}
+
+// Name returns "history"
+func (s *HistorySource) Name() string {
+	return "history"
+}
+
+// Start finds or creates the stream and subscribes from the replay start
+// AIDEV-NOTE: Last counts stream sequences, so on a stream shared with other
+// subjects it replays fewer than Last events of this subject
+func (s *HistorySource) Start(bus *Bus) (func(), error) {
+	stream, err := s.JS.StreamNameBySubject(s.Subject)
+	if errors.Is(err, nats.ErrStreamNotFound) || errors.Is(err, nats.ErrNoMatchingStream) {
+		stream = s.Stream
+		if stream == "" {
+			stream = DefaultHistoryStream
+		}
+		_, err = s.JS.AddStream(&nats.StreamConfig{
+			Name:     stream,
+			Subjects: []string{s.Subject},
+			Storage:  nats.FileStorage,
+			MaxMsgs:  DefaultHistoryMaxMsgs,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.JS.StreamInfo(stream)
+	if err != nil {
+		return nil, err
+	}
+	last := info.State.LastSeq // Messages up to here are history
+
+	start := nats.DeliverNew()
+	switch {
+	case !s.Since.IsZero():
+		start = nats.StartTime(s.Since)
+	case s.Last > 0 && last > 0:
+		first := info.State.FirstSeq
+		if last-first+1 > uint64(s.Last) {
+			first = last - uint64(s.Last) + 1
+		}
+		start = nats.StartSequence(first)
+	}
+
+	sub, err := s.JS.Subscribe(s.Subject, func(msg *nats.Msg) {
+		if msg.Header.Get(events.ProbeHeader) != "" {
+			return
+		}
+		source := "nats"
+		if meta, err := msg.Metadata(); err == nil && meta.Sequence.Stream <= last {
+			source = s.Name()
+		}
+		publish(bus, source, msg.Subject, msg.Header.Get(events.ContentTypeHeader), msg.Data)
+	}, nats.BindStream(stream), nats.OrderedConsumer(), start)
+	if err != nil {
+		return nil, err
+	}
+	s.sub = sub
+	return func() { sub.Unsubscribe() }, nil
+}
+
+// Lag reports how much of the stream is still to be delivered
+func (s *HistorySource) Lag() (Lag, error) {
+	return consumerLag(s.sub)
+}
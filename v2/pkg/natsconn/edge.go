@@ -0,0 +1,50 @@
+package natsconn
+
+import (
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/outbox"
+	"github.com/nats-io/nats.go"
+)
+
+// AIDEV-NOTE: Edge mode targets operators on flaky links. The monitor talks to
+// a leafnode on the same machine, which runs JetStream with a stream (named
+// by NATS_EDGE_STREAM) capturing the events subject and sourcing it from the
+// hub. Core NATS over a leafnode drops messages while the upstream link is
+// down; the stream is what stores them until the link returns. So in edge
+// mode responses count as delivered once the local stream acknowledged them,
+// and events are read through a durable consumer that resumes where it
+// stopped instead of a plain subscription.
+
+// edgeOptions keeps reconnecting to the local leafnode instead of giving up
+func edgeOptions() []nats.Option {
+	return []nats.Option{
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(time.Second),
+	}
+}
+
+// StreamPublisher publishes through JetStream, returning once the stream stored the message
+// It satisfies outbox.Publisher, so outbox entries are only dropped after the ack
+type StreamPublisher struct {
+	JS nats.JetStreamContext
+}
+
+// Publish publishes data and waits for the stream's acknowledgement
+func (p StreamPublisher) Publish(subject string, data []byte) error {
+	_, err := p.JS.Publish(subject, data)
+	return err
+}
+
+// Publisher returns what durable publishes should go through: the edge
+// stream when configured, otherwise the connection itself
+func (s Settings) Publisher(nc *nats.Conn) (outbox.Publisher, error) {
+	if s.EdgeStream == "" {
+		return nc, nil
+	}
+	js, err := nc.JetStream(nats.MaxWait(2 * time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return StreamPublisher{JS: js}, nil
+}
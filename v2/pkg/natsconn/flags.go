@@ -0,0 +1,40 @@
+package natsconn
+
+import (
+	"flag"
+	"os"
+)
+
+// Flag is a command-line flag overriding one of the environment variables Load reads
+type Flag struct {
+	Name  string
+	Env   string
+	Usage string
+}
+
+// Flags are the connection flags every agneto command accepts
+var Flags = []Flag{
+	{"server", EnvURL, "NATS server URL"},
+	{"creds", EnvCreds, "Path to a .creds file (JWT + nkey seed)"},
+	{"token", EnvToken, "Auth token"},
+	{"user", EnvUser, "Username (with --password)"},
+	{"password", EnvPassword, "Password"},
+	{"tls-ca", EnvCA, "PEM file of root CAs to trust"},
+	{"tls-cert", EnvCert, "Client certificate for mutual TLS"},
+	{"tls-key", EnvKey, "Client key for mutual TLS"},
+	{"edge-stream", EnvEdgeStream, "JetStream stream on a local leafnode buffering events and responses"},
+}
+
+// AddFlags registers the connection flags on fs
+// A flag given on the command line sets its environment variable, so Load
+// (and any command started from this process) sees it; flags default to the
+// environment
+func AddFlags(fs *flag.FlagSet) {
+	for _, f := range Flags {
+		env := f.Env
+		usage := f.Usage + " (default $" + env + ")"
+		fs.Func(f.Name, usage, func(value string) error {
+			return os.Setenv(env, value)
+		})
+	}
+}
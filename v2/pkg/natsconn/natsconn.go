@@ -0,0 +1,97 @@
+// Package natsconn builds NATS connection settings shared by every agneto
+// command, so credentials and TLS work the same in the TUI, publisher and doctor.
+package natsconn
+
+import (
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Environment variables read by Load
+const (
+	EnvURL      = "NATS_URL"      // Server URL (default nats://localhost:4222)
+	EnvCreds    = "NATS_CREDS"    // Path to a .creds file (JWT + nkey seed)
+	EnvToken    = "NATS_TOKEN"    // Auth token
+	EnvUser     = "NATS_USER"     // Username (with NATS_PASSWORD)
+	EnvPassword = "NATS_PASSWORD" // Password
+	EnvCA       = "NATS_TLS_CA"   // PEM file of root CAs to trust
+	EnvCert     = "NATS_TLS_CERT" // Client certificate for mutual TLS
+	EnvKey      = "NATS_TLS_KEY"  // Client key for mutual TLS
+
+	EnvEdgeStream = "NATS_EDGE_STREAM" // JetStream stream on a local leafnode buffering events and responses (see edge.go)
+)
+
+// Settings describes how to connect to NATS
+type Settings struct {
+	URL      string
+	Creds    string
+	Token    string
+	User     string
+	Password string
+	CA       string
+	Cert     string
+	Key      string
+	Timeout  time.Duration
+
+	EdgeStream string // Store-and-forward stream on a local leafnode (empty: plain core NATS)
+}
+
+// Load reads settings from the environment
+func Load() Settings {
+	url := os.Getenv(EnvURL)
+	if url == "" {
+		url = nats.DefaultURL // localhost:4222
+	}
+	return Settings{
+		URL:      url,
+		Creds:    os.Getenv(EnvCreds),
+		Token:    os.Getenv(EnvToken),
+		User:     os.Getenv(EnvUser),
+		Password: os.Getenv(EnvPassword),
+		CA:       os.Getenv(EnvCA),
+		Cert:     os.Getenv(EnvCert),
+		Key:      os.Getenv(EnvKey),
+		Timeout:  5 * time.Second,
+
+		EdgeStream: os.Getenv(EnvEdgeStream),
+	}
+}
+
+// UsesTLS reports whether any TLS material is configured
+func (s Settings) UsesTLS() bool {
+	return s.CA != "" || s.Cert != "" || s.Key != ""
+}
+
+// Options converts settings into nats.Options for a named client
+func (s Settings) Options(name string) []nats.Option {
+	opts := []nats.Option{nats.Name(name)}
+	if s.Timeout > 0 {
+		opts = append(opts, nats.Timeout(s.Timeout))
+	}
+	if s.Creds != "" {
+		opts = append(opts, nats.UserCredentials(s.Creds))
+	}
+	if s.Token != "" {
+		opts = append(opts, nats.Token(s.Token))
+	}
+	if s.User != "" {
+		opts = append(opts, nats.UserInfo(s.User, s.Password))
+	}
+	if s.CA != "" {
+		opts = append(opts, nats.RootCAs(s.CA))
+	}
+	if s.Cert != "" || s.Key != "" {
+		opts = append(opts, nats.ClientCert(s.Cert, s.Key))
+	}
+	if s.EdgeStream != "" {
+		opts = append(opts, edgeOptions()...)
+	}
+	return opts
+}
+
+// Connect connects to NATS using the settings
+func (s Settings) Connect(name string, extra ...nats.Option) (*nats.Conn, error) {
+	return nats.Connect(s.URL, append(s.Options(name), extra...)...)
+}
@@ -0,0 +1,211 @@
+// Package outbox is a disk-backed queue for outgoing publishes.
+//
+// Everything a human decided (action responses, typed answers, escalations)
+// is written to the outbox before it is published and removed only after the
+// broker confirmed it, so a transient broker failure delays a decision
+// instead of losing it. Entries are published strictly in enqueue order: a
+// failed entry blocks the ones behind it until it goes through.
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// flushTimeout bounds how long a publish waits for the broker to confirm
+const flushTimeout = 2 * time.Second
+
+// Entry is a queued publish
+type Entry struct {
+	Seq        uint64          `json:"seq"`                  // Enqueue order
+	Subject    string          `json:"subject"`              // Target subject
+	Data       json.RawMessage `json:"data"`                 // Payload (a JSON event)
+	EnqueuedAt time.Time       `json:"enqueued_at"`          // When the entry was queued
+	Attempts   int             `json:"attempts"`             // Failed publish attempts so far
+	LastError  string          `json:"last_error,omitempty"` // Error of the last failed attempt
+}
+
+// Publisher sends a message to a subject (satisfied by *nats.Conn)
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// flusher is implemented by publishers that buffer writes (e.g. *nats.Conn)
+// The outbox only drops an entry once the buffer reached the broker
+type flusher interface {
+	FlushTimeout(timeout time.Duration) error
+}
+
+// Outbox is a directory of queued entries, one JSON file per entry
+type Outbox struct {
+	dir string
+	mu  sync.Mutex // Serializes enqueue and flush so ordering holds within a process
+	seq uint64
+}
+
+// DefaultDir returns the outbox directory of a monitor instance
+// (~/.config/agneto/outbox/<instance>)
+func DefaultDir(instance string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join("agneto-outbox", instance)
+	}
+	return filepath.Join(dir, "agneto", "outbox", instance)
+}
+
+// Open opens (creating if needed) the outbox in dir
+func Open(dir string) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	o := &Outbox{dir: dir}
+
+	// Continue numbering after entries left by a previous run
+	entries, err := o.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		o.seq = entries[len(entries)-1].Seq
+	}
+	return o, nil
+}
+
+// Dir returns the outbox directory
+func (o *Outbox) Dir() string {
+	return o.dir
+}
+
+// Enqueue durably queues a publish and returns its entry
+func (o *Outbox) Enqueue(subject string, data []byte) (Entry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.seq++
+	entry := Entry{
+		Seq:        o.seq,
+		Subject:    subject,
+		Data:       json.RawMessage(data),
+		EnqueuedAt: time.Now(),
+	}
+	return entry, o.write(entry)
+}
+
+// List returns queued entries in publish order
+func (o *Outbox) List() ([]Entry, error) {
+	names, err := filepath.Glob(filepath.Join(o.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names) // Zero-padded sequence numbers sort in order
+
+	entries := make([]Entry, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("%s: invalid entry: %w", name, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Len returns the number of queued entries
+func (o *Outbox) Len() int {
+	names, _ := filepath.Glob(filepath.Join(o.dir, "*.json"))
+	return len(names)
+}
+
+// Flush publishes queued entries in order, removing each once the broker has it
+// Stops at the first failure (recording it on the entry) to preserve ordering
+// Returns the number of entries sent
+func (o *Outbox) Flush(pub Publisher) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entries, err := o.List()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, entry := range entries {
+		err := pub.Publish(entry.Subject, entry.Data)
+		if f, ok := pub.(flusher); ok && err == nil {
+			err = f.FlushTimeout(flushTimeout)
+		}
+		if err != nil {
+			entry.Attempts++
+			entry.LastError = err.Error()
+			if werr := o.write(entry); werr != nil {
+				return sent, errors.Join(err, werr)
+			}
+			return sent, err
+		}
+
+		if err := os.Remove(o.path(entry.Seq)); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Drop removes a queued entry without publishing it
+func (o *Outbox) Drop(seq uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return os.Remove(o.path(seq))
+}
+
+// write atomically stores an entry (write to a temp file, then rename)
+func (o *Outbox) write(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(o.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), o.path(entry.Seq))
+}
+
+// path returns the file holding the entry with the given sequence number
+func (o *Outbox) path(seq uint64) string {
+	return filepath.Join(o.dir, fmt.Sprintf("%020d.json", seq))
+}
+
+// ParseSeq parses an entry sequence number as shown by List
+func ParseSeq(s string) (uint64, error) {
+	var seq uint64
+	if _, err := fmt.Sscan(strings.TrimSpace(s), &seq); err != nil {
+		return 0, fmt.Errorf("invalid sequence number %q", s)
+	}
+	return seq, nil
+}
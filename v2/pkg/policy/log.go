@@ -0,0 +1,46 @@
+package policy
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// Log records decisions as JSON Lines, so unattended runs can be audited
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenLog opens (or creates) a decision log for appending
+func OpenLog(path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Log{file: file}, nil
+}
+
+// Record appends a decision
+// A nil log records nothing
+func (l *Log) Record(d Decision) error {
+	if l == nil {
+		return nil
+	}
+	line, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the log
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.file.Close()
+}
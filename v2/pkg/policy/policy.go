@@ -0,0 +1,255 @@
+// Package policy answers decisions without an operator, for unattended runs:
+// rules pick the action an event gets ("approve plan.ready when data.risk ==
+// low"), evaluated by the TUI (--policy) or the autorespond daemon.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transform"
+)
+
+// RespondedVia is the response Data value of "responded_via" for answers given by a policy
+const RespondedVia = "policy"
+
+// Rule answers the events it matches with one of their actions
+type Rule struct {
+	Name   string          `json:"name"`
+	Match  transform.Match `json:"match"`          // Type glob, pane and message substring, as in transforms
+	When   []string        `json:"when,omitempty"` // Conditions on event fields, all of which must hold
+	Action string          `json:"action"`         // ID (or key) of the event's action to take
+}
+
+// Config is the on-disk policy definition
+type Config struct {
+	Rules []Rule `json:"rules"`
+}
+
+// operators are the comparisons conditions may use, two-character ones first
+var operators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// condition is a parsed When entry: a field compared to a value, or just present
+type condition struct {
+	field string
+	op    string      // "" when the field only needs to be present
+	value interface{} // JSON literal, or the text as a string
+}
+
+// rule is a Rule with its conditions parsed
+type rule struct {
+	Rule
+	conditions []condition
+}
+
+// Policy evaluates rules in order; the first one matching an event answers it
+type Policy struct {
+	rules []rule
+}
+
+// Decision is a policy's answer to an event
+type Decision struct {
+	Rule      string        `json:"rule"`
+	EventID   string        `json:"event_id"`
+	EventType string        `json:"event_type"`
+	ActionID  string        `json:"action_id"`
+	Label     string        `json:"label"`
+	At        time.Time     `json:"at"`
+	Action    events.Action `json:"-"` // The action to take, its response marked as given by the rule
+}
+
+// New creates a policy from rules, validating their patterns and conditions
+func New(rules []Rule) (*Policy, error) {
+	p := &Policy{}
+	for i, r := range rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule[%d]: missing name", i)
+		}
+		if r.Action == "" {
+			return nil, fmt.Errorf("rule %q: missing action", r.Name)
+		}
+		if _, err := transform.New([]transform.Rule{{Match: r.Match}}); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		parsed := rule{Rule: r}
+		for _, when := range r.When {
+			c, err := parseCondition(when)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+			}
+			parsed.conditions = append(parsed.conditions, c)
+		}
+		p.rules = append(p.rules, parsed)
+	}
+	return p, nil
+}
+
+// Parse creates a policy from a JSON config
+func Parse(data []byte) (*Policy, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return New(cfg.Rules)
+}
+
+// Load creates a policy from a JSON config file
+func Load(filename string) (*Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// parseCondition parses "field op value" (the operator surrounded by spaces),
+// or a lone field that only has to be present
+func parseCondition(text string) (condition, error) {
+	text = strings.TrimSpace(text)
+	for _, op := range operators {
+		field, value, ok := strings.Cut(text, " "+op+" ")
+		if !ok {
+			continue
+		}
+		c := condition{field: strings.TrimSpace(field), op: op}
+		value = strings.TrimSpace(value)
+		if err := json.Unmarshal([]byte(value), &c.value); err != nil {
+			c.value = value // A bare word compares as a string
+		}
+		if c.field == "" || value == "" {
+			return condition{}, fmt.Errorf("invalid condition %q (want \"field op value\")", text)
+		}
+		return c, nil
+	}
+	if text == "" || strings.ContainsAny(text, " =<>!") {
+		return condition{}, fmt.Errorf("invalid condition %q (want \"field op value\" with one of %s)", text, strings.Join(operators, " "))
+	}
+	return condition{field: text}, nil
+}
+
+// holds reports whether the event satisfies the condition
+// Ordering operators only hold between numbers (numeric strings count)
+func (c condition) holds(event events.Event) bool {
+	value, ok := event.Field(c.field)
+	if !ok {
+		return false
+	}
+	if c.op == "" {
+		return true
+	}
+
+	a, aNum := number(value)
+	b, bNum := number(c.value)
+	switch c.op {
+	case "==", "!=":
+		equal := fmt.Sprint(value) == fmt.Sprint(c.value)
+		if aNum && bNum {
+			equal = a == b
+		}
+		return equal == (c.op == "==")
+	}
+	if !aNum || !bNum {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+// number reads a value as a number
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// Decide returns the answer of the first rule matching an event that offers its action
+// Only button actions are taken; inputs need an operator
+func (p *Policy) Decide(event events.Event) (Decision, bool) {
+	if p == nil || len(event.Actions) == 0 {
+		return Decision{}, false
+	}
+	for _, r := range p.rules {
+		if !r.Match.Matches(event) || !r.holds(event) {
+			continue
+		}
+		action, ok := r.action(event)
+		if !ok {
+			continue
+		}
+		return Decision{
+			Rule:      r.Name,
+			EventID:   event.ID,
+			EventType: event.Type,
+			ActionID:  action.ID,
+			Label:     action.Label,
+			At:        time.Now(),
+			Action:    respondedBy(action, r.Name),
+		}, true
+	}
+	return Decision{}, false
+}
+
+// holds reports whether every condition of the rule holds
+func (r rule) holds(event events.Event) bool {
+	for _, c := range r.conditions {
+		if !c.holds(event) {
+			return false
+		}
+	}
+	return true
+}
+
+// action finds the rule's action among the event's, by ID and then by key
+func (r rule) action(event events.Event) (events.Action, bool) {
+	for _, match := range []func(events.Action) bool{
+		func(a events.Action) bool { return a.ID == r.Action },
+		func(a events.Action) bool { return a.Key == r.Action },
+	} {
+		for _, a := range event.Actions {
+			// Rules never stand in for the operators a four-eyes action needs
+			if a.InputType == "" && a.RequiredApprovals() == 1 && match(a) {
+				return a, true
+			}
+		}
+	}
+	return events.Action{}, false
+}
+
+// respondedBy returns a copy of the action whose response names the rule that gave it
+// The action's Data map is shared with the event it came from, so it is copied
+func respondedBy(a events.Action, rule string) events.Action {
+	data := make(map[string]interface{}, len(a.Event.Data)+2)
+	for k, v := range a.Event.Data {
+		data[k] = v
+	}
+	data["responded_via"] = RespondedVia
+	data["responded_by"] = rule
+	a.Event.Data = data
+	return a
+}
+
+// String describes the decision for logs
+func (d Decision) String() string {
+	return fmt.Sprintf("rule %q answered %s %s with %s", d.Rule, d.EventType, d.EventID, d.Label)
+}
@@ -0,0 +1,195 @@
+// Package recording keeps monitor events in a SQLite database, indexed for
+// post-mortem queries, and reads them back for replay.
+package recording
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+
+	_ "modernc.org/sqlite" // The pure-Go "sqlite" driver, so builds need no cgo
+)
+
+// TimeFormat is how times are stored: fixed width UTC, so they sort as text
+// and SQLite's date functions read them
+const TimeFormat = "2006-01-02T15:04:05.000Z"
+
+// schema creates the events table with an index per column queries filter on
+const schema = `
+CREATE TABLE IF NOT EXISTS events (
+	seq            INTEGER PRIMARY KEY AUTOINCREMENT, -- Arrival order
+	received_at    TEXT NOT NULL,                     -- When the recorder received it (TimeFormat)
+	subject        TEXT NOT NULL,
+	id             TEXT NOT NULL,
+	type           TEXT NOT NULL,
+	timestamp      TEXT,                              -- The producer's timestamp (TimeFormat), NULL without
+	source         TEXT NOT NULL,
+	session_id     TEXT,
+	correlation_id TEXT,
+	severity       TEXT NOT NULL,
+	payload        TEXT NOT NULL                      -- The event as JSON, for json_extract
+);
+CREATE INDEX IF NOT EXISTS events_received_at ON events (received_at);
+CREATE INDEX IF NOT EXISTS events_type ON events (type, received_at);
+CREATE INDEX IF NOT EXISTS events_source ON events (source, received_at);
+CREATE INDEX IF NOT EXISTS events_session ON events (session_id, received_at);
+CREATE INDEX IF NOT EXISTS events_correlation ON events (correlation_id);
+CREATE INDEX IF NOT EXISTS events_id ON events (id);
+`
+
+// insert adds one event
+const insert = `INSERT INTO events
+	(received_at, subject, id, type, timestamp, source, session_id, correlation_id, severity, payload)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// DefaultPath returns the default recording (~/.config/agneto/recording.db)
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "agneto-recording.db"
+	}
+	return filepath.Join(dir, "agneto", "recording.db")
+}
+
+// header starts every SQLite database file
+var header = []byte("SQLite format 3\x00")
+
+// DB records every message on a monitor bus, in bus order
+type DB struct {
+	db     *sql.DB
+	insert *sql.Stmt
+
+	mu  sync.Mutex // Guards err
+	err error      // Failures since the last Err call
+}
+
+// Create opens the database at path for recording, creating it (and its
+// directory) if needed; an existing recording is appended to
+func Create(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := open(path, "_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	stmt, err := db.Prepare(insert)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &DB{db: db, insert: stmt}, nil
+}
+
+// open opens a database with one connection, so pragmas apply to every statement
+func open(path, params string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+filepath.ToSlash(path)+"?"+params)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// Deliver implements monitor.Sink
+// Payloads that aren't events are skipped (strict mode reports them), as are
+// events replayed from JetStream history, which were recorded on arrival
+func (d *DB) Deliver(msg monitor.Message) {
+	if msg.Source == "history" {
+		return
+	}
+	event, err := events.FromJSON(msg.Data)
+	if err != nil {
+		return
+	}
+	var timestamp interface{}
+	if !event.Timestamp.IsZero() {
+		timestamp = event.Timestamp.UTC().Format(TimeFormat)
+	}
+	_, err = d.insert.Exec(msg.ReceivedAt.UTC().Format(TimeFormat), msg.Subject, event.ID, event.Type, timestamp,
+		event.EffectiveSource(), nullable(event.SessionID), nullable(event.CorrelationID), event.EffectiveSeverity(), string(msg.Data))
+	if err != nil {
+		d.mu.Lock()
+		d.err = errors.Join(d.err, err)
+		d.mu.Unlock()
+	}
+}
+
+// nullable stores an empty string as NULL
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// Err returns the write failures since the last call, clearing them
+func (d *DB) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	err := d.err
+	d.err = nil
+	return err
+}
+
+// Close closes the database; closing again does nothing
+func (d *DB) Close() error {
+	d.insert.Close()
+	return d.db.Close()
+}
+
+// IsDatabase reports whether the file at path is a SQLite database
+func IsDatabase(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	start := make([]byte, len(header))
+	_, err = io.ReadFull(f, start)
+	return err == nil && bytes.Equal(start, header)
+}
+
+// Read returns the events of a recording in the order they were received
+func Read(path string) ([]events.Event, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err // The driver would create a missing file
+	}
+	db, err := open(path, "mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT seq, payload FROM events ORDER BY seq`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	defer rows.Close()
+	var recorded []events.Event
+	for rows.Next() {
+		var seq int64
+		var payload string
+		if err := rows.Scan(&seq, &payload); err != nil {
+			return nil, err
+		}
+		event, err := events.FromJSON([]byte(payload))
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", seq, err)
+		}
+		recorded = append(recorded, *event)
+	}
+	return recorded, rows.Err()
+}
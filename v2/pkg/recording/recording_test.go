@@ -0,0 +1,77 @@
+package recording
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+)
+
+func TestRecordingRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incident.db")
+	db, err := Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	deliver := func(source string, event events.Event) {
+		data, err := event.ToJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.Deliver(monitor.Message{Source: source, Subject: "agneto.events", Data: data, ReceivedAt: received})
+		received = received.Add(time.Second)
+	}
+	deliver("nats", events.Event{ID: "e1", Type: "agent.status", Source: "builder", SessionID: "s1"})
+	deliver("history", events.Event{ID: "old", Type: "agent.status"})
+	deliver("nats", events.Event{ID: "e2", Type: "decision.required", Severity: "warning"})
+	db.Deliver(monitor.Message{Source: "nats", Subject: "agneto.events", Data: []byte("not json"), ReceivedAt: received})
+	if err := db.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int
+	var source string
+	row := db.db.QueryRow(`SELECT count(*), max(source) FROM events WHERE session_id = 's1' AND received_at = '2026-03-01T12:00:00.000Z'`)
+	if err := row.Scan(&count, &source); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || source != "builder" {
+		t.Fatalf("session query found %d events from %q, want 1 from builder", count, source)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsDatabase(path) {
+		t.Fatal("recording not recognized as a database")
+	}
+	recorded, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded) != 2 || recorded[0].ID != "e1" || recorded[1].ID != "e2" {
+		t.Fatalf("read %+v, want e1 and e2 in arrival order", recorded)
+	}
+}
+
+func TestIsDatabase(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "events.jsonl")
+	if err := os.WriteFile(jsonl, []byte(`{"id":"e1","type":"agent.status"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if IsDatabase(jsonl) || IsDatabase(dir) || IsDatabase(filepath.Join(dir, "missing.db")) {
+		t.Fatal("only SQLite files are databases")
+	}
+	if _, err := Read(filepath.Join(dir, "missing.db")); err == nil {
+		t.Fatal("reading a missing recording succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing.db")); err == nil {
+		t.Fatal("reading a missing recording created it")
+	}
+}
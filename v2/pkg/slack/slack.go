@@ -0,0 +1,287 @@
+// Package slack posts actionable events to Slack as messages with
+// interactive buttons and turns button clicks back into action responses.
+//
+// It talks to the Slack Web API directly (chat.postMessage) and verifies
+// interaction callbacks with the app's signing secret, so no Slack SDK is
+// needed.
+package slack
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Environment variables holding the Slack app credentials
+const (
+	EnvBotToken      = "SLACK_BOT_TOKEN"      // xoxb- token with chat:write
+	EnvSigningSecret = "SLACK_SIGNING_SECRET" // Verifies interaction callbacks
+)
+
+// DefaultAPIURL is the Slack Web API base URL
+const DefaultAPIURL = "https://slack.com/api"
+
+// maxSignatureAge rejects replayed callbacks (Slack recommends five minutes)
+const maxSignatureAge = 5 * time.Minute
+
+// maxTextLen keeps message sections under Slack's 3000 character limit
+const maxTextLen = 2900
+
+// Client posts messages with a bot token
+type Client struct {
+	Token  string
+	APIURL string // Defaults to DefaultAPIURL
+	HTTP   *http.Client
+}
+
+// NewClient creates a client for the given bot token
+func NewClient(token string) *Client {
+	return &Client{
+		Token:  token,
+		APIURL: DefaultAPIURL,
+		HTTP:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// PostMessage posts blocks to a channel
+// Returns the channel ID and message timestamp, which identify the message for UpdateMessage
+func (c *Client) PostMessage(channel, text string, blocks []Block) (string, string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"channel": channel,
+		"text":    text, // Fallback for notifications
+		"blocks":  blocks,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.APIURL+"/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK      bool   `json:"ok"`
+		Error   string `json:"error"`
+		Channel string `json:"channel"`
+		TS      string `json:"ts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", "", fmt.Errorf("chat.postMessage: %s: %w", resp.Status, err)
+	}
+	if !result.OK {
+		return "", "", fmt.Errorf("chat.postMessage: %s", result.Error)
+	}
+	return result.Channel, result.TS, nil
+}
+
+// Block is a Slack Block Kit block
+type Block map[string]interface{}
+
+// Actionable reports whether an event has actions Slack buttons can answer
+// Input actions (text, select, confirm) can only be answered in the TUI
+func Actionable(event events.Event) bool {
+	for _, action := range event.Actions {
+		if action.InputType == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageBlocks renders an event as a section plus a row of buttons
+// Each button's value carries the event and action IDs (see ParseValue)
+func MessageBlocks(event events.Event) []Block {
+	text := fmt.Sprintf("*%s*\n%s", event.Type, event.Message)
+	if event.Content != "" {
+		text += "\n```" + event.Content + "```"
+	}
+	if runes := []rune(text); len(runes) > maxTextLen {
+		text = string(runes[:maxTextLen]) + "…"
+	}
+
+	actions := append([]events.Action(nil), event.Actions...)
+	events.SortActions(actions)
+
+	var buttons []Block
+	for _, action := range actions {
+		if action.InputType != "" {
+			continue
+		}
+		button := Block{
+			"type":      "button",
+			"text":      Block{"type": "plain_text", "text": action.Label},
+			"action_id": action.ID,
+			"value":     event.ID + "|" + action.ID,
+		}
+		switch action.Style {
+		case events.ActionStyleDanger:
+			button["style"] = "danger"
+		case "", events.ActionStylePrimary:
+			button["style"] = "primary"
+		}
+		buttons = append(buttons, button)
+	}
+
+	blocks := []Block{{
+		"type": "section",
+		"text": Block{"type": "mrkdwn", "text": text},
+	}}
+	if len(buttons) > 0 {
+		blocks = append(blocks, Block{"type": "actions", "elements": buttons})
+	}
+	return blocks
+}
+
+// ParseValue splits a button value into event and action IDs
+func ParseValue(value string) (eventID, actionID string, err error) {
+	eventID, actionID, ok := strings.Cut(value, "|")
+	if !ok || eventID == "" || actionID == "" {
+		return "", "", fmt.Errorf("malformed button value %q", value)
+	}
+	return eventID, actionID, nil
+}
+
+// VerifySignature checks a callback's X-Slack-Signature against the signing secret
+func VerifySignature(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	signature := header.Get("X-Slack-Signature")
+	if timestamp == "" || signature == "" {
+		return errors.New("missing Slack signature headers")
+	}
+
+	secs, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid request timestamp %q", timestamp)
+	}
+	if age := now.Sub(time.Unix(secs, 0)); age > maxSignatureAge || age < -maxSignatureAge {
+		return errors.New("request timestamp too far from now (replay?)")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// Interaction is a button click from a block_actions callback
+type Interaction struct {
+	UserID      string
+	UserName    string
+	Value       string // Button value (see ParseValue)
+	ResponseURL string // Where to post the message update
+}
+
+// ParseInteraction decodes the form-encoded payload of an interaction callback
+func ParseInteraction(body []byte) (*Interaction, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Type string `json:"type"`
+		User struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"user"`
+		ResponseURL string `json:"response_url"`
+		Actions     []struct {
+			Value string `json:"value"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		return nil, fmt.Errorf("invalid interaction payload: %w", err)
+	}
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		return nil, fmt.Errorf("unsupported interaction %q", payload.Type)
+	}
+
+	return &Interaction{
+		UserID:      payload.User.ID,
+		UserName:    payload.User.Username,
+		Value:       payload.Actions[0].Value,
+		ResponseURL: payload.ResponseURL,
+	}, nil
+}
+
+// ReplaceMessage replaces the original message via an interaction's response URL
+// Used to remove the buttons once a decision was made
+func (c *Client) ReplaceMessage(responseURL, text string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"replace_original": true,
+		"text":             text,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := c.HTTP.Post(responseURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("response_url returned %s", resp.Status)
+	}
+	return nil
+}
+
+// UpdateMessage replaces the text of a posted message (chat.update), removing its buttons
+func (c *Client) UpdateMessage(channel, ts, text string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"channel": channel,
+		"ts":      ts,
+		"text":    text,
+		"blocks":  []Block{},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.APIURL+"/chat.update", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("chat.update: %s: %w", resp.Status, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("chat.update: %s", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+// Package testutil runs agneto end to end inside a test: an in-process
+// nats-server with JetStream, a scripted producer publishing through pkg/client, and a headless
+// monitor that routes, renders and answers events as the TUI does.
+//
+// A test reads as the story it checks:
+//
+//	h := testutil.New(t)
+//	pending := h.Ask(events.Event{Type: "deploy", Content: "Deploy to prod?", Actions: actions})
+//	h.ExpectRendered("Deploy to prod?")
+//	h.Press("y")
+//	response := pending.Expect(t)
+//
+// Everything started is stopped by t.Cleanup. Helpers fail the test with
+// t.Fatalf, waiting at most DefaultWait for things to happen.
+package testutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/client"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// Subject is the subject producers publish on and the monitor watches
+const Subject = "agneto.test.events"
+
+// Screen size renders are checked at
+const (
+	ScreenWidth  = 160
+	ScreenHeight = 50
+)
+
+// Harness is a server, a producer and a monitor wired together for a test
+type Harness struct {
+	T        testing.TB
+	Server   *server.Server
+	Conn     *nats.Conn        // The producer's connection
+	Producer *client.Publisher // Publishes on Subject
+	Monitor  *Monitor          // Watches Subject on a connection of its own
+}
+
+// New starts a harness, stopped when the test ends
+func New(t testing.TB) *Harness {
+	t.Helper()
+	srv := StartServer(t.TempDir())
+	t.Cleanup(srv.Shutdown)
+	h := &Harness{T: t, Server: srv}
+
+	h.Monitor = h.StartMonitor(func(nc *nats.Conn) monitor.Source {
+		return monitor.NATSSource{Conn: nc, Subject: Subject}
+	})
+	h.Conn = h.Connect("agneto-testutil-producer")
+	h.Producer = client.New(h.Conn, Subject)
+	h.Producer.Source = "testutil"
+	return h
+}
+
+// Connect opens a connection to the server, closed when the test ends
+func (h *Harness) Connect(name string) *nats.Conn {
+	h.T.Helper()
+	nc, err := nats.Connect(h.Server.ClientURL(), nats.Name(name), nats.NoReconnect())
+	if err != nil {
+		h.T.Fatalf("testutil: connecting %s: %v", name, err)
+	}
+	h.T.Cleanup(nc.Close)
+	return nc
+}
+
+// JetStream returns a JetStream context on the producer's connection, to
+// set up streams the way a deployment would
+func (h *Harness) JetStream() nats.JetStreamContext {
+	h.T.Helper()
+	js, err := h.Conn.JetStream()
+	if err != nil {
+		h.T.Fatalf("testutil: JetStream: %v", err)
+	}
+	return js
+}
+
+// StartMonitor starts another headless monitor on a connection of its own,
+// reading Subject through the source newSource returns (a HistorySource or
+// JetStreamSource, say); it stops when the test ends or Stop is called
+func (h *Harness) StartMonitor(newSource func(nc *nats.Conn) monitor.Source) *Monitor {
+	h.T.Helper()
+	nc := h.Connect("agneto-testutil-monitor")
+	m, err := StartMonitorFrom(nc, Subject, newSource(nc))
+	if err != nil {
+		h.T.Fatalf("testutil: starting the monitor: %v", err)
+	}
+	h.T.Cleanup(m.Close)
+	return m
+}
+
+// Publish publishes an event as the producer and returns it as published
+func (h *Harness) Publish(event events.Event) events.Event {
+	h.T.Helper()
+	published, err := h.Producer.Publish(context.Background(), event)
+	if err != nil {
+		h.T.Fatalf("testutil: publishing %s: %v", event.Type, err)
+	}
+	return published
+}
+
+// Pending is a decision the producer asked for, waiting for its response
+type Pending struct {
+	Request events.Event // The event as published, with its ID and reply subject
+	done    chan struct{}
+	result  client.Response
+	err     error
+}
+
+// Ask publishes an event with actions and waits for its response in the
+// background, as a producer blocked on client.Publisher.Ask
+func (h *Harness) Ask(event events.Event) *Pending {
+	h.T.Helper()
+	client.Prepare(&event)
+	if event.Source == "" {
+		event.Source = h.Producer.Source
+	}
+	p := &Pending{Request: event, done: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	h.T.Cleanup(cancel)
+	go func() {
+		defer close(p.done)
+		p.result, p.err = h.Producer.Ask(ctx, event)
+	}()
+	// Returned once the request reaches the monitor, reply subject and all
+	p.Request = h.ExpectEvent(func(e events.Event) bool { return e.ID == event.ID })
+	return p
+}
+
+// Response waits up to timeout for the response; ok is false if none came
+func (p *Pending) Response(timeout time.Duration) (client.Response, bool, error) {
+	select {
+	case <-p.done:
+		return p.result, true, p.err
+	case <-time.After(timeout):
+		return client.Response{}, false, nil
+	}
+}
+
+// Expect waits for the response, failing the test if none comes
+func (p *Pending) Expect(t testing.TB) client.Response {
+	t.Helper()
+	response, ok, err := p.Response(DefaultWait)
+	switch {
+	case !ok:
+		t.Fatalf("testutil: no response to %s (%s) within %s", p.Request.ID, p.Request.Type, DefaultWait)
+	case err != nil:
+		t.Fatalf("testutil: asking %s: %v", p.Request.ID, err)
+	}
+	return response
+}
+
+// ExpectAction waits for the response and checks it is the action on key
+func (p *Pending) ExpectAction(t testing.TB, key string) client.Response {
+	t.Helper()
+	response := p.Expect(t)
+	if response.Action == nil || response.Action.Key != key {
+		got := "a response that isn't one of the request's actions"
+		if response.Action != nil {
+			got = "the action on " + response.Action.Key
+		}
+		t.Fatalf("testutil: %s was answered with %s, want the action on %s", p.Request.ID, got, key)
+	}
+	return response
+}
+
+// ExpectEvent waits for the monitor to receive an event matching match
+func (h *Harness) ExpectEvent(match func(events.Event) bool) events.Event {
+	h.T.Helper()
+	event, ok := h.Monitor.WaitFor(match, DefaultWait)
+	if !ok {
+		h.T.Fatalf("testutil: no matching event reached the monitor within %s (received %d, invalid: %v)",
+			DefaultWait, len(h.Monitor.Events()), h.Monitor.Invalid())
+	}
+	return event
+}
+
+// ExpectType waits for the monitor to receive an event of a type
+func (h *Harness) ExpectType(eventType string) events.Event {
+	h.T.Helper()
+	return h.ExpectEvent(func(e events.Event) bool { return e.Type == eventType })
+}
+
+// ExpectRendered waits until the monitor's screen shows text
+func (h *Harness) ExpectRendered(text string) {
+	h.T.Helper()
+	deadline := time.Now().Add(DefaultWait)
+	for !h.Monitor.Rendered(text, ScreenWidth, ScreenHeight) {
+		if time.Now().After(deadline) {
+			h.T.Fatalf("testutil: the monitor doesn't show %q; screen:\n%s", text, h.Monitor.Render(ScreenWidth, ScreenHeight))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// ExpectNotRendered checks the monitor's screen doesn't show text now
+func (h *Harness) ExpectNotRendered(text string) {
+	h.T.Helper()
+	if h.Monitor.Rendered(text, ScreenWidth, ScreenHeight) {
+		h.T.Fatalf("testutil: the monitor shows %q; screen:\n%s", text, h.Monitor.Render(ScreenWidth, ScreenHeight))
+	}
+}
+
+// Press answers the active decision with the action on key, failing the
+// test if there is none; returns the response published
+func (h *Harness) Press(key string) events.Event {
+	h.T.Helper()
+	if _, ok := h.Monitor.Active(); !ok {
+		// The decision may still be on its way
+		deadline := time.Now().Add(DefaultWait)
+		for _, ok := h.Monitor.Active(); !ok && time.Now().Before(deadline); _, ok = h.Monitor.Active() {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	response, err := h.Monitor.Press(key)
+	if err != nil {
+		h.T.Fatalf("testutil: pressing %s: %v", key, err)
+	}
+	return response
+}
+
+// Screen returns the monitor's screen, for tests to log or inspect
+func (h *Harness) Screen() string {
+	return strings.TrimRight(h.Monitor.Render(ScreenWidth, ScreenHeight), "\n")
+}
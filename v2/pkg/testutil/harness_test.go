@@ -0,0 +1,75 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/tui"
+)
+
+// deployActions are the buttons of a yes/no deploy decision
+func deployActions() []events.Action {
+	return []events.Action{
+		{ID: "approve", Label: "Approve", Key: "y", Event: events.Event{Type: "deploy_approved"}},
+		{ID: "reject", Label: "Reject", Key: "n", Event: events.Event{Type: "deploy_rejected"}},
+	}
+}
+
+func TestAskIsAnswered(t *testing.T) {
+	h := New(t)
+	pending := h.Ask(events.Event{Type: "deploy", Content: "Deploy to prod?", Actions: deployActions()})
+	h.ExpectRendered("Deploy to prod?")
+
+	published := h.Press("y")
+	if published.Type != "deploy_approved" {
+		t.Fatalf("published %q, want deploy_approved", published.Type)
+	}
+	response := pending.ExpectAction(t, "y")
+	if response.Type != "deploy_approved" {
+		t.Fatalf("producer got %q, want deploy_approved", response.Type)
+	}
+}
+
+func TestQueuedDecisionsAnsweredInOrder(t *testing.T) {
+	h := New(t)
+	first := h.Ask(events.Event{Type: "deploy", Content: "First?", Actions: deployActions()})
+	second := h.Ask(events.Event{Type: "deploy", Content: "Second?", Actions: deployActions()})
+
+	if active, _ := h.Monitor.Active(); active.ID != first.Request.ID {
+		t.Fatalf("active decision is %s, want the first (%s)", active.ID, first.Request.ID)
+	}
+	h.Press("n")
+	first.ExpectAction(t, "n")
+
+	// Pressed straight away: the harness waits out the debounce like an operator
+	h.Press("y")
+	second.ExpectAction(t, "y")
+}
+
+func TestEventsArriveWhilePressWaits(t *testing.T) {
+	h := New(t)
+	pending := h.Ask(events.Event{Type: "deploy", Content: "Deploy?", Actions: deployActions()})
+	next := h.Ask(events.Event{Type: "deploy", Content: "Again?", Actions: deployActions()})
+	h.Press("y")
+	pending.ExpectAction(t, "y")
+
+	// The debounce wait of the next Press must not hold up delivery
+	defer func(debounce time.Duration) { tui.ActionDebounce = debounce }(tui.ActionDebounce)
+	tui.ActionDebounce = 2 * time.Second
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.Press("y")
+	}()
+	time.Sleep(50 * time.Millisecond) // Let Press start waiting
+	h.Publish(events.Event{Type: "log", Message: "still flowing"})
+	h.ExpectType("log")
+	select {
+	case <-done:
+		t.Fatal("the event only arrived once Press was done waiting")
+	default:
+	}
+	<-done
+	next.ExpectAction(t, "y")
+}
@@ -0,0 +1,154 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/durch/agneto/v2/pkg/natsconn"
+	"github.com/nats-io/nats.go"
+)
+
+// addStream stores Subject in a stream, as a deployment with history would
+func addStream(h *Harness, name string) nats.JetStreamContext {
+	h.T.Helper()
+	js := h.JetStream()
+	if _, err := js.AddStream(&nats.StreamConfig{Name: name, Subjects: []string{Subject}}); err != nil {
+		h.T.Fatalf("adding stream %s: %v", name, err)
+	}
+	return js
+}
+
+// expectReceived waits for an event to reach m, failing the test if it doesn't
+func expectReceived(t *testing.T, m *Monitor, id string) {
+	t.Helper()
+	if _, ok := m.WaitFor(func(e events.Event) bool { return e.ID == id }, DefaultWait); !ok {
+		t.Fatalf("%s never reached the monitor (received %d)", id, len(m.Events()))
+	}
+}
+
+// expectBehind waits until a lag reporter is n messages behind
+func expectBehind(t *testing.T, source monitor.LagReporter, n uint64) {
+	t.Helper()
+	deadline := time.Now().Add(DefaultWait)
+	for {
+		lag, err := source.Lag()
+		if err == nil && lag.Behind() == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("lag is %+v (%v), want %d behind", lag, err, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHistoryReplaysRecentEvents(t *testing.T) {
+	h := New(t)
+	js := addStream(h, "HISTORY")
+	var published []events.Event
+	for _, message := range []string{"one", "two", "three"} {
+		published = append(published, h.Publish(events.Event{Type: "log", Message: message}))
+	}
+	h.ExpectEvent(func(e events.Event) bool { return e.ID == published[2].ID })
+
+	var source *monitor.HistorySource
+	late := h.StartMonitor(func(*nats.Conn) monitor.Source {
+		source = &monitor.HistorySource{JS: js, Subject: Subject, Last: 2}
+		return source
+	})
+	for _, event := range published[1:] {
+		expectReceived(t, late, event.ID)
+		if got := late.Source(event.ID); got != "history" {
+			t.Errorf("%s came through %q, want history", event.Message, got)
+		}
+	}
+	expectBehind(t, source, 0)
+
+	live := h.Publish(events.Event{Type: "log", Message: "four"})
+	expectReceived(t, late, live.ID)
+	if got := late.Source(live.ID); got != "nats" {
+		t.Errorf("an event published after the replay came through %q, want nats", got)
+	}
+	if got := len(late.Events()); got != 3 {
+		t.Errorf("the late monitor received %d events, want the last two and the live one", got)
+	}
+}
+
+func TestEdgeMonitorResumesWhereItStopped(t *testing.T) {
+	h := New(t)
+	js := addStream(h, "EDGE")
+	start := func() (*Monitor, *monitor.JetStreamSource) {
+		var source *monitor.JetStreamSource
+		m := h.StartMonitor(func(nc *nats.Conn) monitor.Source {
+			js, err := nc.JetStream()
+			if err != nil {
+				t.Fatalf("JetStream: %v", err)
+			}
+			source = &monitor.JetStreamSource{JS: js, Stream: "EDGE", Subject: Subject, Durable: "agneto-testutil"}
+			return source
+		})
+		return m, source
+	}
+
+	first, source := start()
+	before := h.Publish(events.Event{Type: "log", Message: "before"})
+	expectReceived(t, first, before.ID)
+	expectBehind(t, source, 0) // Acknowledged, so it isn't delivered again
+	first.Stop()
+
+	// Stored while the monitor was away; the durable consumer counts them
+	var missed []events.Event
+	for _, message := range []string{"while away", "still away"} {
+		missed = append(missed, h.Publish(events.Event{Type: "log", Message: message}))
+	}
+	deadline := time.Now().Add(DefaultWait)
+	for {
+		info, err := js.ConsumerInfo("EDGE", "agneto-testutil")
+		if err == nil && info.NumPending == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("consumer info %+v (%v), want 2 pending", info, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	second, source := start()
+	for _, event := range missed {
+		expectReceived(t, second, event.ID)
+	}
+	expectBehind(t, source, 0)
+	for _, event := range second.Events() {
+		if event.ID == before.ID {
+			t.Fatal("the resumed monitor received an event the first one had already taken")
+		}
+	}
+}
+
+func TestEdgeResponsesAreStoredBeforeDelivery(t *testing.T) {
+	h := New(t)
+	js := addStream(h, "EDGE")
+	pub, err := natsconn.Settings{EdgeStream: "EDGE"}.Publisher(h.Conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := events.Event{ID: "response-1", Type: "deploy_approved", Timestamp: time.Now()}
+	payload, err := response.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Returns only once the stream acknowledged the message
+	if err := pub.Publish(Subject, payload); err != nil {
+		t.Fatalf("publishing through the edge stream: %v", err)
+	}
+	info, err := js.StreamInfo("EDGE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.State.Msgs != 1 {
+		t.Fatalf("the stream holds %d messages once Publish returned, want 1", info.State.Msgs)
+	}
+	h.ExpectEvent(func(e events.Event) bool { return e.ID == response.ID })
+}
@@ -0,0 +1,322 @@
+package testutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/monitor"
+	"github.com/durch/agneto/v2/pkg/tui"
+	"github.com/nats-io/nats.go"
+)
+
+// DefaultWait bounds how long assertions wait for something to happen
+var DefaultWait = 5 * time.Second
+
+// ansiPattern matches terminal escape sequences, stripped from renders
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// Monitor is a headless monitor: events arrive as in the TUI (a NATS source
+// on the monitor bus), are routed into panes and render with the TUI's
+// layout, and decisions are answered with Press
+// One decision is active at a time; later ones wait in a queue, as in the TUI
+// It drives the same pkg/tui pane and action managers but not cmd/tui's
+// model (a main package), whose own tests cover key dispatch and the queue
+type Monitor struct {
+	nc      *nats.Conn
+	subject string
+	bus     *monitor.Bus
+	stop    func()
+
+	closeOnce sync.Once
+
+	mu       sync.Mutex
+	changed  chan struct{} // Closed and replaced whenever an event arrives
+	panes    *tui.PaneManager
+	actions  *tui.ActionManager
+	received []events.Event    // Every event, in arrival order
+	where    map[string]string // Pane of each event, by ID
+	sources  map[string]string // Source each event came through, by ID
+	active   *events.Event     // The decision the action keys answer
+	pressed  time.Time         // When Press last triggered an action
+	invalid  []string          // Payloads that didn't decode
+}
+
+// StartMonitor subscribes a headless monitor to subject
+func StartMonitor(nc *nats.Conn, subject string) (*Monitor, error) {
+	return StartMonitorFrom(nc, subject, monitor.NATSSource{Conn: nc, Subject: subject})
+}
+
+// StartMonitorFrom starts a headless monitor fed by source, which reads
+// subject; responses are published on nc
+func StartMonitorFrom(nc *nats.Conn, subject string, source monitor.Source) (*Monitor, error) {
+	m := &Monitor{
+		nc:      nc,
+		subject: subject,
+		bus:     monitor.New(monitor.DefaultBuffer),
+		changed: make(chan struct{}),
+		panes:   tui.NewPaneManager(1000),
+		actions: tui.NewActionManager(),
+		where:   make(map[string]string),
+		sources: make(map[string]string),
+	}
+	m.bus.AddSink(m)
+	m.bus.Start()
+	stop, err := source.Start(m.bus)
+	if err != nil {
+		m.bus.Close()
+		return nil, err
+	}
+	m.stop = stop
+	return m, nc.Flush() // The subscription is in place before producers publish
+}
+
+// Close unsubscribes and stops the bus; closing again does nothing
+func (m *Monitor) Close() {
+	m.closeOnce.Do(func() {
+		m.stop()
+		m.bus.Close()
+	})
+}
+
+// Stop closes the monitor and its connection, as a monitor going away would
+// A durable consumer it read through keeps its position on the server
+func (m *Monitor) Stop() {
+	m.Close()
+	m.nc.Close()
+}
+
+// Deliver routes a payload from the bus, making it the active decision if
+// it has actions and none is active
+func (m *Monitor) Deliver(msg monitor.Message) {
+	m.mu.Lock()
+	defer m.notify()
+	defer m.mu.Unlock()
+
+	event, err := events.FromJSON(msg.Data)
+	if err != nil {
+		m.invalid = append(m.invalid, fmt.Sprintf("%s: %v", msg.Subject, err))
+		return
+	}
+	event.AddressReplies()
+	m.received = append(m.received, *event)
+	m.sources[event.ID] = msg.Source
+	// Later events of a stream grow its entry, as in the TUI
+	if m.panes.ContinueStream(*event) != nil {
+		return
+	}
+	pane := m.panes.RouteEvent(*event)
+	if pane == nil {
+		return
+	}
+	m.where[event.ID] = pane.Name
+
+	if len(event.Actions) == 0 {
+		return
+	}
+	if m.active != nil {
+		m.actions.Enqueue(tui.QueuedDecision{EventID: event.ID, Actions: event.Actions, Since: msg.ReceivedAt})
+		return
+	}
+	m.activate(*event, pane.LastAdded)
+}
+
+// notify wakes everything waiting for events
+func (m *Monitor) notify() {
+	m.mu.Lock()
+	close(m.changed)
+	m.changed = make(chan struct{})
+	m.mu.Unlock()
+}
+
+// activate makes an event the decision its action keys answer
+func (m *Monitor) activate(event events.Event, index int) {
+	m.active = &event
+	m.actions.RegisterActions(event.Actions, index)
+}
+
+// Events returns every event received so far, in arrival order
+func (m *Monitor) Events() []events.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]events.Event(nil), m.received...)
+}
+
+// Invalid returns the payloads that weren't events, as "subject: error"
+func (m *Monitor) Invalid() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.invalid...)
+}
+
+// Active returns the decision waiting for Press, if any
+func (m *Monitor) Active() (events.Event, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active == nil {
+		return events.Event{}, false
+	}
+	return *m.active, true
+}
+
+// Pane returns the pane an event was routed to ("" if it wasn't received)
+func (m *Monitor) Pane(eventID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.where[eventID]
+}
+
+// Source returns the source an event came through, such as "history" for
+// one a HistorySource replayed ("" if it wasn't received)
+func (m *Monitor) Source(eventID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sources[eventID]
+}
+
+// WaitFor waits until an event matching match arrives (or already has) and
+// returns it; ok is false if none does within timeout
+func (m *Monitor) WaitFor(match func(events.Event) bool, timeout time.Duration) (events.Event, bool) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		m.mu.Lock()
+		for _, event := range m.received {
+			if match(event) {
+				m.mu.Unlock()
+				return event, true
+			}
+		}
+		changed := m.changed
+		m.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return events.Event{}, false
+		}
+	}
+}
+
+// Render draws the monitor as the TUI would at the given size: the pane the
+// active decision is in (else the left pane), with the last event selected
+// Escape sequences are stripped, so assertions can match plain text
+func (m *Monitor) Render(width, height int) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	view := tui.ListView{Pane: events.PaneLeft, VisualStart: -1}
+	if m.active != nil {
+		index := m.actions.GetEventIndex()
+		view.Pane = m.where[m.active.ID]
+		view.SelectedIndex, view.BlockingIndex = index, &index
+	} else if pane := m.panes.GetPane(view.Pane); pane != nil {
+		view.SelectedIndex = len(pane.Events) - 1
+	}
+	return ansiPattern.ReplaceAllString(tui.RenderSplitLayout(m.panes, view, width, height, false, ""), "")
+}
+
+// Rendered reports whether the monitor's render at a size shows text,
+// ignoring the line breaks and borders long lines are wrapped with
+func (m *Monitor) Rendered(text string, width, height int) bool {
+	screen := m.Render(width, height)
+	if strings.Contains(screen, text) {
+		return true
+	}
+	return strings.Contains(unwrap(screen), strings.Join(strings.Fields(text), " "))
+}
+
+// unwrap reads the panes of a render one after the other, each as a single
+// line of words without borders, so text wrapped inside a pane is whole again
+func unwrap(screen string) string {
+	var left, right []string
+	for _, line := range strings.Split(screen, "\n") {
+		l, r, _ := strings.Cut(line, "││") // The panes' facing borders
+		left, right = append(left, l), append(right, r)
+	}
+	columns := strings.Join(left, " ") + " " + strings.Join(right, " ")
+	columns = strings.Map(func(r rune) rune {
+		if strings.ContainsRune("│─╭╮╰╯", r) {
+			return ' '
+		}
+		return r
+	}, columns)
+	return strings.Join(strings.Fields(columns), " ")
+}
+
+// Press triggers the active decision's action bound to key, publishing its
+// response as the TUI does: on the monitor's subject and to the action's
+// reply subject; the next queued decision, if any, becomes active
+func (m *Monitor) Press(key string) (events.Event, error) {
+	// Keys pressed within tui.ActionDebounce of an action are ignored, so wait
+	// it out as an operator would - without the lock, so events keep arriving
+	m.mu.Lock()
+	wait := tui.ActionDebounce - time.Since(m.pressed)
+	m.mu.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active == nil {
+		return events.Event{}, fmt.Errorf("no decision is waiting for %q", key)
+	}
+	for _, action := range m.actions.GetActiveActions() {
+		switch {
+		case action.Key != key:
+		case action.IsInput():
+			return events.Event{}, fmt.Errorf("action %q of %s takes input, which the harness doesn't type", action.Label, m.active.ID)
+		case action.RequiredApprovals() > 1:
+			return events.Event{}, fmt.Errorf("action %q of %s needs %d operators' approval; the harness is one operator", action.Label, m.active.ID, action.RequiredApprovals())
+		}
+	}
+	action, ok := m.actions.HandleKeyPress(key)
+	if !ok {
+		return events.Event{}, fmt.Errorf("decision %s has no action on %q", m.active.ID, key)
+	}
+	m.pressed = time.Now()
+	response := action.Response()
+	payload, err := response.ToJSON()
+	if err != nil {
+		return events.Event{}, err
+	}
+	if err := m.nc.Publish(m.subject, payload); err != nil {
+		return events.Event{}, err
+	}
+	if action.ReplyTo != "" {
+		if err := m.nc.Publish(action.ReplyTo, payload); err != nil {
+			return events.Event{}, err
+		}
+	}
+
+	m.actions.Settle()
+	m.active = nil
+	for {
+		next, ok := m.actions.Next()
+		if !ok {
+			break
+		}
+		if event, index, found := m.find(next.EventID); found {
+			m.activate(event, index)
+			break
+		}
+	}
+	return response, m.nc.Flush()
+}
+
+// find looks an event up in the pane it was routed to
+func (m *Monitor) find(eventID string) (events.Event, int, bool) {
+	pane := m.panes.GetPane(m.where[eventID])
+	if pane == nil {
+		return events.Event{}, 0, false
+	}
+	for i, event := range pane.Events {
+		if event.ID == eventID {
+			return event, i, true
+		}
+	}
+	return events.Event{}, 0, false
+}
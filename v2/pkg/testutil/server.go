@@ -0,0 +1,17 @@
+package testutil
+
+import (
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats-server/v2/test"
+)
+
+// StartServer runs an in-process nats-server with JetStream on a free
+// loopback port, storing streams under dir
+// It panics if the server doesn't start, as nats-server's own test helpers do
+func StartServer(dir string) *server.Server {
+	opts := test.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = dir
+	return test.RunServer(&opts)
+}
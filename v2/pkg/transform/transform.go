@@ -0,0 +1,217 @@
+// Package transform applies user-defined mutators to incoming events before
+// they are routed to panes: redacting secrets, rewriting panes, deriving
+// severity and dropping noise.
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Redacted replaces the value of any Data field matching a redact pattern
+const Redacted = "[REDACTED]"
+
+// Match selects the events a rule applies to
+// All non-empty fields must match; an empty Match matches every event
+type Match struct {
+	Type     string `json:"type,omitempty"`     // Glob against Event.Type (e.g. "agent.*")
+	Pane     string `json:"pane,omitempty"`     // Exact Event.Pane
+	Contains string `json:"contains,omitempty"` // Substring of Event.Message
+}
+
+// Rule is a single transformation step
+// Steps run in the order: drop, redact, pane, severity
+type Rule struct {
+	Match    Match    `json:"match"`
+	Drop     bool     `json:"drop,omitempty"`     // Discard the event entirely
+	Redact   []string `json:"redact,omitempty"`   // Glob patterns for Data keys to mask (e.g. "*_token")
+	Pane     string   `json:"pane,omitempty"`     // Rewrite target pane
+	Severity string   `json:"severity,omitempty"` // Set Event.Severity
+}
+
+// Config is the on-disk pipeline definition
+type Config struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Pipeline applies rules to events in order
+type Pipeline struct {
+	rules []Rule
+}
+
+// New creates a pipeline from rules, validating their patterns
+func New(rules []Rule) (*Pipeline, error) {
+	for i, rule := range rules {
+		if rule.Match.Type != "" {
+			if _, err := path.Match(rule.Match.Type, ""); err != nil {
+				return nil, fmt.Errorf("rule[%d]: invalid type pattern %q: %w", i, rule.Match.Type, err)
+			}
+		}
+		for _, pattern := range rule.Redact {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule[%d]: invalid redact pattern %q: %w", i, pattern, err)
+			}
+		}
+		if rule.Severity != "" {
+			if _, err := events.ParseSeverity(rule.Severity); err != nil {
+				return nil, fmt.Errorf("rule[%d]: %w", i, err)
+			}
+		}
+	}
+	return &Pipeline{rules: rules}, nil
+}
+
+// Parse creates a pipeline from a JSON config
+func Parse(data []byte) (*Pipeline, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return New(cfg.Rules)
+}
+
+// Load creates a pipeline from a JSON config file
+func Load(filename string) (*Pipeline, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Rules returns the pipeline's rules
+func (p *Pipeline) Rules() []Rule {
+	return p.rules
+}
+
+// Apply runs the event through every matching rule
+// Returns the transformed event and false if a rule dropped it
+// The input event is never modified; Data is copied before redaction
+func (p *Pipeline) Apply(event events.Event) (events.Event, bool) {
+	if p == nil {
+		return event, true
+	}
+
+	copied := false
+	for _, rule := range p.rules {
+		if !rule.Match.Matches(event) {
+			continue
+		}
+
+		if rule.Drop {
+			return event, false
+		}
+
+		if len(rule.Redact) > 0 && event.Data != nil {
+			if !copied {
+				event.Data = copyMap(event.Data)
+				copied = true
+			}
+			redact(event.Data, rule.Redact)
+		}
+
+		if rule.Pane != "" {
+			event.Pane = rule.Pane
+		}
+
+		if rule.Severity != "" {
+			event.Severity = events.NormalizeSeverity(rule.Severity)
+		}
+	}
+
+	return event, true
+}
+
+// Matches reports whether the event satisfies every non-empty criterion
+func (m Match) Matches(event events.Event) bool {
+	if m.Type != "" {
+		if ok, _ := path.Match(m.Type, event.Type); !ok {
+			return false
+		}
+	}
+	if m.Pane != "" && m.Pane != event.Pane {
+		return false
+	}
+	if m.Contains != "" && !strings.Contains(event.Message, m.Contains) {
+		return false
+	}
+	return true
+}
+
+// RedactData returns a deep copy of data with values whose key matches any pattern masked
+// The original map is left intact
+func RedactData(data map[string]interface{}, patterns []string) map[string]interface{} {
+	if data == nil || len(patterns) == 0 {
+		return data
+	}
+	copied := copyMap(data)
+	redact(copied, patterns)
+	return copied
+}
+
+// redact masks values whose key matches any pattern, descending into nested objects and arrays
+func redact(data map[string]interface{}, patterns []string) {
+	for key, value := range data {
+		if matchesAny(key, patterns) {
+			data[key] = Redacted
+			continue
+		}
+		redactValue(value, patterns)
+	}
+}
+
+// redactValue descends into nested values looking for keys to redact
+func redactValue(value interface{}, patterns []string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redact(v, patterns)
+	case []interface{}:
+		for _, item := range v {
+			redactValue(item, patterns)
+		}
+	}
+}
+
+// matchesAny reports whether key matches one of the glob patterns (case-insensitive)
+func matchesAny(key string, patterns []string) bool {
+	lower := strings.ToLower(key)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), lower); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// copyMap deep-copies a JSON-style map so redaction never touches the original
+func copyMap(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		out[key] = copyValue(value)
+	}
+	return out
+}
+
+// copyValue deep-copies nested maps and slices
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copyMap(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = copyValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,238 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// mustNew returns a pipeline of rules, failing the test if they're invalid
+func mustNew(t *testing.T, rules ...Rule) *Pipeline {
+	t.Helper()
+	p, err := New(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestMatch(t *testing.T) {
+	event := events.Event{Type: "agent.log", Pane: "right", Message: "build finished"}
+	tests := []struct {
+		name  string
+		match Match
+		want  bool
+	}{
+		{"empty", Match{}, true},
+		{"type glob", Match{Type: "agent.*"}, true},
+		{"type exact", Match{Type: "agent.log"}, true},
+		{"type other", Match{Type: "deploy.*"}, false},
+		{"pane", Match{Pane: "right"}, true},
+		{"pane other", Match{Pane: "left"}, false},
+		{"contains", Match{Contains: "finished"}, true},
+		{"contains other", Match{Contains: "failed"}, false},
+		{"all", Match{Type: "agent.*", Pane: "right", Contains: "build"}, true},
+		{"all but one", Match{Type: "agent.*", Pane: "left", Contains: "build"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.match.Matches(event); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDrop(t *testing.T) {
+	p := mustNew(t, Rule{Match: Match{Type: "progress.*"}, Drop: true})
+	tests := []struct {
+		eventType string
+		keep      bool
+	}{
+		{"progress.tick", false},
+		{"progress", true},
+		{"deploy", true},
+	}
+	for _, tt := range tests {
+		if _, keep := p.Apply(events.Event{Type: tt.eventType}); keep != tt.keep {
+			t.Errorf("%s: kept = %v, want %v", tt.eventType, keep, tt.keep)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		data     map[string]interface{}
+		want     map[string]interface{}
+	}{
+		{
+			name:     "top level",
+			patterns: []string{"*_token", "password"},
+			data:     map[string]interface{}{"api_token": "s3cret", "password": "hunter2", "user": "alice"},
+			want:     map[string]interface{}{"api_token": Redacted, "password": Redacted, "user": "alice"},
+		},
+		{
+			name:     "case-insensitive",
+			patterns: []string{"*_token"},
+			data:     map[string]interface{}{"API_TOKEN": "s3cret"},
+			want:     map[string]interface{}{"API_TOKEN": Redacted},
+		},
+		{
+			name:     "nested",
+			patterns: []string{"secret"},
+			data: map[string]interface{}{
+				"db":    map[string]interface{}{"host": "h", "secret": "x"},
+				"hosts": []interface{}{map[string]interface{}{"secret": "y"}, "plain"},
+			},
+			want: map[string]interface{}{
+				"db":    map[string]interface{}{"host": "h", "secret": Redacted},
+				"hosts": []interface{}{map[string]interface{}{"secret": Redacted}, "plain"},
+			},
+		},
+		{
+			name:     "whole object",
+			patterns: []string{"credentials"},
+			data:     map[string]interface{}{"credentials": map[string]interface{}{"key": "k"}},
+			want:     map[string]interface{}{"credentials": Redacted},
+		},
+		{
+			name:     "no data",
+			patterns: []string{"*"},
+			data:     nil,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := mustNew(t, Rule{Redact: tt.patterns})
+			got, keep := p.Apply(events.Event{Data: tt.data})
+			if !keep {
+				t.Fatal("redaction dropped the event")
+			}
+			if !reflect.DeepEqual(got.Data, tt.want) {
+				t.Errorf("Data = %v, want %v", got.Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactLeavesInputIntact(t *testing.T) {
+	nested := map[string]interface{}{"secret": "x"}
+	event := events.Event{Data: map[string]interface{}{"token": "t", "nested": nested}}
+	p := mustNew(t, Rule{Redact: []string{"token", "secret"}})
+	p.Apply(event)
+	if event.Data["token"] != "t" || nested["secret"] != "x" {
+		t.Fatalf("Apply modified the caller's data: %v", event.Data)
+	}
+}
+
+func TestRewritePaneAndSeverity(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         Rule
+		event        events.Event
+		wantPane     string
+		wantSeverity string
+	}{
+		{"pane", Rule{Match: Match{Type: "deploy"}, Pane: "right"}, events.Event{Type: "deploy", Pane: "left"}, "right", ""},
+		{"severity", Rule{Match: Match{Type: "*.error"}, Severity: "ERROR"}, events.Event{Type: "build.error"}, "", events.SeverityError},
+		{"severity alias", Rule{Severity: "warning"}, events.Event{Type: "x"}, "", events.SeverityWarn},
+		{"no match", Rule{Match: Match{Type: "deploy"}, Pane: "right", Severity: "error"}, events.Event{Type: "log", Pane: "left"}, "left", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := mustNew(t, tt.rule).Apply(tt.event)
+			if got.Pane != tt.wantPane || got.Severity != tt.wantSeverity {
+				t.Errorf("pane %q, severity %q; want %q, %q", got.Pane, got.Severity, tt.wantPane, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestRuleOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []Rule
+		event    events.Event
+		keep     bool
+		wantPane string
+	}{
+		{
+			name: "later rules see earlier rewrites",
+			rules: []Rule{
+				{Match: Match{Type: "agent.*"}, Pane: "agents"},
+				{Match: Match{Pane: "agents", Contains: "heartbeat"}, Drop: true},
+			},
+			event: events.Event{Type: "agent.log", Message: "heartbeat"},
+			keep:  false,
+		},
+		{
+			name: "earlier rules don't see later rewrites",
+			rules: []Rule{
+				{Match: Match{Pane: "agents"}, Drop: true},
+				{Match: Match{Type: "agent.*"}, Pane: "agents"},
+			},
+			event:    events.Event{Type: "agent.log"},
+			keep:     true,
+			wantPane: "agents",
+		},
+		{
+			name: "last rewrite wins",
+			rules: []Rule{
+				{Pane: "first"},
+				{Match: Match{Type: "deploy"}, Pane: "second"},
+			},
+			event:    events.Event{Type: "deploy"},
+			keep:     true,
+			wantPane: "second",
+		},
+		{
+			name:  "drop comes first within a rule",
+			rules: []Rule{{Drop: true, Pane: "never"}},
+			event: events.Event{Type: "x"},
+			keep:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, keep := mustNew(t, tt.rules...).Apply(tt.event)
+			if keep != tt.keep {
+				t.Fatalf("kept = %v, want %v", keep, tt.keep)
+			}
+			if keep && got.Pane != tt.wantPane {
+				t.Errorf("pane = %q, want %q", got.Pane, tt.wantPane)
+			}
+		})
+	}
+}
+
+func TestNilPipelineKeepsEvents(t *testing.T) {
+	var p *Pipeline
+	event := events.Event{Type: "x", Pane: "left"}
+	if got, keep := p.Apply(event); !keep || !reflect.DeepEqual(got, event) {
+		t.Fatalf("nil pipeline changed the event: %v, %v", got, keep)
+	}
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	tests := map[string]string{
+		"not JSON":         `{`,
+		"type pattern":     `{"rules": [{"match": {"type": "["}}]}`,
+		"redact pattern":   `{"rules": [{"redact": ["["]}]}`,
+		"unknown severity": `{"rules": [{"severity": "loud"}]}`,
+	}
+	for name, config := range tests {
+		if _, err := Parse([]byte(config)); err == nil {
+			t.Errorf("%s: Parse accepted %s", name, config)
+		}
+	}
+
+	p, err := Parse([]byte(`{"rules": [{"match": {"type": "progress.*"}, "drop": true}, {"redact": ["*_token"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Rules()) != 2 {
+		t.Fatalf("parsed %d rules, want 2", len(p.Rules()))
+	}
+}
@@ -0,0 +1,217 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPendingLimit is how many messages a Memory subscription queues for
+// a slow handler before dropping new ones, as NATS does for slow consumers
+const DefaultPendingLimit = 65536
+
+// ErrClosed is returned by a closed Memory transport
+var ErrClosed = errors.New("transport closed")
+
+// errUnsubscribed is returned by Unsubscribe for a subscription already stopped
+var errUnsubscribed = errors.New("subscription already stopped")
+
+// Memory is an in-process transport: messages published to it are delivered
+// to its own subscribers, nothing leaves the process
+// Each subscription has a goroutine of its own, so a handler may publish
+// (or request) without deadlocking; the zero value is ready to use
+type Memory struct {
+	mu     sync.RWMutex
+	subs   map[*memorySub]bool
+	closed bool
+}
+
+// NewMemory creates an in-process transport
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// memorySub is a subscription to a Memory transport
+type memorySub struct {
+	m       *Memory
+	pattern string
+	handler Handler
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []Msg
+	stopped bool
+}
+
+// Publish delivers the message to every subscription matching its subject
+func (m *Memory) Publish(msg Msg) error {
+	if err := validSubject(msg.Subject); err != nil {
+		return err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrClosed
+	}
+	for sub := range m.subs {
+		if Match(sub.pattern, msg.Subject) {
+			sub.enqueue(msg)
+		}
+	}
+	return nil
+}
+
+// Subscribe starts delivering messages on subjects matching pattern
+func (m *Memory) Subscribe(pattern string, handler Handler) (Subscription, error) {
+	if err := validSubject(pattern); err != nil {
+		return nil, err
+	}
+	sub := &memorySub{m: m, pattern: pattern, handler: handler}
+	sub.cond = sync.NewCond(&sub.mu)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+	if m.subs == nil {
+		m.subs = make(map[*memorySub]bool)
+	}
+	m.subs[sub] = true
+	go sub.deliver()
+	return sub, nil
+}
+
+// Request publishes msg with a fresh inbox as its reply subject and waits for the first response
+func (m *Memory) Request(ctx context.Context, msg Msg) (Msg, error) {
+	if !m.hasSubscribers(msg.Subject) {
+		return Msg{}, ErrNoResponders
+	}
+	replies := make(chan Msg, 1)
+	msg.Reply = NewInbox()
+	sub, err := m.Subscribe(msg.Reply, func(reply Msg) {
+		select {
+		case replies <- reply:
+		default: // Only the first response counts
+		}
+	})
+	if err != nil {
+		return Msg{}, err
+	}
+	defer sub.Unsubscribe()
+
+	if err := m.Publish(msg); err != nil {
+		return Msg{}, err
+	}
+	select {
+	case reply := <-replies:
+		return reply, nil
+	case <-ctx.Done():
+		return Msg{}, ctx.Err()
+	}
+}
+
+// Flush returns at once: Publish queued every message already
+func (m *Memory) Flush(time.Duration) error {
+	return nil
+}
+
+// Close stops every subscription; later publishes and subscribes fail
+func (m *Memory) Close() {
+	m.mu.Lock()
+	subs := m.subs
+	m.subs, m.closed = nil, true
+	m.mu.Unlock()
+	for sub := range subs {
+		sub.stop()
+	}
+}
+
+// hasSubscribers reports whether any subscription matches a subject
+func (m *Memory) hasSubscribers(subject string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for sub := range m.subs {
+		if Match(sub.pattern, subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unsubscribe stops the subscription; messages still queued are dropped
+func (s *memorySub) Unsubscribe() error {
+	s.m.mu.Lock()
+	_, ok := s.m.subs[s]
+	delete(s.m.subs, s)
+	s.m.mu.Unlock()
+	if !ok {
+		return errUnsubscribed
+	}
+	s.stop()
+	return nil
+}
+
+// enqueue queues a message for the handler, dropping it past DefaultPendingLimit
+func (s *memorySub) enqueue(msg Msg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped || len(s.pending) >= DefaultPendingLimit {
+		return
+	}
+	s.pending = append(s.pending, msg)
+	s.cond.Signal()
+}
+
+// stop ends delivery
+func (s *memorySub) stop() {
+	s.mu.Lock()
+	s.stopped, s.pending = true, nil
+	s.cond.Signal()
+	s.mu.Unlock()
+}
+
+// deliver hands queued messages to the handler, one at a time, until stopped
+func (s *memorySub) deliver() {
+	for {
+		s.mu.Lock()
+		for len(s.pending) == 0 && !s.stopped {
+			s.cond.Wait()
+		}
+		if s.stopped {
+			s.mu.Unlock()
+			return
+		}
+		msg := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+		s.handler(msg)
+	}
+}
+
+// Match reports whether a subject matches a pattern: "*" matches one token
+// and a final ">" one or more
+func Match(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" && i == len(patternTokens)-1 {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) || (token != "*" && token != subjectTokens[i]) {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
+
+// validSubject rejects empty subjects and ones with empty tokens
+func validSubject(subject string) error {
+	if subject == "" || strings.Contains(subject, "..") || strings.HasPrefix(subject, ".") || strings.HasSuffix(subject, ".") {
+		return fmt.Errorf("invalid subject %q", subject)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATS is the transport over a NATS connection
+// The connection stays the caller's: connecting, reconnecting and closing are
+// done with natsconn as before
+type NATS struct {
+	Conn *nats.Conn
+}
+
+// Publish publishes the message; call Flush to wait for the server
+func (t NATS) Publish(msg Msg) error {
+	return t.Conn.PublishMsg(toNATS(msg))
+}
+
+// Subscribe subscribes asynchronously: handlers run on the subscription's own goroutine
+func (t NATS) Subscribe(pattern string, handler Handler) (Subscription, error) {
+	return t.Conn.Subscribe(pattern, func(msg *nats.Msg) {
+		handler(fromNATS(msg))
+	})
+}
+
+// Request sends a request through the connection's response multiplexer
+func (t NATS) Request(ctx context.Context, msg Msg) (Msg, error) {
+	reply, err := t.Conn.RequestMsgWithContext(ctx, toNATS(msg))
+	if errors.Is(err, nats.ErrNoResponders) {
+		return Msg{}, ErrNoResponders
+	}
+	if err != nil {
+		return Msg{}, err
+	}
+	return fromNATS(reply), nil
+}
+
+// Flush waits for the server to take every published message
+func (t NATS) Flush(timeout time.Duration) error {
+	return t.Conn.FlushTimeout(timeout)
+}
+
+// toNATS converts a message for publishing
+func toNATS(msg Msg) *nats.Msg {
+	return &nats.Msg{Subject: msg.Subject, Reply: msg.Reply, Header: nats.Header(msg.Header), Data: msg.Data}
+}
+
+// fromNATS converts a received message
+func fromNATS(msg *nats.Msg) Msg {
+	return Msg{Subject: msg.Subject, Reply: msg.Reply, Header: Header(msg.Header), Data: msg.Data}
+}
@@ -0,0 +1,142 @@
+// Package transport is the messaging the monitor and publishers need from a
+// backend: publishing to subjects, subscribing to subject patterns and
+// request/reply. NATS is the backend agneto ships with (see NATS); Memory
+// connects components within one process, e.g. tests or an embedded monitor.
+//
+// Subjects are dot-separated tokens as in NATS; patterns match one token with
+// "*" and the remaining tokens with a final ">".
+//
+// AIDEV-NOTE: JetStream (history replay, durable consumers, edge streams) has
+// no equivalent here and stays NATS-only in natsconn and monitor; a backend
+// without it still gets live events, responses and operator coordination.
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nuid"
+)
+
+// ErrNoResponders is returned by Request when nobody subscribes to the subject
+var ErrNoResponders = errors.New("no responders available for request")
+
+// Header holds message headers such as the Content-Type events are decoded by
+// It has the layout of nats.Header, so headers convert without copying
+type Header map[string][]string
+
+// Get returns the first value of a header, or ""
+func (h Header) Get(key string) string {
+	if values := h[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Set replaces the values of a header
+func (h Header) Set(key, value string) {
+	h[key] = []string{value}
+}
+
+// Msg is a message published to a subject
+type Msg struct {
+	Subject string
+	Reply   string // Subject a response goes to (requests), or ""
+	Header  Header // May be nil
+	Data    []byte
+}
+
+// NewMsg creates a message with an empty header to set values on
+func NewMsg(subject string, data []byte) Msg {
+	return Msg{Subject: subject, Header: Header{}, Data: data}
+}
+
+// Handler receives the messages of a subscription
+// Messages of one subscription are handed over one at a time, in order
+type Handler func(Msg)
+
+// Subscription stops a Subscribe
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Transport connects to a message backend
+type Transport interface {
+	// Publish sends a message; it may be buffered (see Flusher)
+	Publish(msg Msg) error
+
+	// Subscribe calls handler with every message on subjects matching pattern
+	Subscribe(pattern string, handler Handler) (Subscription, error)
+
+	// Request publishes msg with a fresh reply subject and returns the first
+	// response, or ErrNoResponders, or ctx's error
+	Request(ctx context.Context, msg Msg) (Msg, error)
+}
+
+// Flusher is implemented by transports that buffer published messages
+type Flusher interface {
+	// Flush returns once the backend took every message published so far
+	Flush(timeout time.Duration) error
+}
+
+// Flush waits for a transport's buffered messages, if it buffers any
+func Flush(t Transport, timeout time.Duration) error {
+	if f, ok := t.(Flusher); ok {
+		return f.Flush(timeout)
+	}
+	return nil
+}
+
+// Publisher publishes raw payloads through a transport, as the outbox
+// delivers its entries (it satisfies outbox.Publisher)
+type Publisher struct {
+	Transport Transport
+}
+
+// Publish publishes data to subject
+func (p Publisher) Publish(subject string, data []byte) error {
+	return p.Transport.Publish(Msg{Subject: subject, Data: data})
+}
+
+// FlushTimeout waits for the transport's buffered messages (see Flush), so
+// the outbox only drops an entry once the backend has it
+func (p Publisher) FlushTimeout(timeout time.Duration) error {
+	return Flush(p.Transport, timeout)
+}
+
+// NewInbox returns a unique subject to receive responses on
+func NewInbox() string {
+	return "_INBOX." + nuid.Next()
+}
+
+// dropped counts the messages Chan dropped because their channel was full
+var dropped atomic.Uint64
+
+// Dropped returns how many messages Chan subscriptions have dropped so far
+func Dropped() uint64 {
+	return dropped.Load()
+}
+
+// Chan subscribes to pattern, sending its messages to ch
+// Messages arriving while ch is full are dropped, as with nats.ChanSubscribe,
+// and counted (see Dropped)
+func Chan(t Transport, pattern string, ch chan Msg) (Subscription, error) {
+	return t.Subscribe(pattern, func(msg Msg) {
+		select {
+		case ch <- msg:
+		default:
+			dropped.Add(1)
+		}
+	})
+}
+
+// Respond publishes data to a message's reply subject
+// Messages without one are ignored
+func Respond(t Transport, msg Msg, data []byte) error {
+	if msg.Reply == "" {
+		return nil
+	}
+	return t.Publish(Msg{Subject: msg.Reply, Data: data})
+}
@@ -0,0 +1,30 @@
+package transport
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChanCountsDrops(t *testing.T) {
+	m := NewMemory()
+	defer m.Close()
+	ch := make(chan Msg, 1)
+	if _, err := Chan(m, "a.>", ch); err != nil {
+		t.Fatal(err)
+	}
+
+	before := Dropped()
+	for _, data := range []string{"first", "second", "third"} {
+		if err := m.Publish(Msg{Subject: "a.b", Data: []byte(data)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for deadline := time.Now().Add(time.Second); Dropped()-before < 2; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("dropped %d messages, want 2", Dropped()-before)
+		}
+	}
+	if msg := <-ch; string(msg.Data) != "first" {
+		t.Fatalf("channel holds %q, want the first message", msg.Data)
+	}
+}
@@ -1,15 +1,33 @@
 package tui
 
 import (
-	"sort"
+	"time"
 
 	"github.com/durch/agneto/v2/pkg/events"
 )
 
+// ActionDebounce ignores action keys pressed this soon after an action was triggered
+// Terminal key repeat would otherwise answer the next event with the same key
+var ActionDebounce = 300 * time.Millisecond
+
+// QueuedDecision is an event whose actions wait behind the active decision
+type QueuedDecision struct {
+	EventID string
+	Actions []events.Action
+	Since   time.Time // When the decision started waiting
+}
+
 // ActionManager manages dynamic actions (buttons) that can be triggered by user input
+// One decision is active (its keys trigger actions); decisions arriving
+// meanwhile wait in a queue instead of halting the event stream
 type ActionManager struct {
 	activeActions map[string]events.Action // Map key → Action
+	keys          []string                 // Keys in the order the producer declared them
 	eventIndex    int                      // Index of event these actions belong to
+	inFlight      bool                     // A triggered action's response isn't queued yet
+	triggeredAt   time.Time                // When the last action was triggered
+	triggeredKeys map[string]bool          // Keys of the decision the last key press answered
+	queue         []QueuedDecision         // Decisions waiting behind the active one, oldest first
 }
 
 // NewActionManager creates a new action manager
@@ -24,9 +42,14 @@ func NewActionManager() *ActionManager {
 func (am *ActionManager) RegisterActions(actions []events.Action, eventIndex int) {
 	// Clear previous actions (only one event can have pending actions at a time)
 	am.activeActions = make(map[string]events.Action)
+	am.keys = nil
 	am.eventIndex = eventIndex
+	am.inFlight = false // A fresh decision
 
 	for _, action := range actions {
+		if _, exists := am.activeActions[action.Key]; !exists {
+			am.keys = append(am.keys, action.Key)
+		}
 		am.activeActions[action.Key] = action
 	}
 }
@@ -38,30 +61,63 @@ func (am *ActionManager) GetEventIndex() int {
 
 // HandleKeyPress checks if a key matches an active action
 // If found, returns the action and removes ALL active actions (making a decision clears all options)
+// Keys are ignored while a triggered action is in flight and within ActionDebounce of it
 func (am *ActionManager) HandleKeyPress(key string) (events.Action, bool) {
+	if am.inFlight || time.Since(am.triggeredAt) < ActionDebounce {
+		return events.Action{}, false
+	}
 	if action, exists := am.activeActions[key]; exists {
+		am.triggeredKeys = make(map[string]bool, len(am.keys))
+		for _, k := range am.keys {
+			am.triggeredKeys[k] = true
+		}
 		am.ClearAll() // Clear all actions - once you make a decision, other options disappear
+		am.MarkInFlight()
 		return action, true
 	}
 	return events.Action{}, false
 }
 
-// GetActiveActions returns a sorted list of currently active actions
-// Sorted by key for consistent rendering
+// Bound reports whether an active action is triggered by key
+// The keys of a decision just answered stay bound while its response is in
+// flight and within ActionDebounce, so a repeated press is ignored instead of
+// reaching a shortcut on the same key
+func (am *ActionManager) Bound(key string) bool {
+	if _, exists := am.activeActions[key]; exists {
+		return true
+	}
+	return am.triggeredKeys[key] && (am.inFlight || time.Since(am.triggeredAt) < ActionDebounce)
+}
+
+// MarkInFlight records that an action was triggered and its response is being published
+// Used directly when an action is triggered without a key (defaults, hooks)
+func (am *ActionManager) MarkInFlight() {
+	am.inFlight = true
+	am.triggeredAt = time.Now()
+}
+
+// InFlight reports whether a triggered action's response isn't queued yet
+func (am *ActionManager) InFlight() bool {
+	return am.inFlight
+}
+
+// Settle records that the in-flight response is safely queued
+func (am *ActionManager) Settle() {
+	am.inFlight = false
+}
+
+// GetActiveActions returns the currently active actions in display order
+// Ordered by the actions' Order hints, then as the producer declared them
 func (am *ActionManager) GetActiveActions() []events.Action {
 	if len(am.activeActions) == 0 {
 		return []events.Action{}
 	}
 
 	actions := make([]events.Action, 0, len(am.activeActions))
-	for _, action := range am.activeActions {
-		actions = append(actions, action)
+	for _, key := range am.keys {
+		actions = append(actions, am.activeActions[key])
 	}
-
-	// Sort by key for predictable order
-	sort.Slice(actions, func(i, j int) bool {
-		return actions[i].Key < actions[j].Key
-	})
+	events.SortActions(actions)
 
 	return actions
 }
@@ -69,9 +125,49 @@ func (am *ActionManager) GetActiveActions() []events.Action {
 // ClearAll removes all active actions
 func (am *ActionManager) ClearAll() {
 	am.activeActions = make(map[string]events.Action)
+	am.keys = nil
 }
 
 // HasActions returns true if there are any active actions
 func (am *ActionManager) HasActions() bool {
 	return len(am.activeActions) > 0
 }
+
+// Enqueue adds a decision to the back of the queue
+// A decision already queued for the same event is replaced
+func (am *ActionManager) Enqueue(decision QueuedDecision) {
+	am.Remove(decision.EventID)
+	am.queue = append(am.queue, decision)
+}
+
+// Next removes and returns the decision at the front of the queue
+func (am *ActionManager) Next() (QueuedDecision, bool) {
+	if len(am.queue) == 0 {
+		return QueuedDecision{}, false
+	}
+	decision := am.queue[0]
+	am.queue = am.queue[1:]
+	return decision, true
+}
+
+// Remove drops an event's decision from the queue (answered elsewhere, evicted)
+// Returns false if it wasn't queued
+func (am *ActionManager) Remove(eventID string) bool {
+	for i, decision := range am.queue {
+		if decision.EventID == eventID {
+			am.queue = append(am.queue[:i:i], am.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Queued returns the decisions waiting behind the active one, in queue order
+func (am *ActionManager) Queued() []QueuedDecision {
+	return append([]QueuedDecision(nil), am.queue...)
+}
+
+// QueueLen returns how many decisions wait behind the active one
+func (am *ActionManager) QueueLen() int {
+	return len(am.queue)
+}
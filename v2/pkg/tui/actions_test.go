@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// yesNo are the actions of a yes/no decision
+var yesNo = []events.Action{
+	{ID: "yes", Label: "Yes", Key: "y"},
+	{ID: "no", Label: "No", Key: "n"},
+}
+
+// withDebounce sets ActionDebounce for the rest of a test
+func withDebounce(t *testing.T, debounce time.Duration) {
+	previous := ActionDebounce
+	t.Cleanup(func() { ActionDebounce = previous })
+	ActionDebounce = debounce
+}
+
+func TestHandleKeyPressRepeated(t *testing.T) {
+	withDebounce(t, time.Hour)
+	am := NewActionManager()
+	am.RegisterActions(yesNo, 0)
+
+	action, ok := am.HandleKeyPress("y")
+	if !ok || action.ID != "yes" {
+		t.Fatalf("first press = %q, %v; want yes", action.ID, ok)
+	}
+	for _, key := range []string{"y", "y", "n"} {
+		if _, ok := am.HandleKeyPress(key); ok {
+			t.Fatalf("press of %q after the decision was answered triggered an action", key)
+		}
+		if !am.Bound(key) {
+			t.Fatalf("%q isn't bound while the answer is in flight; it would reach a shortcut", key)
+		}
+	}
+	if am.Bound("s") {
+		t.Fatal("a key the decision didn't have is bound")
+	}
+}
+
+func TestHandleKeyPressDebounce(t *testing.T) {
+	withDebounce(t, 50*time.Millisecond)
+	am := NewActionManager()
+	am.RegisterActions(yesNo, 0)
+	if _, ok := am.HandleKeyPress("y"); !ok {
+		t.Fatal("first press ignored")
+	}
+	am.Settle()
+
+	// The next decision is shown straight away; a held key must not answer it
+	am.RegisterActions(yesNo, 1)
+	if _, ok := am.HandleKeyPress("y"); ok {
+		t.Fatal("press within the debounce answered the next decision")
+	}
+	if !am.HasActions() {
+		t.Fatal("an ignored press cleared the next decision's actions")
+	}
+
+	time.Sleep(ActionDebounce)
+	if action, ok := am.HandleKeyPress("n"); !ok || action.ID != "no" {
+		t.Fatalf("press after the debounce = %q, %v; want no", action.ID, ok)
+	}
+}
+
+func TestBoundExpiresOnceSettled(t *testing.T) {
+	withDebounce(t, 0)
+	am := NewActionManager()
+	am.RegisterActions(yesNo, 0)
+	am.HandleKeyPress("y")
+	if !am.Bound("y") {
+		t.Fatal("key not bound while in flight")
+	}
+	am.Settle()
+	if am.Bound("y") {
+		t.Fatal("key of an answered decision still bound once settled and debounced")
+	}
+}
+
+func TestInFlightBlocksKeys(t *testing.T) {
+	withDebounce(t, 0)
+	am := NewActionManager()
+	am.RegisterActions(yesNo, 0)
+	am.MarkInFlight() // Answered without a key, e.g. by a hook
+	if _, ok := am.HandleKeyPress("y"); ok {
+		t.Fatal("press while an answer is in flight triggered an action")
+	}
+	am.Settle()
+	if _, ok := am.HandleKeyPress("y"); !ok {
+		t.Fatal("press once settled ignored")
+	}
+}
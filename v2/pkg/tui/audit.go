@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// MaxAuditEntries bounds the entries an audit log keeps in memory; the
+// oldest are dropped first (the --audit-log file keeps them all)
+const MaxAuditEntries = 10000
+
+// AuditEntry records an action taken on an event: who answered it with
+// what, and when
+type AuditEntry struct {
+	Time       time.Time   `json:"time"`
+	EventID    string      `json:"event_id"`
+	EventIndex int         `json:"event_index"` // Position in its pane when answered (-1 if no longer in memory)
+	Pane       string      `json:"pane,omitempty"`
+	EventType  string      `json:"event_type,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	ActionID   string      `json:"action_id,omitempty"`
+	Label      string      `json:"label,omitempty"`
+	Response   string      `json:"response_type,omitempty"` // Type of the response published
+	Responder  string      `json:"responder,omitempty"`     // Operator, or the policy rule that answered
+	Via        string      `json:"via"`                     // tui, hook, policy, timeout, or where an answer given elsewhere came from (web, slack)
+	Input      interface{} `json:"input,omitempty"`         // Typed text, picked option or confirmation
+	Reasons    []string    `json:"reasons,omitempty"`       // Reason codes picked
+	Approvers  []string    `json:"approvers,omitempty"`     // Operators who approved a four-eyes action
+	Approval   string      `json:"approval,omitempty"`      // "1/2" for an approval short of the count a four-eyes action needs
+	Deferred   bool        `json:"deferred,omitempty"`      // The response waited in the outbox
+}
+
+// AuditLog keeps the actions taken, oldest first, optionally appending them
+// to a file as JSON Lines
+type AuditLog struct {
+	entries []AuditEntry
+	file    *os.File // nil without a file
+}
+
+// NewAuditLog creates an audit log kept in memory only
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+// OpenAuditLog opens (or creates) an audit log file for appending, loading
+// the entries earlier runs recorded in it
+func OpenAuditLog(path string) (*AuditLog, error) {
+	l := &AuditLog{}
+	if err := l.load(path); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	l.file = file
+	return l, nil
+}
+
+// load reads the entries of an audit log file; a missing file has none
+// Lines that don't parse (e.g. one cut short by a crash) are skipped
+func (l *AuditLog) load(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			l.keep(entry)
+		}
+	}
+	return scanner.Err()
+}
+
+// Add records an action, appending it to the file if there is one
+// The entry is kept in memory even if writing the file fails
+func (l *AuditLog) Add(entry AuditEntry) error {
+	l.keep(entry)
+	if l.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = l.file.Write(append(line, '\n'))
+	return err
+}
+
+// keep adds an entry in memory, dropping the oldest past MaxAuditEntries
+func (l *AuditLog) keep(entry AuditEntry) {
+	l.entries = append(l.entries, entry)
+	if over := len(l.entries) - MaxAuditEntries; over > 0 {
+		l.entries = append([]AuditEntry(nil), l.entries[over:]...)
+	}
+}
+
+// Entries returns the recorded actions, oldest first
+func (l *AuditLog) Entries() []AuditEntry {
+	return l.entries
+}
+
+// Len returns how many actions are recorded
+func (l *AuditLog) Len() int {
+	return len(l.entries)
+}
+
+// WriteJSON writes the recorded actions to w as an indented JSON array
+func (l *AuditLog) WriteJSON(w io.Writer) error {
+	entries := l.entries
+	if entries == nil {
+		entries = []AuditEntry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// Close closes the audit log file, if any
+func (l *AuditLog) Close() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
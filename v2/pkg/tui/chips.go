@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// DefaultMaxChips is the chip limit per row when none is configured
+const DefaultMaxChips = 3
+
+// maxChipValueLen caps how much of a value a chip shows
+const maxChipValueLen = 24
+
+// chipStyle renders a single key-value chip
+var chipStyle = lipgloss.NewStyle().
+	Background(lipgloss.Color("236")).
+	Foreground(lipgloss.Color("250")).
+	Padding(0, 1)
+
+// ChipRule selects which Data keys are shown as chips for matching event types
+type ChipRule struct {
+	Type string   `json:"type"` // Glob against Event.Type
+	Keys []string `json:"keys"` // Data keys (dotted paths allowed, e.g. "result.exit_code")
+}
+
+// ChipConfig configures the quick view chips rendered under event rows
+// The first rule matching an event's type wins
+type ChipConfig struct {
+	Rules []ChipRule
+	Max   int // Maximum chips per row (0 uses DefaultMaxChips)
+}
+
+// Chips returns the "key=value" chips for an event, in rule key order
+// Keys missing from the event's Data are skipped
+func (c ChipConfig) Chips(event events.Event) []string {
+	max := c.Max
+	if max <= 0 {
+		max = DefaultMaxChips
+	}
+
+	for _, rule := range c.Rules {
+		if ok, _ := path.Match(rule.Type, event.Type); !ok {
+			continue
+		}
+		var chips []string
+		for _, key := range rule.Keys {
+			value, ok := event.Field("data." + key)
+			if !ok {
+				continue
+			}
+			text := fmt.Sprint(value)
+			text = Truncate(text, maxChipValueLen)
+			chips = append(chips, fmt.Sprintf("%s=%s", key, text))
+			if len(chips) == max {
+				break
+			}
+		}
+		return chips
+	}
+	return nil
+}
+
+// renderChipRow renders chips as a single indented row, dropping chips that don't fit width
+func renderChipRow(chips []string, width int) string {
+	var row strings.Builder
+	row.WriteString("    ")
+	used := 4
+	for _, chip := range chips {
+		rendered := chipStyle.Render(chip)
+		w := lipgloss.Width(rendered) + 1
+		if used+w > width {
+			break
+		}
+		row.WriteString(rendered)
+		row.WriteString(" ")
+		used += w
+	}
+	return row.String()
+}
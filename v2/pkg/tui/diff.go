@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Diff styles
+var (
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("210"))
+	diffHunkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("75"))
+	diffFileStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255"))
+	diffMetaStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+)
+
+// DiffStat counts the files, added and removed lines of a unified diff
+type DiffStat struct {
+	Files   int
+	Added   int
+	Removed int
+}
+
+// String summarizes the stat, e.g. "2 files, +10 -3"
+func (s DiffStat) String() string {
+	files := "1 file"
+	if s.Files != 1 {
+		files = fmt.Sprintf("%d files", s.Files)
+	}
+	return fmt.Sprintf("%s, +%d -%d", files, s.Added, s.Removed)
+}
+
+// diffLine classifies the lines of a unified diff
+type diffLine int
+
+const (
+	diffContext diffLine = iota
+	diffAdded
+	diffRemoved
+	diffHunk
+	diffFile // ---/+++ file headers
+	diffMeta // diff --git, index, mode and rename lines
+)
+
+// classifyDiff returns the kind of each line of a unified diff
+// Inside a hunk, "--- " starts a file header only when "+++ " follows, so a
+// removed line starting with "--" isn't mistaken for one. A fragment without
+// hunk headers is read as one hunk
+func classifyDiff(lines []string) []diffLine {
+	kinds := make([]diffLine, len(lines))
+	inHunk := !slices.ContainsFunc(lines, func(line string) bool { return strings.HasPrefix(line, "@@") })
+	for i, line := range lines {
+		fileHeader := strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ")
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			kinds[i], inHunk = diffHunk, true
+		case strings.HasPrefix(line, "diff "):
+			kinds[i], inHunk = diffMeta, false
+		case fileHeader:
+			kinds[i], inHunk = diffFile, false
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			kinds[i] = diffFile
+		case strings.HasPrefix(line, `\`): // "\ No newline at end of file"
+			kinds[i] = diffMeta
+		case inHunk && strings.HasPrefix(line, "+"):
+			kinds[i] = diffAdded
+		case inHunk && strings.HasPrefix(line, "-"):
+			kinds[i] = diffRemoved
+		case !inHunk:
+			kinds[i] = diffMeta
+		}
+	}
+	return kinds
+}
+
+// Stat counts what a unified diff changes
+// Files are counted by their +++ headers
+func Stat(diff string) DiffStat {
+	var stat DiffStat
+	lines := strings.Split(diff, "\n")
+	for i, kind := range classifyDiff(lines) {
+		switch {
+		case kind == diffAdded:
+			stat.Added++
+		case kind == diffRemoved:
+			stat.Removed++
+		case kind == diffFile && strings.HasPrefix(lines[i], "+++ "):
+			stat.Files++
+		}
+	}
+	return stat
+}
+
+// RenderDiff colorizes a unified diff: added lines green, removed lines red,
+// hunk headers blue and file headers bold, under a summary of the changes
+// Text that isn't a diff is shown as written, dimmed outside of hunks
+func RenderDiff(diff string) string {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	return diffMetaStyle.Render(Stat(diff).String()) + "\n\n" + renderDiffLines(lines)
+}
+
+// renderDiffLines colorizes the lines of a unified diff
+func renderDiffLines(lines []string) string {
+	out := make([]string, 0, len(lines))
+	for i, kind := range classifyDiff(lines) {
+		line := lines[i]
+		switch kind {
+		case diffAdded:
+			line = diffAddedStyle.Render(line)
+		case diffRemoved:
+			line = diffRemovedStyle.Render(line)
+		case diffHunk:
+			line = diffHunkStyle.Render(line)
+		case diffFile:
+			line = diffFileStyle.Render(line)
+		case diffMeta:
+			line = diffMetaStyle.Render(line)
+		default:
+			line = eventStyle.Render(line)
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
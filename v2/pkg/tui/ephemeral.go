@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"time"
+)
+
+// DefaultIdleMinutes is how long an ephemeral pane stays open without events
+const DefaultIdleMinutes = 10
+
+// PaneOptions configures the panes whose names match a glob
+type PaneOptions struct {
+	Pane        string `json:"pane"`                   // Glob against pane names (e.g. "subtask-*")
+	Ephemeral   bool   `json:"ephemeral,omitempty"`    // Close the pane once idle, archiving its events
+	IdleMinutes int    `json:"idle_minutes,omitempty"` // Minutes without events before an ephemeral pane closes (default 10)
+	Title       string `json:"title,omitempty"`        // Pane title, unless a pane.title event renamed it
+	MaxEvents   int    `json:"max_events,omitempty"`   // Events kept in the pane (default --max-events)
+}
+
+// Validate checks the glob and the idle time
+func (o PaneOptions) Validate() error {
+	if o.Pane == "" {
+		return fmt.Errorf("pane options need a pane glob")
+	}
+	if _, err := path.Match(o.Pane, ""); err != nil {
+		return fmt.Errorf("pane options %q: invalid glob: %w", o.Pane, err)
+	}
+	if o.IdleMinutes < 0 {
+		return fmt.Errorf("pane options %q: idle_minutes can't be negative", o.Pane)
+	}
+	if o.MaxEvents < 0 {
+		return fmt.Errorf("pane options %q: max_events can't be negative", o.Pane)
+	}
+	return nil
+}
+
+// idleTimeout returns how long a matching pane may go without events (0: forever)
+func (o PaneOptions) idleTimeout() time.Duration {
+	if !o.Ephemeral {
+		return 0
+	}
+	if o.IdleMinutes == 0 {
+		return DefaultIdleMinutes * time.Minute
+	}
+	return time.Duration(o.IdleMinutes) * time.Minute
+}
+
+// LastActivity returns when the pane last got an event, or was opened
+func (p *Pane) LastActivity() time.Time {
+	last := p.Opened
+	for _, at := range p.Arrivals {
+		if at.After(last) {
+			last = at
+		}
+	}
+	return last
+}
+
+// SetOptions applies pane options, first matching glob wins, to every pane
+// and the ones created later; the default pane is never ephemeral
+func (pm *PaneManager) SetOptions(options []PaneOptions) {
+	pm.options = options
+	for _, pane := range pm.Panes {
+		pm.applyOptions(pane)
+	}
+}
+
+// applyOptions sets a pane up as the first options matching its name say
+// A configured title doesn't undo runtime renames (pane.title events)
+func (pm *PaneManager) applyOptions(pane *Pane) {
+	pane.IdleTimeout = 0
+	for _, o := range pm.options {
+		if ok, _ := path.Match(o.Pane, pane.Name); ok {
+			if pane.Name != pm.DefaultPane {
+				pane.IdleTimeout = o.idleTimeout()
+			}
+			if o.Title != "" && len(pane.Titles) == 0 {
+				pane.Title = o.Title
+			}
+			if o.MaxEvents > 0 {
+				pane.MaxEvents = o.MaxEvents
+			}
+			return
+		}
+	}
+}
+
+// IdlePanes returns the ephemeral panes idle past their timeout, in order
+func (pm *PaneManager) IdlePanes(now time.Time) []string {
+	var idle []string
+	for _, name := range pm.order {
+		pane := pm.Panes[name]
+		if pane.IdleTimeout > 0 && now.Sub(pane.LastActivity()) >= pane.IdleTimeout {
+			idle = append(idle, name)
+		}
+	}
+	return idle
+}
+
+// ClosePane removes a pane, returning it (nil for unknown panes and the
+// default pane, which events fall back to)
+// Events naming it later open it afresh, as for any unknown pane
+func (pm *PaneManager) ClosePane(name string) *Pane {
+	pane, ok := pm.Panes[name]
+	if !ok || name == pm.DefaultPane {
+		return nil
+	}
+	delete(pm.Panes, name)
+	pm.order = slices.DeleteFunc(pm.order, func(n string) bool { return n == name })
+	return pane
+}
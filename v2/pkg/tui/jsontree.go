@@ -0,0 +1,297 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// PayloadNode is one line of the payload tree: an object key or array item
+type PayloadNode struct {
+	Path  string      // Path from the event root, e.g. data.files[2].path (see events.Event.Field)
+	Depth int         // Nesting level below data
+	Label string      // Key or [index] shown in the tree
+	Value interface{} // The node's value (objects and arrays included)
+}
+
+// Leaf reports whether the node holds a scalar rather than an object or array
+func (n PayloadNode) Leaf() bool {
+	switch n.Value.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return true
+}
+
+// plainKey matches keys that can be written as .key in a path
+var plainKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// PayloadTree flattens an event's Data into tree lines, keys sorted, depth first
+func PayloadTree(data map[string]interface{}) []PayloadNode {
+	var nodes []PayloadNode
+	var walk func(value interface{}, path string, depth int)
+	walk = func(value interface{}, path string, depth int) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				child := JoinPath(path, key)
+				nodes = append(nodes, PayloadNode{Path: child, Depth: depth, Label: key, Value: v[key]})
+				walk(v[key], child, depth+1)
+			}
+		case []interface{}:
+			for i, item := range v {
+				child := fmt.Sprintf("%s[%d]", path, i)
+				nodes = append(nodes, PayloadNode{Path: child, Depth: depth, Label: fmt.Sprintf("[%d]", i), Value: item})
+				walk(item, child, depth+1)
+			}
+		}
+	}
+	walk(data, "data", 0)
+	return nodes
+}
+
+// JoinPath appends an object key to a path, quoting keys that aren't plain identifiers
+func JoinPath(path, key string) string {
+	if plainKey.MatchString(key) {
+		return path + "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	return fmt.Sprintf("%s[%s]", path, quoted)
+}
+
+// NodeSummary renders a node's value for the tree: scalars as JSON, containers by size
+func NodeSummary(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return fmt.Sprintf("{%d keys}", len(v))
+	case []interface{}:
+		return fmt.Sprintf("[%d items]", len(v))
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// FoldDepth is how deep objects and arrays start unfolded in the payload pane:
+// top-level ones are open, anything nested deeper starts folded
+const FoldDepth = 1
+
+// PayloadFold is the JSON tree state of the selected event's payload: which
+// objects and arrays were folded or unfolded and the node under the cursor
+// The payload pane and the full-screen tree viewer share it, so folds and
+// the cursor carry over between them. Like PayloadScroll it's shared with
+// rendering and starts over when another event is selected
+type PayloadFold struct {
+	EventID string          // Event the state applies to
+	Open    map[string]bool // Containers unfolded (true) or folded (false) by hand, by path
+	Cursor  string          // Path of the node under the cursor ("": the first node)
+	reveal  bool            // The cursor moved: scroll it into view at the next render
+}
+
+// Folded reports whether a container's children are hidden
+func (f *PayloadFold) Folded(node PayloadNode) bool {
+	if node.Leaf() {
+		return false
+	}
+	if f == nil {
+		return false // No fold state: everything is shown, as in archives
+	}
+	if open, ok := f.Open[node.Path]; ok {
+		return !open
+	}
+	return node.Depth >= FoldDepth
+}
+
+// Visible returns the nodes not hidden inside a folded container
+func (f *PayloadFold) Visible(nodes []PayloadNode) []PayloadNode {
+	var shown []PayloadNode
+	hideBelow := -1 // Depth of the folded container being skipped
+	for _, node := range nodes {
+		if hideBelow >= 0 && node.Depth > hideBelow {
+			continue
+		}
+		hideBelow = -1
+		shown = append(shown, node)
+		if f.Folded(node) {
+			hideBelow = node.Depth
+		}
+	}
+	return shown
+}
+
+// Move moves the cursor by delta visible nodes, within the tree
+func (f *PayloadFold) Move(event *events.Event, delta int) {
+	nodes := f.visibleFor(event)
+	if len(nodes) == 0 {
+		return
+	}
+	i := max(0, min(cursorIndex(nodes, f.Cursor)+delta, len(nodes)-1))
+	f.Cursor, f.reveal = nodes[i].Path, true
+}
+
+// Jump moves the cursor to the first or last visible node
+func (f *PayloadFold) Jump(event *events.Event, last bool) {
+	nodes := f.visibleFor(event)
+	if len(nodes) == 0 {
+		return
+	}
+	i := 0
+	if last {
+		i = len(nodes) - 1
+	}
+	f.Cursor, f.reveal = nodes[i].Path, true
+}
+
+// Selected returns the node under the cursor, false when the event has no tree
+func (f *PayloadFold) Selected(event *events.Event) (PayloadNode, bool) {
+	nodes := f.visibleFor(event)
+	if len(nodes) == 0 {
+		return PayloadNode{}, false
+	}
+	return nodes[cursorIndex(nodes, f.Cursor)], true
+}
+
+// Expand unfolds the container under the cursor, or moves into it when it's
+// unfolded already
+func (f *PayloadFold) Expand(event *events.Event) {
+	nodes := f.visibleFor(event)
+	if len(nodes) == 0 {
+		return
+	}
+	i := cursorIndex(nodes, f.Cursor)
+	node := nodes[i]
+	switch {
+	case node.Leaf():
+		return
+	case f.Folded(node):
+		f.setOpen(node.Path, true)
+		f.Cursor = node.Path
+	case i+1 < len(nodes) && nodes[i+1].Depth > node.Depth:
+		f.Cursor = nodes[i+1].Path
+	default:
+		return // Empty object or array
+	}
+	f.reveal = true
+}
+
+// Collapse folds the container under the cursor, or moves to its parent
+// when it's a value or folded already
+func (f *PayloadFold) Collapse(event *events.Event) {
+	nodes := f.visibleFor(event)
+	if len(nodes) == 0 {
+		return
+	}
+	i := cursorIndex(nodes, f.Cursor)
+	node := nodes[i]
+	if !node.Leaf() && !f.Folded(node) {
+		f.setOpen(node.Path, false)
+		f.Cursor, f.reveal = node.Path, true
+		return
+	}
+	for j := i - 1; j >= 0; j-- {
+		if nodes[j].Depth < node.Depth {
+			f.Cursor, f.reveal = nodes[j].Path, true
+			return
+		}
+	}
+}
+
+// TreeShown reports whether the payload pane shows an event's Data as the
+// tree; events with Content or a question are shown as such instead
+func TreeShown(event *events.Event) bool {
+	return event != nil && event.Content == "" && event.Question == nil && len(event.Data) > 0
+}
+
+// visibleFor returns the unfolded tree nodes of an event's (redacted) Data,
+// starting over when the event isn't the one the state applies to
+func (f *PayloadFold) visibleFor(event *events.Event) []PayloadNode {
+	if event == nil {
+		return nil
+	}
+	f.sync(event.ID)
+	return f.Visible(PayloadTree(Redacted(*event).Data))
+}
+
+// sync resets the state when another event is selected
+func (f *PayloadFold) sync(eventID string) {
+	if f.EventID != eventID {
+		*f = PayloadFold{EventID: eventID}
+	}
+}
+
+// setOpen records a hand-made fold or unfold
+func (f *PayloadFold) setOpen(path string, open bool) {
+	if f.Open == nil {
+		f.Open = make(map[string]bool)
+	}
+	f.Open[path] = open
+}
+
+// cursorIndex returns the index of the node at path, or 0 when it's not
+// shown (folded away, or gone from an updated event)
+func cursorIndex(nodes []PayloadNode, path string) int {
+	for i, node := range nodes {
+		if node.Path == path {
+			return i
+		}
+	}
+	return 0
+}
+
+// takeReveal reports whether the cursor moved since the last render, so the
+// payload pane scrolls it into view once
+func (f *PayloadFold) takeReveal() bool {
+	if f == nil || !f.reveal {
+		return false
+	}
+	f.reveal = false
+	return true
+}
+
+// PayloadFoldLines renders an event's Data as tree lines of at most width
+// cells, long values wrapped under their key, and returns the line the
+// cursor is on (-1 without fold state)
+func PayloadFoldLines(event *events.Event, fold *PayloadFold, width int) ([]string, int) {
+	nodes := PayloadTree(event.Data)
+	cursor, focus := -1, -1
+	if fold != nil {
+		fold.sync(event.ID)
+		nodes = fold.Visible(nodes)
+		cursor = cursorIndex(nodes, fold.Cursor)
+	}
+
+	var lines []string
+	for i, node := range nodes {
+		marker := "  "
+		if !node.Leaf() {
+			marker = "▾ "
+			if fold.Folded(node) {
+				marker = "▸ "
+			}
+		}
+		pointer, style := "  ", eventStyle
+		if i == cursor {
+			pointer, style, focus = "› ", promptStyle, len(lines)
+		}
+		indent := strings.Repeat("  ", node.Depth)
+		text := node.Label + ": " + NodeSummary(node.Value)
+		for j, part := range Wrap(text, max(10, width-4-len(indent))) {
+			if j > 0 {
+				pointer, marker = "  ", "  "
+			}
+			lines = append(lines, style.Render(pointer+indent+marker+part))
+		}
+	}
+	return lines, focus
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Lanes is how the event list attributes rows to their producer (Event.Source)
+type Lanes int
+
+// Lane modes, in the order m cycles through them
+// The zero value is LanesOff, the list as it has always looked
+const (
+	LanesOff    Lanes = iota // No attribution
+	LanesColor               // Rows tagged with their source in the source's color
+	LanesGutter              // Tags plus a swim-lane column per source left of the rows
+)
+
+// MaxLanes is how many sources get a gutter column; later ones are only tagged
+const MaxLanes = 8
+
+// laneColors are the source colors, assigned in order of first appearance
+var laneColors = []lipgloss.Color{"39", "208", "141", "42", "205", "226", "45", "167"}
+
+// String returns the lane mode's name
+func (l Lanes) String() string {
+	switch l {
+	case LanesColor:
+		return "color"
+	case LanesGutter:
+		return "gutter"
+	default:
+		return "off"
+	}
+}
+
+// Next returns the next lane mode, wrapping back to off
+func (l Lanes) Next() Lanes {
+	return (l + 1) % (LanesGutter + 1)
+}
+
+// ParseLanes returns the lane mode with a name ("" is off)
+func ParseLanes(name string) (Lanes, error) {
+	for l := LanesOff; l <= LanesGutter; l++ {
+		if name == l.String() {
+			return l, nil
+		}
+	}
+	if name == "" {
+		return LanesOff, nil
+	}
+	return LanesOff, fmt.Errorf("unknown lane mode %q (off, color or gutter)", name)
+}
+
+// paneSources returns the distinct sources of a pane's events, by first appearance
+// Assigning colors from the whole pane keeps them stable while scrolling
+func paneSources(pane *Pane) []string {
+	var sources []string
+	seen := make(map[string]bool)
+	for _, event := range pane.Events {
+		if source := event.EffectiveSource(); source != "" && !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// laneStyle returns the style of the lane-th source
+func laneStyle(lane int) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(laneColors[lane%len(laneColors)])
+}
+
+// renderSourceTag renders an event's source in its lane color ("" without a source)
+func renderSourceTag(event events.Event, lane int) string {
+	if lane < 0 {
+		return ""
+	}
+	return laneStyle(lane).Bold(true).Render(event.EffectiveSource()) + " "
+}
+
+// renderGutter renders a row's swim-lane columns, marking the event's lane
+// lane is -1 on rows without a source and on continuation lines
+func renderGutter(lane, lanes int) string {
+	lanes = min(lanes, MaxLanes)
+	if lanes == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for i := 0; i < lanes; i++ {
+		if i == lane {
+			b.WriteString(laneStyle(i).Bold(true).Render("●"))
+		} else {
+			b.WriteString(laneStyle(i).Faint(true).Render("│"))
+		}
+	}
+	b.WriteString(" ")
+	return b.String()
+}
+
+// gutterWidth is how many columns renderGutter takes
+func gutterWidth(lanes int) int {
+	if lanes = min(lanes, MaxLanes); lanes == 0 {
+		return 0
+	}
+	return lanes + 1
+}
+
+// renderLaneLegend renders every source in its color, for the line under the pane title
+func renderLaneLegend(sources []string, width int) string {
+	var parts []string
+	used := 0
+	for i, source := range sources {
+		if used+Width(source)+3 > width {
+			parts = append(parts, timestampStyle.Render(fmt.Sprintf("+%d", len(sources)-i)))
+			break
+		}
+		parts = append(parts, laneStyle(i).Render("● "+source))
+		used += Width(source) + 3
+	}
+	return strings.Join(parts, " ")
+}
@@ -1,39 +1,94 @@
 package tui
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
 )
 
+// Styles derived from the active theme (see theme.go)
 var (
 	// Style for pane borders
-	paneStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240")).
-			Padding(0, 1)
+	paneStyle lipgloss.Style
 
 	// Style for pane titles
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("99"))
+	titleStyle lipgloss.Style
 
 	// Style for event text
-	eventStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252"))
+	eventStyle lipgloss.Style
 
 	// Style for timestamps
-	timestampStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243"))
+	timestampStyle lipgloss.Style
+
+	// Style for the selected event
+	selectedStyle lipgloss.Style
+
+	// Style for the mini-map rows inside the shown window
+	minimapWindowStyle lipgloss.Style
+
+	// Style for short hashes in rows
+	hashStyle lipgloss.Style
+
+	// Style for hints and placeholders
+	hintStyle lipgloss.Style
+
+	// Style for payload headers
+	headerStyle lipgloss.Style
+
+	// Style for question prompts
+	promptStyle lipgloss.Style
+
+	// Style for row badges
+	badgeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Bold(true)
 )
 
+// ListView describes what the event list in the left pane shows and highlights
+type ListView struct {
+	Pane          string         // Pane whose events are listed
+	Filter        ListFilter     // Only list events matching this filter
+	SelectedIndex int            // Selected event (navigation cursor)
+	BlockingIndex *int           // If non-nil, event waiting for action
+	VisualStart   int            // First event of the visual selection (-1 when inactive)
+	VisualEnd     int            // Last event of the visual selection
+	Chips         ChipConfig     // Data keys shown as chips under each row
+	Zoom          Zoom           // How much of each event rows show (see zoom.go)
+	Lanes         Lanes          // How rows are attributed to their producer (see lanes.go)
+	Badges        map[int]string // Short labels shown at the end of rows, by event index
+	FullContent   bool           // Render the selected event's Content in full, not just a preview
+	RawContent    bool           // Show Content as written instead of rendering it as markdown
+	NewSince      time.Time      // Events added after this are below a "new" separator (zero: no separator)
+	Fresh         time.Duration  // How long newly added rows are highlighted (0 disables)
+	Payload       *PayloadScroll // Payload pane scroll position (nil: always from the top)
+	Fold          *PayloadFold   // Payload tree folds and cursor (nil: everything unfolded, no cursor)
+}
+
+// PayloadScroll is the payload pane's scroll position
+// Rendering clamps Offset to the payload's length and resets it when another
+// event is selected, so callers only add or subtract lines
+type PayloadScroll struct {
+	Offset  int    // Lines scrolled past
+	EventID string // Event the offset applies to
+	End     int    // Largest offset at the last render: a growing stream scrolled there follows its output
+}
+
+// FreshHighlight is how long newly arrived rows stay highlighted by default
+const FreshHighlight = 2 * time.Second
+
+// InVisualRange reports whether the event at index i is part of the visual selection
+func (v ListView) InVisualRange(i int) bool {
+	return v.VisualStart >= 0 && i >= v.VisualStart && i <= v.VisualEnd
+}
+
 // RenderSplitLayout renders a two-pane horizontal split layout
-// Left pane shows event list with selection, right pane shows selected event's payload or textarea
-func RenderSplitLayout(pm *PaneManager, selectedIndex int, blockingIndex *int, termWidth, termHeight int, inputMode bool, textareaModel textarea.Model) string {
+// Left pane shows the listed pane's events with selection, right pane shows selected event's payload or,
+// in input mode, the rendered input widget (textarea, option list or confirmation)
+func RenderSplitLayout(pm *PaneManager, view ListView, termWidth, termHeight int, inputMode bool, inputView string) string {
 	// Calculate pane dimensions
 	// Account for borders: 2 chars per border + 1 char separator = 5 chars total overhead
 	// Each pane gets padding: 2 chars (left + right)
@@ -44,12 +99,12 @@ func RenderSplitLayout(pm *PaneManager, selectedIndex int, blockingIndex *int, t
 	contentHeight := termHeight - 6
 
 	// Render left pane (event list with selection)
-	leftPane := pm.GetPane("left")
-	leftContent := renderPane(leftPane, paneWidth, contentHeight, selectedIndex, blockingIndex)
+	leftPane := pm.GetPane(view.Pane)
+	leftContent := renderPane(leftPane, view, paneWidth, contentHeight)
 
-	// Render right pane (payload viewer or textarea)
-	selectedEvent := pm.GetEventByIndex("left", selectedIndex)
-	rightContent := renderPayloadPane(selectedEvent, paneWidth, contentHeight, inputMode, textareaModel)
+	// Render right pane (payload viewer or input)
+	selectedEvent := pm.GetEventByIndex(view.Pane, view.SelectedIndex)
+	rightContent := renderPayloadPane(selectedEvent, view, paneWidth, contentHeight, inputMode, inputView)
 
 	// Join panes horizontally
 	layout := lipgloss.JoinHorizontal(
@@ -61,37 +116,179 @@ func RenderSplitLayout(pm *PaneManager, selectedIndex int, blockingIndex *int, t
 	return layout
 }
 
+// RenderEventDetails renders an event's payload as the payload pane does, for
+// events shown outside the split layout (e.g. found in an archive)
+func RenderEventDetails(event *events.Event, width, height int, scroll *PayloadScroll) string {
+	return renderPayloadPane(event, ListView{Payload: scroll}, width, height, false, "")
+}
+
 // renderPane renders a single pane with its title and events
-// If selectedIndex >= 0, that event will be highlighted
-// If blockingIndex is non-nil, that event is highlighted as blocking (waiting for action)
-func renderPane(pane *Pane, width, height int, selectedIndex int, blockingIndex *int) string {
+// Only events matching view.Filter are listed
+// If view.SelectedIndex >= 0, that event will be highlighted
+// If view.BlockingIndex is non-nil, that event is highlighted as blocking (waiting for action)
+// Events in the visual selection range are highlighted as selected
+func renderPane(pane *Pane, view ListView, width, height int) string {
 	var content strings.Builder
 
 	// Render title
-	title := titleStyle.Render(pane.Title)
+	titleText := pane.Title
+	if view.Filter.Text != "" {
+		titleText = i18n.T("list.filter", pane.Title, view.Filter.Text)
+	}
+	if view.Filter.MinSeverity != "" {
+		titleText += fmt.Sprintf(" [%s+]", view.Filter.MinSeverity)
+	}
+	if n := len(view.Filter.Hidden); n > 0 {
+		titleText += i18n.T("list.hidden_types", n)
+	}
+	if search := view.Filter.Search; search != nil {
+		verb := "find"
+		if search.Filter {
+			verb = "search"
+		}
+		titleText += fmt.Sprintf(" (%s: %s)", verb, search)
+	}
+	title := titleStyle.Render(titleText)
 	content.WriteString(title)
 	content.WriteString("\n")
 	content.WriteString(strings.Repeat("─", width-2))
-	content.WriteString("\n\n")
+	content.WriteString("\n")
+
+	// Producers by lane; the legend takes the blank line under the title
+	var sources []string
+	laneOf := make(map[string]int)
+	gutter := 0
+	if view.Lanes != LanesOff {
+		sources = paneSources(pane)
+		for i, source := range sources {
+			laneOf[source] = i
+		}
+		content.WriteString(renderLaneLegend(sources, width-2))
+		if view.Lanes == LanesGutter {
+			gutter = gutterWidth(len(sources))
+		}
+	}
+	content.WriteString("\n")
+	lane := func(event events.Event) int {
+		if i, ok := laneOf[event.EffectiveSource()]; ok {
+			return i
+		}
+		return -1
+	}
+	budget := width - gutter // Row width left of the gutter
+
+	visible := pane.VisibleIndices(view.Filter)
+	miniMap := ""
 
 	// Render events
 	if len(pane.Events) == 0 {
-		content.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243")).
-			Render("(no events yet)"))
+		content.WriteString(hintStyle.
+			Render(i18n.T("list.empty")))
+	} else if len(visible) == 0 {
+		content.WriteString(hintStyle.
+			Render(i18n.T("list.no_match")))
 	} else {
-		// Calculate how many events we can show
-		maxEvents := height - 3 // Account for title and separators
+		// Calculate how many lines we can show
+		maxLines := height - 3 // Account for title and separators
+
+		// Events that arrived since the pane was last looked at
+		firstNew, newCount := -1, 0
+		if !view.NewSince.IsZero() {
+			for _, idx := range visible {
+				if pane.ArrivedAt(idx).After(view.NewSince) {
+					if firstNew < 0 {
+						firstNew = idx
+					}
+					newCount++
+				}
+			}
+		}
+		if firstNew >= 0 {
+			maxLines-- // Room for the separator
+		}
+
+		// Rows an event takes at the zoom level, counting its chip row and Content lines
+		chips := make(map[int][]string)
+		details := make(map[int][]string)
+		rowLines := func(idx int) int {
+			n := 1
+			if view.Zoom < ZoomNormal {
+				return n
+			}
+			shown := Redacted(pane.Events[idx])
+			if c := view.Chips.Chips(shown); len(c) > 0 {
+				chips[idx] = c
+				n++
+			}
+			if view.Zoom >= ZoomDetailed {
+				if d := detailLines(shown); len(d) > 0 {
+					details[idx] = d
+					n += len(d)
+				}
+			}
+			return n
+		}
+
+		// The window ends Scroll events above the newest, and moves to keep the selection in view
+		// AIDEV-NOTE: Rendering writes the adjusted offset back to pane.Scroll, as
+		// only here is it known how many rows (with chips) fit
+		selected := -1
+		for pos, idx := range visible {
+			if idx == view.SelectedIndex {
+				selected = pos
+			}
+		}
+		end := len(visible) - pane.Scroll
+		if end < 1 || end > len(visible) {
+			end = len(visible)
+		}
+		if selected >= end {
+			end = selected + 1
+		}
+		if end < len(visible) {
+			maxLines-- // Room for the "newer below" line
+		}
+
+		// Fill upwards from the end of the window
+		lines := 0
+		start := end
+		for start > 0 {
+			n := rowLines(visible[start-1])
+			if lines+n > maxLines {
+				break
+			}
+			lines += n
+			start--
+		}
+
+		// Selection above the window: start the window at it instead
+		if selected >= 0 && selected < start {
+			start, end, lines = selected, selected, 0
+			for end < len(visible) {
+				n := rowLines(visible[end])
+				if lines+n > maxLines {
+					break
+				}
+				lines += n
+				end++
+			}
+		}
+		pane.Scroll = len(visible) - end
+		below := len(visible) - end
 
-		// Show most recent events
-		startIdx := 0
-		if len(pane.Events) > maxEvents {
-			startIdx = len(pane.Events) - maxEvents
+		// Mini-map of the whole list when it doesn't fit
+		// Rows start below the border, title, separator and blank line
+		if start > 0 || below > 0 {
+			miniMap = renderMiniMap(pane, visible, start, end, maxLines, 4)
+		}
+		visible = visible[start:end]
+		if firstNew >= 0 && firstNew < visible[0] {
+			firstNew = visible[0] // Older new events scrolled off; mark the top
 		}
 
-		// Style for selected event
-		selectedStyle := lipgloss.NewStyle().
-			Background(lipgloss.Color("240")).
+		// Style for events inside the visual selection
+		visualStyle := lipgloss.NewStyle().
+			Background(lipgloss.Color("24")).
 			Foreground(lipgloss.Color("255"))
 
 		// Style for blocking event (waiting for action)
@@ -100,94 +297,219 @@ func renderPane(pane *Pane, width, height int, selectedIndex int, blockingIndex
 			Foreground(lipgloss.Color("0")).   // Black text
 			Bold(true)
 
-		for i := startIdx; i < len(pane.Events); i++ {
+		// Style for rows that just arrived
+		freshStyle := lipgloss.NewStyle().
+			Background(lipgloss.Color("22")).
+			Foreground(lipgloss.Color("255"))
+
+		for _, i := range visible {
 			event := pane.Events[i]
 
-			// Format timestamp
+			// "New since you last looked" separator
+			if i == firstNew {
+				content.WriteString(renderNewSeparator(newCount, width-2))
+				content.WriteString("\n")
+			}
+
+			// Format timestamp, with the short hash people reference events by
 			timestamp := timestampStyle.Render(
 				fmt.Sprintf("[%s]", event.Timestamp.Format("15:04:05")),
 			)
+			if view.Zoom >= ZoomNormal {
+				timestamp += " " + hashStyle.Render(event.ShortHash())
+			}
 
-			// Format event type and message
-			eventText := eventStyle.Render(
+			// Format event type and message, colored by severity, after the producer's tag
+			eventText := view.Filter.Search.Highlight(
 				fmt.Sprintf("%s: %s", event.Type, event.Message),
+				severityStyle(event),
 			)
+			if view.Lanes != LanesOff {
+				eventText = renderSourceTag(event, lane(event)) + eventText
+			}
 
-			// Combine and truncate if needed
+			// Combine and truncate to the row, past the cursor
 			line := fmt.Sprintf("%s %s", timestamp, eventText)
+			if badge, ok := view.Badges[i]; ok {
+				line = fmt.Sprintf("%s %s", badgeStyle.Render(badge), line)
+			}
+			line = Truncate(line, budget-6)
 
 			// Determine cursor and styling
 			var cursor string
-			isBlocking := blockingIndex != nil && i == *blockingIndex
+			isBlocking := view.BlockingIndex != nil && i == *view.BlockingIndex
 
 			if isBlocking {
 				// Blocking event (waiting for action)
 				cursor = "⚠ "
-				if len(line) > width-6 {
-					line = line[:width-9] + "..."
-				}
 				line = blockingStyle.Render(cursor + line)
-			} else if i == selectedIndex {
+			} else if i == view.SelectedIndex {
 				// Selected event (navigation cursor)
 				cursor = "> "
-				if len(line) > width-6 {
-					line = line[:width-9] + "..."
-				}
 				line = selectedStyle.Render(cursor + line)
+			} else if view.InVisualRange(i) {
+				// Part of the visual selection
+				cursor = "▌ "
+				line = visualStyle.Render(cursor + line)
+			} else if time.Since(pane.ArrivedAt(i)) < view.Fresh {
+				// Just arrived
+				cursor = "+ "
+				line = freshStyle.Render(cursor + line)
 			} else {
 				// Normal event
 				cursor = "  "
-				if len(line) > width-6 {
-					line = line[:width-9] + "..."
-				}
 				line = cursor + line
 			}
 
+			if gutter > 0 {
+				line = renderGutter(lane(event), len(sources)) + line
+			}
 			content.WriteString(line)
 			content.WriteString("\n")
+
+			// Quick view chips under the row
+			if c, ok := chips[i]; ok {
+				if gutter > 0 {
+					content.WriteString(renderGutter(-1, len(sources)))
+				}
+				content.WriteString(renderChipRow(c, budget-2))
+				content.WriteString("\n")
+			}
+
+			// First lines of Content when zoomed in
+			for _, d := range details[i] {
+				if gutter > 0 {
+					content.WriteString(renderGutter(-1, len(sources)))
+				}
+				content.WriteString(renderDetailLine(d, budget-2))
+				content.WriteString("\n")
+			}
+		}
+
+		// Scrolled back: how far the newest event is
+		if below > 0 {
+			content.WriteString(hintStyle.
+				Render(i18n.T("list.newer", below)))
 		}
 	}
 
 	// Apply pane style (border and padding)
-	return paneStyle.
+	rendered := paneStyle.
 		Width(width).
 		Height(height).
 		Render(content.String())
+	if miniMap != "" {
+		rendered = lipgloss.JoinHorizontal(lipgloss.Top, rendered, miniMap)
+	}
+	return rendered
+}
+
+// renderNewSeparator renders the line above events that arrived since the pane was last looked at
+func renderNewSeparator(count, width int) string {
+	label := i18n.T("list.new_separator", count)
+	side := (width - Width(label)) / 2
+	if side < 2 {
+		side = 2
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214")).
+		Render(strings.Repeat("─", side) + label + strings.Repeat("─", side))
+}
+
+// MinInputRows is how many rows the input widget keeps however long the prompt is
+const MinInputRows = 3
+
+// payloadTitleRows is the payload pane title, its separator and the blank line under them
+const payloadTitleRows = 3
+
+// renderInputPrompt renders the prompt shown above the input widget: the
+// event's Content or Message, and the expected answer of typed questions
+// A prompt too long to leave MinInputRows for the input is cut
+func renderInputPrompt(selectedEvent *events.Event, width, height int) string {
+	var b strings.Builder
+
+	// Use event's Content or Message as the prompt text
+	promptText := i18n.T("input.default_prompt")
+	hidden := 0
+	if selectedEvent != nil {
+		if selectedEvent.Content != "" {
+			promptText, hidden = ContentPreview(selectedEvent.Content)
+		} else if selectedEvent.Message != "" {
+			promptText = selectedEvent.Message
+		}
+	}
+
+	b.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("62")).
+		Bold(true).
+		Render(fmt.Sprintf("✍️  %s\n\n", promptText)))
+	if hidden > 0 {
+		b.WriteString(previewFooter(hidden, false))
+		b.WriteString("\n\n")
+	}
+
+	// Typed questions state what kind of answer is expected
+	if selectedEvent != nil && selectedEvent.Question != nil {
+		b.WriteString(renderAnswerHint(selectedEvent.Question))
+	}
+
+	// Wrapped as the pane will wrap it, so the rows can be counted
+	prompt := lipgloss.NewStyle().Width(width - paneStyle.GetHorizontalPadding()).Render(b.String())
+	lines := strings.Split(strings.TrimSuffix(prompt, "\n"), "\n")
+	limit := max(1, height-payloadTitleRows-MinInputRows)
+	if len(lines) > limit {
+		lines = append(lines[:limit-1], hintStyle.
+			Render(i18n.T("payload.prompt_cut")))
+	}
+	return strings.Join(lines, "\n") + "\n"
 }
 
-// renderPayloadPane renders a pane showing the detailed payload of a selected event or textarea for input
-func renderPayloadPane(selectedEvent *events.Event, width, height int, inputMode bool, textareaModel textarea.Model) string {
+// InputRows returns how many rows the payload pane of a split layout leaves
+// for the input widget under the prompt of the selected event
+// termWidth and termHeight are what RenderSplitLayout is given
+func InputRows(selectedEvent *events.Event, termWidth, termHeight int) int {
+	if selectedEvent != nil {
+		shown := Redacted(*selectedEvent)
+		selectedEvent = &shown
+	}
+	width, height := (termWidth-8)/2, termHeight-6
+	prompt := renderInputPrompt(selectedEvent, width, height)
+	return max(MinInputRows, height-payloadTitleRows-lipgloss.Height(prompt)+1)
+}
+
+// renderPayloadPane renders a pane showing the detailed payload of a selected event or the input widget
+// Large Content is cut to a preview unless view.FullContent is set, and is
+// rendered as markdown (or a diff, by its content type) unless view.RawContent is set
+// Event types with a registered renderer are rendered by it (see RegisterRenderer),
+// unless view.RawContent is set
+// A payload longer than the pane scrolls by view.Payload's offset
+func renderPayloadPane(selectedEvent *events.Event, view ListView, width, height int, inputMode bool, inputView string) string {
 	var content strings.Builder
 
 	// Render title
-	title := titleStyle.Render("Event Payload")
+	title := titleStyle.Render(i18n.T("payload.title"))
 	content.WriteString(title)
 	content.WriteString("\n")
 	content.WriteString(strings.Repeat("─", width-2))
 	content.WriteString("\n\n")
+	titleLen := content.Len()
+	focus := -1 // Body line of the tree cursor, kept in view when it moves
+
+	// Secrets in Data are masked for display only
+	if selectedEvent != nil {
+		shown := Redacted(*selectedEvent)
+		selectedEvent = &shown
+	}
 
 	// AIDEV-NOTE: Clear-on-render - this function is called fresh each time,
 	// so old payload is automatically cleared before rendering new one
 
-	// INPUT MODE: Render textarea for user input
+	// INPUT MODE: Render the input widget under the pinned prompt
 	if inputMode {
-		// Use event's Content or Message as the prompt text
-		promptText := "Enter your response below:"
-		if selectedEvent != nil {
-			if selectedEvent.Content != "" {
-				promptText = selectedEvent.Content
-			} else if selectedEvent.Message != "" {
-				promptText = selectedEvent.Message
-			}
-		}
-
-		content.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("62")).
-			Bold(true).
-			Render(fmt.Sprintf("✍️  %s\n\n", promptText)))
+		content.WriteString(renderInputPrompt(selectedEvent, width, height))
 
-		// Render the textarea
-		content.WriteString(textareaModel.View())
+		// Render the input widget
+		content.WriteString(inputView)
 
 		// Apply pane style (border and padding)
 		return paneStyle.
@@ -198,79 +520,174 @@ func renderPayloadPane(selectedEvent *events.Event, width, height int, inputMode
 
 	// NORMAL MODE: Render event payload
 	if selectedEvent == nil {
-		content.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243")).
-			Render("(no event selected)"))
+		content.WriteString(hintStyle.
+			Render(i18n.T("payload.none_selected")))
+	} else if selectedEvent.Question != nil {
+		// Typed question: show the prompt and the allowed answers
+		content.WriteString(renderQuestion(selectedEvent))
+	} else if name, body, ok := renderCustom(*selectedEvent, width-6); ok && !view.RawContent {
+		// A renderer registered for the type, with the event metadata header
+		header := i18n.T("payload.header_renderer",
+			selectedEvent.Type,
+			selectedEvent.Timestamp.Format("15:04:05"),
+			selectedEvent.ShortHash(),
+			name)
+		content.WriteString(headerStyle.Render(header))
+		content.WriteString("\n\n")
+		content.WriteString(body)
 	} else if selectedEvent.Content != "" {
-		// Display raw text/markdown content (no preprocessing)
-		// Display event metadata header
-		header := fmt.Sprintf("Type: %s | Time: %s\n\n",
+		// Display text/markdown content with an event metadata header
+		format := "markdown (M: raw)"
+		if selectedEvent.IsDiff() {
+			format = "diff (M: raw)"
+		}
+		if view.RawContent {
+			format = "raw (M: " + strings.Fields(format)[0] + ")"
+		}
+		header := i18n.T("payload.header_content",
 			selectedEvent.Type,
-			selectedEvent.Timestamp.Format("15:04:05"))
-		content.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("99")).
+			selectedEvent.Timestamp.Format("15:04:05"),
+			selectedEvent.ShortHash(),
+			format)
+		content.WriteString(headerStyle.
 			Render(header))
 
-		// Display raw content as-is (text or markdown)
-		content.WriteString(eventStyle.Render(selectedEvent.Content))
+		// Display the content as markdown, a colorized diff or as-is, only the head of large documents
+		text, hidden := selectedEvent.Content, 0
+		if !view.FullContent {
+			text, hidden = ContentPreview(text)
+		}
+		switch {
+		case view.RawContent:
+			content.WriteString(eventStyle.Render(text))
+		case selectedEvent.IsDiff():
+			content.WriteString(RenderDiff(text))
+		default:
+			content.WriteString(RenderMarkdown(text, width-4))
+		}
+		if hidden > 0 {
+			content.WriteString("\n")
+			content.WriteString(previewFooter(hidden, true))
+		}
 	} else if selectedEvent.Data == nil || len(selectedEvent.Data) == 0 {
 		// Show event metadata when there's no payload
-		content.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243")).
-			Render("(no payload data)\n\n"))
-
-		content.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Render(fmt.Sprintf("Type: %s\n", selectedEvent.Type)))
-		content.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Render(fmt.Sprintf("Message: %s\n", selectedEvent.Message)))
-		content.WriteString(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Render(fmt.Sprintf("Time: %s\n", selectedEvent.Timestamp.Format("15:04:05"))))
+		content.WriteString(hintStyle.
+			Render(i18n.T("payload.empty") + "\n\n"))
+
+		content.WriteString(eventStyle.
+			Render(i18n.T("payload.type", selectedEvent.Type)))
+		content.WriteString(eventStyle.
+			Render(i18n.T("payload.message", selectedEvent.Message)))
+		content.WriteString(eventStyle.
+			Render(i18n.T("payload.time", selectedEvent.Timestamp.Format("15:04:05"))))
+		content.WriteString(eventStyle.
+			Render(i18n.T("payload.id", selectedEvent.ID, selectedEvent.ShortHash())))
 	} else {
-		// Fallback: Show formatted JSON payload (backward compatible)
-		jsonBytes, err := json.MarshalIndent(selectedEvent.Data, "", "  ")
-		if err != nil {
-			content.WriteString(lipgloss.NewStyle().
-				Foreground(lipgloss.Color("196")).
-				Render(fmt.Sprintf("Error formatting payload: %v", err)))
-		} else {
-			// Display event metadata header
-			header := fmt.Sprintf("Type: %s | Time: %s\n\n",
-				selectedEvent.Type,
-				selectedEvent.Timestamp.Format("15:04:05"))
-			content.WriteString(lipgloss.NewStyle().
-				Foreground(lipgloss.Color("99")).
-				Render(header))
-
-			// Display formatted JSON payload
-			payloadStr := string(jsonBytes)
-
-			// Word wrap for long lines
-			lines := strings.Split(payloadStr, "\n")
-			for _, line := range lines {
-				if len(line) > width-6 {
-					// Wrap long lines
-					for i := 0; i < len(line); i += width - 6 {
-						end := i + width - 6
-						if end > len(line) {
-							end = len(line)
-						}
-						content.WriteString(eventStyle.Render(line[i:end]))
-						content.WriteString("\n")
-					}
-				} else {
-					content.WriteString(eventStyle.Render(line))
-					content.WriteString("\n")
-				}
-			}
+		// Display event metadata header
+		header := i18n.T("payload.header",
+			selectedEvent.Type,
+			selectedEvent.Timestamp.Format("15:04:05"),
+			selectedEvent.ShortHash())
+		if view.Fold != nil {
+			header += i18n.T("payload.fold_hint")
+		}
+		content.WriteString(headerStyle.Render(header))
+		content.WriteString("\n\n")
+
+		// Display the payload as a JSON tree, nested objects and arrays folded
+		lines, cursor := PayloadFoldLines(selectedEvent, view.Fold, width-6)
+		if cursor >= 0 && view.Fold.takeReveal() {
+			focus = strings.Count(content.String()[titleLen:], "\n") + cursor
+		}
+		for _, line := range lines {
+			content.WriteString(line)
+			content.WriteString("\n")
 		}
 	}
 
-	// Apply pane style (border and padding)
+	// Apply pane style (border and padding), with clickable URLs
+	body := content.String()[titleLen:]
+	title, body = scrollPayload(body, title, selectedEvent, view.Payload, focus, width, height)
 	return paneStyle.
 		Width(width).
 		Height(height).
-		Render(content.String())
+		Render(Linkify(title + "\n" + strings.Repeat("─", width-2) + "\n\n" + body))
+}
+
+// scrollPayload cuts the payload body to the lines the pane shows at the scroll offset
+// The title gets the shown line range when the body doesn't fit
+// A focus line (>= 0) is scrolled into view
+func scrollPayload(body, title string, event *events.Event, scroll *PayloadScroll, focus, width, height int) (string, string) {
+	if scroll == nil || event == nil {
+		return title, body
+	}
+	if scroll.EventID != event.ID {
+		scroll.EventID, scroll.Offset, scroll.End = event.ID, 0, 0
+	}
+	following := event.IsStreamed() && !event.Final && scroll.Offset >= scroll.End
+
+	// Wrap as the pane would, so the offset counts screen lines
+	lines := strings.Split(lipgloss.NewStyle().Width(width-2).Render(strings.TrimRight(body, "\n")), "\n")
+	rows := height - 3 // Title, separator and blank line
+	if rows < 1 || len(lines) <= rows {
+		scroll.Offset, scroll.End = 0, 0
+		return title, body
+	}
+	scroll.End = len(lines) - rows
+	if following {
+		scroll.Offset = scroll.End
+	}
+	if focus >= 0 {
+		scroll.Offset = max(min(scroll.Offset, focus), focus-rows+1)
+	}
+	scroll.Offset = max(0, min(scroll.Offset, scroll.End))
+	end := scroll.Offset + rows
+	title = titleStyle.Render(i18n.T("payload.title_scrolled", scroll.Offset+1, end, len(lines)))
+	return title, strings.Join(lines[scroll.Offset:end], "\n")
+}
+
+// renderQuestion renders a question event's prompt and its allowed answers
+func renderQuestion(event *events.Event) string {
+	var content strings.Builder
+	question := event.Question
+
+	header := i18n.T("question.header", event.Timestamp.Format("15:04:05"))
+	content.WriteString(headerStyle.
+		Render(header))
+
+	content.WriteString(promptStyle.
+		Render("❓ " + question.Prompt))
+	content.WriteString("\n\n")
+
+	if event.Content != "" {
+		content.WriteString(eventStyle.Render(event.Content))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString(renderAnswerHint(question))
+
+	// List the answers with the keys that choose them
+	for _, action := range event.Actions {
+		if action.InputType != "" {
+			continue
+		}
+		line := fmt.Sprintf("  [%s] %s", action.Key, action.Label)
+		if question.Default != nil && fmt.Sprint(action.Event.Data["answer"]) == fmt.Sprint(question.Default) {
+			line += i18n.T("question.default")
+		}
+		content.WriteString(eventStyle.Render(line))
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
+
+// renderAnswerHint renders the expected answer kind and default of a question
+func renderAnswerHint(question *events.Question) string {
+	hint := i18n.T("question.answer", question.Kind)
+	if question.Default != nil {
+		hint += i18n.T("question.answer_default", question.Default)
+	}
+	return hintStyle.
+		Render(hint) + "\n\n"
 }
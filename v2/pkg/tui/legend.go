@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"path"
+	"sort"
+)
+
+// TypeSet is a set of event types
+type TypeSet map[string]bool
+
+// TypeCount is an event type seen in the panes and how many of its events they hold
+type TypeCount struct {
+	Type  string
+	Count int
+}
+
+// TypeCounts counts the events of each type across all panes, sorted by type
+func (pm *PaneManager) TypeCounts() []TypeCount {
+	counts := make(map[string]int)
+	for _, name := range pm.PaneNames() {
+		for _, event := range pm.GetPane(name).Events {
+			counts[event.Type]++
+		}
+	}
+	types := make([]TypeCount, 0, len(counts))
+	for typ, count := range counts {
+		types = append(types, TypeCount{Type: typ, Count: count})
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Type < types[j].Type })
+	return types
+}
+
+// Muted reports whether one of the filter's mute globs hides a type
+func (f ListFilter) Muted(eventType string) bool {
+	for _, mute := range f.Mutes {
+		if ok, _ := path.Match(mute, eventType); ok {
+			return true
+		}
+	}
+	return false
+}
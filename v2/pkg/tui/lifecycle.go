@@ -0,0 +1,68 @@
+package tui
+
+import "fmt"
+
+// Lifecycle is where an event stands from the operator's point of view
+type Lifecycle string
+
+// Lifecycle states, in the order an event moves through them
+const (
+	LifecycleNew       Lifecycle = "new"       // Arrived, never selected
+	LifecycleSeen      Lifecycle = "seen"      // Selected at least once
+	LifecycleResponded Lifecycle = "responded" // Its actions were answered (here or elsewhere)
+	LifecycleExpired   Lifecycle = "expired"   // Its actions were withdrawn without an answer
+)
+
+// LifecycleStates lists every state in lifecycle order
+var LifecycleStates = []Lifecycle{LifecycleNew, LifecycleSeen, LifecycleResponded, LifecycleExpired}
+
+// Badge returns the glyph shown in front of list rows
+func (l Lifecycle) Badge() string {
+	switch l {
+	case LifecycleNew:
+		return "●"
+	case LifecycleSeen:
+		return "○"
+	case LifecycleResponded:
+		return "✓"
+	case LifecycleExpired:
+		return "✗"
+	}
+	return " "
+}
+
+// ParseLifecycle validates a lifecycle state name
+func ParseLifecycle(s string) (Lifecycle, error) {
+	for _, state := range LifecycleStates {
+		if string(state) == s {
+			return state, nil
+		}
+	}
+	return "", fmt.Errorf("unknown lifecycle state %q", s)
+}
+
+// Lifecycles tracks the lifecycle state of events by ID
+// Events not tracked are new
+type Lifecycles map[string]Lifecycle
+
+// Get returns an event's state
+func (l Lifecycles) Get(id string) Lifecycle {
+	if state, ok := l[id]; ok {
+		return state
+	}
+	return LifecycleNew
+}
+
+// Set moves an event to a state
+// States only move forward: seen never undoes a response, and responded and
+// expired are final
+func (l Lifecycles) Set(id string, state Lifecycle) {
+	current := l.Get(id)
+	if current == LifecycleResponded || current == LifecycleExpired {
+		return
+	}
+	if state == LifecycleNew || (state == LifecycleSeen && current != LifecycleNew) {
+		return
+	}
+	l[id] = state
+}
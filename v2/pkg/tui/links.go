@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Hyperlinks enables OSC 8 hyperlinks for URLs in the payload pane
+// Terminals without OSC 8 support ignore the sequences and show plain text
+var Hyperlinks = true
+
+// urlPattern matches http(s) URLs up to whitespace, quotes, brackets or an escape sequence
+var urlPattern = regexp.MustCompile("https?://[^\\s<>\"'`\\x1b]+")
+
+// FindLinks returns the URLs in text in order of appearance, without duplicates
+func FindLinks(text string) []string {
+	var links []string
+	seen := make(map[string]bool)
+	for _, link := range urlPattern.FindAllString(text, -1) {
+		link = trimLink(link)
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// EventLinks returns the URLs in an event's Message, Content and string Data values
+func EventLinks(event events.Event) []string {
+	texts := []string{event.Message, event.Content}
+	texts = append(texts, dataStrings(event.Data)...)
+	return FindLinks(strings.Join(texts, "\n"))
+}
+
+// Hyperlink wraps text in an OSC 8 hyperlink to url
+func Hyperlink(url, text string) string {
+	return fmt.Sprintf("\x1b]8;;%s\x1b\\%s\x1b]8;;\x1b\\", url, text)
+}
+
+// Linkify turns every URL in text into an OSC 8 hyperlink (when Hyperlinks is on)
+func Linkify(text string) string {
+	if !Hyperlinks {
+		return text
+	}
+	return urlPattern.ReplaceAllStringFunc(text, func(match string) string {
+		link := trimLink(match)
+		return Hyperlink(link, link) + match[len(link):]
+	})
+}
+
+// trimLink drops trailing punctuation that usually ends a sentence, not the URL
+// A closing parenthesis is kept when the URL opened one (Wikipedia-style links)
+func trimLink(link string) string {
+	for len(link) > 0 {
+		last := link[len(link)-1]
+		switch {
+		case strings.IndexByte(".,;:!?]}", last) >= 0:
+		case last == ')' && strings.Count(link, "(") < strings.Count(link, ")"):
+		default:
+			return link
+		}
+		link = link[:len(link)-1]
+	}
+	return link
+}
+
+// dataStrings collects the string values of nested data, in key order
+func dataStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var out []string
+		for _, k := range keys {
+			out = append(out, dataStrings(v[k])...)
+		}
+		return out
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			out = append(out, dataStrings(item)...)
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// markdownRenderers caches a glamour renderer per wrap width and theme
+// AIDEV-NOTE: glamour's renderers keep state while rendering, so they are
+// used one at a time under the lock
+var markdownRenderers = struct {
+	sync.Mutex
+	byKey map[markdownKey]*glamour.TermRenderer
+}{byKey: make(map[markdownKey]*glamour.TermRenderer)}
+
+// markdownKey identifies a cached renderer
+type markdownKey struct {
+	width int
+	light bool
+}
+
+// RenderMarkdown renders the markdown agents write for the terminal with
+// glamour, wrapped to width and styled for the light or dark theme
+// ```diff blocks are colorized like diff content (see RenderDiff); text
+// glamour can't render is shown as written
+func RenderMarkdown(text string, width int) string {
+	var out, markdown, diff []string
+	inCode, inDiff := false, false
+	flush := func() {
+		if len(markdown) > 0 {
+			out = append(out, renderGlamour(strings.Join(markdown, "\n"), width))
+			markdown = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		fence := strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+		switch {
+		case inDiff && fence:
+			out = append(out, renderDiffLines(diff))
+			diff, inCode, inDiff = nil, false, false
+		case inDiff:
+			diff = append(diff, line)
+		case fence && !inCode && strings.TrimLeft(trimmed, "`~ ") == "diff":
+			flush()
+			inCode, inDiff = true, true
+		default:
+			if fence {
+				inCode = !inCode
+			}
+			markdown = append(markdown, line)
+		}
+	}
+	if inDiff {
+		out = append(out, renderDiffLines(diff)) // Unclosed fence
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+// renderGlamour renders markdown without diff blocks
+func renderGlamour(text string, width int) string {
+	markdownRenderers.Lock()
+	defer markdownRenderers.Unlock()
+
+	key := markdownKey{width: max(width, 1), light: CurrentTheme() == "light"}
+	r, ok := markdownRenderers.byKey[key]
+	if !ok {
+		var err error
+		r, err = glamour.NewTermRenderer(
+			glamour.WithStyles(markdownStyle(key.light)),
+			glamour.WithColorProfile(lipgloss.ColorProfile()),
+			glamour.WithWordWrap(key.width),
+		)
+		if err != nil {
+			return text
+		}
+		markdownRenderers.byKey[key] = r
+	}
+	rendered, err := r.Render(text)
+	if err != nil {
+		return text
+	}
+	return strings.Trim(rendered, "\n")
+}
+
+// markdownStyle returns glamour's standard style without the document's
+// margin and blank lines, which the payload pane draws itself
+func markdownStyle(light bool) ansi.StyleConfig {
+	style := styles.DarkStyleConfig
+	if light {
+		style = styles.LightStyleConfig
+	}
+	margin := uint(0)
+	style.Document.Margin = &margin
+	style.Document.BlockPrefix, style.Document.BlockSuffix = "", ""
+	return style
+}
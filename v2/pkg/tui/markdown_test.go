@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	text := "# Plan\n\n| step | owner |\n|---|---|\n| build | ci |\n\n```diff\n-old\n+new\n```\n\n- done"
+	out := RenderMarkdown(text, 40)
+	plain := ansi.Strip(out)
+	for _, want := range []string{"Plan", "build", "│", "• done"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("rendered markdown lacks %q:\n%s", want, plain)
+		}
+	}
+	if strings.Contains(plain, "|---|") || strings.Contains(plain, "# Plan") {
+		t.Errorf("markdown shown as written:\n%s", plain)
+	}
+	if !strings.Contains(out, renderDiffLines([]string{"-old", "+new"})) {
+		t.Errorf("the diff block isn't colorized like diff content:\n%q", out)
+	}
+	for _, line := range strings.Split(plain, "\n") {
+		if w := ansi.StringWidth(line); w > 40 {
+			t.Errorf("line is %d wide, want at most 40: %q", w, line)
+		}
+	}
+}
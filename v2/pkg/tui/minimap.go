@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Mini-map tick levels, in increasing order of importance
+const (
+	tickNone = iota
+	tickInfo
+	tickWarning
+	tickActionable
+	tickError
+)
+
+// Tick styles by level (the window style comes from the theme, see theme.go)
+var tickStyles = map[int]lipgloss.Style{
+	tickInfo:       lipgloss.NewStyle().Foreground(lipgloss.Color("243")),
+	tickWarning:    lipgloss.NewStyle().Foreground(lipgloss.Color("220")),
+	tickActionable: lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+	tickError:      lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
+}
+
+// tickLevel ranks an event for the mini-map by severity (see events.Event.EffectiveSeverity)
+func tickLevel(event events.Event) int {
+	severity := events.SeverityRank(event.EffectiveSeverity())
+	if severity >= events.SeverityRank(events.SeverityError) {
+		return tickError
+	}
+	if len(event.Actions) > 0 {
+		return tickActionable
+	}
+	if severity == events.SeverityRank(events.SeverityWarn) {
+		return tickWarning
+	}
+	return tickInfo
+}
+
+// renderMiniMap renders a one-column overview of every listed event
+// Each row covers an equal share of the list and shows the most important
+// event in it; rows overlapping the window of shown events are highlighted
+// topPad blank rows align the map with the first list row
+func renderMiniMap(pane *Pane, visible []int, windowStart, windowEnd, rows, topPad int) string {
+	n := len(visible)
+	if rows > n {
+		rows = n
+	}
+	if rows <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" \n", topPad))
+	for r := 0; r < rows; r++ {
+		from, to := r*n/rows, (r+1)*n/rows
+		level := tickNone
+		for _, idx := range visible[from:to] {
+			if l := tickLevel(pane.Events[idx]); l > level {
+				level = l
+			}
+		}
+
+		tick := "│"
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+		if level > tickInfo {
+			tick = "■"
+			style = tickStyles[level]
+		}
+		if to > windowStart && from < windowEnd {
+			style = style.Inherit(minimapWindowStyle)
+		}
+		b.WriteString(style.Render(tick))
+		if r < rows-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
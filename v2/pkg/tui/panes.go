@@ -1,16 +1,48 @@
 package tui
 
 import (
+	"path"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
 )
 
 // Pane represents a single display pane in the TUI
 type Pane struct {
-	Name      string          // Pane identifier (e.g., "left", "right")
-	Title     string          // Display title
-	Events    []events.Event  // Events in this pane
-	MaxEvents int             // Maximum events to keep
-	Scroll    int             // Scroll position (for future use)
+	Name        string                   // Pane identifier (e.g., "left", "right")
+	Title       string                   // Display title
+	Events      []events.Event           // Events in this pane
+	MaxEvents   int                      // Maximum events to keep
+	KeepPerType int                      // Always keep at least the last K events of each type (0 disables)
+	Scroll      int                      // Listed events hidden below the window (0: following the newest)
+	Titles      []TitleChange            // Runtime renames, oldest first (see SetTitle)
+	Arrivals    []time.Time              // When each event was added, parallel to Events
+	Reorder     time.Duration            // Late events up to this far behind the newest are inserted in timestamp order (0: delivery order)
+	Late        map[string]time.Duration // How far behind the newest event late arrivals were, by event ID
+	LastAdded   int                      // Index of the event added last (-1 if it was evicted right away)
+	Opened      time.Time                // When the pane was added
+	IdleTimeout time.Duration            // Ephemeral panes close after this long without events (0: never; see ephemeral.go)
+	Held        map[string]bool          // IDs of events never evicted, e.g. decisions waiting for an answer (see PaneManager.Hold)
+}
+
+// TitleChange records a pane rename
+type TitleChange struct {
+	From string
+	To   string
+	At   time.Time
+	By   string // ID of the pane.title event
+}
+
+// SetTitle renames the pane, recording the change
+func (p *Pane) SetTitle(title string, at time.Time, by string) {
+	if title == p.Title {
+		return
+	}
+	p.Titles = append(p.Titles, TitleChange{From: p.Title, To: title, At: at, By: by})
+	p.Title = title
 }
 
 // NewPane creates a new pane with the given name and title
@@ -21,58 +53,303 @@ func NewPane(name, title string, maxEvents int) *Pane {
 		Events:    make([]events.Event, 0),
 		MaxEvents: maxEvents,
 		Scroll:    0,
+		Opened:    time.Now(),
 	}
 }
 
 // AddEvent adds an event to the pane, maintaining the max events limit
+// An event timestamped before the newest one is recorded as late and, within
+// the Reorder window, inserted where its timestamp puts it; LastAdded tells
+// where it went
 func (p *Pane) AddEvent(event events.Event) {
-	p.Events = append(p.Events, event)
+	at := len(p.Events)
+	if at > 0 && !event.Timestamp.IsZero() {
+		if behind := p.Events[at-1].Timestamp.Sub(event.Timestamp); behind > 0 {
+			if p.Late == nil {
+				p.Late = make(map[string]time.Duration)
+			}
+			p.Late[event.ID] = behind
+			if behind <= p.Reorder {
+				at = p.chronologicalIndex(event.Timestamp)
+			}
+		}
+	}
+
+	if p.Scroll > 0 && at >= len(p.Events)-p.Scroll {
+		p.Scroll++ // Keep a scrolled-back window where it is
+	}
+	p.Events = slices.Insert(p.Events, at, event)
+	p.Arrivals = slices.Insert(p.Arrivals, at, time.Now())
+	p.LastAdded = at
 
 	// Keep only the last MaxEvents
-	if len(p.Events) > p.MaxEvents {
-		p.Events = p.Events[1:]
+	for len(p.Events) > p.MaxEvents {
+		idx := p.evictionIndex()
+		if idx < 0 {
+			// Every remaining event is held by a pending decision
+			break
+		}
+		delete(p.Late, p.Events[idx].ID)
+		p.Events = append(p.Events[:idx], p.Events[idx+1:]...)
+		p.Arrivals = append(p.Arrivals[:idx], p.Arrivals[idx+1:]...)
+		switch {
+		case idx < p.LastAdded:
+			p.LastAdded--
+		case idx == p.LastAdded:
+			p.LastAdded = -1
+		}
+	}
+}
+
+// chronologicalIndex returns where an event with timestamp t belongs:
+// after every event at or before t, searching from the newest
+func (p *Pane) chronologicalIndex(t time.Time) int {
+	i := len(p.Events)
+	for i > 0 && p.Events[i-1].Timestamp.After(t) {
+		i--
+	}
+	return i
+}
+
+// evictionIndex returns the index of the oldest event that can be dropped
+// without breaking the per-type retention guarantee, falling back to the
+// oldest event that isn't held, or -1 if every event is held.
+// When per-type retention pins every event (more types than MaxEvents/K), the
+// pane still stays within MaxEvents by dropping the oldest.
+// AIDEV-NOTE: Rare-but-important events (the plan, the last error) survive
+// floods of progress noise because only types with more than KeepPerType
+// newer-or-equal entries are eligible for eviction. Held events (pending
+// decisions) are never evicted: a flood must not take away work the operator
+// still has to do, so a pane full of them grows past MaxEvents.
+func (p *Pane) evictionIndex() int {
+	// Count occurrences per type; the oldest event of a type with more than
+	// K occurrences is never among that type's last K
+	counts := make(map[string]int)
+	if p.KeepPerType > 0 {
+		for _, event := range p.Events {
+			counts[event.Type]++
+		}
+	}
+	for i, event := range p.Events {
+		if p.Held[event.ID] {
+			continue
+		}
+		if p.KeepPerType <= 0 || counts[event.Type] > p.KeepPerType {
+			return i
+		}
+	}
+	for i, event := range p.Events {
+		if !p.Held[event.ID] {
+			return i
+		}
+	}
+	return -1
+}
+
+// ArrivedAt returns when the event at index i was added to the pane
+func (p *Pane) ArrivedAt(i int) time.Time {
+	if i < 0 || i >= len(p.Arrivals) {
+		return time.Time{}
+	}
+	return p.Arrivals[i]
+}
+
+// VisibleIndices returns the indices of events matching the filter, oldest first
+// An empty filter matches every event
+func (p *Pane) VisibleIndices(filter ListFilter) []int {
+	indices := make([]int, 0, len(p.Events))
+	for i, event := range p.Events {
+		if filter.Matches(event) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// ListFilter decides which events are listed
+// Hidden events stay in the pane, so clearing the filter brings them back
+type ListFilter struct {
+	Text        string     // Case-insensitive substring of Type or Message (empty matches all)
+	Mutes       []string   // Type globs that are never listed (e.g. "progress.*")
+	Hidden      TypeSet    // Types hidden with the type legend
+	State       Lifecycle  // Only list events in this lifecycle state (empty matches all)
+	Lifecycles  Lifecycles // Event states consulted by State
+	MinSeverity string     // Only list events at least this severe (empty matches all)
+	Search      *Search    // The / search; only its matches are listed when it filters (nil matches all)
+}
+
+// IsEmpty reports whether the filter lets every event through
+func (f ListFilter) IsEmpty() bool {
+	return f.Text == "" && len(f.Mutes) == 0 && len(f.Hidden) == 0 && f.State == "" && f.MinSeverity == "" &&
+		(f.Search == nil || !f.Search.Filter)
+}
+
+// Matches reports whether an event passes the filter
+func (f ListFilter) Matches(event events.Event) bool {
+	if f.Muted(event.Type) || f.Hidden[event.Type] {
+		return false
+	}
+	if f.State != "" && f.Lifecycles.Get(event.ID) != f.State {
+		return false
+	}
+	if f.MinSeverity != "" && events.SeverityRank(event.EffectiveSeverity()) < events.SeverityRank(f.MinSeverity) {
+		return false
+	}
+	if f.Search != nil && f.Search.Filter && !f.Search.Matches(event) {
+		return false
+	}
+	if f.Text == "" {
+		return true
 	}
+	needle := strings.ToLower(f.Text)
+	return strings.Contains(strings.ToLower(event.Type), needle) ||
+		strings.Contains(strings.ToLower(event.Message), needle)
 }
 
 // Clear removes all events from the pane
 func (p *Pane) Clear() {
 	p.Events = make([]events.Event, 0)
+	p.Arrivals = nil
+	p.Late = nil
+	p.Scroll = 0
+}
+
+// Route sends events whose type matches a glob to a pane, overriding Event.Pane
+type Route struct {
+	Type string `json:"type"` // Glob against Event.Type (e.g. "review.*")
+	Pane string `json:"pane"` // Target pane name
 }
 
 // PaneManager manages multiple panes and routes events to them
 type PaneManager struct {
 	Panes       map[string]*Pane
-	DefaultPane string // Pane to use when event.Pane is empty
+	DefaultPane string  // Pane to use when event.Pane is empty
+	Routes      []Route // Operator routing rules, first match wins
+	MaxPanes    int     // Unknown pane names get a pane of their own until there are this many (0: never)
+
+	order   []string        // Pane names in the order panes were added
+	options []PaneOptions   // Per-pane settings by name glob (see SetOptions)
+	held    map[string]bool // Events no pane evicts, shared by every pane (see Hold)
+
+	// Settings panes created on demand start with
+	maxEvents, keepPerType int
+	reorder                time.Duration
 }
 
 // NewPaneManager creates a new pane manager with left and right panes
 func NewPaneManager(maxEventsPerPane int) *PaneManager {
-	return &PaneManager{
-		Panes: map[string]*Pane{
-			"left":  NewPane("left", "Left Pane", maxEventsPerPane),
-			"right": NewPane("right", "Right Pane", maxEventsPerPane),
-		},
-		DefaultPane: "left",
+	pm := &PaneManager{
+		Panes:       make(map[string]*Pane),
+		DefaultPane: events.PaneLeft,
+		maxEvents:   maxEventsPerPane,
+		held:        make(map[string]bool),
+	}
+	pm.AddPane(events.PaneLeft, i18n.T("pane.left"), maxEventsPerPane)
+	pm.AddPane(events.PaneRight, i18n.T("pane.right"), maxEventsPerPane)
+	return pm
+}
+
+// AddPane adds a pane, listed after the existing ones
+func (pm *PaneManager) AddPane(name, title string, maxEvents int) *Pane {
+	pane := NewPane(name, title, maxEvents)
+	pane.Held = pm.held
+	if _, exists := pm.Panes[name]; !exists {
+		pm.order = append(pm.order, name)
+	}
+	pm.Panes[name] = pane
+	return pane
+}
+
+// Hold keeps the events with these IDs from being evicted from any pane,
+// replacing the ones held before
+func (pm *PaneManager) Hold(ids []string) {
+	clear(pm.held)
+	for _, id := range ids {
+		pm.held[id] = true
+	}
+}
+
+// SetKeepPerType sets the per-type retention floor on every pane
+func (pm *PaneManager) SetKeepPerType(k int) {
+	pm.keepPerType = k
+	for _, pane := range pm.Panes {
+		pane.KeepPerType = k
+	}
+}
+
+// SetReorder sets the window late events are reordered within on every pane
+func (pm *PaneManager) SetReorder(window time.Duration) {
+	pm.reorder = window
+	for _, pane := range pm.Panes {
+		pane.Reorder = window
+	}
+}
+
+// CanCreate reports whether a pane would still be created for an unknown name
+func (pm *PaneManager) CanCreate() bool {
+	return len(pm.Panes) < pm.MaxPanes
+}
+
+// createPane adds a pane for an unknown name, as the standard panes are set up
+// Returns nil at MaxPanes, for invalid names and for the errors and chat
+// panes, which only exist when their mode is on
+func (pm *PaneManager) createPane(name string) *Pane {
+	if !pm.CanCreate() || events.ValidPaneName(name) != nil ||
+		name == events.ErrorsPane || name == events.ChatPane {
+		return nil
 	}
+	pane := pm.AddPane(name, i18n.T("pane.created", strings.ToUpper(name[:1])+name[1:]), pm.maxEvents)
+	pane.KeepPerType = pm.keepPerType
+	pane.Reorder = pm.reorder
+	pm.applyOptions(pane)
+	return pane
 }
 
 // RouteEvent routes an event to the appropriate pane
-func (pm *PaneManager) RouteEvent(event events.Event) {
+// Returns the pane the event was added to, or nil if no pane accepted it
+func (pm *PaneManager) RouteEvent(event events.Event) *Pane {
 	// Use event's pane field, or default if empty
 	targetPane := event.Pane
 	if targetPane == "" {
 		targetPane = pm.DefaultPane
 	}
 
-	// Add to the target pane if it exists
-	if pane, exists := pm.Panes[targetPane]; exists {
-		pane.AddEvent(event)
-	} else {
-		// Fallback to default pane if target doesn't exist
-		if pane, exists := pm.Panes[pm.DefaultPane]; exists {
-			pane.AddEvent(event)
+	// Operator routing rules take precedence over the producer's choice
+	for _, route := range pm.Routes {
+		if ok, _ := path.Match(route.Type, event.Type); ok {
+			targetPane = route.Pane
+			break
 		}
 	}
+
+	// Add to the target pane, creating it if it doesn't exist yet
+	pane, exists := pm.Panes[targetPane]
+	if !exists {
+		pane = pm.createPane(targetPane)
+	}
+	if pane != nil {
+		pane.AddEvent(event)
+		return pane
+	}
+
+	// Fallback to default pane if target can't be created
+	if pane, exists := pm.Panes[pm.DefaultPane]; exists {
+		pane.AddEvent(event)
+		return pane
+	}
+	return nil
+}
+
+// PaneNames returns the names of all panes in the order they were added
+func (pm *PaneManager) PaneNames() []string {
+	return slices.Clone(pm.order)
+}
+
+// NextPane returns the pane delta places after name, wrapping around
+func (pm *PaneManager) NextPane(name string, delta int) string {
+	at := max(0, slices.Index(pm.order, name))
+	n := len(pm.order)
+	return pm.order[((at+delta)%n+n)%n]
 }
 
 // GetPane returns a pane by name
@@ -89,3 +366,30 @@ func (pm *PaneManager) GetEventByIndex(paneName string, index int) *events.Event
 	}
 	return &pane.Events[index]
 }
+
+// Reset removes all events from every pane, keeping panes and routes
+// Runtime renames are undone as well
+func (pm *PaneManager) Reset() {
+	for _, pane := range pm.Panes {
+		pane.Clear()
+		if len(pane.Titles) > 0 {
+			pane.Title = pane.Titles[0].From
+			pane.Titles = nil
+		}
+	}
+}
+
+// ApplyTitle renames a pane if the event is a pane.title event
+// Returns false for other events and unknown panes
+func (pm *PaneManager) ApplyTitle(event events.Event) bool {
+	name, title, ok := event.PaneTitle()
+	if !ok {
+		return false
+	}
+	pane := pm.GetPane(name)
+	if pane == nil {
+		return false
+	}
+	pane.SetTitle(title, event.Timestamp, event.ID)
+	return true
+}
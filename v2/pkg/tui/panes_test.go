@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// paneTypes returns the types of the pane's events, oldest first
+func paneTypes(p *Pane) []string {
+	types := make([]string, len(p.Events))
+	for i, event := range p.Events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func TestKeepPerTypeSurvivesFloods(t *testing.T) {
+	p := NewPane("left", "Left", 3)
+	p.KeepPerType = 1
+	p.AddEvent(events.Event{ID: "plan", Type: "plan"})
+	for i := 0; i < 10; i++ {
+		p.AddEvent(events.Event{ID: fmt.Sprint(i), Type: "progress"})
+	}
+	if got := fmt.Sprint(paneTypes(p)); got != "[plan progress progress]" {
+		t.Fatalf("pane holds %s, want the plan and the last two progress events", got)
+	}
+}
+
+func TestKeepPerTypeFallsBackToOldest(t *testing.T) {
+	p := NewPane("left", "Left", 3)
+	p.KeepPerType = 1
+	// Five types, each pinned by K=1: none is eligible, so the oldest go
+	for i := 0; i < 5; i++ {
+		p.AddEvent(events.Event{ID: fmt.Sprint(i), Type: fmt.Sprintf("type%d", i)})
+	}
+	if got := fmt.Sprint(paneTypes(p)); got != "[type2 type3 type4]" {
+		t.Fatalf("pane holds %s, want the newest three", got)
+	}
+}
+
+func TestHeldEventsAreNeverEvicted(t *testing.T) {
+	p := NewPane("left", "Left", 2)
+	p.Held = map[string]bool{"decision": true}
+	p.AddEvent(events.Event{ID: "decision", Type: "deploy"})
+	for i := 0; i < 5; i++ {
+		p.AddEvent(events.Event{ID: fmt.Sprint(i), Type: "log"})
+	}
+	if got := fmt.Sprint(paneTypes(p)); got != "[deploy log]" {
+		t.Fatalf("pane holds %s, want the held decision and the newest log", got)
+	}
+}
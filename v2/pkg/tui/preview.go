@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// ContentPreviewBytes is how much of a large Content is rendered until the
+// operator loads the rest (0 renders everything)
+// AIDEV-NOTE: Rendering megabyte-scale documents through lipgloss on every
+// selection change makes the list sluggish; only the head is styled by default
+var ContentPreviewBytes = 2048
+
+// ContentPreview returns the head of content to render and how many lines are left out
+// Content within the limit is returned whole with 0 hidden lines
+func ContentPreview(content string) (string, int) {
+	if ContentPreviewBytes <= 0 || len(content) <= ContentPreviewBytes {
+		return content, 0
+	}
+
+	// Cut on a rune boundary, then back to the last full line if there is one
+	cut := ContentPreviewBytes
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	if nl := strings.LastIndexByte(content[:cut], '\n'); nl > 0 {
+		cut = nl + 1
+	}
+
+	rest := content[cut:]
+	hidden := strings.Count(rest, "\n")
+	if !strings.HasSuffix(rest, "\n") {
+		hidden++ // Partial last line
+	}
+	return content[:cut], hidden
+}
+
+// previewFooter renders the hint below a truncated preview
+func previewFooter(hidden int, canLoad bool) string {
+	text := i18n.T("preview.hidden", hidden)
+	if canLoad {
+		text += i18n.T("preview.load")
+	}
+	return timestampStyle.Render(text + ")")
+}
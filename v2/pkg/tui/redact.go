@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/transform"
+)
+
+// RedactPatterns are Data key globs masked wherever events are displayed or
+// exported (e.g. "*_token", "password"), matched case-insensitively at any depth
+// Unlike transform rules, the events themselves keep their values
+var RedactPatterns []string
+
+// SetRedactPatterns validates and applies display redaction patterns
+func SetRedactPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid redact pattern %q: %w", pattern, err)
+		}
+	}
+	RedactPatterns = patterns
+	return nil
+}
+
+// Redacted returns the event as it may be shown, with matching Data values masked
+func Redacted(event events.Event) events.Event {
+	event.Data = transform.RedactData(event.Data, RedactPatterns)
+	return event
+}
+
+// RedactedAll applies Redacted to each event, leaving the slice intact
+func RedactedAll(evts []events.Event) []events.Event {
+	if len(RedactPatterns) == 0 {
+		return evts
+	}
+	out := make([]events.Event, len(evts))
+	for i, event := range evts {
+		out[i] = Redacted(event)
+	}
+	return out
+}
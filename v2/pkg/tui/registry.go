@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// TypeInfo documents an event type for operators
+type TypeInfo struct {
+	Type        string `json:"type"`               // Glob against Event.Type
+	Description string `json:"description"`        // What the event means
+	Expected    string `json:"expected,omitempty"` // What response is expected (derived from actions when empty)
+	Docs        string `json:"docs,omitempty"`     // Documentation link
+}
+
+// BuiltinTypes documents the event types the monitor itself produces
+var BuiltinTypes = []TypeInfo{
+	{
+		Type:        events.TypeSchemaViolation,
+		Description: "A payload broke the event schema (strict mode). The raw payload and the problems found are in Data.",
+		Expected:    "Nothing - fix the producer that sent it.",
+	},
+	{
+		Type:        events.TypePaneTitle,
+		Description: "An orchestrator renamed a pane (new title in message or data.title, target in pane or data.pane).",
+		Expected:    "Nothing - the pane title already changed.",
+	},
+	{
+		Type:        events.TypeQuestionAnswer,
+		Description: "A typed answer to a question event, published by a monitor. data.answer holds the value.",
+		Expected:    "Nothing - this is a response.",
+	},
+}
+
+// TypeRegistry looks up documentation for event types
+// The first matching entry wins; configured entries come before BuiltinTypes
+type TypeRegistry []TypeInfo
+
+// NewTypeRegistry combines configured entries with the built-in ones
+func NewTypeRegistry(configured []TypeInfo) TypeRegistry {
+	return append(append(TypeRegistry(nil), configured...), BuiltinTypes...)
+}
+
+// Lookup returns the entry documenting eventType
+func (r TypeRegistry) Lookup(eventType string) (TypeInfo, bool) {
+	for _, info := range r {
+		if ok, _ := path.Match(info.Type, eventType); ok {
+			return info, true
+		}
+	}
+	return TypeInfo{}, false
+}
+
+// ExpectedResponse describes the response an event asks for
+// A registry entry's Expected wins; otherwise it is derived from the event's question or actions
+func ExpectedResponse(info TypeInfo, event events.Event) string {
+	if info.Expected != "" {
+		return info.Expected
+	}
+	if event.Question != nil {
+		if len(event.Question.Options) > 0 {
+			return i18n.T("typehelp.answer_options", event.Question.Prompt, strings.Join(event.Question.Options, ", "))
+		}
+		return i18n.T("typehelp.answer", event.Question.Prompt, event.Question.Kind)
+	}
+
+	actions := append([]events.Action(nil), event.Actions...)
+	events.SortActions(actions)
+	var choices []string
+	for _, action := range actions {
+		if action.InputType != "" {
+			choices = append(choices, fmt.Sprintf("%s (%s)", action.Label, action.InputHint()))
+			continue
+		}
+		choices = append(choices, fmt.Sprintf("%s [%s]", action.Label, action.Key))
+	}
+	if len(choices) > 0 {
+		return i18n.T("typehelp.choose", strings.Join(choices, ", "))
+	}
+	return i18n.T("typehelp.informational")
+}
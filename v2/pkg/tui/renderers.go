@@ -0,0 +1,253 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Custom renderers: event types can be mapped to functions rendering the
+// payload pane's body, e.g. a progress bar for test.progress events. The
+// first matching type glob wins. Events no renderer matches, or whose
+// renderer declines them, are rendered as before: Content as markdown or a
+// diff, Data as a JSON tree. M shows the default rendering instead
+
+// Renderer renders an event's payload width columns wide
+// Returning false falls back to the default rendering (e.g. for data it doesn't understand)
+type Renderer func(event events.Event, width int) (string, bool)
+
+// RendererRule maps event types to a built-in renderer in the settings file
+type RendererRule struct {
+	Type     string `json:"type"`            // Glob against Event.Type
+	Renderer string `json:"renderer"`        // Built-in renderer (see BuiltinRenderers)
+	Field    string `json:"field,omitempty"` // Event field the renderer reads (see events.Event.Field; default per renderer)
+}
+
+// BuiltinRenderers make the renderers the settings file names, given the field to read
+var BuiltinRenderers = map[string]func(field string) Renderer{
+	"progress": ProgressRenderer,
+	"diff":     DiffRenderer,
+	"markdown": MarkdownRenderer,
+	"table":    TableRenderer,
+}
+
+// registeredRenderer is a renderer with the type glob it renders and the name shown for it
+type registeredRenderer struct {
+	glob   string
+	name   string
+	render Renderer
+}
+
+// renderers are the registered renderers, in registration order
+var renderers []registeredRenderer
+
+var (
+	progressFillStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	progressEmptyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	tableKeyStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+)
+
+// RegisterRenderer renders events whose type matches glob with render; name
+// is shown in the payload header
+// Renderers registered earlier win over later ones
+func RegisterRenderer(glob, name string, render Renderer) error {
+	if _, err := path.Match(glob, ""); err != nil {
+		return fmt.Errorf("invalid renderer type %q: %w", glob, err)
+	}
+	if render == nil {
+		return fmt.Errorf("renderer %q for %s is nil", name, glob)
+	}
+	renderers = append(renderers, registeredRenderer{glob: glob, name: name, render: render})
+	return nil
+}
+
+// ResetRenderers removes every registered renderer
+func ResetRenderers() {
+	renderers = nil
+}
+
+// ApplyRendererRules registers the built-in renderers rules map event types to
+func ApplyRendererRules(rules []RendererRule) error {
+	for _, rule := range rules {
+		builtin, ok := BuiltinRenderers[rule.Renderer]
+		if !ok {
+			names := make([]string, 0, len(BuiltinRenderers))
+			for name := range BuiltinRenderers {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			return fmt.Errorf("unknown renderer %q for %s (want %s)", rule.Renderer, rule.Type, strings.Join(names, ", "))
+		}
+		if err := RegisterRenderer(rule.Type, rule.Renderer, builtin(rule.Field)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderCustom renders an event with the first renderer matching its type
+// Returns the renderer's name, or false when none matches or it declined
+func renderCustom(event events.Event, width int) (string, string, bool) {
+	for _, r := range renderers {
+		if ok, _ := path.Match(r.glob, event.Type); !ok {
+			continue
+		}
+		body, ok := r.render(event, width)
+		return r.name, body, ok
+	}
+	return "", "", false
+}
+
+// ProgressRenderer renders a progress bar from field (default "data"): a
+// percentage, or an object with "percent", or "current" and "total"
+// The event's message is shown under the bar
+func ProgressRenderer(field string) Renderer {
+	if field == "" {
+		field = "data"
+	}
+	return func(event events.Event, width int) (string, bool) {
+		value, ok := fieldValue(event, field)
+		if !ok {
+			return "", false
+		}
+		var percent float64
+		detail := ""
+		switch v := value.(type) {
+		case float64:
+			percent = v
+		case map[string]interface{}:
+			current, hasCurrent := v["current"].(float64)
+			total, hasTotal := v["total"].(float64)
+			if p, ok := v["percent"].(float64); ok {
+				percent = p
+			} else if hasCurrent && hasTotal && total > 0 {
+				percent = current / total * 100
+			} else {
+				return "", false
+			}
+			if hasCurrent && hasTotal {
+				detail = fmt.Sprintf("%s / %s", formatNumber(current), formatNumber(total))
+			}
+		default:
+			return "", false
+		}
+		percent = math.Max(0, math.Min(percent, 100))
+
+		barWidth := max(width-8, 10)
+		filled := int(math.Round(percent / 100 * float64(barWidth)))
+		bar := progressFillStyle.Render(strings.Repeat("█", filled)) +
+			progressEmptyStyle.Render(strings.Repeat("░", barWidth-filled)) +
+			eventStyle.Render(fmt.Sprintf(" %3.0f%%", percent))
+		lines := []string{bar}
+		if detail != "" {
+			lines = append(lines, hintStyle.Render(detail))
+		}
+		if event.Message != "" {
+			lines = append(lines, "", eventStyle.Render(event.Message))
+		}
+		return strings.Join(lines, "\n"), true
+	}
+}
+
+// DiffRenderer renders the text at field (default "content") as a colorized
+// unified diff, e.g. a diff producers put in Data
+func DiffRenderer(field string) Renderer {
+	return func(event events.Event, width int) (string, bool) {
+		text, ok := fieldText(event, field)
+		if !ok {
+			return "", false
+		}
+		return RenderDiff(text), true
+	}
+}
+
+// MarkdownRenderer renders the text at field (default "content") as markdown
+func MarkdownRenderer(field string) Renderer {
+	return func(event events.Event, width int) (string, bool) {
+		text, ok := fieldText(event, field)
+		if !ok {
+			return "", false
+		}
+		return RenderMarkdown(text, width), true
+	}
+}
+
+// TableRenderer renders the object at field (default "data") as aligned
+// key/value rows, sorted by key; nested values are shown as compact JSON
+func TableRenderer(field string) Renderer {
+	if field == "" {
+		field = "data"
+	}
+	return func(event events.Event, width int) (string, bool) {
+		value, ok := fieldValue(event, field)
+		obj, isObject := value.(map[string]interface{})
+		if !ok || !isObject || len(obj) == 0 {
+			return "", false
+		}
+		keys := make([]string, 0, len(obj))
+		keyWidth := 0
+		for key := range obj {
+			keys = append(keys, key)
+			keyWidth = max(keyWidth, ansi.StringWidth(key))
+		}
+		sort.Strings(keys)
+		keyWidth = min(keyWidth, width/3)
+
+		lines := make([]string, 0, len(keys))
+		for _, key := range keys {
+			cell := ansi.Truncate(key, keyWidth, "…")
+			cell += strings.Repeat(" ", keyWidth-ansi.StringWidth(cell))
+			lines = append(lines, tableKeyStyle.Render(cell)+"  "+eventStyle.Render(tableValue(obj[key])))
+		}
+		return strings.Join(lines, "\n"), true
+	}
+}
+
+// fieldValue looks up an event field (see events.Event.Field); "data" is the whole payload
+func fieldValue(event events.Event, field string) (interface{}, bool) {
+	if field == "data" {
+		return map[string]interface{}(event.Data), event.Data != nil
+	}
+	return event.Field(field)
+}
+
+// fieldText returns the non-empty string at field (default "content")
+func fieldText(event events.Event, field string) (string, bool) {
+	if field == "" {
+		field = "content"
+	}
+	value, ok := event.Field(field)
+	text, isText := value.(string)
+	return text, ok && isText && text != ""
+}
+
+// tableValue formats a table cell: strings as written, anything else as compact JSON
+func tableValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return formatNumber(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// formatNumber formats a JSON number without a fraction when it's whole
+func formatNumber(n float64) string {
+	if n == math.Trunc(n) && math.Abs(n) < 1e15 {
+		return fmt.Sprintf("%d", int64(n))
+	}
+	return fmt.Sprintf("%g", n)
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// searchHighlightStyle marks search matches in list rows
+var searchHighlightStyle = lipgloss.NewStyle().
+	Background(lipgloss.Color("220")).
+	Foreground(lipgloss.Color("0"))
+
+// Search matches events against a query typed in the / bar
+// Queries are case-insensitive substrings, or regular expressions when Regex is set
+type Search struct {
+	Query  string
+	Regex  bool
+	Filter bool // Only list matching events; otherwise matches are just highlighted
+
+	re *regexp.Regexp
+}
+
+// NewSearch compiles a query
+func NewSearch(query string, regex, filter bool) (*Search, error) {
+	pattern := regexp.QuoteMeta(query)
+	if regex {
+		pattern = query
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return &Search{Query: query, Regex: regex, Filter: filter, re: re}, nil
+}
+
+// String describes the search for pane titles, e.g. /deploy.*/ or "deploy"
+func (s *Search) String() string {
+	if s.Regex {
+		return "/" + s.Query + "/"
+	}
+	return fmt.Sprintf("%q", s.Query)
+}
+
+// Matches reports whether the query occurs in the event's Type, Message or Content
+func (s *Search) Matches(event events.Event) bool {
+	return s.re.MatchString(event.Type) ||
+		s.re.MatchString(event.Message) ||
+		s.re.MatchString(event.Content)
+}
+
+// Highlight renders text in base with the matches picked out
+func (s *Search) Highlight(text string, base lipgloss.Style) string {
+	if s == nil || s.Query == "" {
+		return base.Render(text)
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range s.re.FindAllStringIndex(text, -1) {
+		if loc[0] == loc[1] {
+			continue // Empty matches (e.g. a*) have nothing to show
+		}
+		if loc[0] > last {
+			b.WriteString(base.Render(text[last:loc[0]]))
+		}
+		b.WriteString(searchHighlightStyle.Render(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	if last < len(text) {
+		b.WriteString(base.Render(text[last:]))
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// severityColors color event text by severity; info uses the theme's text color
+var severityColors = map[string]lipgloss.Color{
+	events.SeverityWarn:     lipgloss.Color("220"),
+	events.SeverityError:    lipgloss.Color("196"),
+	events.SeverityCritical: lipgloss.Color("196"),
+}
+
+// severityStyle returns the style of an event's list text
+// Debug is muted like timestamps, critical is bold on top of the error color
+func severityStyle(event events.Event) lipgloss.Style {
+	severity := event.EffectiveSeverity()
+	switch severity {
+	case events.SeverityDebug:
+		return timestampStyle
+	case events.SeverityInfo:
+		return eventStyle
+	}
+	style := eventStyle.Foreground(severityColors[severity])
+	if severity == events.SeverityCritical {
+		style = style.Bold(true)
+	}
+	return style
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// ContinueStream grows the open entry of the stream an event belongs to,
+// in whichever pane the stream's first event was routed to
+// Returns that pane, with LastAdded at the entry, or nil if the stream has
+// no open entry (it finished, was evicted, or its first event was missed):
+// the event is then routed as a new one
+func (pm *PaneManager) ContinueStream(event events.Event) *Pane {
+	if !event.IsStreamed() || len(event.Actions) > 0 || event.Question != nil {
+		return nil
+	}
+	for _, name := range pm.order {
+		pane := pm.Panes[name]
+		if pane == nil {
+			continue
+		}
+		if i := pane.openStream(event.StreamID); i >= 0 {
+			pane.Events[i] = pane.Events[i].WithChunk(event)
+			if i < len(pane.Arrivals) {
+				pane.Arrivals[i] = time.Now() // Streaming keeps ephemeral panes open
+			}
+			pane.LastAdded = i
+			return pane
+		}
+	}
+	return nil
+}
+
+// openStream returns the index of a stream's entry if it isn't final, or -1
+func (p *Pane) openStream(streamID string) int {
+	for i := len(p.Events) - 1; i >= 0; i-- {
+		if event := p.Events[i]; event.StreamID == streamID {
+			if event.Final || len(event.Actions) > 0 {
+				return -1
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// Streaming reports whether the event at index i is a stream still growing
+func (p *Pane) Streaming(i int) bool {
+	if i < 0 || i >= len(p.Events) {
+		return false
+	}
+	return p.Events[i].IsStreamed() && !p.Events[i].Final
+}
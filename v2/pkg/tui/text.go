@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Text is laid out in terminal cells, not bytes or runes: rows mix styled
+// text with CJK characters and emoji, which take two cells, and combining
+// marks, which take none. Widths ignore escape sequences, and cuts keep
+// sequences and grapheme clusters whole, so a cut row keeps its colors and
+// never ends in half a character.
+
+// Ellipsis marks text cut to fit
+const Ellipsis = "…"
+
+// Width returns how many cells text takes on screen
+func Width(text string) int {
+	return ansi.StringWidth(text)
+}
+
+// Truncate cuts text to at most width cells, ending it with an ellipsis when
+// anything was cut
+func Truncate(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return ansi.Truncate(text, width, Ellipsis)
+}
+
+// Wrap breaks text into lines of at most width cells, inside words if need
+// be; leading spaces (indentation) are kept
+func Wrap(text string, width int) []string {
+	if width <= 0 {
+		return strings.Split(text, "\n")
+	}
+	return strings.Split(ansi.Hardwrap(text, width, true), "\n")
+}
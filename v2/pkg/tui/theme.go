@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultTheme is the theme used unless another is applied
+const DefaultTheme = "default"
+
+// Theme is a named set of colors (ANSI 256 codes or hex) used by the layout
+type Theme struct {
+	Border    string `json:"border"`    // Pane borders
+	Title     string `json:"title"`     // Pane titles
+	Text      string `json:"text"`      // Event text
+	Muted     string `json:"muted"`     // Timestamps and hints
+	Selected  string `json:"selected"`  // Selected row background
+	Highlight string `json:"highlight"` // Selected row text
+}
+
+// Themes lists the built-in themes by name
+var Themes = map[string]Theme{
+	"default": {
+		Border: "240", Title: "99", Text: "252", Muted: "243", Selected: "240", Highlight: "255",
+	},
+	"light": {
+		Border: "250", Title: "55", Text: "235", Muted: "244", Selected: "153", Highlight: "16",
+	},
+	"high-contrast": {
+		Border: "255", Title: "226", Text: "255", Muted: "250", Selected: "21", Highlight: "231",
+	},
+}
+
+// currentTheme is the name of the applied theme
+var currentTheme string
+
+// themeColors overrides colors of every theme (set from the config file)
+var themeColors Theme
+
+// activeColors are the colors in use: the applied theme with overrides
+var activeColors Theme
+
+func init() {
+	ApplyTheme(DefaultTheme)
+}
+
+// ThemeNames returns the names of the built-in themes in sorted order
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CurrentTheme returns the name of the applied theme
+func CurrentTheme() string {
+	return currentTheme
+}
+
+// Merge returns the theme with the non-empty colors of o replacing its own
+func (t Theme) Merge(o Theme) Theme {
+	pick := func(own, override string) string {
+		if override != "" {
+			return override
+		}
+		return own
+	}
+	return Theme{
+		Border:    pick(t.Border, o.Border),
+		Title:     pick(t.Title, o.Title),
+		Text:      pick(t.Text, o.Text),
+		Muted:     pick(t.Muted, o.Muted),
+		Selected:  pick(t.Selected, o.Selected),
+		Highlight: pick(t.Highlight, o.Highlight),
+	}
+}
+
+// SetThemeColors overrides colors of whichever theme is applied and reapplies it
+// Empty colors keep the theme's own
+func SetThemeColors(colors Theme) {
+	themeColors = colors
+	ApplyTheme(currentTheme)
+}
+
+// Colors returns the colors in use, for styles drawn outside this package
+func Colors() Theme {
+	return activeColors
+}
+
+// ApplyTheme switches the layout styles to a named theme, effective on the next render
+func ApplyTheme(name string) error {
+	theme, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+	theme = theme.Merge(themeColors)
+
+	paneStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.Border)).
+		Padding(0, 1)
+	titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(theme.Title))
+	eventStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.Text))
+	timestampStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.Muted))
+	selectedStyle = lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.Selected)).
+		Foreground(lipgloss.Color(theme.Highlight))
+	minimapWindowStyle = lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.Selected))
+	hintStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.Muted))
+	headerStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.Title))
+	hashStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.Border))
+	promptStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(theme.Highlight))
+
+	activeColors = theme
+
+	currentTheme = name
+	return nil
+}
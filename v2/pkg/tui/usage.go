@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+	"github.com/durch/agneto/v2/pkg/i18n"
+)
+
+// UsageKey is whose usage a total adds up: a producer within a session
+type UsageKey struct {
+	Session  string
+	Producer string
+}
+
+// UsageTotal is the usage events of one session and producer added up to
+type UsageTotal struct {
+	UsageKey
+	Usage  events.Usage
+	Events int       // Events that reported usage
+	Last   time.Time // When the latest of them arrived
+}
+
+// UsageTotals keeps running totals of the usage events report, per session
+// and producer, from when the monitor started (events evicted from panes
+// still count)
+type UsageTotals struct {
+	totals map[UsageKey]*UsageTotal
+}
+
+// NewUsageTotals creates empty totals
+func NewUsageTotals() *UsageTotals {
+	return &UsageTotals{totals: make(map[UsageKey]*UsageTotal)}
+}
+
+// Add counts an event's usage; events without usage, or with negative
+// amounts, are left out and false is returned
+func (t *UsageTotals) Add(event events.Event, at time.Time) bool {
+	if event.Usage == nil || event.Usage.Validate() != nil {
+		return false
+	}
+	key := UsageKey{Session: event.SessionID, Producer: event.EffectiveSource()}
+	total := t.totals[key]
+	if total == nil {
+		total = &UsageTotal{UsageKey: key}
+		t.totals[key] = total
+	}
+	total.Usage = total.Usage.Add(*event.Usage)
+	total.Events++
+	total.Last = at
+	return true
+}
+
+// Len returns how many session and producer pairs reported usage
+func (t *UsageTotals) Len() int {
+	return len(t.totals)
+}
+
+// Reset forgets every total
+func (t *UsageTotals) Reset() {
+	t.totals = make(map[UsageKey]*UsageTotal)
+}
+
+// Rows returns the totals, most expensive first (then by session and producer)
+func (t *UsageTotals) Rows() []UsageTotal {
+	rows := make([]UsageTotal, 0, len(t.totals))
+	for _, total := range t.totals {
+		rows = append(rows, *total)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Usage.CostUSD != b.Usage.CostUSD {
+			return a.Usage.CostUSD > b.Usage.CostUSD
+		}
+		if a.Usage.Tokens() != b.Usage.Tokens() {
+			return a.Usage.Tokens() > b.Usage.Tokens()
+		}
+		if a.Session != b.Session {
+			return a.Session < b.Session
+		}
+		return a.Producer < b.Producer
+	})
+	return rows
+}
+
+// Sum returns every total added up
+func (t *UsageTotals) Sum() UsageTotal {
+	var sum UsageTotal
+	for _, total := range t.totals {
+		sum.Usage = sum.Usage.Add(total.Usage)
+		sum.Events += total.Events
+		if total.Last.After(sum.Last) {
+			sum.Last = total.Last
+		}
+	}
+	return sum
+}
+
+// FormatTokens shortens a token count: 950, 12.3k, 4.56M
+func FormatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.2fM", float64(n)/1_000_000)
+	case n >= 10_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprint(n)
+	}
+}
+
+// FormatCost formats dollars, with more decimals for amounts under a cent
+func FormatCost(usd float64) string {
+	if usd > 0 && usd < 0.01 {
+		return fmt.Sprintf("$%.4f", usd)
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
+
+// RenderUsage renders the totals as a table, one row per session and
+// producer with the sum underneath
+func RenderUsage(t *UsageTotals, width int) string {
+	label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+
+	var content strings.Builder
+	content.WriteString(label.Render(i18n.T("usage.title")))
+	content.WriteString("\n\n")
+
+	rows := t.Rows()
+	if len(rows) == 0 {
+		content.WriteString(dim.Render(i18n.T("usage.none")))
+		content.WriteString("\n\n")
+	} else {
+		sessionWidth, producerWidth := len(i18n.T("usage.session")), len(i18n.T("usage.producer"))
+		for _, row := range rows {
+			sessionWidth = max(sessionWidth, lipgloss.Width(usageName(row.Session)))
+			producerWidth = max(producerWidth, lipgloss.Width(usageName(row.Producer)))
+		}
+		line := func(session, producer, events, input, output, cost, duration string) string {
+			return fmt.Sprintf("%-*s  %-*s  %6s  %8s  %8s  %10s  %9s",
+				sessionWidth, session, producerWidth, producer, events, input, output, cost, duration)
+		}
+		content.WriteString(label.Render(line(i18n.T("usage.session"), i18n.T("usage.producer"), i18n.T("usage.events"),
+			i18n.T("usage.input"), i18n.T("usage.output"), i18n.T("usage.cost"), i18n.T("usage.duration"))))
+		content.WriteString("\n")
+		row := func(session, producer string, total UsageTotal) string {
+			return line(session, producer, fmt.Sprint(total.Events), FormatTokens(total.Usage.InputTokens),
+				FormatTokens(total.Usage.OutputTokens), FormatCost(total.Usage.CostUSD), total.Usage.Duration().Round(time.Second).String())
+		}
+		for _, total := range rows {
+			text := row(usageName(total.Session), usageName(total.Producer), total)
+			if total.Usage.Model != "" {
+				text += "  " + dim.Render(total.Usage.Model)
+			}
+			content.WriteString(text + "\n")
+		}
+		content.WriteString(label.Render(row(i18n.T("usage.total"), "", t.Sum())))
+		content.WriteString("\n\n")
+	}
+	content.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Render(i18n.T("usage.help")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 1).
+		Width(width - 4).
+		Render(content.String())
+}
+
+// usageName shows events without a session or producer as "-"
+func usageName(name string) string {
+	if name == "" {
+		return "-"
+	}
+	return name
+}
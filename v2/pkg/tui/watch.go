@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Watch aggregations: last shows the newest value, the others fold numbers
+var watchAggregations = []string{"last", "sum", "min", "max", "avg", "count"}
+
+// watchNameStyle and watchValueStyle render the watches panel entries
+var (
+	watchNameStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	watchValueStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255"))
+)
+
+// Watch is an expression evaluated against every incoming event and pinned in
+// the watches panel, e.g. "data.tests_passed" or "sum(data.cost_usd)"
+// Expressions are an event field path (as in Event.Field), optionally wrapped
+// in an aggregation: last (default), sum, min, max, avg or count
+type Watch struct {
+	Name string `json:"name,omitempty"` // Label in the panel (default: the expression)
+	Expr string `json:"expr"`
+	Type string `json:"type,omitempty"` // Glob against Event.Type limiting the events watched
+}
+
+// ParseWatch parses a --watch spec: an expression, optionally labeled as name=expr
+func ParseWatch(spec string) (Watch, error) {
+	var w Watch
+	w.Expr = strings.TrimSpace(spec)
+	if name, expr, ok := strings.Cut(spec, "="); ok {
+		w.Name, w.Expr = strings.TrimSpace(name), strings.TrimSpace(expr)
+	}
+	_, _, err := w.parse()
+	return w, err
+}
+
+// Label returns the watch's name in the panel
+func (w Watch) Label() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return w.Expr
+}
+
+// parse splits the expression into its aggregation and field path
+func (w Watch) parse() (agg, field string, err error) {
+	expr := strings.TrimSpace(w.Expr)
+	agg, field = "last", expr
+	if open := strings.Index(expr, "("); open >= 0 {
+		if !strings.HasSuffix(expr, ")") {
+			return "", "", fmt.Errorf("watch %q: missing closing parenthesis", w.Expr)
+		}
+		agg, field = strings.TrimSpace(expr[:open]), strings.TrimSpace(expr[open+1:len(expr)-1])
+		known := false
+		for _, a := range watchAggregations {
+			known = known || a == agg
+		}
+		if !known {
+			return "", "", fmt.Errorf("watch %q: unknown aggregation %q (want %s)", w.Expr, agg, strings.Join(watchAggregations, ", "))
+		}
+	}
+	// count() counts the matching events themselves
+	if field == "" && agg != "count" {
+		return "", "", fmt.Errorf("watch %q: no field", w.Expr)
+	}
+	if w.Type != "" {
+		if _, err := path.Match(w.Type, ""); err != nil {
+			return "", "", fmt.Errorf("watch %q: invalid type glob %q", w.Expr, w.Type)
+		}
+	}
+	return agg, field, nil
+}
+
+// watchState is a watch with the value it has folded so far
+type watchState struct {
+	Watch
+	agg, field string
+
+	last  interface{} // Newest value (last)
+	value float64     // Folded number (sum, min, max, avg)
+	count int         // Values folded, or events counted
+}
+
+// Watches evaluates the configured watches against incoming events
+type Watches struct {
+	states []*watchState
+}
+
+// NewWatches validates watches and starts them from nothing
+func NewWatches(watches []Watch) (*Watches, error) {
+	ws := &Watches{}
+	for _, w := range watches {
+		agg, field, err := w.parse()
+		if err != nil {
+			return nil, err
+		}
+		ws.states = append(ws.states, &watchState{Watch: w, agg: agg, field: field})
+	}
+	return ws, nil
+}
+
+// Len returns the number of watches
+func (ws *Watches) Len() int {
+	if ws == nil {
+		return 0
+	}
+	return len(ws.states)
+}
+
+// Reset forgets every value, e.g. before events are replayed again
+func (ws *Watches) Reset() {
+	if ws == nil {
+		return
+	}
+	for _, s := range ws.states {
+		s.last, s.value, s.count = nil, 0, 0
+	}
+}
+
+// Observe folds an event into the watches it matches
+// Events missing a watch's field are skipped, and so are values that aren't
+// numbers for the numeric aggregations
+func (ws *Watches) Observe(event events.Event) {
+	if ws == nil {
+		return
+	}
+	for _, s := range ws.states {
+		if s.Type != "" {
+			if ok, _ := path.Match(s.Type, event.Type); !ok {
+				continue
+			}
+		}
+		if s.field == "" {
+			s.count++
+			continue
+		}
+		value, ok := event.Field(s.field)
+		if !ok {
+			continue
+		}
+		switch s.agg {
+		case "last":
+			s.last = value
+			s.count++
+			continue
+		case "count":
+			s.count++
+			continue
+		}
+		n, ok := watchNumber(value)
+		if !ok {
+			continue
+		}
+		switch {
+		case s.count == 0:
+			s.value = n
+		case s.agg == "sum", s.agg == "avg":
+			s.value += n
+		case s.agg == "min":
+			s.value = math.Min(s.value, n)
+		case s.agg == "max":
+			s.value = math.Max(s.value, n)
+		}
+		s.count++
+	}
+}
+
+// watchNumber reads a value as a number; numeric strings count too
+func watchNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// formatWatchNumber shows up to 4 decimals, without trailing zeros
+func formatWatchNumber(n float64) string {
+	return strconv.FormatFloat(math.Round(n*1e4)/1e4, 'f', -1, 64)
+}
+
+// String returns the watch's current value ("-" until it has one)
+func (s *watchState) String() string {
+	if s.agg == "count" {
+		return strconv.Itoa(s.count)
+	}
+	if s.count == 0 {
+		return "-"
+	}
+	switch s.agg {
+	case "last":
+		if n, ok := s.last.(float64); ok {
+			return formatWatchNumber(n)
+		}
+		return Truncate(fmt.Sprint(s.last), maxChipValueLen)
+	case "avg":
+		return formatWatchNumber(s.value / float64(s.count))
+	}
+	return formatWatchNumber(s.value)
+}
+
+// Render renders the watches panel, wrapping entries to width ("" without watches)
+func (ws *Watches) Render(width int) string {
+	if ws.Len() == 0 {
+		return ""
+	}
+	var lines []string
+	line := ""
+	for _, s := range ws.states {
+		entry := watchNameStyle.Render(s.Label()+" ") + watchValueStyle.Render(s.String())
+		if line != "" && lipgloss.Width(line)+3+lipgloss.Width(entry) > width-4 {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " │ "
+		}
+		line += entry
+	}
+	lines = append(lines, line)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
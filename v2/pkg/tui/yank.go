@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// FormatEventsJSON concatenates events as JSON Lines (one event per line)
+func FormatEventsJSON(evts []events.Event) (string, error) {
+	var out strings.Builder
+	for _, event := range evts {
+		data, err := event.ToJSON()
+		if err != nil {
+			return "", err
+		}
+		out.Write(data)
+		out.WriteString("\n")
+	}
+	return out.String(), nil
+}
+
+// FormatEventsMarkdown renders events as a markdown document suitable for
+// pasting into an issue or chat: one heading per event followed by its
+// Content (diffs fenced, so they are highlighted), or its Data as a fenced JSON block
+func FormatEventsMarkdown(evts []events.Event) string {
+	var out strings.Builder
+	for i, event := range evts {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		out.WriteString(fmt.Sprintf("### [%s] %s: %s\n",
+			event.Timestamp.Format("15:04:05"), event.Type, event.Message))
+
+		if event.IsDiff() {
+			out.WriteString("\n```diff\n")
+			out.WriteString(strings.TrimRight(event.Content, "\n"))
+			out.WriteString("\n```\n")
+		} else if event.Content != "" {
+			out.WriteString("\n")
+			out.WriteString(strings.TrimRight(event.Content, "\n"))
+			out.WriteString("\n")
+		} else if len(event.Data) > 0 {
+			jsonBytes, err := json.MarshalIndent(event.Data, "", "  ")
+			if err == nil {
+				out.WriteString("\n```json\n")
+				out.Write(jsonBytes)
+				out.WriteString("\n```\n")
+			}
+		}
+	}
+	return out.String()
+}
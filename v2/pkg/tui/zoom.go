@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/durch/agneto/v2/pkg/events"
+)
+
+// Zoom is how much of each event the list pane shows
+type Zoom int
+
+// Zoom levels, from densest to most detailed
+// The zero value is ZoomNormal, the list as it has always looked
+const (
+	ZoomCompact  Zoom = -1 // One line per event
+	ZoomNormal   Zoom = 0  // Line plus quick view chips
+	ZoomDetailed Zoom = 1  // Line, chips and the first lines of Content
+)
+
+// DetailLines is how many lines of Content a detailed row shows
+const DetailLines = 3
+
+// detailStyle styles the Content lines of detailed rows
+var detailStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+// String returns the zoom level's name
+func (z Zoom) String() string {
+	switch {
+	case z <= ZoomCompact:
+		return "compact"
+	case z >= ZoomDetailed:
+		return "detailed"
+	default:
+		return "normal"
+	}
+}
+
+// In returns the next more detailed level, staying at the most detailed
+func (z Zoom) In() Zoom {
+	return min(z+1, ZoomDetailed)
+}
+
+// Out returns the next denser level, staying at the densest
+func (z Zoom) Out() Zoom {
+	return max(z-1, ZoomCompact)
+}
+
+// detailLines returns the first non-blank lines of an event's Content, for detailed rows
+func detailLines(event events.Event) []string {
+	var lines []string
+	for _, line := range strings.Split(event.Content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) == DetailLines {
+			break
+		}
+	}
+	return lines
+}
+
+// renderDetailLine renders one Content line under a detailed row, cut to width
+func renderDetailLine(line string, width int) string {
+	line = strings.ReplaceAll(line, "\t", "  ")
+	if width > 9 {
+		line = Truncate(line, width-8)
+	}
+	return "    " + detailStyle.Render("│ "+line)
+}